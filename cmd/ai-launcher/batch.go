@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/batch"
+	"ai-launcher/internal/project"
+)
+
+// newBatchCommand 创建 batch 命令：在多个已注册项目中非交互地执行同一提示词
+func newBatchCommand() *cobra.Command {
+	var (
+		filter batch.Filter
+		model  string
+		runner = batch.NewRunner()
+		yolo   bool
+		outDir string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "batch <prompt>",
+		Short: "在多个项目中批量执行提示词",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm := project.NewConfigManager()
+			filter.Model = project.AIModelType(model)
+			if filter.Model != "" && !cm.IsValidModel(filter.Model) {
+				return fmt.Errorf("无效的AI模型: %s", model)
+			}
+			if err := cm.LoadProjects(); err != nil {
+				return err
+			}
+			projects := filter.Select(cm.GetProjects())
+			if len(projects) == 0 {
+				return fmt.Errorf("没有符合条件的项目")
+			}
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				if jsonOutput {
+					return printJSON(out, projects)
+				}
+				for _, p := range projects {
+					fmt.Fprintf(out, "%-20s %-12s %s\n", p.Name, p.AIModel, p.Path)
+				}
+				return nil
+			}
+
+			if !jsonOutput {
+				fmt.Fprintf(out, "在 %d 个项目中执行（并发 %d）...\n", len(projects), runner.Concurrency)
+			}
+			report := runner.Run(cmd.Context(), projects, batch.Options{
+				Prompt:    strings.Join(args, " "),
+				ForceYolo: yolo,
+			})
+			report.Filter = filter
+
+			if outDir == "" {
+				outDir = batch.DefaultReportDir(report.StartedAt)
+			}
+			jsonPath, mdPath, err := report.Save(outDir)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if err := printJSON(out, report); err != nil {
+					return err
+				}
+			} else {
+				writeBatchSummary(cmd, report)
+				fmt.Fprintf(out, "\n报告: %s\n      %s\n", mdPath, jsonPath)
+			}
+
+			if !report.Succeeded() {
+				counts := report.Counts()
+				return fmt.Errorf("%d 个项目执行失败", counts[batch.StatusFailed]+counts[batch.StatusError])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.Tag, "tag", "", "按标签过滤项目")
+	cmd.Flags().StringVar(&filter.PathGlob, "path", "", "按路径通配符过滤项目 (如 ~/work/* 或 api-*)")
+	cmd.Flags().StringVar(&model, "model", "", "按模型过滤项目 (claude_code, gemini_cli, codex, aider)")
+	cmd.Flags().BoolVar(&filter.IncludeArchived, "include-archived", false, "同时在归档的项目中运行")
+	cmd.Flags().IntVarP(&runner.Concurrency, "concurrency", "c", batch.DefaultConcurrency, "同时运行的项目数")
+	cmd.Flags().DurationVar(&runner.Timeout, "timeout", batch.DefaultTimeout, "单个项目的超时时间，0 表示不限制")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "全部以 YOLO 模式运行")
+	cmd.Flags().StringVarP(&outDir, "out", "o", "", "报告输出目录（默认 ~/.ai-launcher/batch/<时间>）")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只列出将要执行的项目")
+	return cmd
+}
+
+// writeBatchSummary 输出每个项目的执行结果
+func writeBatchSummary(cmd *cobra.Command, report *batch.Report) {
+	out := cmd.OutOrStdout()
+	for _, res := range report.Results {
+		icon := "✅"
+		if res.Status != batch.StatusSucceeded {
+			icon = "❌"
+		}
+		line := fmt.Sprintf("%s %-20s exit=%-3d %s", icon, res.Project, res.ExitCode,
+			(time.Duration(res.DurationMs) * time.Millisecond).String())
+		if res.Error != "" {
+			line += "  " + res.Error
+		}
+		fmt.Fprintln(out, line)
+	}
+}
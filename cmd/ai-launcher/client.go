@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiClient 访问正在运行的 Web 启动器
+var apiClient = &http.Client{Timeout: 10 * time.Second}
+
+// apiGet 请求启动器接口并解析 JSON 响应
+func apiGet(path string, v interface{}) error {
+	resp, err := apiClient.Get(serverURL + path)
+	if err != nil {
+		return fmt.Errorf("无法连接到启动器 %s: %v", serverURL, err)
+	}
+	return decodeAPIResponse(resp, v)
+}
+
+// apiPost 以 JSON 请求体调用启动器接口并解析 JSON 响应
+func apiPost(path string, body, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("序列化请求失败: %v", err)
+	}
+	resp, err := apiClient.Post(serverURL+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("无法连接到启动器 %s: %v", serverURL, err)
+	}
+	return decodeAPIResponse(resp, v)
+}
+
+// decodeAPIResponse 解析响应；出错时返回服务端的错误信息
+func decodeAPIResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("启动器返回状态 %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/project"
+)
+
+// configOverrides --set 指定的配置覆盖
+var configOverrides []string
+
+// configOptions 生成配置加载选项；flagPaths 将命令自身的标志映射到配置路径，仅在用户显式指定时覆盖配置
+func configOptions(cmd *cobra.Command, projectDir string, flagPaths map[string]string) (config.Options, error) {
+	var overrides []config.Override
+	for _, kv := range configOverrides {
+		path, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return config.Options{}, fmt.Errorf("--set 格式应为 path=value: %s", kv)
+		}
+		overrides = append(overrides, config.Override{Path: strings.TrimSpace(path), Value: value})
+	}
+	for name, path := range flagPaths {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			overrides = append(overrides, config.Override{Path: path, Value: f.Value.String(), Origin: "--" + name})
+		}
+	}
+	return config.Options{ProjectDir: projectDir, Overrides: overrides}, nil
+}
+
+// loadConfig 按 configOptions 加载合并后的配置
+func loadConfig(cmd *cobra.Command, projectDir string, flagPaths map[string]string) (*config.Result, error) {
+	opts, err := configOptions(cmd, projectDir, flagPaths)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.Load(opts)
+	if err != nil {
+		return nil, fmt.Errorf("配置无效:\n%v", err)
+	}
+	return res, nil
+}
+
+// newConfigCommand 创建 config 命令：查看与校验分层配置
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "查看与校验配置",
+		Long: `配置按以下顺序合并，后者覆盖前者：
+  默认值 < ~/.ai-launcher/config.yaml（或 config.json）< 项目 .ai-launcher.yaml < 环境变量 < 命令行
+
+环境变量以 AI_LAUNCHER_ 开头，路径各级用双下划线分隔，例如 AI_LAUNCHER_OLLAMA__HOST=http://gpu:11434。
+命令行可用 --set path=value 覆盖任意配置项，例如 --set performance.max_concurrent_terminals=3。`,
+	}
+
+	cmd.AddCommand(
+		newConfigShowCommand(),
+		newConfigValidateCommand(),
+	)
+	return cmd
+}
+
+// newConfigShowCommand 创建 config show 命令
+func newConfigShowCommand() *cobra.Command {
+	var (
+		projectPath string
+		effective   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "显示合并后的配置；--effective 逐项列出每个值的来源",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			res, err := loadConfig(cmd, path, nil)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			if !effective {
+				masked := maskSecrets(res.Config)
+				if jsonOutput {
+					return printJSON(out, masked)
+				}
+				encoder := yaml.NewEncoder(out)
+				encoder.SetIndent(2)
+				return encoder.Encode(masked)
+			}
+
+			entries := res.Entries()
+			for i, e := range entries {
+				if isSecretPath(res.Config, e.Path) {
+					entries[i].Value = secretMask
+				}
+			}
+			if jsonOutput {
+				return printJSON(out, entries)
+			}
+			for _, e := range entries {
+				fmt.Fprintf(out, "%s = %s  # %s\n", e.Path, formatValue(e.Value), e.Source)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录，用于读取 .ai-launcher.yaml")
+	cmd.Flags().BoolVar(&effective, "effective", false, "逐项列出合并结果及来源")
+	return cmd
+}
+
+// newConfigValidateCommand 创建 config validate 命令
+func newConfigValidateCommand() *cobra.Command {
+	var projectPath string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "校验配置，列出所有错误字段",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			res, err := loadConfig(cmd, path, nil)
+			if err != nil {
+				return err
+			}
+			// 同一文件中的 launch 段是项目清单，单独校验
+			if _, err := project.LoadManifest(path); err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), map[string]interface{}{"valid": true, "files": res.Files})
+			}
+			if len(res.Files) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "没有配置文件，使用默认配置")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "配置有效: %s\n", strings.Join(res.Files, ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录，用于读取 .ai-launcher.yaml")
+	return cmd
+}
+
+const secretMask = "********"
+
+// isSecretPath 终端环境变量名匹配 security.sensitive_env_vars 且值不是 ${VAR} 引用时视为敏感
+func isSecretPath(cfg *config.Config, path string) bool {
+	parts := strings.Split(path, ".")
+	if len(parts) != 4 || parts[0] != "terminals" || parts[2] != "env" {
+		return false
+	}
+	value := cfg.Terminals[parts[1]].Env[parts[3]]
+	return cfg.Security.IsSensitive(parts[3]) && !strings.HasPrefix(value, "${")
+}
+
+// maskSecrets 返回隐藏敏感环境变量值的配置副本
+func maskSecrets(cfg *config.Config) *config.Config {
+	masked := *cfg
+	masked.Terminals = make(map[string]config.TerminalConfig, len(cfg.Terminals))
+	for name, t := range cfg.Terminals {
+		env := make(map[string]string, len(t.Env))
+		for k, v := range t.Env {
+			if isSecretPath(cfg, "terminals."+name+".env."+k) {
+				v = secretMask
+			}
+			env[k] = v
+		}
+		t.Env = env
+		masked.Terminals[name] = t
+	}
+	return &masked
+}
+
+// formatValue 按 YAML 行内格式显示值
+func formatValue(v interface{}) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = formatValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
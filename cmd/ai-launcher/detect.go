@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/project/detect"
+)
+
+// newDetectCommand 创建 detect 命令：显示项目环境检测结果
+func newDetectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "detect [project-path]",
+		Short: "检测项目的语言、框架、包管理器与常用命令",
+		Long: `检测项目的语言、框架、包管理器、测试/构建/lint 命令、monorepo 工作区、CI、
+容器文件与已有的 AI 配置文件（CLAUDE.md、GEMINI.md、AGENTS.md 等）。
+init、质量门禁、提示词优化与图形界面的项目对话框使用同样的检测结果。`,
+		Example: `  ai-launcher detect
+  ai-launcher detect ~/code/app --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				return fmt.Errorf("项目目录不存在: %s", path)
+			}
+			result := detect.Detect(path)
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, path)
+			for _, line := range result.Summary() {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/project"
+)
+
+// newProjectDiscoverCommand 创建 project discover 命令：在根目录下查找项目并批量导入
+func newProjectDiscoverCommand() *cobra.Command {
+	var (
+		ignore    []string
+		importAll bool
+		tool      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "discover [root...]",
+		Short: "在根目录下查找项目，可批量导入",
+		Long: `扫描根目录（默认为配置中的 discovery.roots），找出含 .git、go.mod、package.json、
+pyproject.toml 等标记的目录。找到项目后不再扫描其子目录。
+不加 --import 时只列出结果；加 --import 时导入所有尚未保存的项目。`,
+		Example: `  ai-launcher project discover ~/work ~/src --depth 2
+  ai-launcher project discover --import --tool gemini_cli`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := loadConfig(cmd, "", map[string]string{"depth": "discovery.max_depth"})
+			if err != nil {
+				return err
+			}
+			settings := res.Config.Discovery
+
+			opts := project.DiscoverOptions{Roots: args, MaxDepth: settings.MaxDepth, Ignore: settings.Ignore}
+			if len(opts.Roots) == 0 {
+				opts.Roots = settings.RootDirs()
+			}
+			if len(opts.Roots) == 0 {
+				return fmt.Errorf("请指定根目录，或在配置文件的 discovery.roots 中设置")
+			}
+			if cmd.Flags().Changed("ignore") {
+				opts.Ignore = ignore
+			}
+
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			model, err := parseTool(cm, tool)
+			if err != nil {
+				return err
+			}
+			found, err := cm.Discover(opts)
+			if err != nil {
+				return err
+			}
+
+			var added []project.ProjectConfig
+			if importAll {
+				if added, err = cm.ImportProjects(found, model); err != nil {
+					return err
+				}
+			}
+			if jsonOutput {
+				if importAll {
+					return printJSON(cmd.OutOrStdout(), added)
+				}
+				return printJSON(cmd.OutOrStdout(), found)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(found) == 0 {
+				fmt.Fprintf(out, "在 %s 中没有找到项目\n", strings.Join(opts.Roots, ", "))
+				return nil
+			}
+			newCount := 0
+			for _, p := range found {
+				state := "新"
+				if p.Known {
+					state = "已保存"
+				} else {
+					newCount++
+				}
+				fmt.Fprintf(out, "%-6s %-20s %-32s %s\n", state, p.Name, strings.Join(p.Markers, ","), p.Path)
+			}
+			switch {
+			case importAll:
+				fmt.Fprintf(out, "\n已导入 %d 个项目\n", len(added))
+			case newCount > 0:
+				fmt.Fprintf(out, "\n找到 %d 个新项目，使用 --import 全部导入，或 ai-launcher project add <path> 逐个添加\n", newCount)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("depth", 0, "根目录之下的最大扫描深度（默认取 discovery.max_depth）")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "跳过的目录名，支持 * 通配符，替换配置中的 discovery.ignore")
+	cmd.Flags().BoolVar(&importAll, "import", false, "导入所有尚未保存的项目")
+	cmd.Flags().StringVar(&tool, "tool", "", "导入项目使用的 AI 工具，项目清单指定了工具时以清单为准（默认 claude_code）")
+	return cmd
+}
+
+// newProjectRescanCommand 创建 project rescan 命令：检查已保存项目的路径是否仍存在
+func newProjectRescanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rescan",
+		Short: "检查已保存项目的路径，标记已不存在的项目",
+		Long:  "路径不存在的项目在列表中标记为“路径不存在”，路径恢复后标记自动清除。图形界面与 Web 启动器按 discovery.rescan_interval 在后台定期检查。",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			missing, err := cm.CheckMissing()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), missing)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(missing) == 0 {
+				fmt.Fprintf(out, "%d 个项目的路径都存在\n", len(cm.GetProjects()))
+				return nil
+			}
+			fmt.Fprintf(out, "%d 个项目的路径不存在:\n", len(missing))
+			for _, p := range missing {
+				fmt.Fprintf(out, "  %-20s %s\n", p.Name, p.Path)
+			}
+			fmt.Fprintln(out, "\n可使用 ai-launcher project rm <project> 删除，或 project add <新路径> 重新添加")
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/doctor"
+)
+
+// newDoctorCommand 创建 doctor 命令：诊断运行环境并给出修复建议
+func newDoctorCommand() *cobra.Command {
+	opts := doctor.DefaultOptions()
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "诊断运行环境（AI 工具、Ollama、配置、进程、字体）",
+		Long: `检查 AI 工具是否在 PATH 中及其版本、Ollama 服务与默认模型、配置文件能否解析、
+~/.ai-launcher 是否可写、是否有遗留的 AI 工具进程，以及图形界面显示中文所需的字体。
+每项结果为 pass、warn 或 fail，未通过的项目附带修复建议；有 fail 时命令以非零状态退出。`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 配置无效时仍然运行诊断，由 config 检查项报告错误
+			if !cmd.Flags().Changed("ollama") {
+				if res, err := loadConfig(cmd, "", nil); err == nil {
+					opts.OllamaURL = res.Config.Ollama.Host
+				}
+			}
+			report := doctor.Run(cmd.Context(), opts)
+			if jsonOutput {
+				if err := printJSON(cmd.OutOrStdout(), report); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), report.Details())
+			}
+			if report.Failed > 0 {
+				return fmt.Errorf("%d 项检查未通过", report.Failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.OllamaURL, "ollama", "", "Ollama 服务地址，默认取配置 ollama.host")
+	return cmd
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/project"
+)
+
+// newGatesCommand 创建 gates 命令：运行与查看项目的质量门禁
+func newGatesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gates",
+		Short: "运行与查看项目的质量门禁",
+		Long: `质量门禁是会话结束或空闲后运行的检查命令（测试、lint、构建）。
+门禁定义在 <项目>/.ai-launcher/gates.yaml 中；没有定义时根据 go.mod、package.json 等自动检测。
+报告保存在 <项目>/.addp/gates/reports。`,
+	}
+
+	cmd.AddCommand(
+		newGatesRunCommand(),
+		newGatesShowCommand(),
+		newGatesReportsCommand(),
+	)
+	return cmd
+}
+
+// newGatesRunCommand 创建 gates run 命令
+func newGatesRunCommand() *cobra.Command {
+	var feedback bool
+
+	cmd := &cobra.Command{
+		Use:   "run [project-path]",
+		Short: "立即运行质量门禁并保存报告",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			cfg, err := gates.LoadConfig(path, project.NewConfigManager().ManifestTrusted)
+			if err != nil {
+				return err
+			}
+			if len(cfg.Gates) == 0 {
+				if cfg.Untrusted {
+					return fmt.Errorf("%s 中的门禁配置尚未批准，请先运行 ai-launcher project trust %s", path, path)
+				}
+				return fmt.Errorf("%s 没有可运行的门禁，请在 %s 中定义", path, gates.ConfigPath(path))
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			report, err := gates.RunAndSave(ctx, path, cfg, gates.TriggerManual, "")
+			if err != nil && !errors.Is(err, gates.ErrNotInitialized) {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			switch {
+			case jsonOutput:
+				if err := printJSON(out, report); err != nil {
+					return err
+				}
+			case feedback && !report.Passed:
+				fmt.Fprintln(out, report.FeedbackPrompt())
+			default:
+				fmt.Fprintln(out, report.Details())
+				if report.Path != "" {
+					fmt.Fprintf(out, "报告: %s\n", report.Path)
+				} else {
+					fmt.Fprintln(out, "项目尚未初始化 ADDP（ai-launcher init），报告未保存")
+				}
+			}
+
+			if !report.Passed {
+				return fmt.Errorf("%d 个门禁未通过", len(report.Failed()))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&feedback, "feedback", false, "失败时输出可发回 AI 会话的后续提示词")
+	return cmd
+}
+
+// newGatesShowCommand 创建 gates show 命令：显示生效的门禁配置
+func newGatesShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show [project-path]",
+		Short: "显示项目生效的门禁配置",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			cfg, err := gates.LoadConfig(path, project.NewConfigManager().ManifestTrusted)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), cfg)
+			}
+
+			out := cmd.OutOrStdout()
+			source := map[gates.Source]string{gates.SourceFile: gates.ConfigPath(path), gates.SourceManifest: gates.ManifestPath(path), gates.SourceDetected: "自动检测"}[cfg.Source]
+			trigger := cfg.Trigger
+			if trigger == "" {
+				trigger = gates.TriggerBoth
+			}
+			fmt.Fprintf(out, "来源: %s\n", source)
+			if cfg.Untrusted {
+				fmt.Fprintf(out, "%s 中的门禁配置尚未批准，已忽略（ai-launcher project trust %s）\n", path, path)
+			}
+			fmt.Fprintf(out, "自动运行: %t（时机: %s，空闲判定: %s，失败反馈: %t）\n", cfg.AutoRun(path), trigger, cfg.IdleDuration(), cfg.Feedback)
+			if len(cfg.Gates) == 0 {
+				fmt.Fprintln(out, "没有门禁")
+				return nil
+			}
+			for _, g := range cfg.Gates {
+				timeout := g.Timeout
+				if timeout <= 0 {
+					timeout = gates.DefaultGateTimeout
+				}
+				fmt.Fprintf(out, "  %-10s %s (超时 %s)\n", g.Name, g.Command, timeout)
+			}
+			return nil
+		},
+	}
+}
+
+// newGatesReportsCommand 创建 gates reports 命令
+func newGatesReportsCommand() *cobra.Command {
+	var limit int
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "reports [project-path]",
+		Short: "列出最近的门禁报告",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			reports, err := gates.Reports(path, limit)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				if reports == nil {
+					reports = []*gates.Report{}
+				}
+				return printJSON(cmd.OutOrStdout(), reports)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(reports) == 0 {
+				fmt.Fprintln(out, "没有门禁报告")
+				return nil
+			}
+			for _, r := range reports {
+				writeGateReport(out, r, verbose)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "显示的报告数量（0 表示全部）")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "显示每个门禁的结果与失败输出")
+	return cmd
+}
+
+// writeGateReport 输出一条门禁报告
+func writeGateReport(out io.Writer, r *gates.Report, verbose bool) {
+	status := "通过"
+	if !r.Passed {
+		status = "失败"
+	}
+	fmt.Fprintf(out, "%s  %-7s %s  %s\n", r.StartedAt.Format(time.DateTime), r.Trigger, status, r.Summary())
+	if verbose {
+		fmt.Fprintln(out, r.Details())
+		fmt.Fprintln(out)
+	}
+}
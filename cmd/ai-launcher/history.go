@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/history"
+)
+
+// newHistoryCommand 创建 history 命令：查询各项目/工具的输入历史
+func newHistoryCommand() *cobra.Command {
+	var query history.Query
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "查询输入历史",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := history.NewHistoryStore().Search(query)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), entries)
+			}
+			for _, entry := range entries {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s  %-8s %s\n    %s\n",
+					entry.Time.Format("2006-01-02 15:04"), entry.Tool, entry.Project, entry.Text)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&query.Project, "project", "", "项目路径")
+	cmd.Flags().StringVar(&query.Tool, "tool", "", "工具名称 (claude, gemini, ...)")
+	cmd.Flags().StringVarP(&query.Text, "search", "s", "", "搜索关键字")
+	cmd.Flags().IntVarP(&query.Limit, "limit", "n", 20, "最多显示条数，0 表示不限制")
+
+	cmd.AddCommand(newHistoryClearCommand())
+	return cmd
+}
+
+// newHistoryClearCommand 创建 history clear 命令
+func newHistoryClearCommand() *cobra.Command {
+	var projectPath, tool string
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "清除指定项目与工具的输入历史",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if projectPath == "" || tool == "" {
+				return fmt.Errorf("必须同时指定 --project 和 --tool")
+			}
+			return history.NewHistoryStore().Clear(projectPath, tool)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", "", "项目路径")
+	cmd.Flags().StringVar(&tool, "tool", "", "工具名称")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/addp"
+)
+
+// newInitCommand 创建 init 命令：在项目中创建 .addp 目录结构
+func newInitCommand() *cobra.Command {
+	var opts addp.Options
+
+	cmd := &cobra.Command{
+		Use:   "init [project-path]",
+		Short: "初始化项目的 ADDP 结构 (.addp)",
+		Long:  "创建 .addp 目录、规格与工作流模板、Constitution 和配置文件。重复执行只补全缺失的部分，不覆盖已有文件。",
+		Example: `  ai-launcher init
+  ai-launcher init ~/code/app --framework golang --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			result, err := addp.Init(path, opts)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+
+			out := cmd.OutOrStdout()
+			verb := "已创建"
+			if result.DryRun {
+				verb = "将创建"
+			}
+			for _, dir := range result.Directories {
+				fmt.Fprintf(out, "%s目录 %s/\n", verb, dir)
+			}
+			for _, file := range result.Files {
+				fmt.Fprintf(out, "%s文件 %s\n", verb, file)
+			}
+			if len(result.Directories) == 0 && len(result.Files) == 0 {
+				fmt.Fprintf(out, "%s 的 ADDP 结构已完整，无需改动\n", path)
+				return nil
+			}
+			fmt.Fprintln(out, "\n检测到的项目环境:")
+			for _, line := range result.Environment.Summary() {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+			fmt.Fprintf(out, "\n项目 '%s'（%s）: %s %d 个目录、%d 个文件，保留 %d 个已有文件\n",
+				result.ProjectInfo.Name, result.ProjectInfo.Framework, verb, len(result.Directories), len(result.Files), len(result.Existing))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Framework, "framework", addp.FrameworkAuto, "项目框架 (react, vue, golang, python, rust, kotlin...)，默认自动检测")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "项目名称，默认为目录名")
+	cmd.Flags().StringVar(&opts.ProjectType, "type", addp.DefaultProjectType, "项目类型")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "只列出将要创建的内容")
+	return cmd
+}
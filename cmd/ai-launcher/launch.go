@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/launch"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/terminal"
+)
+
+// launchResult launch 命令的 JSON 输出
+type launchResult struct {
+	Name      string        `json:"name"`
+	Path      string        `json:"path"`
+	SessionID string        `json:"session_id,omitempty"`
+	Detached  bool          `json:"detached"`
+	ExitCode  int           `json:"exit_code"`
+	Gates     *gates.Report `json:"gates,omitempty"`
+	Warnings  []string      `json:"warnings,omitempty"`
+}
+
+// newLaunchCommand 创建 launch 命令：在当前终端或 Web 启动器中启动项目的 AI 工具
+func newLaunchCommand() *cobra.Command {
+	var (
+		tool   string
+		yolo   bool
+		req    launch.Request
+		detach bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "launch <project>",
+		Short: "启动项目的 AI 工具会话",
+		Long: `按名称或路径启动项目的 AI 工具。与图形界面相同，新会话会记录到项目历史，
+ADDP 项目会注入当前阶段的提示词、交接摘要与项目记忆，会话结束后按配置运行质量门禁。
+
+默认在当前终端前台运行；--detach 交给正在运行的 Web 启动器在后台运行（只能启动已保存的项目），之后可用 ps、logs、send、stop 管理。`,
+		Example: `  ai-launcher launch web --tool gemini_cli --yolo
+  ai-launcher launch web --profile review --prompt "main.go"
+  ai-launcher launch . --prompt "fix the failing tests" --detach`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			proj, err := launch.Resolve(cm, args[0])
+			if err != nil {
+				return err
+			}
+			if req.Tool, err = parseTool(cm, tool); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("yolo") {
+				req.Yolo = &yolo
+			}
+
+			if detach {
+				req.Project = proj.Path
+				var started launchResult
+				if err := apiPost("/api/terminals/start", req, &started); err != nil {
+					return err
+				}
+				started.Detached = true
+				if jsonOutput {
+					return printJSON(cmd.OutOrStdout(), started)
+				}
+				printWarnings(cmd, started.Warnings)
+				fmt.Fprintf(cmd.OutOrStdout(), "已在后台启动 %s\n查看输出: ai-launcher logs -f %q\n", started.Name, started.Name)
+				return nil
+			}
+
+			opts := req.Options()
+			if req.Prompt != "" {
+				// 配置档可能为第一条提示词指定模板
+				res, err := loadConfig(cmd, proj.Path, nil)
+				if err != nil {
+					return err
+				}
+				if opts.Templates, err = templatesFor(res.Config, proj.Path); err != nil {
+					return err
+				}
+			}
+			config, warnings, err := launch.Prepare(cm, proj, opts)
+			if err != nil {
+				return err
+			}
+			printWarnings(cmd, warnings)
+			return runForeground(cmd, proj.Path, config)
+		},
+	}
+
+	cmd.Flags().StringVar(&tool, "tool", "", "AI 工具 (claude_code, gemini_cli, codex, aider)，默认使用项目配置")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "以 YOLO 模式启动，默认使用项目配置")
+	cmd.Flags().StringVar(&req.Profile, "profile", "", "启动配置档，默认使用项目的默认配置档；none 表示不使用")
+	cmd.Flags().StringVarP(&req.Prompt, "prompt", "p", "", "第一条提示词")
+	cmd.Flags().StringVar(&req.Resume, "resume", "", "恢复会话：会话 ID 或 latest")
+	cmd.Flags().BoolVarP(&detach, "detach", "d", false, "在 Web 启动器中后台运行")
+	return cmd
+}
+
+// runForeground 运行启动钩子后在当前终端运行 AI 工具，结束后按配置运行质量门禁
+func runForeground(cmd *cobra.Command, projectPath string, config terminal.TerminalConfig) error {
+	if err := launch.RunPreLaunch(context.Background(), config); err != nil {
+		return err
+	}
+	c, pendingInput, err := terminal.NewTerminalManager().BuildCommand(config)
+	if err != nil {
+		return err
+	}
+	if pendingInput != "" {
+		// 标准输入连接到当前终端，无法代为输入
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s 不支持通过参数传入提示词，请在会话中输入：\n%s\n\n", config.Type, pendingInput)
+	}
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// Ctrl+C 交给 AI 工具处理，启动器自身不退出
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	err = c.Run()
+	signal.Stop(signals)
+
+	result := launchResult{Name: config.Name, Path: projectPath, SessionID: config.SessionID}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return fmt.Errorf("启动 %s 失败: %v", config.Command[0], err)
+	}
+
+	cfg, err := gates.LoadConfig(projectPath, project.NewConfigManager().ManifestTrusted)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "读取门禁配置失败: %v\n", err)
+	} else if cfg.AutoRun(projectPath) && cfg.RunsOn(gates.TriggerExit) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		report, err := gates.RunAndSave(ctx, projectPath, cfg, gates.TriggerExit, config.SessionID)
+		if err != nil && !errors.Is(err, gates.ErrNotInitialized) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "保存门禁报告失败: %v\n", err)
+		}
+		result.Gates = report
+		if !jsonOutput {
+			fmt.Fprintln(cmd.OutOrStdout(), report.Details())
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(cmd.OutOrStdout(), result)
+	}
+	return nil
+}
+
+// printWarnings 在标准错误输出启动警告，例如项目清单中未经批准的设置
+func printWarnings(cmd *cobra.Command, warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "警告: %s\n", w)
+	}
+}
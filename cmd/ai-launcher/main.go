@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// 全局标志
+var (
+	jsonOutput bool   // 以 JSON 格式输出
+	serverURL  string // 正在运行的 Web 启动器地址
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand 创建根命令并注册所有子命令
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "ai-launcher",
+		Short:        "AI启动器 - 智能多AI工具启动器",
+		SilenceUsage: true,
+	}
+
+	root.PersistentFlags().BoolVar(&jsonOutput, "json", false, "以 JSON 格式输出")
+	root.PersistentFlags().StringVar(&serverURL, "server", "http://localhost:8080", "Web 启动器地址")
+	root.PersistentFlags().StringArrayVar(&configOverrides, "set", nil, "覆盖配置项 path=value，可重复，例如 --set ollama.host=http://gpu:11434")
+
+	root.AddCommand(
+		newStatusCommand(),
+		newInitCommand(),
+		newDetectCommand(),
+		newHistoryCommand(),
+		newSessionsCommand(),
+		newBatchCommand(),
+		newQueueCommand(),
+		newScheduleCommand(),
+		newPipelineCommand(),
+		newSyncCommand(),
+		newWorkflowCommand(),
+		newGatesCommand(),
+		newMemoryCommand(),
+		newProjectCommand(),
+		newLaunchCommand(),
+		newPsCommand(),
+		newStopCommand(),
+		newLogsCommand(),
+		newSendCommand(),
+		newTemplateCommand(),
+		newOptimizeCommand(),
+		newOllamaCommand(),
+		newDoctorCommand(),
+		newTuiCommand(),
+		newConfigCommand(),
+	)
+
+	return root
+}
+
+// printJSON 以缩进 JSON 格式输出
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("序列化输出失败: %v", err)
+	}
+	return nil
+}
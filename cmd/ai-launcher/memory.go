@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/memory"
+)
+
+// newMemoryCommand 创建 memory 命令：读写 .addp/memory 中的项目记忆
+func newMemoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "memory",
+		Short: "记录与检索项目记忆（背景、决策、经验、会话）",
+		Long: `项目记忆保存在 <项目>/.addp/memory/{context,decisions,lessons,sessions}，每条记忆是一个带 front matter 的 Markdown 文件。
+新会话的第一条提示词会自动带上最相关的几条记忆。`,
+	}
+
+	cmd.AddCommand(
+		newMemoryAddCommand(),
+		newMemoryListCommand(),
+		newMemorySearchCommand(),
+		newMemoryShowCommand(),
+	)
+	return cmd
+}
+
+// newMemoryAddCommand 创建 memory add 命令
+func newMemoryAddCommand() *cobra.Command {
+	var (
+		projectPath string
+		kind        string
+		record      memory.Record
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add [text...]",
+		Short: "添加一条记忆；正文为 - 时从标准输入读取",
+		Example: `  ai-launcher memory add --kind decision --title "Use SQLite for jobs" --tags storage,queue "JSON files do not scale"
+  git log -1 --format=%B | ai-launcher memory add --kind session -`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			if record.Kind, err = memory.ParseKind(kind); err != nil {
+				return err
+			}
+
+			record.Body = strings.Join(args, " ")
+			if record.Body == "-" {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("读取标准输入失败: %v", err)
+				}
+				record.Body = string(data)
+			}
+
+			saved, err := memory.NewStore(path).Add(record)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), saved)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已添加记忆 %s\n%s\n", saved.ID, saved.Path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().StringVarP(&kind, "kind", "k", string(memory.KindContext), "记忆类型: context, decision, lesson, session")
+	cmd.Flags().StringVarP(&record.Title, "title", "t", "", "标题（默认取正文第一行）")
+	cmd.Flags().StringSliceVar(&record.Tags, "tags", nil, "标签，逗号分隔")
+	cmd.Flags().StringVar(&record.Tool, "tool", "", "记录记忆的工具")
+	return cmd
+}
+
+// newMemoryListCommand 创建 memory list 命令
+func newMemoryListCommand() *cobra.Command {
+	var (
+		projectPath string
+		kind        string
+		limit       int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "列出记忆，最新的在前",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMemorySearch(cmd, projectPath, memory.Query{Limit: limit}, kind)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().StringVarP(&kind, "kind", "k", "", "只列出指定类型")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "显示数量（0 表示全部）")
+	return cmd
+}
+
+// newMemorySearchCommand 创建 memory search 命令
+func newMemorySearchCommand() *cobra.Command {
+	var (
+		projectPath string
+		kind        string
+		limit       int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query...>",
+		Short: "全文检索记忆，按相关度排序",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMemorySearch(cmd, projectPath, memory.Query{Text: strings.Join(args, " "), Limit: limit}, kind)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().StringVarP(&kind, "kind", "k", "", "只检索指定类型")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "显示数量（0 表示全部）")
+	return cmd
+}
+
+// runMemorySearch 执行 list 与 search 共用的查询并输出结果
+func runMemorySearch(cmd *cobra.Command, projectPath string, query memory.Query, kind string) error {
+	path, err := projectArg([]string{projectPath})
+	if err != nil {
+		return err
+	}
+	if kind != "" {
+		if query.Kind, err = memory.ParseKind(kind); err != nil {
+			return err
+		}
+	}
+
+	results, err := memory.NewStore(path).Search(query)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if results == nil {
+			results = []memory.Result{}
+		}
+		return printJSON(cmd.OutOrStdout(), results)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(results) == 0 {
+		fmt.Fprintln(out, "没有记忆")
+		return nil
+	}
+	for _, r := range results {
+		fmt.Fprintf(out, "%s  %-9s %s", r.Created.Format(time.DateOnly), r.Kind, r.Title)
+		if len(r.Tags) > 0 {
+			fmt.Fprintf(out, "  [%s]", strings.Join(r.Tags, ", "))
+		}
+		fmt.Fprintf(out, "\n    %s\n", r.ID)
+	}
+	return nil
+}
+
+// newMemoryShowCommand 创建 memory show 命令
+func newMemoryShowCommand() *cobra.Command {
+	var projectPath string
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "显示一条记忆",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			r, err := memory.NewStore(path).Get(args[0])
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), r)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s\n类型: %s  时间: %s", r.Title, r.Kind, r.Created.Format(time.DateTime))
+			if len(r.Tags) > 0 {
+				fmt.Fprintf(out, "  标签: %s", strings.Join(r.Tags, ", "))
+			}
+			if r.Tool != "" {
+				fmt.Fprintf(out, "  工具: %s", r.Tool)
+			}
+			fmt.Fprintf(out, "\n文件: %s\n\n%s\n", r.Path, r.Body)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	return cmd
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/ollama"
+)
+
+// newOllamaCommand 创建 ollama 命令：管理用于提示词优化的本地模型
+func newOllamaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ollama",
+		Short: "查看与拉取本地 Ollama 模型",
+	}
+	cmd.PersistentFlags().String("url", "", "Ollama 服务地址，默认取配置 ollama.host")
+
+	cmd.AddCommand(
+		newOllamaModelsCommand(),
+		newOllamaPullCommand(),
+	)
+	return cmd
+}
+
+// ollamaConfig 加载 Ollama 配置；--url 覆盖 ollama.host
+func ollamaConfig(cmd *cobra.Command) (config.OllamaConfig, error) {
+	res, err := loadConfig(cmd, "", map[string]string{"url": "ollama.host"})
+	if err != nil {
+		return config.OllamaConfig{}, err
+	}
+	return res.Config.Ollama, nil
+}
+
+// newOllamaModelsCommand 创建 ollama models 命令
+func newOllamaModelsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "models",
+		Aliases: []string{"ls"},
+		Short:   "列出已安装的模型",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := ollamaConfig(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+
+			client := cfg.NewClient()
+			models, err := client.GetAvailableModels(ctx)
+			if err != nil {
+				return fmt.Errorf("无法获取 Ollama 模型列表 (%s): %v", cfg.Host, err)
+			}
+			if models == nil {
+				models = []ollama.ModelInfo{}
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), models)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(models) == 0 {
+				fmt.Fprintln(out, "没有已安装的模型，使用 ai-launcher ollama pull <model> 拉取")
+				return nil
+			}
+			for _, m := range models {
+				mark := " "
+				if m.Name == client.GetModel() {
+					mark = "*" // 提示词优化默认使用的模型
+				}
+				fmt.Fprintf(out, "%s %-32s %8s\n", mark, m.Name, formatBytes(m.Size))
+			}
+			return nil
+		},
+	}
+}
+
+// newOllamaPullCommand 创建 ollama pull 命令
+func newOllamaPullCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull <model>",
+		Short: "拉取模型并显示下载进度",
+		Long:  "拉取模型并显示下载进度。--json 时每条进度消息输出为一行 JSON。",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := ollamaConfig(cmd)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			// 拉取耗时较长，不使用 ollama.timeout
+			out := cmd.OutOrStdout()
+			last := ""
+			err = ollama.NewOllamaClient(cfg.Host).PullModel(ctx, args[0], func(p ollama.PullProgress) {
+				if jsonOutput {
+					_ = printJSON(out, p)
+					return
+				}
+				line := p.Status
+				if p.Total > 0 {
+					line = fmt.Sprintf("%s %s/%s (%d%%)", p.Status, formatBytes(p.Completed), formatBytes(p.Total), p.Completed*100/p.Total)
+				}
+				if line != last {
+					fmt.Fprintf(out, "\r\033[K%s", line)
+					last = line
+				}
+			})
+			if !jsonOutput && last != "" {
+				fmt.Fprintln(out)
+			}
+			return err
+		},
+	}
+}
+
+// formatBytes 以可读单位显示字节数
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/memory"
+	"ai-launcher/internal/ollama"
+	"ai-launcher/internal/project/detect"
+)
+
+// optimizeResult optimize 命令的 JSON 输出
+type optimizeResult struct {
+	*ollama.OptimizationResult
+	Template string `json:"template,omitempty"`
+	Context  string `json:"context,omitempty"`
+}
+
+// newOptimizeCommand 创建 optimize 命令：用本地 Ollama 模型优化提示词
+func newOptimizeCommand() *cobra.Command {
+	var (
+		projectPath string
+		templateID  string
+		vars        map[string]string
+		timeout     time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "optimize <query...>",
+		Short: "用本地 Ollama 模型优化提示词",
+		Long: `用本地 Ollama 模型改写提示词，使其更清晰、具体。
+指定 --template 时先套用模板；检测到的项目环境与项目中与查询最相关的记忆会作为上下文传给模型，
+检测到的语言与框架作为 language、framework 变量的默认值。`,
+		Example: `  ai-launcher optimize "make the queue faster"
+  ai-launcher optimize --template performance --var language=Go "make the queue faster"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+
+			res, err := loadConfig(cmd, path, map[string]string{
+				"ollama": "ollama.host",
+				"model":  "ollama.default_model",
+			})
+			if err != nil {
+				return err
+			}
+
+			// 检测到的语言与框架作为模板变量的默认值，--var 优先
+			env := detect.Detect(path)
+			templateVars := env.TemplateVars()
+			for k, v := range vars {
+				templateVars[k] = v
+			}
+
+			query := strings.Join(args, " ")
+			if templateID != "" {
+				tm, err := templatesFor(res.Config, path)
+				if err != nil {
+					return err
+				}
+				applied, err := tm.ApplyTemplate(templateID, query, templateVars)
+				if err != nil {
+					return err
+				}
+				query = applied.OptimizedPrompt
+			}
+			queryContext := strings.TrimSpace(env.ContextPrompt() + "\n\n" + memory.QueryContext(path, query, memory.DefaultRelevant))
+			client := res.Config.Ollama.NewClient()
+			if timeout > 0 {
+				client.HTTPClient.Timeout = timeout
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), client.HTTPClient.Timeout)
+			defer cancel()
+
+			result, err := client.OptimizeQuery(ctx, query, queryContext)
+			if err != nil {
+				return fmt.Errorf("优化失败 (%s, %s): %v", res.Config.Ollama.Host, client.GetModel(), err)
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), optimizeResult{OptimizationResult: result, Template: templateID, Context: queryContext})
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), result.OptimizedQuery)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录，用于读取 .ai-launcher.yaml 与检索项目记忆")
+	cmd.Flags().StringVarP(&templateID, "template", "t", "", "先套用的模板 ID")
+	cmd.Flags().StringToStringVar(&vars, "var", nil, "模板变量 key=value，可重复")
+	cmd.Flags().String("ollama", "", "Ollama 服务地址，默认取配置 ollama.host")
+	cmd.Flags().String("model", "", "使用的模型，默认取配置 ollama.default_model")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "请求超时时间，默认取配置 ollama.timeout")
+	return cmd
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/pipeline"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/terminal"
+)
+
+// newPipelineCommand 创建 pipeline 命令：运行项目中定义的多阶段 AI 流水线
+func newPipelineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "运行多阶段 AI 流水线",
+		Long:  "流水线定义在项目的 .ai-launcher/pipelines/*.yaml 中，每个阶段指定工具、提示词模板与输入来源。",
+	}
+
+	cmd.AddCommand(
+		newPipelineListCommand(),
+		newPipelineInitCommand(),
+		newPipelineRunCommand(),
+		newPipelineTrustCommand(),
+	)
+	return cmd
+}
+
+// newPipelineListCommand 创建 pipeline ls 命令
+func newPipelineListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "ls [project-path]",
+		Aliases: []string{"list"},
+		Short:   "列出项目中的流水线",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			pipelines, errs := pipeline.List(path)
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), pipelines)
+			}
+			out := cmd.OutOrStdout()
+			if len(pipelines) == 0 && len(errs) == 0 {
+				fmt.Fprintf(out, "%s 中没有流水线，可使用 ai-launcher pipeline init 创建示例\n", pipeline.Dir(path))
+			}
+			for _, p := range pipelines {
+				var stages []string
+				for _, stage := range p.Stages {
+					stages = append(stages, stage.Name)
+				}
+				fmt.Fprintf(out, "%-20s 迭代≤%d  %s\n", p.Name, p.Iterations(), strings.Join(stages, " → "))
+				if p.Description != "" {
+					fmt.Fprintf(out, "    %s\n", p.Description)
+				}
+			}
+			for _, err := range errs {
+				fmt.Fprintf(cmd.ErrOrStderr(), "⚠️ %v\n", err)
+			}
+			return nil
+		},
+	}
+}
+
+// newPipelineInitCommand 创建 pipeline init 命令：写入示例流水线
+func newPipelineInitCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init [project-path]",
+		Short: "在项目中创建示例流水线",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			file, err := pipeline.WriteExample(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已创建 %s\n", file)
+			return nil
+		},
+	}
+}
+
+// newPipelineRunCommand 创建 pipeline run 命令
+func newPipelineRunCommand() *cobra.Command {
+	var (
+		projectPath   string
+		task          string
+		yolo          bool
+		maxIterations int
+		outDir        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <pipeline> [task]",
+		Short: "运行流水线",
+		Example: `  ai-launcher pipeline run implement-review "add rate limiting to the login handler"
+  ai-launcher pipeline run ./review.yaml --project ~/src/api --max-iterations 1`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(projectPath)
+			if err != nil {
+				return err
+			}
+			p, err := pipeline.Find(path, args[0])
+			if err != nil {
+				return err
+			}
+			if len(args) > 1 {
+				task = strings.Join(args[1:], " ")
+			}
+
+			res, err := loadConfig(cmd, path, nil)
+			if err != nil {
+				return err
+			}
+			templates, err := templatesFor(res.Config, path)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			runner := pipeline.NewRunner(terminal.NewTerminalManager(), templates)
+			result, err := runner.Run(ctx, p, pipeline.RunOptions{
+				Project:       path,
+				Task:          task,
+				ForceYolo:     yolo,
+				MaxIterations: maxIterations,
+				OnStage: func(sr pipeline.StageResult) {
+					if !jsonOutput {
+						writeStageSummary(out, sr)
+					}
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			if outDir == "" {
+				outDir = pipeline.DefaultTranscriptDir(path, p.Name, result.StartedAt)
+			}
+			if err := result.Save(outDir); err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if err := printJSON(out, result); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(out, "\n流水线 %s: %s（%d 轮）\n记录: %s\n", p.Name, result.Status, result.Iterations,
+					filepath.Join(outDir, "transcript.md"))
+			}
+
+			if result.Status == pipeline.StatusFailed || result.Status == pipeline.StatusCancelled {
+				return fmt.Errorf("流水线未完成: %s", result.Error)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().StringVar(&task, "task", "", "任务描述，在提示词中以 {{.Task}} 引用")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "所有阶段以 YOLO 模式运行")
+	cmd.Flags().IntVar(&maxIterations, "max-iterations", 0, "覆盖流水线定义中的最大迭代次数")
+	cmd.Flags().StringVarP(&outDir, "out", "o", "", "记录输出目录（默认 <项目>/.ai-launcher/pipelines/runs/<名称>-<时间>）")
+	return cmd
+}
+
+// newPipelineTrustCommand 创建 pipeline trust 命令：批准流水线中需要信任的设置
+func newPipelineTrustCommand() *cobra.Command {
+	var projectPath string
+
+	cmd := &cobra.Command{
+		Use:   "trust <pipeline>",
+		Short: "批准流水线中需要信任的设置",
+		Long: `流水线定义随仓库提交，其中的 YOLO 阶段、自定义命令与环境变量会在本机不经确认地执行，批准前流水线不会运行。
+批准只针对当前内容，这些设置变化后需要重新批准；ai-launcher project trust --revoke 会同时撤销对流水线的批准。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(projectPath)
+			if err != nil {
+				return err
+			}
+			p, err := pipeline.Find(path, args[0])
+			if err != nil {
+				return err
+			}
+			settings := p.TrustRequired()
+			if err := project.NewConfigManager().TrustPipeline(path, p.Path, settings); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput {
+				return printJSON(out, map[string]interface{}{"pipeline": p.Name, "path": p.Path, "trusted": true, "settings": settings})
+			}
+			if len(settings) == 0 {
+				fmt.Fprintf(out, "流水线 %s 中没有需要信任的设置\n", p.Name)
+				return nil
+			}
+			fmt.Fprintf(out, "已信任流水线 %s 中的以下设置:\n", p.Name)
+			for _, s := range settings {
+				fmt.Fprintf(out, "  %s\n", s)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	return cmd
+}
+
+// writeStageSummary 输出单个阶段的执行结果
+func writeStageSummary(out io.Writer, sr pipeline.StageResult) {
+	icon := "✅"
+	switch {
+	case sr.Error != "":
+		icon = "❌"
+	case sr.Stopped:
+		icon = "⏹"
+	}
+	line := fmt.Sprintf("%s [%d] %-16s exit=%-3d %s", icon, sr.Iteration, sr.Stage, sr.ExitCode,
+		(time.Duration(sr.DurationMs) * time.Millisecond).Round(time.Millisecond))
+	if sr.Error != "" {
+		line += "  " + sr.Error
+	}
+	if sr.Stopped {
+		line += "  满足停止条件"
+	}
+	fmt.Fprintln(out, line)
+}
+
+// projectArg 返回可选的项目目录参数，默认当前目录
+func projectArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return filepath.Abs(args[0])
+	}
+	return os.Getwd()
+}
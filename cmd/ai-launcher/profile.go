@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/project"
+)
+
+// newProjectProfileCommand 创建 project profile 命令：管理项目的启动配置档
+func newProjectProfileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "管理项目的启动配置档",
+		Long: `一个项目可以保存多个启动配置档，例如“Claude YOLO + MCP”“Gemini 只读审查”，
+每个配置档可以指定工具、参数、环境变量、提示词模板、工作子目录与沙箱级别。
+使用 ai-launcher launch <project> --profile <name> 按配置档启动，未指定时使用默认配置档。`,
+	}
+
+	cmd.AddCommand(
+		newProfileListCommand(),
+		newProfileSetCommand(),
+		newProfileRemoveCommand(),
+	)
+	return cmd
+}
+
+// newProfileListCommand 创建 project profile list 命令
+func newProfileListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list <project>",
+		Aliases: []string{"ls"},
+		Short:   "列出项目的配置档",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			p, err := cm.FindProject(args[0])
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), p.Profiles)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(p.Profiles) == 0 {
+				fmt.Fprintf(out, "%s 没有配置档，使用 ai-launcher project profile set %s <name> 添加\n", p.Name, p.Name)
+				return nil
+			}
+			for _, profile := range p.Profiles {
+				fmt.Fprintln(out, profileSummary(*p, profile))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// profileSummary 返回配置档的单行摘要
+func profileSummary(p project.ProjectConfig, profile project.LaunchProfile) string {
+	tool := profile.Tool
+	if tool == "" {
+		tool = p.AIModel
+	}
+	parts := []string{fmt.Sprintf("%-16s %-12s", profile.Name, tool.String())}
+	if profile.Default {
+		parts = append(parts, "默认")
+	}
+	if profile.Yolo != nil {
+		parts = append(parts, fmt.Sprintf("YOLO=%t", *profile.Yolo))
+	}
+	if profile.Sandbox != project.SandboxNone {
+		parts = append(parts, "沙箱="+string(profile.Sandbox))
+	}
+	if profile.WorkDir != "" {
+		parts = append(parts, "目录="+profile.WorkDir)
+	}
+	if profile.Template != "" {
+		parts = append(parts, "模板="+profile.Template)
+	}
+	if len(profile.Args) > 0 {
+		parts = append(parts, "参数="+strings.Join(profile.Args, " "))
+	}
+	for _, k := range sortedKeys(profile.Env) {
+		parts = append(parts, k+"="+profile.Env[k])
+	}
+	return strings.Join(parts, "  ")
+}
+
+// newProfileSetCommand 创建 project profile set 命令
+func newProfileSetCommand() *cobra.Command {
+	var (
+		tool      string
+		yolo      bool
+		extra     []string
+		env       map[string]string
+		tmpl      string
+		workDir   string
+		sandbox   string
+		isDefault bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <project> <name>",
+		Short: "添加或修改配置档，只修改指定的选项",
+		Example: `  ai-launcher project profile set web mcp --yolo --arg=--mcp-config --arg=mcp.json --default
+  ai-launcher project profile set web review --tool gemini_cli --sandbox workspace_write --template review
+  ai-launcher project profile set web docs --dir docs --env LANG=zh_CN.UTF-8`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			p, err := cm.FindProject(args[0])
+			if err != nil {
+				return err
+			}
+			model, err := parseTool(cm, tool)
+			if err != nil {
+				return err
+			}
+
+			flags := cmd.Flags()
+			var saved project.LaunchProfile
+			err = cm.UpdateProject(p.Path, func(p *project.ProjectConfig) error {
+				profile := project.LaunchProfile{Name: args[1]}
+				if existing, err := p.Profile(args[1]); err == nil && existing != nil {
+					profile = *existing
+				}
+				if flags.Changed("tool") {
+					profile.Tool = model
+				}
+				if flags.Changed("yolo") {
+					profile.Yolo = &yolo
+				}
+				if flags.Changed("arg") {
+					profile.Args = extra
+				}
+				if flags.Changed("env") {
+					profile.Env = env
+				}
+				if flags.Changed("template") {
+					profile.Template = tmpl
+				}
+				if flags.Changed("dir") {
+					profile.WorkDir = workDir
+				}
+				if flags.Changed("sandbox") {
+					profile.Sandbox = project.SandboxMode(sandbox)
+				}
+				if flags.Changed("default") {
+					profile.Default = isDefault
+				}
+				p.SetProfile(profile)
+				saved = profile
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), saved)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已保存配置档: %s\n", profileSummary(*p, saved))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tool, "tool", "", "AI 工具 (claude_code, gemini_cli, codex, aider)，为空时使用项目的工具")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "以 YOLO 模式启动；--yolo=false 总是关闭")
+	cmd.Flags().StringArrayVar(&extra, "arg", nil, "追加到工具命令的参数，可重复；以 - 开头时写作 --arg=--flag")
+	cmd.Flags().StringToStringVar(&env, "env", nil, "环境变量 KEY=VALUE，可重复")
+	cmd.Flags().StringVar(&tmpl, "template", "", "应用于第一条提示词的模板 ID")
+	cmd.Flags().StringVar(&workDir, "dir", "", "工作目录，项目内的相对路径")
+	cmd.Flags().StringVar(&sandbox, "sandbox", "", "沙箱级别 (read_only, workspace_write)，为空时不限制")
+	cmd.Flags().BoolVar(&isDefault, "default", false, "设为默认配置档")
+	return cmd
+}
+
+// newProfileRemoveCommand 创建 project profile rm 命令
+func newProfileRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm <project> <name>",
+		Aliases: []string{"remove"},
+		Short:   "删除配置档",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			p, err := cm.FindProject(args[0])
+			if err != nil {
+				return err
+			}
+			err = cm.UpdateProject(p.Path, func(p *project.ProjectConfig) error {
+				return p.RemoveProfile(args[1])
+			})
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), map[string]string{"project": p.Name, "profile": args[1]})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已删除 %s 的配置档 %s\n", p.Name, args[1])
+			return nil
+		},
+	}
+	return cmd
+}
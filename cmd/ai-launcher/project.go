@@ -0,0 +1,419 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/addp"
+	"ai-launcher/internal/launch"
+	"ai-launcher/internal/project"
+)
+
+// newProjectCommand 创建 project 命令：管理 ~/.ai-launcher/projects.json 中的项目
+func newProjectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "管理已保存的项目",
+		Long:  "项目与图形界面、Web 启动器共用 ~/.ai-launcher/projects.json，可以按名称或路径引用。",
+	}
+
+	cmd.AddCommand(
+		newProjectListCommand(),
+		newProjectAddCommand(),
+		newProjectRemoveCommand(),
+		newProjectShowCommand(),
+		newProjectTrustCommand(),
+		newProjectProfileCommand(),
+		newProjectDiscoverCommand(),
+		newProjectRescanCommand(),
+	)
+	return cmd
+}
+
+// loadProjects 加载项目配置
+func loadProjects() (*project.ConfigManager, error) {
+	cm := project.NewConfigManager()
+	if err := cm.LoadProjects(); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// parseTool 解析 --tool 参数，为空时返回空字符串表示使用项目配置
+func parseTool(cm *project.ConfigManager, tool string) (project.AIModelType, error) {
+	if tool == "" {
+		return "", nil
+	}
+	model := project.AIModelType(tool)
+	if !cm.IsValidModel(model) {
+		var names []string
+		for _, m := range cm.GetAvailableModels() {
+			names = append(names, string(m))
+		}
+		return "", fmt.Errorf("未知的 AI 工具 %q (%s)", tool, strings.Join(names, ", "))
+	}
+	return model, nil
+}
+
+// newProjectListCommand 创建 project list 命令
+func newProjectListCommand() *cobra.Command {
+	var (
+		query project.Query
+		tool  string
+		order string
+		all   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "list [search...]",
+		Aliases: []string{"ls"},
+		Short:   "列出项目，置顶的在前，其余按最近使用排序",
+		Long:    "可以按标签、分组、工具与路径筛选，并在名称、路径与备注中搜索；默认不列出归档的项目。",
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			if query.Model, err = parseTool(cm, tool); err != nil {
+				return err
+			}
+			if query.Sort, err = project.ParseSortOrder(order); err != nil {
+				return err
+			}
+			if all {
+				query.Archived = project.ArchiveInclude
+			}
+			query.Text = strings.Join(args, " ")
+			projects := cm.Query(query)
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), projects)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(projects) == 0 {
+				if len(cm.GetProjects()) == 0 {
+					fmt.Fprintln(out, "没有项目，使用 ai-launcher project add <path> 添加")
+				} else {
+					fmt.Fprintln(out, "没有符合条件的项目")
+				}
+				return nil
+			}
+			for _, p := range projects {
+				flags := ""
+				if p.Pinned {
+					flags += " 置顶"
+				}
+				if p.Archived {
+					flags += " 归档"
+				}
+				if p.YoloMode {
+					flags += " YOLO"
+				}
+				if p.Missing {
+					flags += " 路径不存在"
+				}
+				lastUsed := "-"
+				if !p.LastUsed.IsZero() {
+					lastUsed = p.LastUsed.Format("2006-01-02 15:04")
+				}
+				fmt.Fprintf(out, "%-20s %-12s%s  %-16s  %s\n", p.Name, p.AIModel, flags, lastUsed, p.Path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&query.Tags, "tag", nil, "只列出带有这些标签的项目，可重复")
+	cmd.Flags().StringVar(&query.Group, "group", "", "只列出该分组的项目")
+	cmd.Flags().StringVar(&tool, "tool", "", "只列出使用该 AI 工具的项目")
+	cmd.Flags().StringVar(&query.PathGlob, "path", "", "路径模式，不含路径分隔符时只匹配目录名，例如 'api-*'")
+	cmd.Flags().BoolVar(&query.Pinned, "pinned", false, "只列出置顶的项目")
+	cmd.Flags().BoolVar(&all, "all", false, "同时列出归档的项目")
+	cmd.Flags().StringVar(&order, "sort", "", "排序方式 (last_used, name, frequency)")
+	cmd.Flags().IntVarP(&query.Limit, "limit", "n", 0, "最多列出的数量")
+	return cmd
+}
+
+// newProjectAddCommand 创建 project add 命令
+func newProjectAddCommand() *cobra.Command {
+	var (
+		name     string
+		tool     string
+		yolo     bool
+		tags     []string
+		group    string
+		notes    string
+		pinned   bool
+		archived bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add [path]",
+		Short: "添加或更新项目（默认当前目录）",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			if err := cm.ValidateProjectPath(path); err != nil {
+				return err
+			}
+			model, err := parseTool(cm, tool)
+			if err != nil {
+				return err
+			}
+
+			// 已有项目只更新指定的字段；新项目未指定工具时使用项目清单中的工具
+			p := project.ProjectConfig{Name: filepath.Base(path), Path: path}
+			if existing, err := cm.GetProjectByPath(path); err == nil {
+				p = *existing
+			}
+			if name != "" {
+				p.Name = name
+			}
+			if model != "" {
+				p.AIModel = model
+			}
+			if cmd.Flags().Changed("yolo") {
+				p.YoloMode = yolo
+			}
+			if cmd.Flags().Changed("tags") {
+				p.Tags = tags
+			}
+			if cmd.Flags().Changed("group") {
+				p.Group = group
+			}
+			if cmd.Flags().Changed("notes") {
+				p.Notes = notes
+			}
+			if cmd.Flags().Changed("pin") {
+				p.Pinned = pinned
+			}
+			if cmd.Flags().Changed("archive") {
+				p.Archived = archived
+			}
+			if err := cm.AddProject(p); err != nil {
+				return err
+			}
+
+			saved, err := cm.GetProjectByPath(path)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), saved)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已保存项目 %s (%s)\n", saved.Name, saved.Path)
+			if _, warnings, err := cm.ApplyManifest(*saved); err != nil {
+				printWarnings(cmd, []string{err.Error()})
+			} else {
+				printWarnings(cmd, warnings)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "项目名称（默认目录名）")
+	cmd.Flags().StringVar(&tool, "tool", "", "AI 工具 (claude_code, gemini_cli, codex, aider)")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "默认以 YOLO 模式启动")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil, "标签，逗号分隔")
+	cmd.Flags().StringVar(&group, "group", "", "分组名称")
+	cmd.Flags().StringVar(&notes, "notes", "", "备注，可被 project list 搜索")
+	cmd.Flags().BoolVar(&pinned, "pin", false, "置顶，--pin=false 取消")
+	cmd.Flags().BoolVar(&archived, "archive", false, "归档，默认不再列出；--archive=false 恢复")
+	return cmd
+}
+
+// newProjectRemoveCommand 创建 project rm 命令
+func newProjectRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm <name|path>",
+		Aliases: []string{"remove"},
+		Short:   "删除项目（不会删除项目文件）",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			p, err := cm.FindProject(args[0])
+			if err != nil {
+				return err
+			}
+			if err := cm.RemoveProject(p.Path); err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), p)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已删除项目 %s (%s)\n", p.Name, p.Path)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// projectDetails project show 的输出
+type projectDetails struct {
+	project.ProjectConfig
+	ADDP     bool              `json:"addp"`
+	Manifest *project.Manifest `json:"manifest,omitempty"` // 合并后的项目清单
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// newProjectShowCommand 创建 project show 命令
+func newProjectShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <name|path>",
+		Short: "显示项目配置与最近的会话",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			p, err := cm.FindProject(args[0])
+			if err != nil {
+				return err
+			}
+			merged, warnings, err := cm.ApplyManifest(*p)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+			}
+			details := projectDetails{ProjectConfig: merged, ADDP: addp.Initialized(p.Path), Manifest: merged.Manifest, Warnings: warnings}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), details)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "名称: %s\n路径: %s\n工具: %s\nYOLO: %t\nADDP: %t\n", p.Name, p.Path, merged.AIModel.String(), merged.YoloMode, details.ADDP)
+			if m := merged.Manifest; m != nil {
+				fmt.Fprintf(out, "清单: %s\n", m.Path)
+				if len(m.Args) > 0 {
+					fmt.Fprintf(out, "  参数: %s\n", strings.Join(m.Args, " "))
+				}
+				for _, k := range sortedKeys(m.Env) {
+					fmt.Fprintf(out, "  环境变量: %s=%s\n", k, m.Env[k])
+				}
+				if m.Templates != "" {
+					fmt.Fprintf(out, "  模板目录: %s\n", m.TemplatesDir(p.Path))
+				}
+				for _, hook := range m.Hooks.PreLaunch {
+					fmt.Fprintf(out, "  启动钩子: %s\n", hook)
+				}
+			}
+			printWarnings(cmd, warnings)
+			if !p.LastUsed.IsZero() {
+				fmt.Fprintf(out, "最近使用: %s\n", p.LastUsed.Format(time.DateTime))
+			}
+			if len(p.Tags) > 0 {
+				fmt.Fprintf(out, "标签: %s\n", strings.Join(p.Tags, ", "))
+			}
+			if p.Group != "" {
+				fmt.Fprintf(out, "分组: %s\n", p.Group)
+			}
+			if p.Pinned || p.Archived {
+				fmt.Fprintf(out, "置顶: %t  归档: %t\n", p.Pinned, p.Archived)
+			}
+			if p.Missing {
+				fmt.Fprintln(out, "路径不存在，使用 ai-launcher project rescan 重新检查")
+			}
+			if p.UseCount > 0 {
+				fmt.Fprintf(out, "启动次数: %d\n", p.UseCount)
+			}
+			if p.Notes != "" {
+				fmt.Fprintf(out, "备注: %s\n", p.Notes)
+			}
+			if len(p.Profiles) > 0 {
+				fmt.Fprintln(out, "\n配置档:")
+				for _, profile := range p.Profiles {
+					fmt.Fprintf(out, "  %s\n", profileSummary(*p, profile))
+				}
+			}
+			if len(p.Sessions) > 0 {
+				fmt.Fprintln(out, "\n会话:")
+				for _, s := range p.Sessions {
+					fmt.Fprintf(out, "  %s  %-12s %s  %s\n", s.LastUsed.Format("2006-01-02 15:04"), s.AIModel, s.ID, s.Title)
+				}
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// sortedKeys 返回排序后的键
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newProjectTrustCommand 创建 project trust 命令：批准项目清单中需要信任的设置
+func newProjectTrustCommand() *cobra.Command {
+	var revoke bool
+
+	cmd := &cobra.Command{
+		Use:   "trust <name|path>",
+		Short: "批准项目清单 .ai-launcher.yaml 与 .ai-launcher/gates.yaml 中需要信任的设置",
+		Long: `项目清单 .ai-launcher.yaml 随仓库提交，其中启用 YOLO、跳过确认的参数会让 AI 工具不经确认修改文件，启动钩子会在本机执行命令；
+.ai-launcher/gates.yaml 中的门禁命令会在会话结束后自动运行。批准前这些设置不会生效。
+批准只针对当前内容，这些设置变化后需要重新批准。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm, err := loadProjects()
+			if err != nil {
+				return err
+			}
+			proj, err := launch.Resolve(cm, args[0])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if revoke {
+				if err := cm.RevokeTrust(proj.Path); err != nil {
+					return err
+				}
+				if jsonOutput {
+					return printJSON(out, map[string]interface{}{"path": proj.Path, "trusted": false})
+				}
+				fmt.Fprintf(out, "已撤销对 %s 的信任\n", project.ManifestPath(proj.Path))
+				return nil
+			}
+
+			settings, err := cm.TrustManifest(proj.Path)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(out, map[string]interface{}{"path": proj.Path, "trusted": true, "settings": settings})
+			}
+			if len(settings) == 0 {
+				fmt.Fprintf(out, "%s 中没有需要信任的设置\n", proj.Path)
+				return nil
+			}
+			fmt.Fprintf(out, "已信任 %s 中的以下设置:\n", proj.Path)
+			for _, s := range settings {
+				fmt.Fprintf(out, "  %s\n", s)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&revoke, "revoke", false, "撤销批准")
+	return cmd
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/terminal"
+)
+
+// logsPollInterval logs -f 轮询新输出的间隔
+const logsPollInterval = 500 * time.Millisecond
+
+// terminalLogs /api/terminals/logs 的响应
+type terminalLogs struct {
+	Name     string   `json:"name"`
+	Lines    []string `json:"lines"`
+	Next     int      `json:"next"`
+	Exited   bool     `json:"exited"`
+	ExitCode int      `json:"exit_code"`
+}
+
+// newPsCommand 创建 ps 命令：列出 Web 启动器中的会话
+func newPsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ps",
+		Short: "列出 Web 启动器中运行的会话",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := fetchHealthReport(serverURL)
+			if err != nil {
+				return err
+			}
+			terminals := report.Terminals
+			if terminals == nil {
+				terminals = []terminal.TerminalHealth{}
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), terminals)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(terminals) == 0 {
+				fmt.Fprintln(out, "没有会话")
+				return nil
+			}
+			for _, th := range terminals {
+				status := th.Status
+				if th.ExitCode >= 0 {
+					status = fmt.Sprintf("exited(%d)", th.ExitCode)
+				}
+				fmt.Fprintf(out, "%s %-28s %-8s %-12s %s  %s\n", healthIcon(th.State), th.Name, th.Type, status,
+					th.StartedAt.Format("01-02 15:04"), th.WorkingDir)
+			}
+			return nil
+		},
+	}
+}
+
+// newStopCommand 创建 stop 命令
+func newStopCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop <name>",
+		Short: "停止并移除会话",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiPost("/api/terminals/stop", map[string]string{"name": args[0]}, nil); err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), map[string]interface{}{"name": args[0], "stopped": true})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已停止 %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// newLogsCommand 创建 logs 命令
+func newLogsCommand() *cobra.Command {
+	var (
+		follow bool
+		tail   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs <name>",
+		Short: "显示会话输出",
+		Long:  "显示会话最近的输出（启动器为每个会话保留最近 1000 行）。--json 时每批输出为一行 JSON。",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			since := 0
+			for first := true; ; first = false {
+				var logs terminalLogs
+				if err := apiGet(fmt.Sprintf("/api/terminals/logs?name=%s&since=%d", url.QueryEscape(args[0]), since), &logs); err != nil {
+					return err
+				}
+				if first && tail > 0 && len(logs.Lines) > tail {
+					logs.Lines = logs.Lines[len(logs.Lines)-tail:]
+				}
+				since = logs.Next
+
+				if jsonOutput {
+					if len(logs.Lines) > 0 || !follow || logs.Exited {
+						if err := printJSON(out, logs); err != nil {
+							return err
+						}
+					}
+				} else if len(logs.Lines) > 0 {
+					fmt.Fprintln(out, strings.Join(logs.Lines, "\n"))
+				}
+
+				if !follow {
+					return nil
+				}
+				if logs.Exited {
+					if !jsonOutput {
+						fmt.Fprintf(cmd.ErrOrStderr(), "会话已退出，退出码 %d\n", logs.ExitCode)
+					}
+					return nil
+				}
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(logsPollInterval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "持续输出新内容直到会话退出")
+	cmd.Flags().IntVarP(&tail, "tail", "n", 0, "只显示最后 n 行（0 表示全部）")
+	return cmd
+}
+
+// newSendCommand 创建 send 命令
+func newSendCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "send <name> <text...>",
+		Short: "向会话发送一行输入",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text := strings.Join(args[1:], " ")
+			if err := apiPost("/api/terminals/send", map[string]string{"name": args[0], "text": text}, nil); err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), map[string]interface{}{"name": args[0], "sent": true})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已发送到 %s\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+	"ai-launcher/internal/terminal"
+)
+
+// newQueueCommand 创建 queue 命令：管理排队执行的 AI 任务
+func newQueueCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "管理任务队列",
+	}
+
+	cmd.AddCommand(
+		newQueueAddCommand(),
+		newQueueListCommand(),
+		newQueueCancelCommand(),
+		newQueueRunCommand(),
+	)
+	return cmd
+}
+
+// newQueueAddCommand 创建 queue add 命令
+func newQueueAddCommand() *cobra.Command {
+	var (
+		tool    string
+		job     queue.Job
+		yolo    bool
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <project-path> <prompt>",
+		Short: "添加任务",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			job.Project = path
+			job.Prompt = strings.Join(args[1:], " ")
+			job.Tool = project.AIModelType(tool)
+			job.Options.YoloMode = yolo
+			job.Options.TimeoutSeconds = int(timeout.Seconds())
+
+			// 已注册的项目默认使用其模型与 YOLO 设置
+			cm := project.NewConfigManager()
+			if err := cm.LoadProjects(); err == nil {
+				if p, err := cm.GetProjectByPath(path); err == nil {
+					if job.Tool == "" {
+						job.Tool = p.AIModel
+					}
+					if !cmd.Flags().Changed("yolo") {
+						job.Options.YoloMode = p.YoloMode
+					}
+				}
+			}
+
+			added, err := queue.NewQueue(nil).Add(job)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), added)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已添加任务 %s\n", added.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tool, "tool", "", "AI 工具 (claude_code, gemini_cli, codex, aider)，默认使用项目配置")
+	cmd.Flags().IntVarP(&job.Priority, "priority", "p", 0, "优先级，数值越大越先执行")
+	cmd.Flags().IntVar(&job.Options.MaxRetries, "retries", 0, "失败后的重试次数")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "以 YOLO 模式运行")
+	cmd.Flags().DurationVar(&timeout, "timeout", queue.DefaultTimeout, "任务超时时间")
+	return cmd
+}
+
+// newQueueListCommand 创建 queue ls 命令
+func newQueueListCommand() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "列出任务",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := queue.NewQueue(nil).List()
+			if err != nil {
+				return err
+			}
+			if !all {
+				active := jobs[:0]
+				for _, job := range jobs {
+					if !job.Status.IsFinal() {
+						active = append(active, job)
+					}
+				}
+				jobs = active
+			}
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), jobs)
+			}
+			for _, job := range jobs {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s  %-9s p=%-3d %-12s %s\n    %s\n",
+					job.ID, job.Status, job.Priority, job.Tool, job.Project, truncate(job.Prompt, 80))
+				if job.Error != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "    错误: %s (第 %d 次)\n", job.Error, job.Attempts)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "包括已结束的任务")
+	return cmd
+}
+
+// newQueueCancelCommand 创建 queue cancel 命令
+func newQueueCancelCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <job-id>",
+		Short: "取消任务",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := queue.NewQueue(nil).Cancel(args[0])
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), job)
+			}
+			if job.Status == queue.JobCancelled {
+				fmt.Fprintf(cmd.OutOrStdout(), "已取消任务 %s\n", job.ID)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "已请求取消运行中的任务 %s\n", job.ID)
+			}
+			return nil
+		},
+	}
+}
+
+// newQueueRunCommand 创建 queue run 命令：在前台运行工作池直到中断
+func newQueueRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "在前台执行队列中的任务",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := loadConfig(cmd, "", map[string]string{"max-concurrent": "performance.max_concurrent_terminals"})
+			if err != nil {
+				return err
+			}
+			maxConcurrent := res.Config.Performance.MaxConcurrentTerminals
+			q := queue.NewQueue(terminal.NewTerminalManager())
+			q.SetMaxConcurrent(maxConcurrent)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			fmt.Fprintf(cmd.OutOrStdout(), "工作池已启动（最多 %d 个终端），按 Ctrl+C 退出\n", maxConcurrent)
+			return q.Run(ctx)
+		},
+	}
+
+	cmd.Flags().Int("max-concurrent", queue.DefaultMaxConcurrent, "最大同时运行的终端数量，默认取配置 performance.max_concurrent_terminals")
+	return cmd
+}
+
+// truncate 截断过长的文本
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+	"ai-launcher/internal/schedule"
+	"ai-launcher/internal/terminal"
+)
+
+// newScheduleCommand 创建 schedule 命令：管理按 cron 表达式定期执行的任务
+func newScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "管理定时任务",
+	}
+
+	cmd.AddCommand(
+		newScheduleAddCommand(),
+		newScheduleListCommand(),
+		newScheduleRemoveCommand(),
+		newSchedulePauseCommand(true),
+		newSchedulePauseCommand(false),
+		newScheduleTriggerCommand(),
+		newScheduleHistoryCommand(),
+		newScheduleRunCommand(),
+	)
+	return cmd
+}
+
+// newScheduler 创建只用于管理计划的计划器；触发的任务写入共享任务队列
+func newScheduler(cmd *cobra.Command) (*schedule.Scheduler, error) {
+	templates, err := loadTemplates(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.NewScheduler(queue.NewQueue(nil), templates), nil
+}
+
+// newScheduleAddCommand 创建 schedule add 命令
+func newScheduleAddCommand() *cobra.Command {
+	var (
+		tool    string
+		missed  string
+		sc      schedule.Schedule
+		yolo    bool
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <project-path> <cron> [prompt]",
+		Short: "添加定时任务",
+		Example: `  ai-launcher schedule add ~/src/api "0 7 * * 1-5" --tool gemini_cli --template debug "audit dependencies"
+  ai-launcher schedule add . @daily "summarize yesterday's commits"`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			sc.Project = path
+			sc.Cron = args[1]
+			sc.Prompt = strings.Join(args[2:], " ")
+			sc.Tool = project.AIModelType(tool)
+			sc.Missed = schedule.MissedPolicy(missed)
+			sc.Options.YoloMode = yolo
+			sc.Options.TimeoutSeconds = int(timeout.Seconds())
+
+			// 已注册的项目默认使用其模型与 YOLO 设置
+			cm := project.NewConfigManager()
+			if err := cm.LoadProjects(); err == nil {
+				if p, err := cm.GetProjectByPath(path); err == nil {
+					if sc.Tool == "" {
+						sc.Tool = p.AIModel
+					}
+					if !cmd.Flags().Changed("yolo") {
+						sc.Options.YoloMode = p.YoloMode
+					}
+				}
+			}
+
+			scheduler, err := newScheduler(cmd)
+			if err != nil {
+				return err
+			}
+			added, err := scheduler.Add(sc)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), added)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已添加定时任务 %s，下次运行: %s\n", added.ID, formatRunTime(added.NextRunAt))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sc.Name, "name", "", "任务名称")
+	cmd.Flags().StringVar(&tool, "tool", "", "AI 工具 (claude_code, gemini_cli, codex, aider)，默认使用项目配置")
+	cmd.Flags().StringVar(&sc.Template, "template", "", "使用的查询模板 ID，提示词作为模板输入")
+	cmd.Flags().StringVar(&sc.Timezone, "timezone", "", "时区（如 Asia/Shanghai），默认使用本地时区")
+	cmd.Flags().StringVar(&missed, "missed", string(schedule.MissedRunOnce), "错过运行时的策略 (run_once, skip, run_all)")
+	cmd.Flags().IntVarP(&sc.Priority, "priority", "p", 0, "任务优先级")
+	cmd.Flags().IntVar(&sc.Options.MaxRetries, "retries", 0, "失败后的重试次数")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "以 YOLO 模式运行")
+	cmd.Flags().BoolVar(&sc.Paused, "paused", false, "添加后先暂停")
+	cmd.Flags().DurationVar(&timeout, "timeout", queue.DefaultTimeout, "单次运行超时时间")
+	return cmd
+}
+
+// newScheduleListCommand 创建 schedule ls 命令
+func newScheduleListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "列出定时任务",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheduler, err := newScheduler(cmd)
+			if err != nil {
+				return err
+			}
+			schedules, err := scheduler.List()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), schedules)
+			}
+			for _, sc := range schedules {
+				state := "下次: " + formatRunTime(sc.NextRunAt)
+				if sc.Paused {
+					state = "已暂停"
+				}
+				name := sc.Name
+				if name == "" {
+					name = truncate(sc.Prompt, 40)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s  %-16s %-12s %s  %s\n    %s\n",
+					sc.ID, sc.Cron, sc.Tool, state, name, sc.Project)
+			}
+			return nil
+		},
+	}
+}
+
+// newScheduleRemoveCommand 创建 schedule rm 命令
+func newScheduleRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <schedule-id>",
+		Aliases: []string{"remove"},
+		Short:   "删除定时任务",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheduler, err := newScheduler(cmd)
+			if err != nil {
+				return err
+			}
+			removed, err := scheduler.Remove(args[0])
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), removed)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已删除定时任务 %s\n", removed.ID)
+			return nil
+		},
+	}
+}
+
+// newSchedulePauseCommand 创建 schedule pause / resume 命令
+func newSchedulePauseCommand(pause bool) *cobra.Command {
+	use, short, done := "resume <schedule-id>", "恢复定时任务", "已恢复"
+	if pause {
+		use, short, done = "pause <schedule-id>", "暂停定时任务", "已暂停"
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheduler, err := newScheduler(cmd)
+			if err != nil {
+				return err
+			}
+			sc, err := scheduler.SetPaused(args[0], pause)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), sc)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s定时任务 %s\n", done, sc.ID)
+			return nil
+		},
+	}
+}
+
+// newScheduleTriggerCommand 创建 schedule trigger 命令：立即投递一次
+func newScheduleTriggerCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trigger <schedule-id>",
+		Short: "立即运行一次定时任务",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheduler, err := newScheduler(cmd)
+			if err != nil {
+				return err
+			}
+			run, err := scheduler.Trigger(args[0])
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), run)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已加入任务队列: %s\n", run.JobID)
+			return nil
+		},
+	}
+}
+
+// scheduleRunView 运行记录及其任务的执行结果
+type scheduleRunView struct {
+	schedule.Run
+	Job *queue.Job `json:"job,omitempty"`
+}
+
+// newScheduleHistoryCommand 创建 schedule history 命令
+func newScheduleHistoryCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history [schedule-id]",
+		Short: "查看定时任务运行记录",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := ""
+			if len(args) > 0 {
+				id = args[0]
+			}
+			scheduler, err := newScheduler(cmd)
+			if err != nil {
+				return err
+			}
+			runs, err := scheduler.History(id, limit)
+			if err != nil {
+				return err
+			}
+
+			// 关联任务队列中的执行状态与输出
+			q := queue.NewQueue(nil)
+			views := make([]scheduleRunView, 0, len(runs))
+			for _, run := range runs {
+				view := scheduleRunView{Run: run}
+				if run.JobID != "" {
+					view.Job, _ = q.Get(run.JobID)
+				}
+				views = append(views, view)
+			}
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), views)
+			}
+			for _, view := range views {
+				status := string(view.Status)
+				if view.Job != nil {
+					status = fmt.Sprintf("%s -> %s", view.JobID, view.Job.Status)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s  %s  计划 %s  %s\n",
+					view.TriggeredAt.Local().Format("2006-01-02 15:04"), view.ScheduleID,
+					view.ScheduledFor.Local().Format("01-02 15:04"), status)
+				if view.Missed > 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "    错过 %d 次\n", view.Missed)
+				}
+				if view.Error != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "    错误: %s\n", view.Error)
+				}
+				if view.Job != nil && view.Job.LogPath != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "    日志: %s\n", view.Job.LogPath)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "显示的记录数量，0 表示全部")
+	return cmd
+}
+
+// newScheduleRunCommand 创建 schedule run 命令：在前台运行计划器与任务队列工作池
+func newScheduleRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "在前台运行定时任务",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := loadConfig(cmd, "", map[string]string{"max-concurrent": "performance.max_concurrent_terminals"})
+			if err != nil {
+				return err
+			}
+			maxConcurrent := res.Config.Performance.MaxConcurrentTerminals
+			q := queue.NewQueue(terminal.NewTerminalManager())
+			q.SetMaxConcurrent(maxConcurrent)
+			templates, err := templatesFor(res.Config, "")
+			if err != nil {
+				return err
+			}
+			s := schedule.NewScheduler(q, templates)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			go s.Run(ctx)
+			fmt.Fprintf(cmd.OutOrStdout(), "定时任务已启动（最多 %d 个终端），按 Ctrl+C 退出\n", maxConcurrent)
+			return q.Run(ctx)
+		},
+	}
+
+	cmd.Flags().Int("max-concurrent", queue.DefaultMaxConcurrent, "最大同时运行的终端数量，默认取配置 performance.max_concurrent_terminals")
+	return cmd
+}
+
+// formatRunTime 格式化下一次运行时间
+func formatRunTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/project"
+)
+
+// newSessionsCommand 创建 sessions 命令：列出项目中可恢复的 AI 工具会话
+func newSessionsCommand() *cobra.Command {
+	var model string
+
+	cmd := &cobra.Command{
+		Use:   "sessions <project-path>",
+		Short: "列出项目可恢复的会话",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+
+			cm := project.NewConfigManager()
+			if err := cm.LoadProjects(); err != nil {
+				return err
+			}
+			sessions, err := cm.GetSessions(path, project.AIModelType(model))
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), sessions)
+			}
+			for _, s := range sessions {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s  %-12s %s  %s\n",
+					s.LastUsed.Format("2006-01-02 15:04"), s.AIModel, s.ID, s.Title)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&model, "model", "", "按模型过滤 (claude_code, gemini_cli, codex, ...)")
+	return cmd
+}
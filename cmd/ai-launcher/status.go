@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/terminal"
+)
+
+// newStatusCommand 创建 status 命令：显示终端管理器健康报告
+func newStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "显示终端管理器与各终端的健康状态",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := fetchHealthReport(serverURL)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), report)
+			}
+			writeHealthReport(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+}
+
+// fetchHealthReport 从正在运行的启动器获取健康报告
+func fetchHealthReport(baseURL string) (*terminal.HealthReport, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(baseURL + "/api/health")
+	if err != nil {
+		return nil, fmt.Errorf("无法连接到启动器 %s: %v", baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	// 不健康时服务端返回 503，但报告内容仍然有效
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("启动器返回状态 %d", resp.StatusCode)
+	}
+
+	var report terminal.HealthReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("解析健康报告失败: %v", err)
+	}
+	return &report, nil
+}
+
+// writeHealthReport 以文本格式输出健康报告
+func writeHealthReport(w io.Writer, report *terminal.HealthReport) {
+	fmt.Fprintf(w, "状态: %s (%s)\n", report.Summary(), report.CheckedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "goroutines: %d, 监控: %d\n\n", report.Goroutines, report.Workers)
+
+	fmt.Fprintln(w, "管理器:")
+	for _, check := range report.Checks {
+		fmt.Fprintf(w, "  %s %-16s %s\n", healthIcon(check.State), check.Name, check.Message)
+	}
+
+	if len(report.Terminals) == 0 {
+		fmt.Fprintln(w, "\n没有终端")
+		return
+	}
+
+	for _, th := range report.Terminals {
+		fmt.Fprintf(w, "\n%s %s [%s] %s\n", healthIcon(th.State), th.Name, th.Type, th.Status)
+		for _, check := range th.Checks {
+			fmt.Fprintf(w, "  %s %-16s %s\n", healthIcon(check.State), check.Name, check.Message)
+		}
+	}
+}
+
+// healthIcon 返回健康状态对应的图标
+func healthIcon(state terminal.HealthState) string {
+	switch state {
+	case terminal.HealthOK:
+		return "✓"
+	case terminal.HealthDegraded:
+		return "!"
+	default:
+		return "✗"
+	}
+}
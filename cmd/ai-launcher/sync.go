@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	addpsync "ai-launcher/internal/sync"
+)
+
+// newSyncCommand 创建 sync 命令：查看与维护 .addp/sync 中的跨工具状态
+func newSyncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "查看跨工具同步状态与交接摘要",
+	}
+
+	cmd.AddCommand(
+		newSyncStatusCommand(),
+		newSyncHandoffCommand(),
+		newSyncSaveCommand(),
+	)
+	return cmd
+}
+
+// newSyncStatusCommand 创建 sync status 命令
+func newSyncStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [project-path]",
+		Short: "显示各工具的同步状态",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			status, err := addpsync.NewManager(path).Status()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), status)
+			}
+
+			out := cmd.OutOrStdout()
+			if !status.SyncEnabled {
+				fmt.Fprintf(out, "%s 尚未初始化 ADDP 结构（缺少 .addp 目录），同步未启用\n", path)
+				return nil
+			}
+			tools := make([]string, 0, len(status.ToolStatus))
+			for tool := range status.ToolStatus {
+				tools = append(tools, tool)
+			}
+			sort.Strings(tools)
+			for _, tool := range tools {
+				fmt.Fprintf(out, "%-10s %-11s %s\n", tool, status.ToolStatus[tool], status.LastSyncTimes[tool])
+			}
+			if h := status.LastHandoff; h != nil {
+				fmt.Fprintf(out, "\n最近交接: %s (%s)\n", h.FromTool, h.CreatedAt)
+			}
+			return nil
+		},
+	}
+}
+
+// newSyncHandoffCommand 创建 sync handoff 命令：显示将注入下一个工具的上下文
+func newSyncHandoffCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "handoff [project-path]",
+		Short: "显示最近一次的交接摘要",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			h, err := addpsync.NewManager(path).LatestHandoff()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), h)
+			}
+			if h == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), "暂无交接摘要")
+				return nil
+			}
+			if addpsync.PendingHandoff(path) == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), "（摘要已过期或同步未启用，不会注入新会话）")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), h.ContextPrompt())
+			return nil
+		},
+	}
+}
+
+// newSyncSaveCommand 创建 sync save 命令：与 Python sync_state(save) 相同地保存工具状态
+func newSyncSaveCommand() *cobra.Command {
+	var (
+		projectPath string
+		summary     string
+		task        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save <tool> [state-json]",
+		Short: "保存工具状态或手动记录交接摘要",
+		Example: `  ai-launcher sync save claude
+  ai-launcher sync save gemini --task "migrate auth" --summary "schema done, handlers pending"`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			m := addpsync.NewManager(path)
+			tool := args[0]
+
+			if summary != "" || task != "" {
+				h := &addpsync.Handoff{FromTool: tool, Task: task, Summary: summary}
+				if err := m.RecordHandoff(h); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "已记录 %s 的交接摘要\n", tool)
+				return nil
+			}
+
+			var state map[string]interface{}
+			if len(args) > 1 {
+				// 与 Python 实现一致：非 JSON 内容按原始文本保存
+				if err := json.Unmarshal([]byte(args[1]), &state); err != nil {
+					state = map[string]interface{}{"raw_data": strings.TrimSpace(args[1])}
+				}
+			}
+			if err := m.SaveState(tool, state); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "工具 %s 状态保存成功\n", tool)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().StringVar(&summary, "summary", "", "交接摘要")
+	cmd.Flags().StringVar(&task, "task", "", "当前任务")
+	return cmd
+}
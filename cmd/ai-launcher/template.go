@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/template"
+)
+
+// newTemplateCommand 创建 template 命令：查看与应用提示词模板
+func newTemplateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "查看与应用提示词模板",
+	}
+
+	cmd.AddCommand(
+		newTemplateListCommand(),
+		newTemplateShowCommand(),
+		newTemplateApplyCommand(),
+	)
+	return cmd
+}
+
+// loadTemplates 创建包含内置模板与配置中自定义模板目录的模板管理器
+func loadTemplates(cmd *cobra.Command) (*template.TemplateManager, error) {
+	res, err := loadConfig(cmd, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return templatesFor(res.Config, "")
+}
+
+// templatesFor 按配置加载自定义模板；projectPath 非空时同时加载项目清单中的团队模板目录
+func templatesFor(cfg *config.Config, projectPath string) (*template.TemplateManager, error) {
+	dirs := []string{cfg.Templates.Dir()}
+	if projectPath != "" {
+		m, err := project.LoadManifest(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, m.TemplatesDir(projectPath))
+	}
+
+	tm := template.NewTemplateManager()
+	if err := tm.LoadDir(dirs...); err != nil {
+		return nil, fmt.Errorf("加载自定义模板失败: %v", err)
+	}
+	return tm, nil
+}
+
+// newTemplateListCommand 创建 template list 命令
+func newTemplateListCommand() *cobra.Command {
+	var category string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "列出模板",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tm, err := loadTemplates(cmd)
+			if err != nil {
+				return err
+			}
+			templates := tm.GetAvailableTemplates()
+			if category != "" {
+				templates = tm.GetTemplatesByCategory(category)
+			}
+			sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), templates)
+			}
+			for _, t := range templates {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-15s %-12s %s - %s\n", t.ID, t.Category, t.Name, t.Description)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&category, "category", "", "按分类过滤 (development, maintenance, analysis)")
+	return cmd
+}
+
+// newTemplateShowCommand 创建 template show 命令
+func newTemplateShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "显示模板内容与变量",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tm, err := loadTemplates(cmd)
+			if err != nil {
+				return err
+			}
+			t, ok := tm.GetTemplate(args[0])
+			if !ok {
+				return fmt.Errorf("模板不存在: %s", args[0])
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), t)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n%s\n分类: %s\n变量: %s\n\n%s\n",
+				t.Name, t.ID, t.Description, t.Category, strings.Join(t.Variables, ", "), t.Prompt)
+			return nil
+		},
+	}
+}
+
+// newTemplateApplyCommand 创建 template apply 命令
+func newTemplateApplyCommand() *cobra.Command {
+	var vars map[string]string
+
+	cmd := &cobra.Command{
+		Use:     "apply <id> <query...>",
+		Short:   "用模板生成提示词",
+		Example: `  ai-launcher template apply coding "add retry to the HTTP client" --var language=Go --var complexity=medium`,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tm, err := loadTemplates(cmd)
+			if err != nil {
+				return err
+			}
+			result, err := tm.ApplyTemplate(args[0], strings.Join(args[1:], " "), vars)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), result.OptimizedPrompt)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringToStringVar(&vars, "var", nil, "模板变量 key=value，可重复")
+	return cmd
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/tui"
+)
+
+// newTuiCommand 创建 tui 命令：在终端中以键盘操作启动和管理 AI 会话
+func newTuiCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "键盘驱动的终端界面（适用于 SSH 等无法运行图形界面的环境）",
+		Long: `在终端中浏览项目、选择 AI 工具启动会话、查看运行中的会话并附加到会话收发输入。
+快捷键与配色读取配置的 keybindings 与 global.theme（可用 --set 覆盖），参见 config.example.yaml。
+退出时停止由 TUI 启动的所有会话；日志写入 ~/.ai-launcher/logs/tui.log。`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := configOptions(cmd, "", nil)
+			if err != nil {
+				return err
+			}
+
+			// 全屏界面下日志不能写到终端
+			logDir := filepath.Join(config.Dir(), "logs")
+			if err := os.MkdirAll(logDir, 0755); err == nil {
+				if f, err := tea.LogToFile(filepath.Join(logDir, "tui.log"), "tui "); err == nil {
+					defer f.Close()
+				}
+			}
+			return tui.Run(opts)
+		},
+	}
+}
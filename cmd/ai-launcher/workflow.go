@@ -0,0 +1,299 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"ai-launcher/internal/workflow"
+)
+
+// newWorkflowCommand 创建 workflow 命令：管理项目的 ADDP 四阶段工作流
+func newWorkflowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workflow",
+		Short: "管理 ADDP 工作流阶段",
+	}
+
+	cmd.AddCommand(
+		newWorkflowStatusCommand(),
+		newWorkflowStartCommand(),
+		newWorkflowConfirmCommand(),
+		newWorkflowAdvanceCommand(),
+		newWorkflowReturnCommand(),
+		newWorkflowPromptCommand(),
+	)
+	return cmd
+}
+
+// newWorkflowStatusCommand 创建 workflow status 命令
+func newWorkflowStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [project-path]",
+		Short: "显示当前阶段与门禁检查",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			m := workflow.NewManager(path)
+			status, err := m.Load()
+			if err != nil {
+				return err
+			}
+			if status == nil {
+				if jsonOutput {
+					return printJSON(cmd.OutOrStdout(), map[string]string{"status": "not_initialized"})
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s 尚未开始 ADDP 工作流，运行 ai-launcher workflow start 开始\n", path)
+				return nil
+			}
+
+			gate, err := m.CheckGate()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), map[string]interface{}{"workflow": status, "gate": gate})
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "第 %d 轮，当前阶段: %s (%s)\n\n", status.Iteration, status.Current().Label(), status.Current())
+			writeGate(out, gate)
+			return nil
+		},
+	}
+}
+
+// newWorkflowStartCommand 创建 workflow start 命令
+func newWorkflowStartCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "start [project-path]",
+		Short: "从分析阶段开始工作流",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			status, err := workflow.NewManager(path).Start()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), status)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "工作流当前阶段: %s\n", status.Current().Label())
+			return nil
+		},
+	}
+}
+
+// newWorkflowConfirmCommand 创建 workflow confirm 命令：确认当前阶段的质量检查项
+func newWorkflowConfirmCommand() *cobra.Command {
+	var (
+		projectPath string
+		all         bool
+		undo        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "confirm [check|number...]",
+		Short: "确认当前阶段的质量检查项",
+		Example: `  ai-launcher workflow confirm 1 2
+  ai-launcher workflow confirm --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			m := workflow.NewManager(path)
+			status, err := m.Load()
+			if err != nil {
+				return err
+			}
+			if status == nil {
+				return fmt.Errorf("workflow not started in %s", path)
+			}
+
+			spec, _ := workflow.SpecFor(status.Current())
+			checks := args
+			if all {
+				checks = spec.QualityChecks
+			}
+			if len(checks) == 0 {
+				return fmt.Errorf("specify quality checks or --all")
+			}
+			// 数字表示 workflow status 中列出的序号
+			for i, check := range checks {
+				if n, err := strconv.Atoi(check); err == nil && n >= 1 && n <= len(spec.QualityChecks) {
+					checks[i] = spec.QualityChecks[n-1]
+				}
+			}
+
+			if undo {
+				_, err = m.Unconfirm(checks...)
+			} else {
+				_, err = m.Confirm(checks...)
+			}
+			if err != nil {
+				return err
+			}
+			gate, err := m.CheckGate()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), gate)
+			}
+			writeGate(cmd.OutOrStdout(), gate)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().BoolVar(&all, "all", false, "确认所有质量检查项")
+	cmd.Flags().BoolVar(&undo, "undo", false, "取消确认")
+	return cmd
+}
+
+// newWorkflowAdvanceCommand 创建 workflow advance 命令
+func newWorkflowAdvanceCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "advance [project-path]",
+		Short: "门禁通过后进入下一阶段",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			status, err := workflow.NewManager(path).Advance(force)
+			var gateErr *workflow.GateError
+			if errors.As(err, &gateErr) && !jsonOutput {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s阶段门禁未通过（可使用 --force 强制推进）:\n\n", gateErr.Gate.Phase.Label())
+				writeGate(cmd.OutOrStdout(), gateErr.Gate)
+			}
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), status)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已进入第 %d 轮 %s阶段\n", status.Iteration, status.Current().Label())
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "跳过门禁强制推进")
+	return cmd
+}
+
+// newWorkflowReturnCommand 创建 workflow return 命令
+func newWorkflowReturnCommand() *cobra.Command {
+	var (
+		projectPath string
+		note        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "return <phase>",
+		Short: "退回到较早的阶段 (analysis, design, development)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg([]string{projectPath})
+			if err != nil {
+				return err
+			}
+			phase, err := workflow.ParsePhase(args[0])
+			if err != nil {
+				return err
+			}
+			status, err := workflow.NewManager(path).Return(phase, note)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), status)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "已退回到%s阶段\n", status.Current().Label())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "项目目录")
+	cmd.Flags().StringVar(&note, "note", "", "退回原因")
+	return cmd
+}
+
+// newWorkflowPromptCommand 创建 workflow prompt 命令：显示启动工具时注入的阶段提示词
+func newWorkflowPromptCommand() *cobra.Command {
+	var phaseName string
+
+	cmd := &cobra.Command{
+		Use:   "prompt [project-path]",
+		Short: "显示当前阶段的提示词",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := projectArg(args)
+			if err != nil {
+				return err
+			}
+			m := workflow.NewManager(path)
+
+			var prompt string
+			if phaseName != "" {
+				phase, err := workflow.ParsePhase(phaseName)
+				if err != nil {
+					return err
+				}
+				prompt, err = m.RenderPrompt(phase, 1)
+				if err != nil {
+					return err
+				}
+			} else if prompt, err = m.Prompt(); err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(cmd.OutOrStdout(), map[string]string{"prompt": prompt})
+			}
+			if prompt == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), "工作流尚未开始，可使用 --phase 预览指定阶段的提示词")
+				return nil
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), prompt)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&phaseName, "phase", "", "预览指定阶段的提示词")
+	return cmd
+}
+
+// writeGate 输出门禁检查结果，质量检查项带序号供 workflow confirm 使用
+func writeGate(out io.Writer, gate *workflow.GateResult) {
+	for i, c := range gate.Checks {
+		mark := "[ ]"
+		if c.Passed {
+			mark = "[x]"
+		}
+		label := "  "
+		if i > 0 {
+			label = strconv.Itoa(i) + "."
+		}
+		fmt.Fprintf(out, "%s %s %s", mark, label, c.Name)
+		if c.Detail != "" {
+			fmt.Fprintf(out, " (%s)", c.Detail)
+		}
+		fmt.Fprintln(out)
+	}
+	if gate.Passed {
+		fmt.Fprintln(out, "\n门禁已通过，可运行 ai-launcher workflow advance")
+	}
+}
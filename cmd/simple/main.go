@@ -1,30 +1,52 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
-)
 
-// 项目配置
-type ProjectConfig struct {
-	Name     string    `json:"name"`
-	Path     string    `json:"path"`
-	AIModel  string    `json:"ai_model"`
-	YoloMode bool      `json:"yolo_mode"`
-	LastUsed time.Time `json:"last_used"`
-}
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/history"
+	"ai-launcher/internal/memory"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+	"ai-launcher/internal/schedule"
+	addpsync "ai-launcher/internal/sync"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+	"ai-launcher/internal/workflow"
+)
 
 // AI启动器
 type AILauncher struct {
 	configDir string
-	projects  []ProjectConfig
+	settings  *config.Config
+	templates *template.TemplateManager
+	mu        sync.Mutex             // 保护 projects 与 reload
+	projects  *project.ConfigManager // 与命令行、图形界面共用 projects.json
+	reload    reloadStatus
+	terminals *terminal.TerminalManager
+	history   *history.HistoryStore
+	queue     *queue.Queue
+	scheduler *schedule.Scheduler
+
+	// 后台检查项目路径，间隔取 discovery.rescan_interval，由 mu 保护
+	stopRescan     context.CancelFunc
+	rescanInterval time.Duration
 }
 
 // 创建新的启动器
@@ -33,90 +55,98 @@ func NewAILauncher() *AILauncher {
 	configDir := filepath.Join(homeDir, ".ai-launcher")
 	os.MkdirAll(configDir, 0755)
 
+	// 配置无效时使用默认配置启动
+	settings := config.Default()
+	if res, err := config.Load(config.Options{}); err != nil {
+		log.Printf("配置无效，使用默认配置:\n%v", err)
+	} else {
+		settings = res.Config
+	}
+
+	terminals := terminal.NewTerminalManager()
+	taskQueue := queue.NewQueue(terminals)
+	taskQueue.SetMaxConcurrent(settings.Performance.MaxConcurrentTerminals)
+	templates := template.NewTemplateManager()
+	if err := templates.LoadDir(settings.Templates.Dir()); err != nil {
+		log.Printf("加载自定义模板失败: %v", err)
+	}
 	launcher := &AILauncher{
 		configDir: configDir,
-		projects:  []ProjectConfig{},
+		settings:  settings,
+		templates: templates,
+		projects:  project.NewConfigManager(),
+		terminals: terminals,
+		history:   history.NewHistoryStore(),
+		queue:     taskQueue,
+		scheduler: schedule.NewScheduler(taskQueue, templates),
 	}
-	launcher.loadProjects()
-	return launcher
-}
-
-// 加载项目配置
-func (a *AILauncher) loadProjects() {
-	configFile := filepath.Join(a.configDir, "projects.json")
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return // 文件不存在是正常的
+	if err := launcher.loadProjects(); err != nil {
+		log.Printf("加载项目配置失败: %v", err)
 	}
-	json.Unmarshal(data, &a.projects)
-}
-
-// 保存项目配置
-func (a *AILauncher) saveProjects() error {
-	configFile := filepath.Join(a.configDir, "projects.json")
-	data, _ := json.MarshalIndent(a.projects, "", "  ")
-	return os.WriteFile(configFile, data, 0644)
+	return launcher
 }
 
-// 添加项目
-func (a *AILauncher) addProject(config ProjectConfig) {
-	config.LastUsed = time.Now()
-
-	// 查找是否已存在
-	for i, p := range a.projects {
-		if p.Path == config.Path {
-			a.projects[i] = config
-			a.saveProjects()
-			return
-		}
+// 加载项目配置；旧格式的文件先备份再迁移，文件无效时保留当前的项目列表
+func (a *AILauncher) loadProjects() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.projects.LoadProjects(); err != nil {
+		return err
 	}
-
-	// 添加新项目
-	a.projects = append([]ProjectConfig{config}, a.projects...)
-	if len(a.projects) > 10 {
-		a.projects = a.projects[:10] // 只保留最近10个
+	if backup := a.projects.MigrationBackup(); backup != "" {
+		log.Printf("已迁移 projects.json 到 schema_version %d，原文件备份为 %s", project.SchemaVersion, backup)
 	}
-	a.saveProjects()
+	return nil
 }
 
-// 获取AI命令
-func getAICommand(model string, yoloMode bool) []string {
-	switch model {
-	case "claude_code":
-		if yoloMode {
-			return []string{"claude", "--dangerously-skip-permissions"}
-		}
-		return []string{"claude"}
-	case "gemini_cli":
-		if yoloMode {
-			return []string{"gemini", "--yolo"}
-		}
-		return []string{"gemini"}
-	case "codex":
-		if yoloMode {
-			return []string{"codex", "--dangerously-bypass-approvals-and-sandbox"}
-		}
-		return []string{"codex"}
-	default:
-		return []string{"echo", "Unknown AI model"}
+// 添加或更新项目：只更新页面表单中的字段，保留偏好、标签、分组、备注与会话记录
+func (a *AILauncher) addProject(config project.ProjectConfig) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if existing, err := a.projects.GetProjectByPath(config.Path); err == nil {
+		existing.Name = config.Name
+		existing.AIModel = config.AIModel
+		existing.YoloMode = config.YoloMode
+		config = *existing
 	}
+	return a.projects.AddProject(config)
 }
 
 // 启动AI工具
-func (a *AILauncher) launchAI(config ProjectConfig) error {
+func (a *AILauncher) launchAI(config project.ProjectConfig) error {
 	// 验证路径
 	if _, err := os.Stat(config.Path); os.IsNotExist(err) {
 		return fmt.Errorf("项目路径不存在: %s", config.Path)
 	}
+	if !a.projects.IsValidModel(config.AIModel) {
+		return fmt.Errorf("无效的AI模型: %s", config.AIModel)
+	}
 
 	// 获取命令
-	cmdArgs := getAICommand(config.AIModel, config.YoloMode)
-	if len(cmdArgs) == 0 {
-		return fmt.Errorf("无效的AI模型: %s", config.AIModel)
+	cmdArgs := config.AIModel.GetCommand(config.YoloMode)
+
+	// 保存配置并记录使用时间与次数
+	if err := a.addProject(config); err != nil {
+		return err
+	}
+	if err := a.projects.UpdateProjectUsage(config.Path); err != nil {
+		return err
 	}
 
-	// 保存配置
-	a.addProject(config)
+	// ADDP 项目中将当前阶段的提示词、上一个工具的交接摘要与项目记忆作为初始提示词（Windows 批处理无法安全传递多行文本）
+	if runtime.GOOS != "windows" {
+		prompt := workflow.PhasePrompt(config.Path)
+		if h := addpsync.PendingHandoff(config.Path); h != nil {
+			prompt = strings.TrimSpace(prompt + "\n\n" + h.ContextPrompt())
+		}
+		prompt = strings.TrimSpace(prompt + "\n\n" + memory.QueryContext(config.Path, "", memory.DefaultRelevant))
+		if prompt != "" {
+			if extra, ok := terminal.InitialPromptArgs(config.AIModel.TerminalType(), prompt); ok {
+				cmdArgs = append(cmdArgs, extra...)
+			}
+		}
+	}
 
 	// 启动命令
 	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
@@ -140,12 +170,34 @@ func (a *AILauncher) launchAI(config ProjectConfig) error {
 		exec.Command("cmd", "/c", "start", batFile).Start()
 	default:
 		// Linux/macOS
-		return cmd.Start()
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go runGatesAfterExit(cmd, config.Path)
 	}
 
 	return nil
 }
 
+// 会话结束后运行项目的质量门禁，报告写入 .addp/gates/reports
+func runGatesAfterExit(cmd *exec.Cmd, projectPath string) {
+	_ = cmd.Wait()
+
+	cfg, err := gates.LoadConfig(projectPath, project.NewConfigManager().ManifestTrusted)
+	if err != nil {
+		log.Printf("读取门禁配置失败 %s: %v", projectPath, err)
+		return
+	}
+	if !cfg.AutoRun(projectPath) || !cfg.RunsOn(gates.TriggerExit) {
+		return
+	}
+	report, err := gates.RunAndSave(context.Background(), projectPath, cfg, gates.TriggerExit, "")
+	if err != nil {
+		log.Printf("保存门禁报告失败 %s: %v", projectPath, err)
+	}
+	log.Printf("%s: %s", projectPath, report.Summary())
+}
+
 // 辅助函数：连接参数
 func joinArgs(args []string) string {
 	result := ""
@@ -161,6 +213,15 @@ func (a *AILauncher) setupRoutes() {
 	http.HandleFunc("/api/projects", a.handleProjects)
 	http.HandleFunc("/api/launch", a.handleLaunch)
 	http.HandleFunc("/api/save", a.handleSave)
+	http.HandleFunc("/api/health", a.handleHealth)
+	http.HandleFunc("/api/history", a.handleHistory)
+	http.HandleFunc("/api/queue", a.handleQueue)
+	http.HandleFunc("/api/queue/cancel", a.handleQueueCancel)
+	http.HandleFunc("/api/schedules", a.handleSchedules)
+	http.HandleFunc("/api/schedules/history", a.handleScheduleHistory)
+	http.HandleFunc("/api/gates", a.handleGates)
+	http.HandleFunc("/api/reload", a.handleReload)
+	a.setupTerminalRoutes()
 }
 
 // 主页面
@@ -289,6 +350,7 @@ func (a *AILauncher) handleHome(w http.ResponseWriter, r *http.Request) {
         <div class="content">
             <div class="sidebar">
                 <h3>📁 最近项目</h3>
+                <input type="search" id="project-search" class="form-control" placeholder="搜索名称、路径、备注" oninput="loadRecentProjects()">
                 <div id="recent-projects"></div>
                 <button class="btn btn-primary" onclick="clearForm()">➕ 新建项目</button>
             </div>
@@ -332,7 +394,8 @@ func (a *AILauncher) handleHome(w http.ResponseWriter, r *http.Request) {
         // 加载最近项目
         async function loadRecentProjects() {
             try {
-                const response = await fetch('/api/projects');
+                const search = document.getElementById('project-search').value;
+                const response = await fetch('/api/projects?q=' + encodeURIComponent(search));
                 const projects = await response.json();
                 const container = document.getElementById('recent-projects');
 
@@ -342,11 +405,12 @@ func (a *AILauncher) handleHome(w http.ResponseWriter, r *http.Request) {
                     card.className = 'project-card';
                     card.onclick = () => loadProject(project);
                     card.innerHTML = ` + "`" + `
-                        <div><strong>${project.name}</strong></div>
-                        <div>📍 ${project.path}</div>
+                        <div><strong>${project.pinned ? '📌 ' : ''}${project.name}</strong></div>
+                        <div>📍 ${project.path}${project.missing ? ' ⚠️ 路径不存在' : ''}</div>
                         <div>🤖 ${getModelName(project.ai_model)}</div>
                         <div>⚡ ${project.yolo_mode ? '🚀 YOLO' : '🛡️ 普通'}</div>
                     ` + "`" + `;
+                    container.appendChild(card);
                 });
             } catch (error) {
                 console.error('加载项目失败:', error);
@@ -452,10 +516,33 @@ func (a *AILauncher) handleHome(w http.ResponseWriter, r *http.Request) {
             }
         }
 
+        // 配置文件被修改后刷新项目列表；无效的文件被拒绝时显示原因
+        let reloadVersion = null;
+        let reloadError = '';
+        async function checkReload() {
+            try {
+                const response = await fetch('/api/reload');
+                const status = await response.json();
+                if (reloadVersion !== null && status.version !== reloadVersion) {
+                    loadRecentProjects();
+                }
+                reloadVersion = status.version;
+                const error = Object.values(status.errors || {}).join('; ');
+                if (error && error !== reloadError) {
+                    showStatus('❌ 配置文件无效，继续使用上一次的配置: ' + error, 'error');
+                }
+                reloadError = error;
+            } catch (error) {
+                console.error('检查配置状态失败:', error);
+            }
+        }
+
         // 页面加载时初始化
         document.addEventListener('DOMContentLoaded', function() {
             loadRecentProjects();
             showStatus('🚀 AI启动器已就绪，Web版本运行中', 'success');
+            checkReload();
+            setInterval(checkReload, 3000);
         });
     </script>
 </body>
@@ -465,10 +552,49 @@ func (a *AILauncher) handleHome(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(html))
 }
 
-// 处理项目API
+// 处理项目API：支持 q、tag、group、tool、path、pinned、archived、sort、limit 查询参数
 func (a *AILauncher) handleProjects(w http.ResponseWriter, r *http.Request) {
+	query, err := projectQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	projects := a.projects.Query(query)
+	a.mu.Unlock()
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(a.projects)
+	json.NewEncoder(w).Encode(projects)
+}
+
+// projectQuery 解析项目查询参数，archived 可为 include 或 only
+func projectQuery(values url.Values) (project.Query, error) {
+	query := project.Query{
+		Tags:     values["tag"],
+		Group:    values.Get("group"),
+		Model:    project.AIModelType(values.Get("tool")),
+		PathGlob: values.Get("path"),
+		Text:     values.Get("q"),
+		Pinned:   values.Get("pinned") == "true",
+		Archived: project.ArchiveFilter(values.Get("archived")),
+	}
+	switch query.Archived {
+	case project.ArchiveExclude, project.ArchiveInclude, project.ArchiveOnly:
+	default:
+		return query, fmt.Errorf("invalid archived: %s", query.Archived)
+	}
+
+	var err error
+	if query.Sort, err = project.ParseSortOrder(values.Get("sort")); err != nil {
+		return query, err
+	}
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("invalid limit: %s", limit)
+		}
+	}
+	return query, nil
 }
 
 // 处理启动API
@@ -478,7 +604,7 @@ func (a *AILauncher) handleLaunch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var config ProjectConfig
+	var config project.ProjectConfig
 	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -503,21 +629,250 @@ func (a *AILauncher) handleSave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var config ProjectConfig
+	var config project.ProjectConfig
 	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	a.addProject(config)
+	err := a.addProject(config)
 	response := map[string]interface{}{
-		"success": true,
+		"success": err == nil,
+	}
+	if err != nil {
+		response["error"] = err.Error()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// 处理健康检查API
+func (a *AILauncher) handleHealth(w http.ResponseWriter, r *http.Request) {
+	report := a.terminals.CheckHealth()
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.State == terminal.HealthUnhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// 处理输入历史API：?project=&tool=&q=&limit=
+func (a *AILauncher) handleHistory(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := history.Query{
+		Project: params.Get("project"),
+		Tool:    params.Get("tool"),
+		Text:    params.Get("q"),
+		Limit:   50,
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		query.Limit = n
+	}
+
+	entries, err := a.history.Search(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// 处理任务队列API：GET 列出任务，POST 添加任务
+func (a *AILauncher) handleQueue(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		jobs, err := a.queue.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jobs)
+	case "POST":
+		if !checkLocalRequest(w, r) {
+			return
+		}
+		var job queue.Job
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := a.resolveJob(&job.Project, &job.Tool, job.Options); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		added, err := a.queue.Add(job)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(added)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// resolveJob 校验 Web 接口提交的任务：命令与环境变量不能由请求指定，只能在已保存的项目中
+// 运行，命令由服务端按工具生成；未指定工具时使用项目配置的模型
+func (a *AILauncher) resolveJob(projectRef *string, tool *project.AIModelType, options queue.JobOptions) error {
+	if len(options.Command) > 0 || len(options.Environment) > 0 {
+		return fmt.Errorf("不能通过 Web 接口指定命令或环境变量")
+	}
+	a.mu.Lock()
+	proj, err := a.projects.FindProject(*projectRef)
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	*projectRef = proj.Path
+	if *tool == "" {
+		*tool = proj.AIModel
+	}
+	if *tool == project.ModelCustom {
+		return fmt.Errorf("自定义工具的任务不能通过 Web 接口添加")
+	}
+	return nil
+}
+
+// checkLocalRequest 拒绝跨站与 DNS 重绑定的写请求：Host 必须是本机地址，Origin 必须与 Host 一致，
+// 且内容类型为 application/json（跨站表单无法在不经过 CORS 预检的情况下发送）
+func checkLocalRequest(w http.ResponseWriter, r *http.Request) bool {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return false
+		}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return false
+	}
+	return true
+}
+
+// 处理取消任务API
+func (a *AILauncher) handleQueueCancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkLocalRequest(w, r) {
+		return
+	}
+
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := a.queue.Cancel(req.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// 处理定时任务API：GET 列出计划，POST 添加计划
+func (a *AILauncher) handleSchedules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		schedules, err := a.scheduler.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(schedules)
+	case "POST":
+		if !checkLocalRequest(w, r) {
+			return
+		}
+		var sc schedule.Schedule
+		if err := json.NewDecoder(r.Body).Decode(&sc); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := a.resolveJob(&sc.Project, &sc.Tool, sc.Options); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		added, err := a.scheduler.Add(sc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(added)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// 处理定时任务运行记录API，可用 id 与 limit 参数过滤
+func (a *AILauncher) handleScheduleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	runs, err := a.scheduler.History(r.URL.Query().Get("id"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// 处理门禁报告API：GET ?path=<项目>&limit=<数量> 列出最近的报告
+func (a *AILauncher) handleGates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		http.Error(w, "path is required", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	reports, err := gates.Reports(path, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if reports == nil {
+		reports = []*gates.Report{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
 // 打开浏览器
 func openBrowser(url string) {
 	var cmd string
@@ -564,8 +919,19 @@ func main() {
 	launcher := NewAILauncher()
 	launcher.setupRoutes()
 
-	port := "8080"
-	url := fmt.Sprintf("http://localhost:%s", port)
+	// 后台执行任务队列与定时任务
+	go func() {
+		if err := launcher.queue.Run(context.Background()); err != nil {
+			log.Printf("任务队列启动失败: %v", err)
+		}
+	}()
+	go launcher.scheduler.Run(context.Background())
+	launcher.watchConfig(context.Background())
+	launcher.startRescan()
+
+	// 只监听本机地址，Web 接口可以启动 AI 工具与运行任务
+	addr := "127.0.0.1:8080"
+	url := "http://" + addr
 
 	fmt.Printf("🚀 AI启动器 Web版本启动成功！\n")
 	fmt.Printf("📱 请在浏览器中打开: %s\n", url)
@@ -577,5 +943,5 @@ func main() {
 		openBrowser(url)
 	}()
 
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	log.Fatal(http.ListenAndServe(addr, nil))
 }
\ No newline at end of file
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/project"
+)
+
+// 配置重新加载状态，页面轮询 /api/reload 以刷新项目列表并显示被拒绝的文件
+type reloadStatus struct {
+	Version    int                    `json:"version"` // 每次成功重新加载后递增
+	ReloadedAt time.Time              `json:"reloaded_at"`
+	Errors     map[config.Kind]string `json:"errors,omitempty"` // 各类文件最近一次被拒绝的原因
+}
+
+// 监视配置文件、自定义模板与 projects.json，变化时重新加载；无效的文件被拒绝并保留上一次的内容
+func (a *AILauncher) watchConfig(ctx context.Context) {
+	watcher, err := config.NewWatcher(a.settings, config.WatchOptions{Dir: a.configDir})
+	if err != nil {
+		log.Printf("无法监视配置文件变化: %v", err)
+		return
+	}
+	watcher.Subscribe(a.applyChange)
+	go watcher.Run(ctx)
+}
+
+// 按 discovery.rescan_interval 在后台检查项目路径，标记路径已不存在的项目；间隔变化时重新启动
+func (a *AILauncher) startRescan() {
+	a.mu.Lock()
+	interval := time.Duration(a.settings.Discovery.RescanInterval) * time.Minute
+	if a.stopRescan != nil && interval == a.rescanInterval {
+		a.mu.Unlock()
+		return
+	}
+	if a.stopRescan != nil {
+		a.stopRescan()
+		a.stopRescan = nil
+	}
+	a.rescanInterval = interval
+	if interval <= 0 {
+		a.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopRescan = cancel
+	a.mu.Unlock()
+
+	go a.projects.WatchMissing(ctx, interval, func(missing []project.ProjectConfig, err error) {
+		if err != nil {
+			log.Printf("检查项目路径失败: %v", err)
+			return
+		}
+		for _, p := range missing {
+			log.Printf("项目路径不存在: %s (%s)", p.Name, p.Path)
+		}
+	})
+}
+
+// 应用一次文件变化
+func (a *AILauncher) applyChange(c config.Change) {
+	var err error
+	switch c.Kind {
+	case config.KindConfig:
+		if err = c.Err; err == nil {
+			a.queue.SetMaxConcurrent(c.Config.Performance.MaxConcurrentTerminals)
+		}
+	case config.KindTemplates:
+		err = a.templates.LoadDir(c.Config.Templates.Dir())
+	case config.KindProjects:
+		err = a.loadProjects()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err != nil {
+		log.Printf("已拒绝 %s，继续使用上一次的内容: %v", c.Path, err)
+		if a.reload.Errors == nil {
+			a.reload.Errors = make(map[config.Kind]string)
+		}
+		a.reload.Errors[c.Kind] = strings.ReplaceAll(err.Error(), "\n", "; ")
+		return
+	}
+	if c.Kind == config.KindConfig {
+		a.settings = c.Config
+		go a.startRescan()
+	}
+	delete(a.reload.Errors, c.Kind)
+	a.reload.Version++
+	a.reload.ReloadedAt = time.Now()
+	log.Printf("已重新加载 %s", c.Path)
+}
+
+// 处理重新加载状态API
+func (a *AILauncher) handleReload(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	status := a.reload
+	status.Errors = make(map[config.Kind]string, len(a.reload.Errors))
+	for kind, msg := range a.reload.Errors {
+		status.Errors[kind] = msg
+	}
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/launch"
+	"ai-launcher/internal/project"
+)
+
+// 会话输出增量
+type terminalLogs struct {
+	Name     string   `json:"name"`
+	Lines    []string `json:"lines"`
+	Next     int      `json:"next"` // 下一次请求的 since
+	Exited   bool     `json:"exited"`
+	ExitCode int      `json:"exit_code"`
+}
+
+// 注册会话管理API，供命令行的 launch --detach、stop、logs 与 send 使用
+func (a *AILauncher) setupTerminalRoutes() {
+	http.HandleFunc("/api/terminals/start", a.handleTerminalStart)
+	http.HandleFunc("/api/terminals/stop", a.handleTerminalStop)
+	http.HandleFunc("/api/terminals/send", a.handleTerminalSend)
+	http.HandleFunc("/api/terminals/logs", a.handleTerminalLogs)
+}
+
+// 处理启动会话API：在启动器进程中运行已保存项目的 AI 工具，会话结束后按配置运行质量门禁
+func (a *AILauncher) handleTerminalStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkLocalRequest(w, r) {
+		return
+	}
+
+	var req launch.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cm := project.NewConfigManager()
+	if err := cm.LoadProjects(); err != nil {
+		log.Printf("读取项目配置失败: %v", err)
+	}
+	// 只启动已保存的项目，不接受任意目录
+	found, err := cm.FindProject(req.Project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	proj := *found
+
+	opts := req.Options()
+	opts.Templates = a.templates
+	if err := launch.CheckAvailable(a.terminals, opts.TerminalName(proj)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	config, warnings, err := launch.Prepare(cm, proj, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = launch.Start(a.terminals, config, func(report *gates.Report, err error) {
+		log.Printf("%s: %s", config.Name, report.Summary())
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":       config.Name,
+		"path":       proj.Path,
+		"session_id": config.SessionID,
+		"warnings":   warnings,
+	})
+}
+
+// 处理停止会话API：停止进程并从管理器中移除
+func (a *AILauncher) handleTerminalStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkLocalRequest(w, r) {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := a.terminals.RemoveTerminal(req.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
+// 处理发送输入API
+func (a *AILauncher) handleTerminalSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkLocalRequest(w, r) {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, ok := a.terminals.GetTerminal(req.Name); !ok {
+		http.Error(w, fmt.Sprintf("terminal '%s' not found", req.Name), http.StatusNotFound)
+		return
+	}
+	if err := a.terminals.SendCommand(req.Name, req.Text); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
+// 处理会话输出API：GET ?name=<会话>&since=<序号> 返回新输出
+func (a *AILauncher) handleTerminalLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	t, ok := a.terminals.GetTerminal(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("terminal '%s' not found", name), http.StatusNotFound)
+		return
+	}
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+
+	logs := terminalLogs{Name: name, Exited: t.Exited(), ExitCode: t.ExitCode()}
+	logs.Lines, logs.Next = t.OutputSince(since)
+	if logs.Lines == nil {
+		logs.Lines = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs)
+}
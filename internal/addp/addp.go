@@ -0,0 +1,262 @@
+// Package addp 在項目中創建 ADDP 目錄結構（.addp），與 Python ProjectInitializer 生成相同的目錄、模板與配置；
+// 重複執行只補全缺失的部分，不覆蓋已有文件
+package addp
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"ai-launcher/internal/project/detect"
+	"ai-launcher/internal/workflow"
+)
+
+const (
+	// DirName ADDP 根目錄名
+	DirName = ".addp"
+
+	// DefaultProjectType 默認項目類型
+	DefaultProjectType = "universal-coding"
+
+	// FrameworkAuto 自動檢測框架
+	FrameworkAuto = "auto-detect"
+
+	structureVersion = "1.0.0"
+	timestampLayout  = "2006-01-02T15:04:05.000000" // 與 Python datetime.isoformat() 一致
+)
+
+//go:embed templates/*.md
+var templateFS embed.FS
+
+// Section .addp 下的一級目錄
+type Section struct {
+	Name    string
+	Desc    string
+	Subdirs []string
+}
+
+// Structure 與 Python ProjectInitializer.addp_structure 相同的目錄結構
+var Structure = []Section{
+	{"specifications", "规格驱动文档", []string{"templates", "active", "archive", "reviews"}},
+	{"workflows", "ADDP 四阶段工作流", []string{"analysis", "design", "development", "persistence"}},
+	{"memory", "跨工具项目记忆", []string{"context", "decisions", "lessons", "sessions"}},
+	{"queries", "Ollama 查询优化", []string{"optimized", "cache", "analytics", "feedback"}},
+	{"gates", "质量门禁检查", []string{"constitution", "rules", "validations", "reports"}},
+	{"sync", "工具状态同步", []string{"claude", "gemini", "cursor", "universal"}},
+	{"analytics", "性能使用分析", []string{"metrics", "reports", "trends", "benchmarks"}},
+	{"experiments", "A/B 测试研究", []string{"configs", "results", "comparisons", "insights"}},
+	{"configs", "配置管理", []string{"mcp", "ollama", "tools", "templates"}},
+	{"cache", "缓存优化", []string{"queries", "results", "models", "states"}},
+}
+
+// Options 初始化選項
+type Options struct {
+	Name        string // 項目名稱，默認為目錄名
+	Framework   string // 框架，空或 FrameworkAuto 時自動檢測
+	ProjectType string // 項目類型，默認 DefaultProjectType
+	DryRun      bool   // 只報告將要創建的內容，不寫入磁盤
+}
+
+// ProjectInfo 寫入模板與元數據的項目信息，字段與 Python project_info 相同
+type ProjectInfo struct {
+	Name          string `json:"name"`
+	Framework     string `json:"framework"`
+	Path          string `json:"path"`
+	InitializedAt string `json:"initialized_at"`
+}
+
+// Result 初始化結果；路徑均相對於項目根目錄
+type Result struct {
+	ProjectInfo ProjectInfo    `json:"project_info"`
+	Commands    Commands       `json:"commands"`
+	Environment *detect.Result `json:"environment"`
+	DryRun      bool           `json:"dry_run"`
+	Directories []string       `json:"directories_created"`
+	Files       []string       `json:"files_created"`
+	Existing    []string       `json:"files_existing"`
+}
+
+// Initialized 檢查項目是否已有 .addp 目錄
+func Initialized(projectPath string) bool {
+	info, err := os.Stat(filepath.Join(projectPath, DirName))
+	return err == nil && info.IsDir()
+}
+
+// Init 在項目中創建 .addp 結構；已存在的目錄與文件保持不變
+func Init(projectPath string, opts Options) (*Result, error) {
+	info, err := os.Stat(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("project path not found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("project path is not a directory: %s", projectPath)
+	}
+
+	env := Detect(projectPath)
+	project := ProjectInfo{
+		Name:          opts.Name,
+		Framework:     opts.Framework,
+		Path:          projectPath,
+		InitializedAt: time.Now().Format(timestampLayout),
+	}
+	if project.Name == "" {
+		project.Name = filepath.Base(projectPath)
+	}
+	commands := CommandsFor(project.Framework)
+	if project.Framework == "" || project.Framework == FrameworkAuto {
+		project.Framework = env.Framework
+		commands = env.Commands(CommandsFor(project.Framework))
+	}
+	if opts.ProjectType == "" {
+		opts.ProjectType = DefaultProjectType
+	}
+
+	s := &scaffold{
+		root:   projectPath,
+		dryRun: opts.DryRun,
+		result: &Result{ProjectInfo: project, Commands: commands, Environment: env.Project, DryRun: opts.DryRun},
+	}
+	s.createDirectories()
+	s.createTemplates()
+	s.createConfigs()
+	s.writeJSON("metadata.json", metadata(project, opts.ProjectType))
+	s.writeTemplate("README.md", "README.md", nil)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.result, nil
+}
+
+// scaffold 記錄創建過程，遇到第一個錯誤後停止寫入
+type scaffold struct {
+	root   string
+	dryRun bool
+	result *Result
+	err    error
+}
+
+func (s *scaffold) createDirectories() {
+	dirs := []string{DirName}
+	for _, section := range Structure {
+		dirs = append(dirs, filepath.Join(DirName, section.Name))
+		for _, sub := range section.Subdirs {
+			dirs = append(dirs, filepath.Join(DirName, section.Name, sub))
+		}
+	}
+
+	for _, dir := range dirs {
+		if s.err != nil {
+			return
+		}
+		path := filepath.Join(s.root, dir)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if !s.dryRun {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				s.err = fmt.Errorf("failed to create %s: %w", dir, err)
+				return
+			}
+		}
+		s.result.Directories = append(s.result.Directories, filepath.ToSlash(dir))
+	}
+}
+
+func (s *scaffold) createTemplates() {
+	data := struct {
+		ProjectInfo
+		Commands Commands
+	}{s.result.ProjectInfo, s.result.Commands}
+
+	for _, name := range []string{"prd_template.md", "plan_template.md", "tasks_template.md", "adr_template.md"} {
+		s.writeTemplate(filepath.Join("specifications", "templates", name), name, data)
+	}
+
+	for _, phase := range workflow.Phases {
+		spec, _ := workflow.SpecFor(phase)
+		s.writeTemplate(filepath.Join("workflows", string(phase), workflow.TemplateFileName(phase)), "workflow_phase.md", map[string]interface{}{
+			"Phase":     phase,
+			"Title":     spec.Title,
+			"Objective": phaseObjectives[phase],
+			"Checklist": phaseChecklists[phase],
+			"Gates":     spec.QualityChecks,
+		})
+	}
+
+	info := s.result.ProjectInfo
+	s.writeJSON(filepath.Join("memory", "context", "project_context.json"), map[string]interface{}{
+		"project_info":    info,
+		"technical_stack": map[string]string{"frontend": info.Framework},
+		"constraints":     map[string]interface{}{},
+		"current_state":   map[string]string{"phase": "initialized"},
+		"decisions":       []interface{}{},
+		"lessons":         []interface{}{},
+	})
+	s.writeJSON(filepath.Join("queries", "optimized", "optimization_config.json"), optimizationConfig())
+
+	s.writeTemplate(filepath.Join("gates", "constitution", "constitution.md"), "constitution.md", data)
+	s.writeJSON(filepath.Join("gates", "constitution", "validation_rules.json"), validationRules())
+}
+
+func (s *scaffold) createConfigs() {
+	s.writeJSON(filepath.Join("configs", "mcp", "server_config.json"), mcpConfig(s.result.ProjectInfo))
+	s.writeJSON(filepath.Join("configs", "ollama", "model_config.json"), ollamaConfig())
+	s.writeJSON(filepath.Join("configs", "tools", "integration_config.json"), toolsConfig())
+}
+
+// writeTemplate 渲染內嵌模板並寫入 .addp/rel
+func (s *scaffold) writeTemplate(rel, name string, data interface{}) {
+	if s.err != nil {
+		return
+	}
+	tmpl, err := template.ParseFS(templateFS, "templates/"+name)
+	if err != nil {
+		s.err = fmt.Errorf("failed to parse template %s: %w", name, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		s.err = fmt.Errorf("failed to render template %s: %w", name, err)
+		return
+	}
+	s.write(rel, buf.Bytes())
+}
+
+// writeJSON 以縮進 JSON 寫入 .addp/rel
+func (s *scaffold) writeJSON(rel string, v interface{}) {
+	if s.err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		s.err = fmt.Errorf("failed to encode %s: %w", rel, err)
+		return
+	}
+	s.write(rel, data)
+}
+
+// write 寫入尚不存在的文件
+func (s *scaffold) write(rel string, data []byte) {
+	rel = filepath.Join(DirName, rel)
+	path := filepath.Join(s.root, rel)
+	if _, err := os.Stat(path); err == nil {
+		s.result.Existing = append(s.result.Existing, filepath.ToSlash(rel))
+		return
+	}
+	if !s.dryRun {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			s.err = fmt.Errorf("failed to create directory for %s: %w", rel, err)
+			return
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			s.err = fmt.Errorf("failed to write %s: %w", rel, err)
+			return
+		}
+	}
+	s.result.Files = append(s.result.Files, filepath.ToSlash(rel))
+}
@@ -0,0 +1,167 @@
+package addp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	addpsync "ai-launcher/internal/sync"
+	"ai-launcher/internal/workflow"
+)
+
+func TestInit_CreatesStructure(t *testing.T) {
+	project := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(project, "go.mod"), []byte("module demo\n"), 0644))
+
+	result, err := Init(project, Options{})
+	require.NoError(t, err)
+	assert.True(t, Initialized(project))
+	assert.Equal(t, "golang", result.ProjectInfo.Framework)
+	assert.Equal(t, filepath.Base(project), result.ProjectInfo.Name)
+	assert.Empty(t, result.Existing)
+
+	for _, section := range Structure {
+		for _, sub := range section.Subdirs {
+			assert.DirExists(t, filepath.Join(project, DirName, section.Name, sub))
+		}
+	}
+	for _, rel := range []string{
+		"metadata.json",
+		"README.md",
+		"specifications/templates/prd_template.md",
+		"specifications/templates/adr_template.md",
+		"workflows/design/design_template.md",
+		"memory/context/project_context.json",
+		"queries/optimized/optimization_config.json",
+		"gates/constitution/constitution.md",
+		"gates/constitution/validation_rules.json",
+		"configs/mcp/server_config.json",
+		"configs/ollama/model_config.json",
+		"configs/tools/integration_config.json",
+	} {
+		assert.Contains(t, result.Files, DirName+"/"+rel)
+		assert.FileExists(t, filepath.Join(project, DirName, rel))
+	}
+
+	prd, err := os.ReadFile(filepath.Join(project, DirName, "specifications", "templates", "prd_template.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(prd), "**项目名称**: "+filepath.Base(project))
+	assert.Contains(t, string(prd), "**技术栈**: golang")
+
+	constitution, err := os.ReadFile(filepath.Join(project, DirName, "gates", "constitution", "constitution.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(constitution), "go vet ./...")
+	assert.NotContains(t, string(constitution), "npm run")
+
+	// 階段模板不算作工作流產出
+	assert.Empty(t, workflow.NewManager(project).Outputs(workflow.PhaseDesign))
+}
+
+func TestInit_IsIdempotent(t *testing.T) {
+	project := t.TempDir()
+	_, err := Init(project, Options{Framework: "rust", Name: "demo"})
+	require.NoError(t, err)
+
+	constitution := filepath.Join(project, DirName, "gates", "constitution", "constitution.md")
+	require.NoError(t, os.WriteFile(constitution, []byte("edited"), 0644))
+	require.NoError(t, os.RemoveAll(filepath.Join(project, DirName, "cache")))
+
+	result, err := Init(project, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".addp/cache", ".addp/cache/queries", ".addp/cache/results", ".addp/cache/models", ".addp/cache/states"}, result.Directories)
+	assert.Empty(t, result.Files)
+	assert.Contains(t, result.Existing, ".addp/gates/constitution/constitution.md")
+
+	data, err := os.ReadFile(constitution)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", string(data))
+}
+
+func TestInit_DryRun(t *testing.T) {
+	project := t.TempDir()
+
+	result, err := Init(project, Options{DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Contains(t, result.Directories, ".addp/workflows/analysis")
+	assert.Contains(t, result.Files, ".addp/metadata.json")
+	assert.False(t, Initialized(project))
+
+	entries, err := os.ReadDir(project)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = Init(filepath.Join(project, "missing"), Options{DryRun: true})
+	assert.Error(t, err)
+}
+
+func TestInit_MetadataReadBySync(t *testing.T) {
+	project := t.TempDir()
+	_, err := Init(project, Options{Name: "demo", Framework: "python", ProjectType: "web"})
+	require.NoError(t, err)
+
+	var meta map[string]interface{}
+	data, err := os.ReadFile(filepath.Join(project, DirName, "metadata.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &meta))
+	assert.Equal(t, "web", meta["project_type"])
+
+	// 同步狀態的默認項目信息來自 metadata.json
+	m := addpsync.NewManager(project)
+	require.NoError(t, m.SaveState("claude", nil))
+	state, err := m.LoadState("claude")
+	require.NoError(t, err)
+	info := state.State["project_info"].(map[string]interface{})
+	assert.Equal(t, "demo", info["name"])
+	assert.Equal(t, "python", info["framework"])
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		files     map[string]string
+		framework string
+	}{
+		{map[string]string{}, "universal"},
+		{map[string]string{"package.json": `{"dependencies":{"react":"18"}}`}, "react"},
+		{map[string]string{"package.json": `{"devDependencies":{"vue":"3"}}`}, "vue"},
+		{map[string]string{"package.json": `{"dependencies":{"@angular/core":"17"}}`}, "angular"},
+		{map[string]string{"package.json": `{"dependencies":{"next":"14"}}`}, "nextjs"},
+		{map[string]string{"package.json": `not json`}, "nodejs"},
+		{map[string]string{"pyproject.toml": ""}, "python"},
+		{map[string]string{"build.gradle.kts": ""}, "kotlin"},
+		{map[string]string{"go.mod": ""}, "golang"},
+		{map[string]string{"Cargo.toml": ""}, "rust"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.framework, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+			env := Detect(dir)
+			assert.Equal(t, tt.framework, env.Framework)
+			assert.Len(t, env.Markers, len(tt.files))
+		})
+	}
+}
+
+func TestEnvironment_Commands(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"scripts":{"test":"vitest","build":"vite build","lint":"eslint ."}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yarn.lock"), nil, 0644))
+
+	env := Detect(dir)
+	assert.Equal(t, Commands{
+		Test:  "yarn run test",
+		Gates: []string{"yarn run lint", "yarn run test", "yarn run build"},
+	}, env.Commands(CommandsFor(env.Framework)))
+
+	// 沒有檢測到命令時沿用框架默認命令
+	empty := Detect(t.TempDir())
+	assert.Equal(t, CommandsFor("universal"), empty.Commands(CommandsFor("universal")))
+}
@@ -0,0 +1,131 @@
+package addp
+
+import (
+	"time"
+
+	"ai-launcher/internal/workflow"
+)
+
+// phaseObjectives 各階段目標，與 Python _get_phase_objective 相同
+var phaseObjectives = map[workflow.Phase]string{
+	workflow.PhaseAnalysis:    "深入分析需求，识别技术约束和风险，为设计阶段提供清晰的输入",
+	workflow.PhaseDesign:      "基于分析结果设计技术方案，权衡各种选择，输出详细的实施计划",
+	workflow.PhaseDevelopment: "按照 TDD 原则执行开发，保持最小化修改，确保质量",
+	workflow.PhasePersistence: "验证开发结果，更新项目记忆，为下一轮迭代做准备",
+}
+
+// phaseChecklists 各階段檢查清單，與 Python _get_phase_checklist 相同
+var phaseChecklists = map[workflow.Phase][]string{
+	workflow.PhaseAnalysis:    {"需求已澄清和确认", "技术约束已识别", "风险评估已完成", "影响分析已进行", "输入数据已验证"},
+	workflow.PhaseDesign:      {"技术方案已选择", "架构设计已完成", "实施计划已制定", "风险缓解策略已定义", "设计评审已通过"},
+	workflow.PhaseDevelopment: {"测试用例已编写 (TDD)", "代码实现已完成", "单元测试已通过", "代码审查已完成", "集成测试已执行"},
+	workflow.PhasePersistence: {"功能验证已完成", "性能指标已确认", "文档已更新", "项目记忆已同步", "经验教训已记录"},
+}
+
+func metadata(info ProjectInfo, projectType string) map[string]interface{} {
+	return map[string]interface{}{
+		"version":                "1.0.0",
+		"framework_version":      "1.0.0",
+		"project_type":           projectType,
+		"project_info":           info,
+		"addp_structure_version": structureVersion,
+		"initialized_by":         "ai-launcher",
+		"initialization_date":    time.Now().Format(timestampLayout),
+		"features": map[string]bool{
+			"spec_driven_development": true,
+			"addp_workflow":           true,
+			"cross_tool_sync":         true,
+			"query_optimization":      true,
+			"quality_gates":           true,
+		},
+	}
+}
+
+func optimizationConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"ollama_endpoint": "http://localhost:11434",
+		"model":           "qwen2.5:14b",
+		"optimization_levels": map[string]string{
+			"basic":    "基础语法和术语优化",
+			"smart":    "上下文感知的智能优化",
+			"detailed": "深度分析和多方案生成",
+		},
+		"prompt_templates": map[string]string{
+			"optimization":             "请优化以下查询使其更加精确和可执行: {query}",
+			"context_enhancement":      "基于项目上下文 {context}，优化查询: {query}",
+			"specification_generation": "将需求 '{query}' 转化为详细的技术规格",
+		},
+	}
+}
+
+func validationRules() map[string]interface{} {
+	return map[string]interface{}{
+		"tdd_enforcement": map[string]interface{}{
+			"enabled":                 true,
+			"pre_commit_check":        true,
+			"test_coverage_threshold": 80,
+		},
+		"anti_abstraction": map[string]interface{}{
+			"enabled":                true,
+			"max_abstraction_layers": 2,
+			"duplication_threshold":  3,
+		},
+		"simplify_first": map[string]interface{}{
+			"enabled":              true,
+			"complexity_threshold": 10,
+			"dependency_limit":     20,
+		},
+		"integration_priority": map[string]interface{}{
+			"enabled":                true,
+			"e2e_coverage_threshold": 70,
+			"api_test_required":      true,
+		},
+	}
+}
+
+func mcpConfig(info ProjectInfo) map[string]interface{} {
+	tool := func(name, desc string) map[string]string {
+		return map[string]string{"name": name, "description": desc}
+	}
+	return map[string]interface{}{
+		"server": map[string]string{
+			"name":        "universal-coding-assistant",
+			"version":     "1.0.0",
+			"description": "Universal AI Coding Framework MCP Server",
+		},
+		"tools": []map[string]string{
+			tool("initialize_addp_structure", "自动初始化 ADDP 项目结构"),
+			tool("optimize_query", "使用 Ollama 优化用户查询"),
+			tool("start_addp_workflow", "启动 ADDP 工作流阶段"),
+			tool("sync_project_state", "同步项目状态到所有工具"),
+		},
+		"project_info": info,
+	}
+}
+
+func ollamaConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"endpoint":    "http://localhost:11434",
+		"model":       "qwen2.5:14b",
+		"temperature": 0.7,
+		"max_tokens":  2048,
+		"timeout":     30,
+	}
+}
+
+func toolsConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"claude_code": map[string]interface{}{
+			"mcp_server": "universal-coding-assistant",
+			"commands":   []string{"initialize", "optimize", "workflow", "sync"},
+		},
+		"gemini_cli": map[string]string{
+			"mcp_server":  "universal-coding-assistant",
+			"integration": "mcp-protocol",
+		},
+		"cursor": map[string]string{
+			"mcp_config":  ".cursor/mcp.json",
+			"integration": "plugin",
+		},
+	}
+}
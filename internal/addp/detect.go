@@ -0,0 +1,111 @@
+package addp
+
+import "ai-launcher/internal/project/detect"
+
+// Environment 項目環境檢測結果
+type Environment struct {
+	Framework  string   `json:"framework"`
+	Markers    []string `json:"markers"` // 檢測到的標誌文件
+	Git        bool     `json:"git"`
+	TypeScript bool     `json:"typescript"`
+
+	Project *detect.Result `json:"project"` // 完整的項目環境檢測結果
+}
+
+// Commands 框架對應的質量門禁命令，用於填寫 Constitution
+type Commands struct {
+	Test  string   `json:"test"`
+	Gates []string `json:"gates"`
+}
+
+// frameworkMarkers 決定框架的標誌文件，按優先級排列
+var frameworkMarkers = []struct{ marker, framework string }{
+	{"package.json", "nodejs"},
+	{"requirements.txt", "python"},
+	{"pyproject.toml", "python"},
+	{"build.gradle.kts", "kotlin"},
+	{"build.gradle", "kotlin"},
+	{"go.mod", "golang"},
+	{"Cargo.toml", "rust"},
+}
+
+// nodeFrameworks 按優先級排列的前端框架
+var nodeFrameworks = []string{"react", "vue", "angular", "nextjs"}
+
+// Detect 根據項目環境檢測結果判斷框架，規則與 Python ProjectInitializer 相同
+func Detect(projectPath string) Environment {
+	result := detect.Detect(projectPath)
+	env := Environment{
+		Framework:  "universal",
+		Git:        result.Git,
+		TypeScript: result.HasMarker("tsconfig.json"),
+		Project:    result,
+	}
+	for _, m := range frameworkMarkers {
+		if !result.HasMarker(m.marker) {
+			continue
+		}
+		env.Markers = append(env.Markers, m.marker)
+		if env.Framework == "universal" {
+			env.Framework = m.framework
+		}
+	}
+	if env.Framework == "nodejs" {
+		for _, name := range nodeFrameworks {
+			if result.HasFramework(name) {
+				env.Framework = name
+				break
+			}
+		}
+	}
+	return env
+}
+
+// Commands 返回檢測到的項目命令：測試命令取第一個，門禁按 lint、test、build 排列；
+// 沒有檢測到命令時返回 fallback
+func (env Environment) Commands(fallback Commands) Commands {
+	if env.Project == nil || len(env.Project.Commands) == 0 {
+		return fallback
+	}
+	commands := Commands{Test: fallback.Test}
+	if tests := env.Project.CommandsOf(detect.KindTest); len(tests) > 0 {
+		commands.Test = tests[0].Command
+	}
+	for _, kind := range []detect.CommandKind{detect.KindLint, detect.KindTest, detect.KindBuild} {
+		for _, c := range env.Project.CommandsOf(kind) {
+			commands.Gates = append(commands.Gates, c.Command)
+		}
+	}
+	return commands
+}
+
+// CommandsFor 返回框架的測試與門禁命令；未知框架沿用 Python 模板中的 npm 命令
+func CommandsFor(framework string) Commands {
+	switch framework {
+	case "golang":
+		return Commands{
+			Test:  "go test ./...",
+			Gates: []string{"gofmt -l .", "go vet ./...", "go test ./...", "go build ./..."},
+		}
+	case "python":
+		return Commands{
+			Test:  "pytest",
+			Gates: []string{"ruff check .", "mypy .", "pytest"},
+		}
+	case "rust":
+		return Commands{
+			Test:  "cargo test",
+			Gates: []string{"cargo fmt --check", "cargo clippy", "cargo test", "cargo build"},
+		}
+	case "kotlin":
+		return Commands{
+			Test:  "./gradlew test",
+			Gates: []string{"./gradlew lint", "./gradlew test", "./gradlew build"},
+		}
+	default:
+		return Commands{
+			Test:  "npm test -- --watch",
+			Gates: []string{"npm run lint", "npm run type-check", "npm run test", "npm run test:e2e", "npm run build"},
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultReportDir 返回報告的默認保存目錄 ~/.ai-launcher/batch/<時間戳>
+func DefaultReportDir(t time.Time) string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".ai-launcher", "batch", t.Format("20060102-150405"))
+}
+
+// WriteJSON 以 JSON 格式輸出報告
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown 以 Markdown 格式輸出報告
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	counts := r.Counts()
+
+	fmt.Fprintf(&b, "# Batch Report\n\n")
+	fmt.Fprintf(&b, "- **Prompt:** %s\n", r.Prompt)
+	fmt.Fprintf(&b, "- **Started:** %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Duration:** %s\n", r.FinishedAt.Sub(r.StartedAt).Round(time.Millisecond))
+	fmt.Fprintf(&b, "- **Projects:** %d (%d succeeded, %d failed, %d errors)\n\n",
+		len(r.Results), counts[StatusSucceeded], counts[StatusFailed], counts[StatusError])
+
+	b.WriteString("| Project | Model | Status | Exit | Duration |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, res := range r.Results {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n",
+			escapeCell(res.Project), res.Model, res.Status, res.ExitCode,
+			(time.Duration(res.DurationMs) * time.Millisecond).String())
+	}
+
+	for _, res := range r.Results {
+		fmt.Fprintf(&b, "\n## %s\n\n", res.Project)
+		fmt.Fprintf(&b, "`%s`\n\n", res.Path)
+		if res.Error != "" {
+			fmt.Fprintf(&b, "**Error:** %s\n\n", res.Error)
+		}
+		writeFence(&b, res.Stdout)
+		if res.Stderr != "" {
+			b.WriteString("\n<details><summary>stderr</summary>\n\n")
+			writeFence(&b, res.Stderr)
+			b.WriteString("\n</details>\n")
+		}
+		if res.OutputTruncated {
+			b.WriteString("\n_Output truncated._\n")
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// Save 將 JSON 與 Markdown 報告寫入目錄，返回兩個文件的路徑
+func (r *Report) Save(dir string) (string, string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	jsonPath := filepath.Join(dir, "report.json")
+	mdPath := filepath.Join(dir, "report.md")
+
+	var jsonBuf, mdBuf bytes.Buffer
+	if err := r.WriteJSON(&jsonBuf); err != nil {
+		return "", "", err
+	}
+	if err := r.WriteMarkdown(&mdBuf); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(jsonPath, jsonBuf.Bytes(), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.WriteFile(mdPath, mdBuf.Bytes(), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return jsonPath, mdPath, nil
+}
+
+// writeFence 將輸出放入代碼塊，必要時加長圍欄避免與內容衝突
+func writeFence(b *strings.Builder, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		b.WriteString("_No output._\n")
+		return
+	}
+	fmt.Fprintf(b, "%s\n%s\n%s\n", fence, text, fence)
+}
+
+// escapeCell 轉義表格單元格中的豎線
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
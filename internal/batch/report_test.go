@@ -0,0 +1,58 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/project"
+)
+
+func sampleReport() *Report {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &Report{
+		Prompt:     "summarise open TODOs",
+		StartedAt:  start,
+		FinishedAt: start.Add(3 * time.Second),
+		Results: []Result{
+			{Project: "api", Path: "/work/api", Model: project.ModelClaudeCode, Status: StatusSucceeded, Stdout: "No TODOs.\n", DurationMs: 1200},
+			{Project: "web|ui", Path: "/work/web", Model: project.ModelCodex, Status: StatusFailed, ExitCode: 2,
+				Stdout: "```go\nx\n```\n", Stderr: "boom", Error: "exit status 2", DurationMs: 800},
+		},
+	}
+}
+
+func TestReport_WriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sampleReport().WriteMarkdown(&buf))
+	md := buf.String()
+
+	assert.Contains(t, md, "- **Prompt:** summarise open TODOs")
+	assert.Contains(t, md, "(1 succeeded, 1 failed, 0 errors)")
+	assert.Contains(t, md, "| api | Claude Code | succeeded | 0 | 1.2s |")
+	assert.Contains(t, md, `| web\|ui | Codex | failed | 2 | 800ms |`)
+	// 輸出中含有代碼塊時使用更長的圍欄
+	assert.Contains(t, md, "````\n```go\nx\n```\n````")
+	assert.Contains(t, md, "<summary>stderr</summary>")
+}
+
+func TestReport_Save(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath, mdPath, err := sampleReport().Save(dir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	var decoded Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Results, 2)
+	assert.Equal(t, 2, decoded.Results[1].ExitCode)
+
+	_, err = os.Stat(mdPath)
+	assert.NoError(t, err)
+}
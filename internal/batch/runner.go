@@ -0,0 +1,163 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/terminal"
+)
+
+// Runner 在多個項目中以非交互方式執行同一個提示詞
+type Runner struct {
+	Concurrency    int           // 同時運行的項目數
+	Timeout        time.Duration // 單個項目的超時，0 表示不限制
+	MaxOutputBytes int           // stdout/stderr 各自保留的字節上限
+}
+
+// NewRunner 創建使用默認設置的批量執行器
+func NewRunner() *Runner {
+	return &Runner{
+		Concurrency:    DefaultConcurrency,
+		Timeout:        DefaultTimeout,
+		MaxOutputBytes: DefaultMaxOutputBytes,
+	}
+}
+
+// Run 並發執行所有項目並返回報告；結果順序與輸入項目一致
+func (r *Runner) Run(ctx context.Context, projects []project.ProjectConfig, opts Options) *Report {
+	report := &Report{
+		Prompt:    opts.Prompt,
+		StartedAt: time.Now(),
+		Results:   make([]Result, len(projects)),
+	}
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, p := range projects {
+		wg.Add(1)
+		go func(i int, p project.ProjectConfig) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				report.Results[i] = r.runProject(ctx, p, opts)
+			case <-ctx.Done():
+				report.Results[i] = newResult(p, nil)
+				report.Results[i].fail(StatusError, -1, ctx.Err())
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	report.FinishedAt = time.Now()
+	return report
+}
+
+// runProject 在單個項目目錄中執行命令
+func (r *Runner) runProject(ctx context.Context, p project.ProjectConfig, opts Options) Result {
+	args, err := commandFor(p, opts)
+	result := newResult(p, args)
+	if err != nil {
+		result.fail(StatusError, -1, err)
+		return result
+	}
+
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	stdout := &cappedBuffer{limit: r.MaxOutputBytes}
+	stderr := &cappedBuffer{limit: r.MaxOutputBytes}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = p.Path
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	result.StartedAt = time.Now()
+	err = cmd.Run()
+	result.DurationMs = time.Since(result.StartedAt).Milliseconds()
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	result.OutputTruncated = stdout.truncated || stderr.truncated
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		result.fail(StatusError, -1, ctx.Err())
+	case errors.As(err, &exitErr):
+		result.fail(StatusFailed, exitErr.ExitCode(), nil)
+	case err != nil:
+		result.fail(StatusError, -1, err)
+	}
+	return result
+}
+
+// commandFor 根據項目模型生成非交互命令
+func commandFor(p project.ProjectConfig, opts Options) ([]string, error) {
+	if len(opts.Command) > 0 {
+		return append(append([]string(nil), opts.Command...), opts.Prompt), nil
+	}
+	return terminal.HeadlessCommand(p.AIModel.TerminalType(), opts.Prompt, p.YoloMode || opts.ForceYolo)
+}
+
+// newResult 創建默認為成功的結果
+func newResult(p project.ProjectConfig, args []string) Result {
+	return Result{
+		Project:   p.Name,
+		Path:      p.Path,
+		Model:     p.AIModel,
+		Command:   args,
+		Status:    StatusSucceeded,
+		StartedAt: time.Now(),
+	}
+}
+
+// fail 記錄失敗狀態與原因
+func (res *Result) fail(status Status, exitCode int, err error) {
+	res.Status = status
+	res.ExitCode = exitCode
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.Error = fmt.Sprintf("exit status %d", exitCode)
+	}
+}
+
+// cappedBuffer 只保留前 limit 字節的緩衝區
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+	remaining := b.limit - b.buf.Len()
+	if len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
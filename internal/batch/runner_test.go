@@ -0,0 +1,138 @@
+package batch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/project"
+)
+
+func TestFilter_Select(t *testing.T) {
+	projects := []project.ProjectConfig{
+		{Name: "api", Path: "/work/api", AIModel: project.ModelClaudeCode, Tags: []string{"Go", "backend"}},
+		{Name: "web", Path: "/work/web", AIModel: project.ModelGeminiCLI, Tags: []string{"frontend"}},
+		{Name: "tools", Path: "/opt/tools", AIModel: project.ModelClaudeCode},
+		{Name: "legacy", Path: "/work/legacy", AIModel: project.ModelClaudeCode, Archived: true},
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"Empty", Filter{}, []string{"api", "web", "tools"}},
+		{"Tag", Filter{Tag: "go"}, []string{"api"}},
+		{"Model", Filter{Model: project.ModelClaudeCode}, []string{"api", "tools"}},
+		{"Path glob", Filter{PathGlob: "/work/*"}, []string{"api", "web"}},
+		{"Base name glob", Filter{PathGlob: "w*"}, []string{"web"}},
+		{"Combined", Filter{PathGlob: "/work/*", Model: project.ModelGeminiCLI}, []string{"web"}},
+		{"Include archived", Filter{PathGlob: "/work/*", IncludeArchived: true}, []string{"api", "web", "legacy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, p := range tt.filter.Select(projects) {
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+// newProjectDir 創建帶有可選標記文件的項目目錄
+func newProjectDir(t *testing.T, name string, marker bool) project.ProjectConfig {
+	dir := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	if marker {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "marker"), []byte("x"), 0644))
+	}
+	return project.ProjectConfig{Name: name, Path: dir, AIModel: project.ModelClaudeCode}
+}
+
+func TestRunner_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	projects := []project.ProjectConfig{
+		newProjectDir(t, "ok", true),
+		newProjectDir(t, "missing", false),
+		{Name: "gone", Path: filepath.Join(t.TempDir(), "does-not-exist")},
+	}
+
+	runner := NewRunner()
+	runner.Concurrency = 2
+	report := runner.Run(context.Background(), projects, Options{
+		Prompt:  "hello",
+		Command: []string{"sh", "-c", `echo "$1"; test -f marker || exit 3`, "sh"},
+	})
+
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, "hello", report.Prompt)
+
+	ok := report.Results[0]
+	assert.Equal(t, StatusSucceeded, ok.Status)
+	assert.Equal(t, 0, ok.ExitCode)
+	assert.Equal(t, "hello\n", ok.Stdout)
+
+	failed := report.Results[1]
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, 3, failed.ExitCode)
+
+	// 目錄不存在時無法啟動
+	assert.Equal(t, StatusError, report.Results[2].Status)
+	assert.Equal(t, -1, report.Results[2].ExitCode)
+
+	assert.False(t, report.Succeeded())
+	assert.Equal(t, map[Status]int{StatusSucceeded: 1, StatusFailed: 1, StatusError: 1}, report.Counts())
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+
+	runner := NewRunner()
+	runner.Timeout = 100 * time.Millisecond
+	report := runner.Run(context.Background(), []project.ProjectConfig{newProjectDir(t, "slow", false)}, Options{
+		Prompt:  "10",
+		Command: []string{"sleep"},
+	})
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, StatusError, report.Results[0].Status)
+	assert.Contains(t, report.Results[0].Error, "deadline exceeded")
+}
+
+func TestRunner_OutputLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	runner := NewRunner()
+	runner.MaxOutputBytes = 4
+	report := runner.Run(context.Background(), []project.ProjectConfig{newProjectDir(t, "noisy", false)}, Options{
+		Prompt:  "abcdefgh",
+		Command: []string{"echo"},
+	})
+
+	assert.Equal(t, "abcd", report.Results[0].Stdout)
+	assert.True(t, report.Results[0].OutputTruncated)
+}
+
+func TestRunner_UnsupportedModel(t *testing.T) {
+	report := NewRunner().Run(context.Background(), []project.ProjectConfig{
+		{Name: "custom", Path: t.TempDir(), AIModel: project.ModelCustom},
+	}, Options{Prompt: "hi"})
+
+	assert.Equal(t, StatusError, report.Results[0].Status)
+	assert.Contains(t, report.Results[0].Error, "not supported")
+}
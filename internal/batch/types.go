@@ -0,0 +1,109 @@
+package batch
+
+import (
+	"time"
+
+	"ai-launcher/internal/project"
+)
+
+const (
+	DefaultConcurrency    = 4                // 默認並發數
+	DefaultTimeout        = 10 * time.Minute // 單個項目的默認超時
+	DefaultMaxOutputBytes = 1024 * 1024      // 每個項目保留的輸出上限
+)
+
+// Filter 項目篩選條件，空字段表示不過濾
+type Filter struct {
+	Tag             string              `json:"tag,omitempty"`
+	PathGlob        string              `json:"path_glob,omitempty"`
+	Model           project.AIModelType `json:"model,omitempty"`
+	IncludeArchived bool                `json:"include_archived,omitempty"` // 默認跳過歸檔項目
+}
+
+// Match 檢查項目是否符合篩選條件
+func (f Filter) Match(p project.ProjectConfig) bool {
+	return f.query().Match(p)
+}
+
+// query 返回對應的項目查詢條件
+func (f Filter) query() project.Query {
+	q := project.Query{Model: f.Model, PathGlob: f.PathGlob}
+	if f.IncludeArchived {
+		q.Archived = project.ArchiveInclude
+	}
+	if f.Tag != "" {
+		q.Tags = []string{f.Tag}
+	}
+	return q
+}
+
+// Select 返回符合篩選條件的項目，保持原有順序
+func (f Filter) Select(projects []project.ProjectConfig) []project.ProjectConfig {
+	selected := make([]project.ProjectConfig, 0, len(projects))
+	for _, p := range projects {
+		if f.Match(p) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// Options 批量執行選項
+type Options struct {
+	Prompt    string   // 發送給每個項目的提示詞
+	ForceYolo bool     // 忽略項目設置，全部以 YOLO 模式運行
+	Command   []string // 自定義命令；提示詞作為最後一個參數追加
+}
+
+// Status 單個項目的執行結果狀態
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded" // 退出碼為 0
+	StatusFailed    Status = "failed"    // 非 0 退出碼
+	StatusError     Status = "error"     // 無法啟動、超時或被取消
+)
+
+// Result 單個項目的執行結果
+type Result struct {
+	Project         string              `json:"project"`
+	Path            string              `json:"path"`
+	Model           project.AIModelType `json:"model"`
+	Command         []string            `json:"command,omitempty"`
+	Status          Status              `json:"status"`
+	ExitCode        int                 `json:"exit_code"`
+	Stdout          string              `json:"stdout"`
+	Stderr          string              `json:"stderr,omitempty"`
+	OutputTruncated bool                `json:"output_truncated,omitempty"`
+	Error           string              `json:"error,omitempty"`
+	StartedAt       time.Time           `json:"started_at"`
+	DurationMs      int64               `json:"duration_ms"`
+}
+
+// Report 批量執行報告
+type Report struct {
+	Prompt     string    `json:"prompt"`
+	Filter     Filter    `json:"filter"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Results    []Result  `json:"results"`
+}
+
+// Counts 統計各狀態的項目數量
+func (r *Report) Counts() map[Status]int {
+	counts := make(map[Status]int)
+	for _, result := range r.Results {
+		counts[result.Status]++
+	}
+	return counts
+}
+
+// Succeeded 檢查是否所有項目都執行成功
+func (r *Report) Succeeded() bool {
+	for _, result := range r.Results {
+		if result.Status != StatusSucceeded {
+			return false
+		}
+	}
+	return true
+}
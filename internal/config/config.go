@@ -0,0 +1,369 @@
+// Package config 加載 AI 啟動器的配置（config.example.yaml 描述的完整結構），
+// 按 默認值 < ~/.ai-launcher/config.yaml < 項目 .ai-launcher.yaml < 環境變量 < 命令行 的順序合併，
+// 並記錄每個值的來源
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/ollama"
+)
+
+// Config 完整的配置結構
+type Config struct {
+	Global       GlobalConfig              `yaml:"global" json:"global"`
+	Server       ServerConfig              `yaml:"server" json:"server"`
+	Ollama       OllamaConfig              `yaml:"ollama" json:"ollama"`
+	Project      ProjectConfig             `yaml:"project" json:"project"`
+	Discovery    DiscoveryConfig           `yaml:"discovery" json:"discovery"`
+	Terminals    map[string]TerminalConfig `yaml:"terminals" json:"terminals"`
+	Templates    TemplatesConfig           `yaml:"templates" json:"templates"`
+	Keybindings  Keybindings               `yaml:"keybindings" json:"keybindings"`
+	Performance  PerformanceConfig         `yaml:"performance" json:"performance"`
+	Security     SecurityConfig            `yaml:"security" json:"security"`
+	Network      NetworkConfig             `yaml:"network" json:"network"`
+	Backup       BackupConfig              `yaml:"backup" json:"backup"`
+	Plugins      PluginsConfig             `yaml:"plugins" json:"plugins"`
+	Experimental ExperimentalConfig        `yaml:"experimental" json:"experimental"`
+	Analytics    AnalyticsConfig           `yaml:"analytics" json:"analytics"`
+}
+
+// GlobalConfig 全局設置
+type GlobalConfig struct {
+	LogLevel string `yaml:"log_level" json:"log_level"` // debug, info, warn, error
+	LogFile  string `yaml:"log_file" json:"log_file"`
+	Theme    Theme  `yaml:"theme" json:"theme"`
+}
+
+// Theme 界面配色，顏色為 #RRGGBB 或 0-255 的終端顏色編號
+type Theme struct {
+	PrimaryColor   string `yaml:"primary_color" json:"primary_color"`
+	SecondaryColor string `yaml:"secondary_color" json:"secondary_color"`
+	SuccessColor   string `yaml:"success_color" json:"success_color"`
+	ErrorColor     string `yaml:"error_color" json:"error_color"`
+	WarningColor   string `yaml:"warning_color" json:"warning_color"`
+}
+
+// ServerConfig MCP 服務設置（config.example.json）
+type ServerConfig struct {
+	Name        string `yaml:"name" json:"name"`
+	Version     string `yaml:"version" json:"version"`
+	Description string `yaml:"description" json:"description"`
+	Host        string `yaml:"host" json:"host"`
+	Port        int    `yaml:"port" json:"port"`
+	Debug       bool   `yaml:"debug" json:"debug"`
+}
+
+// OllamaConfig Ollama 服務設置
+type OllamaConfig struct {
+	Host         string           `yaml:"host" json:"host"`
+	DefaultModel string           `yaml:"default_model" json:"default_model"`
+	Generation   GenerationConfig `yaml:"generation" json:"generation"`
+	Timeout      int              `yaml:"timeout" json:"timeout"` // 秒
+	Retry        RetryConfig      `yaml:"retry" json:"retry"`
+}
+
+// GenerationConfig 生成參數
+type GenerationConfig struct {
+	Temperature float64 `yaml:"temperature" json:"temperature"`
+	MaxTokens   int     `yaml:"max_tokens" json:"max_tokens"`
+	TopP        float64 `yaml:"top_p" json:"top_p"`
+}
+
+// RetryConfig 重試設置
+type RetryConfig struct {
+	MaxAttempts  int `yaml:"max_attempts" json:"max_attempts"`
+	DelaySeconds int `yaml:"delay_seconds" json:"delay_seconds"`
+}
+
+// ProjectConfig ADDP 項目設置（config.example.json）
+type ProjectConfig struct {
+	AddpDirectory       string `yaml:"addp_directory" json:"addp_directory"`
+	AutoInitialize      bool   `yaml:"auto_initialize" json:"auto_initialize"`
+	QualityGatesEnabled bool   `yaml:"quality_gates_enabled" json:"quality_gates_enabled"`
+	CrossToolSync       bool   `yaml:"cross_tool_sync" json:"cross_tool_sync"`
+	AnalyticsEnabled    bool   `yaml:"analytics_enabled" json:"analytics_enabled"`
+}
+
+// DiscoveryConfig 項目自動發現設置
+type DiscoveryConfig struct {
+	Roots          []string `yaml:"roots" json:"roots"`                     // 掃描的根目錄，如 ~/work、~/src
+	MaxDepth       int      `yaml:"max_depth" json:"max_depth"`             // 根目錄之下的最大掃描深度
+	Ignore         []string `yaml:"ignore" json:"ignore"`                   // 跳過的目錄名，支持 * 通配符
+	RescanInterval int      `yaml:"rescan_interval" json:"rescan_interval"` // 分鐘，後台檢查項目路徑是否仍存在；0 表示不檢查
+}
+
+// TerminalConfig 單個 AI 工具的啟動設置，鍵為工具名（claude_code、gemini_cli 等）
+type TerminalConfig struct {
+	Command           string            `yaml:"command" json:"command"`
+	Args              []string          `yaml:"args" json:"args"`
+	WorkingDir        string            `yaml:"working_dir" json:"working_dir"`
+	Env               map[string]string `yaml:"env" json:"env"`
+	StartupIndicators []string          `yaml:"startup_indicators" json:"startup_indicators"`
+	StartupTimeout    int               `yaml:"startup_timeout" json:"startup_timeout"` // 秒
+}
+
+// TemplatesConfig 模板設置
+type TemplatesConfig struct {
+	Cache              CacheConfig       `yaml:"cache" json:"cache"`
+	CustomTemplatesDir string            `yaml:"custom_templates_dir" json:"custom_templates_dir"`
+	GlobalVariables    map[string]string `yaml:"global_variables" json:"global_variables"`
+}
+
+// CacheConfig 模板緩存設置
+type CacheConfig struct {
+	Enabled    bool `yaml:"enabled" json:"enabled"`
+	MaxSize    int  `yaml:"max_size" json:"max_size"`
+	TTLMinutes int  `yaml:"ttl_minutes" json:"ttl_minutes"`
+}
+
+// Keybindings 快捷鍵，鍵為動作名、值為按鍵名（如 "q"、"esc"、"ctrl+o"）
+type Keybindings struct {
+	MainMenu map[string]string `yaml:"main_menu" json:"main_menu"`
+	General  map[string]string `yaml:"general" json:"general"`
+}
+
+// PerformanceConfig 性能設置
+type PerformanceConfig struct {
+	MaxConcurrentTerminals int `yaml:"max_concurrent_terminals" json:"max_concurrent_terminals"`
+	CommandTimeout         int `yaml:"command_timeout" json:"command_timeout"` // 秒
+	MemoryLimit            int `yaml:"memory_limit" json:"memory_limit"`       // MB
+	CPULimit               int `yaml:"cpu_limit" json:"cpu_limit"`             // 百分比
+}
+
+// SecurityConfig 安全設置
+type SecurityConfig struct {
+	AllowedCommands  []string `yaml:"allowed_commands" json:"allowed_commands"`
+	BlockedCommands  []string `yaml:"blocked_commands" json:"blocked_commands"`
+	SensitiveEnvVars []string `yaml:"sensitive_env_vars" json:"sensitive_env_vars"` // 支持 * 通配符
+}
+
+// NetworkConfig 網絡設置
+type NetworkConfig struct {
+	Proxy   ProxyConfig   `yaml:"proxy" json:"proxy"`
+	Timeout TimeoutConfig `yaml:"timeout" json:"timeout"`
+}
+
+// ProxyConfig 代理設置
+type ProxyConfig struct {
+	HTTP    string `yaml:"http" json:"http"`
+	HTTPS   string `yaml:"https" json:"https"`
+	NoProxy string `yaml:"no_proxy" json:"no_proxy"`
+}
+
+// TimeoutConfig 網絡超時（秒）
+type TimeoutConfig struct {
+	Connect int `yaml:"connect" json:"connect"`
+	Read    int `yaml:"read" json:"read"`
+	Write   int `yaml:"write" json:"write"`
+}
+
+// BackupConfig 備份設置
+type BackupConfig struct {
+	AutoBackup     bool   `yaml:"auto_backup" json:"auto_backup"`
+	BackupDir      string `yaml:"backup_dir" json:"backup_dir"`
+	MaxBackups     int    `yaml:"max_backups" json:"max_backups"`
+	BackupInterval int    `yaml:"backup_interval" json:"backup_interval"` // 小時
+}
+
+// PluginsConfig 插件設置
+type PluginsConfig struct {
+	PluginDir string                            `yaml:"plugin_dir" json:"plugin_dir"`
+	Enabled   []string                          `yaml:"enabled" json:"enabled"`
+	Config    map[string]map[string]interface{} `yaml:"config" json:"config"`
+}
+
+// ExperimentalConfig 實驗性功能
+type ExperimentalConfig struct {
+	Enabled  bool     `yaml:"enabled" json:"enabled"`
+	Features []string `yaml:"features" json:"features"`
+}
+
+// AnalyticsConfig 使用統計設置
+type AnalyticsConfig struct {
+	Enabled bool            `yaml:"enabled" json:"enabled"`
+	DataDir string          `yaml:"data_dir" json:"data_dir"`
+	Privacy PrivacySettings `yaml:"privacy" json:"privacy"`
+}
+
+// PrivacySettings 統計的隱私設置
+type PrivacySettings struct {
+	Exclude   []string `yaml:"exclude" json:"exclude"`
+	Anonymize bool     `yaml:"anonymize" json:"anonymize"`
+}
+
+// 主選單動作：啟動工具的動作名與 project.AIModelType 相同
+const (
+	ActionClaudeCode      = "claude_code"
+	ActionGeminiCLI       = "gemini_cli"
+	ActionCodex           = "codex"
+	ActionAider           = "aider"
+	ActionQueryOptimize   = "query_optimize"
+	ActionTerminalManager = "terminal_manager"
+	ActionQuit            = "quit"
+)
+
+// 通用動作
+const (
+	ActionBack    = "back"
+	ActionConfirm = "confirm"
+	ActionUp      = "up"
+	ActionDown    = "down"
+	ActionLeft    = "left"
+	ActionRight   = "right"
+	ActionStop    = "stop"
+)
+
+// MainMenuActions 主選單動作，按幫助欄中的顯示順序排列
+var MainMenuActions = []string{
+	ActionClaudeCode, ActionGeminiCLI, ActionCodex, ActionAider,
+	ActionQueryOptimize, ActionTerminalManager, ActionQuit,
+}
+
+// GeneralActions 通用動作
+var GeneralActions = []string{
+	ActionBack, ActionConfirm, ActionUp, ActionDown, ActionLeft, ActionRight, ActionStop,
+}
+
+// Default 返回默認配置，與 config.example.yaml 一致（示例中的終端與插件配置除外）
+func Default() *Config {
+	return &Config{
+		Global: GlobalConfig{
+			LogLevel: "info",
+			LogFile:  "~/.ai-launcher/logs/app.log",
+			Theme: Theme{
+				PrimaryColor:   "#007ACC",
+				SecondaryColor: "#6C7B7F",
+				SuccessColor:   "#28A745",
+				ErrorColor:     "#DC3545",
+				WarningColor:   "#FFC107",
+			},
+		},
+		Server: ServerConfig{
+			Name:        "universal-coding-assistant",
+			Version:     "1.0.0",
+			Description: "Universal AI Coding Framework MCP Server",
+			Host:        "localhost",
+			Port:        8000,
+		},
+		Ollama: OllamaConfig{
+			Host:         "http://localhost:11434",
+			DefaultModel: "qwen2.5:14b",
+			Generation:   GenerationConfig{Temperature: 0.7, MaxTokens: 2048, TopP: 0.9},
+			Timeout:      30,
+			Retry:        RetryConfig{MaxAttempts: 3, DelaySeconds: 1},
+		},
+		Project: ProjectConfig{
+			AddpDirectory:       ".addp",
+			AutoInitialize:      true,
+			QualityGatesEnabled: true,
+			CrossToolSync:       true,
+			AnalyticsEnabled:    true,
+		},
+		Discovery: DiscoveryConfig{
+			MaxDepth: 3,
+			Ignore:   []string{"node_modules", "vendor", "dist", "build", "target", ".*"},
+		},
+		Terminals: map[string]TerminalConfig{},
+		Templates: TemplatesConfig{
+			Cache:              CacheConfig{Enabled: true, MaxSize: 100, TTLMinutes: 30},
+			CustomTemplatesDir: "~/.ai-launcher/templates",
+			GlobalVariables:    map[string]string{},
+		},
+		Keybindings: Keybindings{
+			MainMenu: map[string]string{
+				ActionClaudeCode:      "1",
+				ActionGeminiCLI:       "2",
+				ActionCodex:           "3",
+				ActionAider:           "4",
+				ActionQueryOptimize:   "5",
+				ActionTerminalManager: "6",
+				ActionQuit:            "q",
+			},
+			General: map[string]string{
+				ActionBack:    "esc",
+				ActionConfirm: "enter",
+				ActionUp:      "up",
+				ActionDown:    "down",
+				ActionLeft:    "left",
+				ActionRight:   "right",
+				ActionStop:    "x",
+			},
+		},
+		Performance: PerformanceConfig{
+			MaxConcurrentTerminals: 5,
+			CommandTimeout:         30,
+			MemoryLimit:            1024,
+			CPULimit:               80,
+		},
+		Security: SecurityConfig{
+			AllowedCommands:  []string{"claude", "gemini", "codex", "cursor", "aider", "code", "vim", "nano"},
+			BlockedCommands:  []string{"rm", "del", "format", "mkfs"},
+			SensitiveEnvVars: []string{"*_API_KEY", "*_SECRET", "*_PASSWORD", "*_TOKEN"},
+		},
+		Network: NetworkConfig{
+			Proxy:   ProxyConfig{NoProxy: "localhost,127.0.0.1"},
+			Timeout: TimeoutConfig{Connect: 10, Read: 30, Write: 10},
+		},
+		Backup: BackupConfig{
+			AutoBackup:     true,
+			BackupDir:      "~/.ai-launcher/backups",
+			MaxBackups:     10,
+			BackupInterval: 24,
+		},
+		Plugins: PluginsConfig{
+			PluginDir: "~/.ai-launcher/plugins",
+			Config:    map[string]map[string]interface{}{},
+		},
+		Analytics: AnalyticsConfig{
+			Enabled: true,
+			DataDir: "~/.ai-launcher/analytics",
+			Privacy: PrivacySettings{
+				Exclude:   []string{"user_input", "api_keys", "file_contents"},
+				Anonymize: true,
+			},
+		},
+	}
+}
+
+// Dir 返回用戶配置目錄 ~/.ai-launcher
+func Dir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".ai-launcher")
+}
+
+// ExpandHome 將路徑開頭的 ~ 展開為用戶主目錄
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, path[1:])
+}
+
+// Dir 返回展開 ~ 後的自定義模板目錄
+func (t TemplatesConfig) Dir() string {
+	return ExpandHome(t.CustomTemplatesDir)
+}
+
+// RootDirs 返回展開 ~ 後的掃描根目錄
+func (d DiscoveryConfig) RootDirs() []string {
+	roots := make([]string, 0, len(d.Roots))
+	for _, root := range d.Roots {
+		roots = append(roots, ExpandHome(root))
+	}
+	return roots
+}
+
+// NewClient 按配置創建 Ollama 客戶端
+func (o OllamaConfig) NewClient() *ollama.OllamaClient {
+	client := ollama.NewOllamaClient(o.Host)
+	client.SetModel(o.DefaultModel)
+	if o.Timeout > 0 {
+		client.HTTPClient.Timeout = time.Duration(o.Timeout) * time.Second
+	}
+	return client
+}
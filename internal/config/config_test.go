@@ -0,0 +1,274 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestDefault_IsValid(t *testing.T) {
+	assert.Empty(t, Default().Validate())
+}
+
+func TestLoad_ExampleFiles(t *testing.T) {
+	res, err := Load(Options{UserFile: filepath.Join("..", "..", "config.example.yaml"), Env: []string{}})
+	require.NoError(t, err)
+	cfg := res.Config
+	assert.Equal(t, "qwen2.5:14b", cfg.Ollama.DefaultModel)
+	assert.Equal(t, []string{"--interactive"}, cfg.Terminals["claude_code"].Args)
+	assert.Equal(t, "${CLAUDE_API_KEY}", cfg.Terminals["claude_code"].Env["CLAUDE_API_KEY"], "variables are not expanded")
+	assert.Equal(t, false, cfg.Plugins.Config["git_integration"]["auto_commit"])
+	assert.Equal(t, "3", cfg.Keybindings.MainMenu[ActionCodex])
+
+	// config.example.json 使用舊的 Ollama 字段名
+	res, err = Load(Options{UserFile: filepath.Join("..", "..", "config.example.json"), Env: []string{}})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:11434", res.Config.Ollama.Host)
+	assert.Equal(t, 2048, res.Config.Ollama.Generation.MaxTokens)
+	assert.Equal(t, 8000, res.Config.Server.Port)
+	assert.Equal(t, LayerUser, res.Source("ollama.generation.max_tokens").Layer)
+}
+
+func TestLoad_LayerPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, filepath.Join(dir, "config.yaml"), `
+ollama:
+  host: "http://user:11434"
+  default_model: "llama3"
+performance:
+  max_concurrent_terminals: 3
+  cpu_limit: 50
+terminals:
+  claude_code:
+    command: claude
+    env:
+      FOO: user
+      BAR: user
+`)
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, ProjectFile), `
+ollama:
+  default_model: "qwen2.5:7b"
+performance:
+  max_concurrent_terminals: 4
+terminals:
+  claude_code:
+    env:
+      FOO: project
+      BAR: ~
+# 啟動清單由 project.LoadManifest 讀取，加載配置時跳過
+launch:
+  tool: codex
+`)
+
+	res, err := Load(Options{
+		UserFile:   user,
+		ProjectDir: project,
+		Env: []string{
+			"AI_LAUNCHER_PERFORMANCE__MAX_CONCURRENT_TERMINALS=6",
+			"AI_LAUNCHER_GLOBAL__LOG_LEVEL=debug",
+			"OLLAMA_HOST=ignored",
+		},
+		Overrides: []Override{
+			{Path: "performance.max_concurrent_terminals", Value: "8", Origin: "--max-concurrent"},
+			{Path: "security.blocked_commands", Value: "[rm, shred]"},
+		},
+	})
+	require.NoError(t, err)
+	cfg := res.Config
+
+	assert.Equal(t, "http://user:11434", cfg.Ollama.Host)
+	assert.Equal(t, "qwen2.5:7b", cfg.Ollama.DefaultModel)
+	assert.Equal(t, 8, cfg.Performance.MaxConcurrentTerminals)
+	assert.Equal(t, 50, cfg.Performance.CPULimit)
+	assert.Equal(t, 1024, cfg.Performance.MemoryLimit)
+	assert.Equal(t, "debug", cfg.Global.LogLevel)
+	assert.Equal(t, []string{"rm", "shred"}, cfg.Security.BlockedCommands)
+	assert.Equal(t, "claude", cfg.Terminals["claude_code"].Command)
+	assert.Equal(t, map[string]string{"FOO": "project"}, cfg.Terminals["claude_code"].Env, "maps merge per key and null removes a key")
+	assert.Equal(t, []string{user, filepath.Join(project, ProjectFile)}, res.Files)
+
+	assert.Equal(t, Source{Layer: LayerUser, Origin: user}, res.Source("ollama.host"))
+	assert.Equal(t, LayerProject, res.Source("ollama.default_model").Layer)
+	assert.Equal(t, Source{Layer: LayerEnv, Origin: "AI_LAUNCHER_GLOBAL__LOG_LEVEL"}, res.Source("global.log_level"))
+	assert.Equal(t, Source{Layer: LayerFlag, Origin: "--max-concurrent"}, res.Source("performance.max_concurrent_terminals"))
+	assert.Equal(t, Source{Layer: LayerFlag, Origin: "--set"}, res.Source("security.blocked_commands"))
+	assert.Equal(t, Source{Layer: LayerDefault}, res.Source("performance.memory_limit"))
+
+	entries := res.Entries()
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "global.log_level", entries[0].Path)
+	found := false
+	for _, e := range entries {
+		if e.Path == "terminals.claude_code.env.FOO" {
+			found = true
+			assert.Equal(t, "project", e.Value)
+			assert.Equal(t, LayerProject, e.Source.Layer)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestLoad_ErrorsNameFieldPath(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, filepath.Join(dir, "config.yaml"), `
+global:
+  log_level: verbose
+  theme:
+    primary_color: blue
+ollama:
+  host: localhost:11434
+  timeout: soon
+  generation:
+    temperature: 3
+performance:
+  max_concurrent_terminals: 0
+keybindings:
+  main_menu:
+    cursor: "3"
+    aider: "1"
+terminals:
+  claude_code:
+    args: --verbose
+  broken:
+    env: {}
+unknown_section: true
+`)
+
+	_, err := Load(Options{UserFile: user, Env: []string{"AI_LAUNCHER_OLLAMA__RETRY__MAX_ATTEMPTS=many"}})
+	require.Error(t, err)
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+
+	messages := make(map[string]string)
+	for _, e := range errs {
+		messages[e.Path] = e.Error()
+	}
+	for path, want := range map[string]string{
+		"unknown_section":                      "unknown_section: unknown field (user " + user + ")",
+		"ollama.timeout":                       `ollama.timeout: expected an integer, got "soon"`,
+		"terminals.claude_code.args":           `terminals.claude_code.args: expected a list of strings, got "--verbose"`,
+		"ollama.retry.max_attempts":            `expected an integer, got "many" (env AI_LAUNCHER_OLLAMA__RETRY__MAX_ATTEMPTS)`,
+		"global.log_level":                     `must be one of debug, info, warn, error, got "verbose"`,
+		"global.theme.primary_color":           `must be #RRGGBB or a terminal color number 0-255, got "blue"`,
+		"ollama.host":                          `must be an http or https URL, got "localhost:11434"`,
+		"ollama.generation.temperature":        "must be between 0 and 2, got 3",
+		"performance.max_concurrent_terminals": "must be at least 1, got 0 (user " + user + ")",
+		"keybindings.main_menu.cursor":         "unknown action (valid: claude_code,",
+		"keybindings.main_menu.claude_code":    `key "1" is already bound to keybindings.main_menu.aider`,
+		"terminals.broken.command":             "must not be empty",
+	} {
+		assert.Contains(t, messages[path], want, path)
+	}
+	assert.True(t, strings.Contains(err.Error(), "\n"), "one error per line")
+
+	// 顯式指定的文件不存在時報錯；默認位置的文件不存在時忽略
+	_, err = Load(Options{UserFile: filepath.Join(dir, "missing.yaml"), Env: []string{}})
+	assert.Error(t, err)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	res, err := Load(Options{Env: []string{}})
+	require.NoError(t, err)
+	assert.Empty(t, res.Files)
+	assert.Equal(t, Default(), res.Config)
+}
+
+func TestLoad_ParseError(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), "config.yaml"), "global: [\n")
+	_, err := Load(Options{UserFile: path, Env: []string{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse")
+	assert.Contains(t, err.Error(), path)
+
+	path = writeFile(t, filepath.Join(t.TempDir(), "config.yaml"), "- a\n- b\n")
+	_, err = Load(Options{UserFile: path, Env: []string{}})
+	assert.ErrorContains(t, err, "top level must be a mapping")
+}
+
+func TestSecurity_IsSensitive(t *testing.T) {
+	s := Default().Security
+	assert.True(t, s.IsSensitive("OPENAI_API_KEY"))
+	assert.True(t, s.IsSensitive("github_token"))
+	assert.False(t, s.IsSensitive("CLAUDE_PROJECT_PATH"))
+}
+
+func TestOllamaConfig_NewClient(t *testing.T) {
+	o := Default().Ollama
+	o.Host = "http://gpu:11434"
+	o.Timeout = 5
+	client := o.NewClient()
+	assert.Equal(t, "http://gpu:11434", client.BaseURL)
+	assert.Equal(t, "qwen2.5:14b", client.GetModel())
+	assert.Equal(t, "5s", client.HTTPClient.Timeout.String())
+}
+
+func TestWatcher_ReloadsAndRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	templates := filepath.Join(dir, "templates")
+	userFile := filepath.Join(dir, "config.yaml")
+	content := func(host string) string {
+		return "ollama:\n  host: " + host + "\ntemplates:\n  custom_templates_dir: " + templates + "\n"
+	}
+	writeFile(t, userFile, content("http://first:11434"))
+
+	opts := Options{Env: []string{}}
+	res, err := Load(Options{UserFile: userFile, Env: []string{}})
+	require.NoError(t, err)
+	w, err := NewWatcher(res.Config, WatchOptions{Options: opts, Dir: dir, Debounce: 20 * time.Millisecond})
+	require.NoError(t, err)
+
+	changes := make(chan Change, 16)
+	w.Subscribe(func(c Change) { changes <- c })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	next := func(kind Kind) Change {
+		t.Helper()
+		for {
+			select {
+			case c := <-changes:
+				if c.Kind == kind {
+					return c
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("no %s change", kind)
+			}
+		}
+	}
+
+	writeFile(t, userFile, content("http://second:11434"))
+	c := next(KindConfig)
+	require.NoError(t, c.Err)
+	assert.Equal(t, "http://second:11434", c.Config.Ollama.Host)
+	assert.Equal(t, "http://second:11434", w.Config().Ollama.Host)
+
+	// 無效配置被拒絕，保留上一次有效的配置
+	writeFile(t, userFile, content("second:11434"))
+	c = next(KindConfig)
+	assert.ErrorContains(t, c.Err, "ollama.host")
+	assert.Equal(t, "http://second:11434", c.Config.Ollama.Host)
+	assert.Equal(t, "http://second:11434", w.Config().Ollama.Host)
+
+	writeFile(t, filepath.Join(dir, ProjectsFile), "[]")
+	assert.Equal(t, filepath.Join(dir, ProjectsFile), next(KindProjects).Path)
+
+	// 模板目錄在啟動後創建
+	require.NoError(t, os.Mkdir(templates, 0755))
+	next(KindTemplates)
+	time.Sleep(50 * time.Millisecond)
+	writeFile(t, filepath.Join(templates, "review.yaml"), "prompt: x")
+	assert.Equal(t, filepath.Join(templates, "review.yaml"), next(KindTemplates).Path)
+}
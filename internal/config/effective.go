@@ -0,0 +1,41 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Entry 合併後的單個配置值及其來源
+type Entry struct {
+	Path   string      `json:"path"`
+	Value  interface{} `json:"value"`
+	Source Source      `json:"source"`
+}
+
+// Entries 按配置結構的順序列出所有值；映射按鍵排序，列表作為單個值
+func (r *Result) Entries() []Entry {
+	var entries []Entry
+	var walk func(v reflect.Value, path []string)
+	walk = func(v reflect.Value, path []string) {
+		switch v.Kind() {
+		case reflect.Struct:
+			t := v.Type()
+			for i := 0; i < t.NumField(); i++ {
+				name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+				walk(v.Field(i), appendPath(path, name))
+			}
+		case reflect.Map:
+			keys := v.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, k := range keys {
+				walk(v.MapIndex(k), appendPath(path, k.String()))
+			}
+		default:
+			p := strings.Join(path, ".")
+			entries = append(entries, Entry{Path: p, Value: v.Interface(), Source: r.Source(p)})
+		}
+	}
+	walk(reflect.ValueOf(r.Config).Elem(), nil)
+	return entries
+}
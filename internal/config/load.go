@@ -0,0 +1,460 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProjectFile 項目根目錄中的配置文件，覆蓋用戶配置
+	ProjectFile = ".ai-launcher.yaml"
+
+	// ManifestSection 項目文件中的啟動清單段（見 project.Manifest），不屬於配置，加載時跳過
+	ManifestSection = "launch"
+
+	// EnvPrefix 環境變量前綴；路徑各級用雙下劃線分隔，如 AI_LAUNCHER_OLLAMA__HOST
+	EnvPrefix = "AI_LAUNCHER_"
+)
+
+// Layer 配置層，後面的層覆蓋前面的層
+type Layer string
+
+const (
+	LayerDefault Layer = "default" // 內置默認值
+	LayerUser    Layer = "user"    // ~/.ai-launcher/config.yaml
+	LayerProject Layer = "project" // 項目 .ai-launcher.yaml
+	LayerEnv     Layer = "env"     // AI_LAUNCHER_* 環境變量
+	LayerFlag    Layer = "flag"    // 命令行參數
+)
+
+// Source 配置值的來源
+type Source struct {
+	Layer  Layer  `json:"layer"`
+	Origin string `json:"origin,omitempty"` // 文件路徑、環境變量名或命令行參數
+}
+
+// String 返回來源描述，如 "user /home/me/.ai-launcher/config.yaml"
+func (s Source) String() string {
+	if s.Origin == "" {
+		return string(s.Layer)
+	}
+	return string(s.Layer) + " " + s.Origin
+}
+
+// Override 命令行覆蓋的單個值，Value 按 YAML 標量解析（"5" 為整數，"[a, b]" 為列表）
+type Override struct {
+	Path   string // 字段路徑，如 performance.max_concurrent_terminals
+	Value  string
+	Origin string // 命令行參數名，為空時為 --set
+}
+
+// Options 加載選項
+type Options struct {
+	UserFile   string     // 用戶配置文件；為空時使用 ~/.ai-launcher/config.yaml，不存在時嘗試 config.json
+	ProjectDir string     // 項目目錄；為空時不加載項目層
+	Env        []string   // 環境變量（KEY=VALUE）；為 nil 時使用 os.Environ()
+	Overrides  []Override // 命令行覆蓋
+}
+
+// Result 合併後的配置及每個值的來源
+type Result struct {
+	Config  *Config
+	Sources map[string]Source // 字段路徑 -> 來源；沒有記錄的字段來自默認值
+	Files   []string          // 已加載的配置文件
+}
+
+// FieldError 某個字段的錯誤
+type FieldError struct {
+	Path    string
+	Message string
+	Source  Source
+}
+
+func (e *FieldError) Error() string {
+	msg := e.Path + ": " + e.Message
+	if e.Path == "" {
+		msg = e.Message
+	}
+	if e.Source.Layer != "" && e.Source.Layer != LayerDefault {
+		msg += " (" + e.Source.String() + ")"
+	}
+	return msg
+}
+
+// Errors 加載或校驗配置時的全部錯誤
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *Errors) add(path []string, src Source, format string, args ...interface{}) {
+	*e = append(*e, &FieldError{Path: strings.Join(path, "."), Message: fmt.Sprintf(format, args...), Source: src})
+}
+
+// legacyKeys config.example.json 使用的舊字段名
+var legacyKeys = []struct{ from, to string }{
+	{"ollama.endpoint", "ollama.host"},
+	{"ollama.model", "ollama.default_model"},
+	{"ollama.temperature", "ollama.generation.temperature"},
+	{"ollama.max_tokens", "ollama.generation.max_tokens"},
+	{"ollama.top_p", "ollama.generation.top_p"},
+}
+
+// layerFile 配置文件層；required 為 false 時文件不存在則跳過
+type layerFile struct {
+	path     string
+	layer    Layer
+	required bool
+}
+
+// Load 按層合併配置並校驗；任一層有錯誤時返回 Errors，列出所有出錯的字段
+func Load(opts Options) (*Result, error) {
+	res := &Result{Config: Default(), Sources: make(map[string]Source)}
+	var errs Errors
+
+	var files []layerFile
+	if opts.UserFile != "" {
+		files = append(files, layerFile{opts.UserFile, LayerUser, true})
+	} else if path := UserFile(Dir()); path != "" {
+		files = append(files, layerFile{path, LayerUser, false})
+	}
+	if opts.ProjectDir != "" {
+		files = append(files, layerFile{filepath.Join(opts.ProjectDir, ProjectFile), LayerProject, false})
+	}
+
+	for _, f := range files {
+		src := Source{Layer: f.layer, Origin: f.path}
+		raw, err := readFile(f.path)
+		if errors.Is(err, os.ErrNotExist) && !f.required {
+			continue
+		}
+		if err != nil {
+			errs.add(nil, src, "%v", err)
+			continue
+		}
+		if f.layer == LayerProject {
+			delete(raw, ManifestSection)
+		}
+		res.Files = append(res.Files, f.path)
+		res.apply(raw, src, &errs)
+	}
+
+	env := opts.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, EnvPrefix) || key == EnvPrefix {
+			continue
+		}
+		path := strings.Split(strings.ToLower(strings.TrimPrefix(key, EnvPrefix)), "__")
+		res.apply(nest(path, parseScalar(value)), Source{Layer: LayerEnv, Origin: key}, &errs)
+	}
+
+	for _, o := range opts.Overrides {
+		origin := o.Origin
+		if origin == "" {
+			origin = "--set"
+		}
+		path := strings.Split(o.Path, ".")
+		res.apply(nest(path, parseScalar(o.Value)), Source{Layer: LayerFlag, Origin: origin}, &errs)
+	}
+
+	for _, err := range res.Config.Validate() {
+		err.Source = res.Source(err.Path)
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return res, nil
+}
+
+// Source 返回字段的來源；字段本身沒有記錄時使用最近的上級路徑
+func (r *Result) Source(path string) Source {
+	for p := path; p != ""; {
+		if src, ok := r.Sources[p]; ok {
+			return src
+		}
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return Source{Layer: LayerDefault}
+}
+
+// UserFile 返回目錄中存在的用戶配置文件，優先 config.yaml；都不存在時返回空字符串
+func UserFile(dir string) string {
+	for _, name := range []string{"config.yaml", "config.yml", "config.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// readFile 讀取 YAML 或 JSON 配置文件（JSON 是 YAML 的子集）
+func readFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse: %v", err)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	m, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("top level must be a mapping")
+	}
+	for _, k := range legacyKeys {
+		if v, ok := take(m, strings.Split(k.from, ".")); ok {
+			if _, exists := lookup(m, strings.Split(k.to, ".")); !exists {
+				put(m, strings.Split(k.to, "."), v)
+			}
+		}
+	}
+	return m, nil
+}
+
+// apply 將一層配置寫入 r.Config，並記錄寫入的每個字段的來源
+func (r *Result) apply(raw map[string]interface{}, src Source, errs *Errors) {
+	if raw == nil {
+		return
+	}
+	r.walk(reflect.ValueOf(r.Config).Elem(), nil, raw, src, errs)
+}
+
+func (r *Result) walk(v reflect.Value, path []string, raw interface{}, src Source, errs *Errors) {
+	switch v.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			errs.add(path, src, "expected a mapping, got %s", describe(raw))
+			return
+		}
+		for _, key := range sortedKeys(m) {
+			field, ok := fieldByTag(v, key)
+			if !ok {
+				errs.add(appendPath(path, key), src, "unknown field")
+				continue
+			}
+			r.walk(field, appendPath(path, key), m[key], src, errs)
+		}
+
+	case reflect.Map:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			errs.add(path, src, "expected a mapping, got %s", describe(raw))
+			return
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for _, key := range sortedKeys(m) {
+			k := reflect.ValueOf(key)
+			p := appendPath(path, key)
+			// null 刪除下層的同名項
+			if m[key] == nil {
+				v.SetMapIndex(k, reflect.Value{})
+				r.Sources[strings.Join(p, ".")] = src
+				continue
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if existing := v.MapIndex(k); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if elem.Kind() == reflect.Map && !elem.IsNil() {
+				// 複製下層的映射，避免修改默認值共享的實例
+				copied := reflect.MakeMap(elem.Type())
+				for _, mk := range elem.MapKeys() {
+					copied.SetMapIndex(mk, elem.MapIndex(mk))
+				}
+				elem = copied
+			}
+			r.walk(elem, p, m[key], src, errs)
+			v.SetMapIndex(k, elem)
+		}
+
+	default:
+		if err := decodeLeaf(v, raw); err != nil {
+			errs.add(path, src, "%v", err)
+			return
+		}
+		r.Sources[strings.Join(path, ".")] = src
+	}
+}
+
+// decodeLeaf 將原始值解碼為字段類型
+func decodeLeaf(v reflect.Value, raw interface{}) error {
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	ptr := reflect.New(v.Type())
+	if err := yaml.Unmarshal(data, ptr.Interface()); err != nil {
+		return fmt.Errorf("expected %s, got %s", typeName(v.Type()), describe(raw))
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
+
+// fieldByTag 按 yaml 標籤查找結構體字段
+func fieldByTag(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "a list of " + strings.TrimPrefix(typeName(t.Elem()), "a ") + "s"
+	case reflect.Map:
+		return "a mapping"
+	}
+	return t.String()
+}
+
+func describe(raw interface{}) string {
+	switch v := raw.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case map[string]interface{}:
+		return "a mapping"
+	case []interface{}:
+		return "a list"
+	}
+	return fmt.Sprint(raw)
+}
+
+// parseScalar 將環境變量或命令行中的字符串按 YAML 解析，無法解析時保留原字符串
+func parseScalar(s string) interface{} {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil || v == nil {
+		return s
+	}
+	if _, ok := v.(map[string]interface{}); ok {
+		return s
+	}
+	return normalize(v)
+}
+
+// normalize 將 YAML 中非字符串鍵的映射轉換為字符串鍵
+func normalize(raw interface{}) interface{} {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalize(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+	}
+	return raw
+}
+
+// nest 將路徑與值轉換為嵌套映射
+func nest(path []string, value interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	put(m, path, value)
+	return m
+}
+
+func put(m map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+func lookup(m map[string]interface{}, path []string) (interface{}, bool) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		m = next
+	}
+	v, ok := m[path[len(path)-1]]
+	return v, ok
+}
+
+// take 取出並刪除路徑上的值
+func take(m map[string]interface{}, path []string) (interface{}, bool) {
+	parent := m
+	if len(path) > 1 {
+		v, ok := lookup(m, path[:len(path)-1])
+		pm, isMap := v.(map[string]interface{})
+		if !ok || !isMap {
+			return nil, false
+		}
+		parent = pm
+	}
+	key := path[len(path)-1]
+	v, ok := parent[key]
+	delete(parent, key)
+	return v, ok
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendPath(path []string, key string) []string {
+	p := make([]string, len(path)+1)
+	copy(p, path)
+	p[len(path)] = key
+	return p
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	logLevels  = []string{"debug", "info", "warn", "error"}
+	colorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+)
+
+// validator 收集字段錯誤
+type validator struct {
+	errs Errors
+}
+
+func (v *validator) check(ok bool, field, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, &FieldError{Path: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+func (v *validator) min(value, min int, field string) {
+	v.check(value >= min, field, "must be at least %d, got %d", min, value)
+}
+
+func (v *validator) between(value, min, max float64, field string) {
+	v.check(value >= min && value <= max, field, "must be between %g and %g, got %g", min, max, value)
+}
+
+// url 校驗 http(s) 地址；允許空值及尚未展開的 ${VAR}
+func (v *validator) url(value, field string, required bool) {
+	if value == "" {
+		v.check(!required, field, "must not be empty")
+		return
+	}
+	if strings.Contains(value, "${") {
+		return
+	}
+	u, err := url.Parse(value)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", field, "must be an http or https URL, got %q", value)
+}
+
+// Validate 校驗配置，錯誤中的 Path 為字段路徑
+func (c *Config) Validate() Errors {
+	v := &validator{}
+
+	v.check(contains(logLevels, c.Global.LogLevel), "global.log_level", "must be one of %s, got %q", strings.Join(logLevels, ", "), c.Global.LogLevel)
+	for name, color := range map[string]string{
+		"primary_color":   c.Global.Theme.PrimaryColor,
+		"secondary_color": c.Global.Theme.SecondaryColor,
+		"success_color":   c.Global.Theme.SuccessColor,
+		"error_color":     c.Global.Theme.ErrorColor,
+		"warning_color":   c.Global.Theme.WarningColor,
+	} {
+		v.check(validColor(color), "global.theme."+name, "must be #RRGGBB or a terminal color number 0-255, got %q", color)
+	}
+
+	v.check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+
+	v.url(c.Ollama.Host, "ollama.host", true)
+	v.check(strings.TrimSpace(c.Ollama.DefaultModel) != "", "ollama.default_model", "must not be empty")
+	v.between(c.Ollama.Generation.Temperature, 0, 2, "ollama.generation.temperature")
+	v.min(c.Ollama.Generation.MaxTokens, 1, "ollama.generation.max_tokens")
+	v.between(c.Ollama.Generation.TopP, 0, 1, "ollama.generation.top_p")
+	v.min(c.Ollama.Timeout, 1, "ollama.timeout")
+	v.min(c.Ollama.Retry.MaxAttempts, 1, "ollama.retry.max_attempts")
+	v.min(c.Ollama.Retry.DelaySeconds, 0, "ollama.retry.delay_seconds")
+
+	v.check(strings.TrimSpace(c.Project.AddpDirectory) != "", "project.addp_directory", "must not be empty")
+
+	v.min(c.Discovery.MaxDepth, 0, "discovery.max_depth")
+	v.min(c.Discovery.RescanInterval, 0, "discovery.rescan_interval")
+	for i, pattern := range c.Discovery.Ignore {
+		_, err := path.Match(pattern, "")
+		v.check(err == nil, fmt.Sprintf("discovery.ignore.%d", i), "invalid pattern %q", pattern)
+	}
+
+	for _, name := range sortedNames(c.Terminals) {
+		t := c.Terminals[name]
+		v.check(strings.TrimSpace(t.Command) != "", "terminals."+name+".command", "must not be empty")
+		v.min(t.StartupTimeout, 0, "terminals."+name+".startup_timeout")
+	}
+
+	v.min(c.Templates.Cache.MaxSize, 0, "templates.cache.max_size")
+	v.min(c.Templates.Cache.TTLMinutes, 0, "templates.cache.ttl_minutes")
+
+	c.Keybindings.validate(v)
+
+	v.min(c.Performance.MaxConcurrentTerminals, 1, "performance.max_concurrent_terminals")
+	v.min(c.Performance.CommandTimeout, 0, "performance.command_timeout")
+	v.min(c.Performance.MemoryLimit, 0, "performance.memory_limit")
+	v.check(c.Performance.CPULimit >= 0 && c.Performance.CPULimit <= 100, "performance.cpu_limit", "must be between 0 and 100, got %d", c.Performance.CPULimit)
+
+	for i, pattern := range c.Security.SensitiveEnvVars {
+		_, err := path.Match(pattern, "")
+		v.check(err == nil, fmt.Sprintf("security.sensitive_env_vars.%d", i), "invalid pattern %q", pattern)
+	}
+
+	v.url(c.Network.Proxy.HTTP, "network.proxy.http", false)
+	v.url(c.Network.Proxy.HTTPS, "network.proxy.https", false)
+	v.min(c.Network.Timeout.Connect, 0, "network.timeout.connect")
+	v.min(c.Network.Timeout.Read, 0, "network.timeout.read")
+	v.min(c.Network.Timeout.Write, 0, "network.timeout.write")
+
+	v.min(c.Backup.MaxBackups, 0, "backup.max_backups")
+	v.min(c.Backup.BackupInterval, 0, "backup.backup_interval")
+
+	sort.SliceStable(v.errs, func(i, j int) bool { return v.errs[i].Path < v.errs[j].Path })
+	return v.errs
+}
+
+// validate 動作必須已知、按鍵不能為空，同一按鍵不能綁定兩個動作
+func (k Keybindings) validate(v *validator) {
+	owners := make(map[string]string)
+	check := func(section string, bindings map[string]string, actions []string) {
+		for _, action := range sortedNames(bindings) {
+			field := section + "." + action
+			key := strings.TrimSpace(bindings[action])
+			if !contains(actions, action) {
+				v.check(false, field, "unknown action (valid: %s)", strings.Join(actions, ", "))
+				continue
+			}
+			if key == "" {
+				v.check(false, field, "key must not be empty")
+				continue
+			}
+			if owner, ok := owners[key]; ok {
+				v.check(false, field, "key %q is already bound to %s", key, owner)
+				continue
+			}
+			owners[key] = field
+		}
+	}
+	check("keybindings.general", k.General, GeneralActions)
+	check("keybindings.main_menu", k.MainMenu, MainMenuActions)
+}
+
+// IsSensitive 檢查環境變量名是否匹配 sensitive_env_vars 中的模式
+func (s SecurityConfig) IsSensitive(name string) bool {
+	for _, pattern := range s.SensitiveEnvVars {
+		if ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func validColor(c string) bool {
+	if colorRegex.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
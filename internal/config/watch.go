@@ -0,0 +1,246 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ProjectsFile 配置目錄中的項目列表
+const ProjectsFile = "projects.json"
+
+// DefaultDebounce 合併連續文件事件的等待時間；編輯器保存時通常會產生多個事件
+const DefaultDebounce = 200 * time.Millisecond
+
+// Kind 發生變化的文件類別
+type Kind string
+
+const (
+	KindConfig    Kind = "config"    // config.yaml 或項目 .ai-launcher.yaml
+	KindTemplates Kind = "templates" // 自定義模板目錄
+	KindProjects  Kind = "projects"  // projects.json
+)
+
+// Change 一次文件變化，按順序發給所有訂閱者
+type Change struct {
+	Kind   Kind
+	Path   string  // 觸發變化的文件
+	Config *Config // 當前生效的配置；Err 非 nil 時為上一次有效的配置
+	Err    error   // 配置無效時的錯誤，新配置已被拒絕
+}
+
+// WatchOptions 監視選項
+type WatchOptions struct {
+	Options                // 重新加載配置時的選項，應與初次加載一致
+	Dir      string        // 配置目錄，監視其中的配置文件與 projects.json；為空時使用 Dir()
+	Debounce time.Duration // 為 0 時使用 DefaultDebounce
+}
+
+// Watcher 監視配置文件、模板目錄與項目列表，變化時重新加載配置並通知訂閱者。
+// 配置整體替換：新配置無效時保留上一次有效的配置；模板與項目列表由訂閱者自行重新加載。
+type Watcher struct {
+	opts    WatchOptions
+	fs      *fsnotify.Watcher
+	watched map[string]bool // 已監視的目錄
+
+	mu        sync.RWMutex
+	config    *Config
+	templates string // 當前監視的模板目錄
+	subs      []func(Change)
+}
+
+// NewWatcher 創建監視器；cfg 為初次加載的配置（加載失敗時為默認配置）
+func NewWatcher(cfg *Config, opts WatchOptions) (*Watcher, error) {
+	if opts.Dir == "" {
+		opts.Dir = Dir()
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{opts: opts, fs: fsw, watched: make(map[string]bool), config: cfg}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	w.watch(opts.Dir)
+	if opts.UserFile != "" {
+		w.watch(filepath.Dir(opts.UserFile))
+	}
+	if opts.ProjectDir != "" {
+		w.watch(opts.ProjectDir)
+	}
+	w.templates = filepath.Clean(cfg.Templates.Dir())
+	w.watch(w.templates)
+	return w, nil
+}
+
+// Config 返回當前生效的配置
+func (w *Watcher) Config() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.config
+}
+
+// Subscribe 註冊訂閱者；訂閱者在監視器的 goroutine 中依次調用
+func (w *Watcher) Subscribe(fn func(Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Run 處理文件事件直到 ctx 取消，返回前關閉監視器
+func (w *Watcher) Run(ctx context.Context) error {
+	defer w.fs.Close()
+
+	timer := time.NewTimer(w.opts.Debounce)
+	timer.Stop()
+	pending := make(map[Kind]string)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if kind, ok := w.classify(event); ok {
+				pending[kind] = event.Name
+				timer.Reset(w.opts.Debounce)
+			}
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("[config] watch error: %v", err)
+		case <-timer.C:
+			// 配置先於模板處理，模板目錄可能隨配置變化
+			for _, kind := range []Kind{KindConfig, KindTemplates, KindProjects} {
+				if path, ok := pending[kind]; ok {
+					delete(pending, kind)
+					w.reload(kind, path)
+				}
+			}
+		}
+	}
+}
+
+// classify 判斷事件所屬的文件類別
+func (w *Watcher) classify(event fsnotify.Event) (Kind, bool) {
+	name := filepath.Clean(event.Name)
+	dir, base := filepath.Dir(name), filepath.Base(name)
+
+	w.mu.RLock()
+	templates := w.templates
+	w.mu.RUnlock()
+
+	switch {
+	case w.opts.UserFile != "" && name == filepath.Clean(w.opts.UserFile):
+		return KindConfig, true
+	case w.opts.UserFile == "" && dir == filepath.Clean(w.opts.Dir) && (base == "config.yaml" || base == "config.yml" || base == "config.json"):
+		return KindConfig, true
+	case w.opts.ProjectDir != "" && dir == filepath.Clean(w.opts.ProjectDir) && base == ProjectFile:
+		return KindConfig, true
+	case dir == filepath.Clean(w.opts.Dir) && base == ProjectsFile:
+		return KindProjects, true
+	case name == templates:
+		// 模板目錄創建後開始監視
+		if event.Has(fsnotify.Create) {
+			w.watch(templates)
+		}
+		return KindTemplates, true
+	case dir == templates && isTemplateFile(base):
+		return KindTemplates, true
+	}
+	return "", false
+}
+
+// reload 重新加載配置並通知訂閱者
+func (w *Watcher) reload(kind Kind, path string) {
+	if kind != KindConfig {
+		w.notify(Change{Kind: kind, Path: path, Config: w.Config()})
+		return
+	}
+
+	opts := w.opts.Options
+	if opts.UserFile == "" {
+		opts.UserFile = UserFile(w.opts.Dir)
+	}
+	res, err := Load(opts)
+	if err != nil {
+		log.Printf("[config] rejected %s: %v", path, err)
+		w.notify(Change{Kind: kind, Path: path, Config: w.Config(), Err: err})
+		return
+	}
+
+	w.mu.Lock()
+	w.config = res.Config
+	oldTemplates := w.templates
+	w.templates = filepath.Clean(res.Config.Templates.Dir())
+	w.mu.Unlock()
+
+	w.notify(Change{Kind: kind, Path: path, Config: res.Config})
+	if w.templates != oldTemplates {
+		w.unwatch(oldTemplates)
+		w.watch(w.templates)
+		w.notify(Change{Kind: KindTemplates, Path: w.templates, Config: res.Config})
+	}
+}
+
+func (w *Watcher) notify(c Change) {
+	w.mu.RLock()
+	subs := append([]func(Change){}, w.subs...)
+	w.mu.RUnlock()
+	for _, fn := range subs {
+		fn(c)
+	}
+}
+
+// watch 監視目錄；目錄不存在時忽略，位於已監視目錄中的模板目錄創建後再開始監視
+func (w *Watcher) watch(dir string) {
+	dir = filepath.Clean(dir)
+	if w.watched[dir] {
+		return
+	}
+	if err := w.fs.Add(dir); err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[config] cannot watch %s: %v", dir, err)
+		}
+		return
+	}
+	w.watched[dir] = true
+}
+
+// unwatch 停止監視目錄；配置目錄與項目目錄保持監視
+func (w *Watcher) unwatch(dir string) {
+	if !w.watched[dir] || dir == filepath.Clean(w.opts.Dir) || dir == filepath.Clean(w.opts.ProjectDir) {
+		return
+	}
+	if w.opts.UserFile != "" && dir == filepath.Dir(w.opts.UserFile) {
+		return
+	}
+	_ = w.fs.Remove(dir)
+	delete(w.watched, dir)
+}
+
+func isTemplateFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
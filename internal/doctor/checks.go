@@ -0,0 +1,269 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/ollama"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/terminal"
+)
+
+// versionTimeout 獲取工具版本的超時時間
+const versionTimeout = 5 * time.Second
+
+// installHints 各 AI 工具的安裝方式
+var installHints = map[project.AIModelType]string{
+	project.ModelClaudeCode: "npm install -g @anthropic-ai/claude-code",
+	project.ModelGeminiCLI:  "npm install -g @google/gemini-cli",
+	project.ModelCodex:      "npm install -g @openai/codex",
+	project.ModelAider:      "python -m pip install aider-install && aider-install",
+}
+
+// checkTools 檢查每個支持的 AI 工具是否在 PATH 中並獲取版本；
+// 已註冊項目使用的工具缺失時為失敗，其餘為警告
+func checkTools(ctx context.Context, opts Options) []Check {
+	used := make(map[project.AIModelType]bool)
+	projects, _ := readProjects(opts.ConfigDir)
+	for _, p := range projects {
+		used[p.AIModel] = true
+	}
+
+	pa := terminal.NewPlatformAdapter()
+	var checks []Check
+	for _, model := range project.NewConfigManager().GetAvailableModels() {
+		command := model.GetCommand(false)[0]
+		c := Check{Category: "tools", Name: command}
+
+		if !pa.ValidateCommand(command) {
+			c.Status = StatusWarn
+			c.Message = fmt.Sprintf("%s not found in PATH", model.String())
+			if used[model] {
+				c.Status = StatusFail
+				c.Message += " (used by registered projects)"
+			}
+			c.Hint = fmt.Sprintf("install with: %s\nif it is installed, add its directory to PATH; the GUI started from the desktop may not inherit your shell PATH", installHints[model])
+			checks = append(checks, c)
+			continue
+		}
+
+		path, _ := exec.LookPath(command)
+		version, err := toolVersion(ctx, path)
+		if err != nil {
+			c.Status = StatusWarn
+			c.Message = fmt.Sprintf("%s found but `%s --version` failed: %v", path, command, err)
+			c.Hint = fmt.Sprintf("reinstall with: %s", installHints[model])
+		} else {
+			c.Status = StatusPass
+			c.Message = fmt.Sprintf("%s (%s)", version, path)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// toolVersion 運行 --version 並返回輸出的第一個非空行
+func toolVersion(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "--version")
+	cmd.Stdin = nil
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("timed out after %s", versionTimeout)
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+	return "unknown version", nil
+}
+
+// checkOllama 檢查 Ollama 服務與默認模型；Ollama 只用於提示詞優化，問題均為警告
+func checkOllama(ctx context.Context, opts Options) []Check {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	client := ollama.NewOllamaClient(opts.OllamaURL)
+	models, err := client.GetAvailableModels(ctx)
+	if err != nil {
+		return []Check{{
+			Category: "ollama",
+			Name:     "service",
+			Status:   StatusWarn,
+			Message:  fmt.Sprintf("not reachable at %s: %v", opts.OllamaURL, err),
+			Hint:     "start it with `ollama serve` (install from https://ollama.com); prompt optimization is unavailable without it",
+		}}
+	}
+
+	checks := []Check{{Category: "ollama", Name: "service", Status: StatusPass, Message: fmt.Sprintf("reachable at %s, %d models installed", opts.OllamaURL, len(models))}}
+	model := Check{Category: "ollama", Name: "model", Status: StatusPass, Message: fmt.Sprintf("default model %s installed", client.GetModel())}
+	if !containsModel(models, client.GetModel()) {
+		model.Status = StatusWarn
+		model.Message = fmt.Sprintf("default model %s is not installed", client.GetModel())
+		model.Hint = fmt.Sprintf("ai-launcher ollama pull %s", client.GetModel())
+	}
+	return append(checks, model)
+}
+
+func containsModel(models []ollama.ModelInfo, name string) bool {
+	for _, m := range models {
+		// 未寫標籤的模型名等同於 :latest
+		if m.Name == name || m.Name == name+":latest" {
+			return true
+		}
+	}
+	return false
+}
+
+// configFiles 配置目錄中需要檢查的 JSON 文件
+var configFiles = []string{
+	"projects.json",
+	filepath.Join("queue", "queue.json"),
+	"schedules.json",
+}
+
+// checkConfig 檢查配置文件能否解析，以及已註冊項目的路徑與模型是否有效
+func checkConfig(ctx context.Context, opts Options) []Check {
+	var checks []Check
+	for _, name := range configFiles {
+		path := filepath.Join(opts.ConfigDir, name)
+		c := Check{Category: "config", Name: filepath.ToSlash(name), Status: StatusPass}
+
+		data, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			c.Message = "not created yet"
+		case err != nil:
+			c.Status = StatusFail
+			c.Message = err.Error()
+			c.Hint = fmt.Sprintf("check the permissions of %s", path)
+		case !json.Valid(data):
+			c.Status = StatusFail
+			c.Message = fmt.Sprintf("%s is not valid JSON", path)
+			c.Hint = "fix the file by hand, or move it aside to start with an empty configuration"
+		default:
+			c.Message = path
+		}
+		if name == "projects.json" && c.Status == StatusPass && data != nil {
+			checkProjectsSchema(&c, data)
+		}
+		checks = append(checks, c)
+	}
+	checks = append(checks, checkSettings(opts.ConfigDir))
+
+	projects, err := readProjects(opts.ConfigDir)
+	if err != nil || len(projects) == 0 {
+		return checks
+	}
+	cm := project.NewConfigManager()
+	var missing, invalid []string
+	for _, p := range projects {
+		if info, err := os.Stat(p.Path); err != nil || !info.IsDir() {
+			missing = append(missing, fmt.Sprintf("%s (%s)", p.Name, p.Path))
+		}
+		if !cm.IsValidModel(p.AIModel) {
+			invalid = append(invalid, fmt.Sprintf("%s (%q)", p.Name, string(p.AIModel)))
+		}
+	}
+
+	c := Check{Category: "config", Name: "projects", Status: StatusPass, Message: fmt.Sprintf("%d projects registered", len(projects))}
+	var hints []string
+	if len(missing) > 0 {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("%d of %d project paths are missing: %s", len(missing), len(projects), strings.Join(missing, ", "))
+		hints = append(hints, "remove them with `ai-launcher project rm <name>` or restore the directories")
+	}
+	if len(invalid) > 0 {
+		c.Status = StatusWarn
+		if len(missing) == 0 {
+			c.Message = ""
+		} else {
+			c.Message += "; "
+		}
+		c.Message += fmt.Sprintf("unknown AI tool: %s", strings.Join(invalid, ", "))
+		hints = append(hints, "set a supported tool with `ai-launcher project add <path> --tool claude_code`")
+	}
+	c.Hint = strings.Join(hints, "\n")
+	return append(checks, c)
+}
+
+// readProjects 只讀地解析 projects.json，不存在時返回空列表
+func readProjects(configDir string) ([]project.ProjectConfig, error) {
+	data, err := os.ReadFile(filepath.Join(configDir, "projects.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	projects, _, err := project.DecodeProjects(data)
+	return projects, err
+}
+
+// checkProjectsSchema 檢查 projects.json 的格式版本
+func checkProjectsSchema(c *Check, data []byte) {
+	_, version, err := project.DecodeProjects(data)
+	switch {
+	case version > project.SchemaVersion:
+		c.Status = StatusFail
+		c.Message = err.Error()
+		c.Hint = "the file was written by a newer ai-launcher; upgrade this installation"
+	case err != nil:
+		c.Status = StatusFail
+		c.Message = err.Error()
+		c.Hint = "fix the file by hand, or restore a projects.json.v*.bak backup"
+	case version < project.SchemaVersion:
+		c.Message += fmt.Sprintf(" (schema_version %d, migrated with a backup on next start)", version)
+	}
+}
+
+// checkSettings 檢查 config.yaml 能否解析並通過校驗，環境變量覆蓋一併計入
+func checkSettings(dir string) Check {
+	c := Check{Category: "config", Name: "config.yaml", Status: StatusPass}
+	path := config.UserFile(dir)
+	if path == "" {
+		c.Message = "not created yet, using defaults"
+		return c
+	}
+	c.Name = filepath.Base(path)
+	if _, err := config.Load(config.Options{UserFile: path}); err != nil {
+		c.Status = StatusFail
+		c.Message = strings.ReplaceAll(err.Error(), "\n", "; ")
+		c.Hint = "fix the listed fields (see config.example.yaml), then run `ai-launcher config validate`"
+		return c
+	}
+	c.Message = path
+	return c
+}
+
+// checkConfigDir 檢查配置目錄可寫
+func checkConfigDir(ctx context.Context, opts Options) []Check {
+	c := Check{Category: "config", Name: "directory", Status: StatusPass, Message: fmt.Sprintf("%s is writable", opts.ConfigDir)}
+	err := os.MkdirAll(opts.ConfigDir, 0755)
+	if err == nil {
+		var f *os.File
+		if f, err = os.CreateTemp(opts.ConfigDir, ".doctor-*"); err == nil {
+			f.Close()
+			err = os.Remove(f.Name())
+		}
+	}
+	if err != nil {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%s is not writable: %v", opts.ConfigDir, err)
+		c.Hint = fmt.Sprintf("fix the owner and permissions of %s (e.g. chown -R $USER %s)", opts.ConfigDir, opts.ConfigDir)
+	}
+	return []Check{c}
+}
@@ -0,0 +1,133 @@
+// Package doctor 診斷運行環境：AI 工具、Ollama、配置文件、配置目錄權限、
+// 孤兒進程與中日韓字體，並為每個問題給出修復建議
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status 檢查結果
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn" // 不影響核心功能，但部分功能不可用
+	StatusFail Status = "fail"
+)
+
+// Check 單項檢查的結果
+type Check struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"` // 修復建議，通過時為空
+}
+
+// Report 診斷報告
+type Report struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Checks    []Check   `json:"checks"`
+	Passed    int       `json:"passed"`
+	Warnings  int       `json:"warnings"`
+	Failed    int       `json:"failed"`
+}
+
+// Options 診斷選項
+type Options struct {
+	ConfigDir string // 啟動器配置目錄，默認 ~/.ai-launcher
+	OllamaURL string // Ollama 服務地址
+}
+
+// DefaultOptions 返回默認的診斷選項
+func DefaultOptions() Options {
+	homeDir, _ := os.UserHomeDir()
+	return Options{
+		ConfigDir: filepath.Join(homeDir, ".ai-launcher"),
+		OllamaURL: "http://localhost:11434",
+	}
+}
+
+// Run 運行所有檢查；各類檢查並行執行，結果按固定順序排列
+func Run(ctx context.Context, opts Options) *Report {
+	defaults := DefaultOptions()
+	if opts.ConfigDir == "" {
+		opts.ConfigDir = defaults.ConfigDir
+	}
+	if opts.OllamaURL == "" {
+		opts.OllamaURL = defaults.OllamaURL
+	}
+
+	groups := []func(context.Context, Options) []Check{
+		checkTools,
+		checkOllama,
+		checkConfig,
+		checkConfigDir,
+		checkOrphans,
+		checkFont,
+	}
+	results := make([][]Check, len(groups))
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		wg.Add(1)
+		go func(i int, group func(context.Context, Options) []Check) {
+			defer wg.Done()
+			results[i] = group(ctx, opts)
+		}(i, group)
+	}
+	wg.Wait()
+
+	report := &Report{CheckedAt: time.Now()}
+	for _, checks := range results {
+		for _, c := range checks {
+			report.add(c)
+		}
+	}
+	return report
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+	switch c.Status {
+	case StatusPass:
+		r.Passed++
+	case StatusWarn:
+		r.Warnings++
+	default:
+		r.Failed++
+	}
+}
+
+// Summary 返回一行摘要，例如 "9 passed, 2 warnings, 1 failed"
+func (r *Report) Summary() string {
+	return fmt.Sprintf("%d passed, %d warnings, %d failed", r.Passed, r.Warnings, r.Failed)
+}
+
+// Details 返回逐項的文本報告，未通過的檢查附帶修復建議
+func (r *Report) Details() string {
+	var b strings.Builder
+	category := ""
+	for _, c := range r.Checks {
+		if c.Category != category {
+			if category != "" {
+				b.WriteString("\n")
+			}
+			category = c.Category
+			fmt.Fprintf(&b, "%s:\n", category)
+		}
+		fmt.Fprintf(&b, "  [%s] %-16s %s\n", strings.ToUpper(string(c.Status)), c.Name, c.Message)
+		if c.Hint != "" {
+			for _, line := range strings.Split(c.Hint, "\n") {
+				fmt.Fprintf(&b, "         -> %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\n%s", r.Summary())
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCheck(t *testing.T, checks []Check, name string) Check {
+	t.Helper()
+	for _, c := range checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("check %q not found in %+v", name, checks)
+	return Check{}
+}
+
+func TestCheckTools(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake tool")
+	}
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\necho\necho '1.0.42 (Claude Code)'\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "codex"), []byte("#!/bin/sh\nexit 2\n"), 0755))
+	t.Setenv("PATH", bin)
+
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "projects.json"), []byte(`[{"name":"web","path":"/tmp","ai_model":"gemini_cli"}]`), 0644))
+
+	checks := checkTools(context.Background(), Options{ConfigDir: configDir})
+	require.Len(t, checks, 4)
+
+	claude := findCheck(t, checks, "claude")
+	assert.Equal(t, StatusPass, claude.Status)
+	assert.Equal(t, fmt.Sprintf("1.0.42 (Claude Code) (%s)", filepath.Join(bin, "claude")), claude.Message)
+
+	// 已註冊項目使用的工具缺失時為失敗
+	gemini := findCheck(t, checks, "gemini")
+	assert.Equal(t, StatusFail, gemini.Status)
+	assert.Contains(t, gemini.Hint, "@google/gemini-cli")
+
+	assert.Equal(t, StatusWarn, findCheck(t, checks, "aider").Status)
+	assert.Contains(t, findCheck(t, checks, "codex").Message, "--version` failed")
+}
+
+func TestCheckOllama(t *testing.T) {
+	models := `{"models":[{"name":"qwen2.5:14b","size":1}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, models)
+	}))
+	defer server.Close()
+
+	checks := checkOllama(context.Background(), Options{OllamaURL: server.URL})
+	require.Len(t, checks, 2)
+	assert.Equal(t, StatusPass, checks[0].Status)
+	assert.Equal(t, StatusPass, checks[1].Status)
+
+	models = `{"models":[{"name":"llama3:latest","size":1}]}`
+	checks = checkOllama(context.Background(), Options{OllamaURL: server.URL})
+	assert.Equal(t, StatusWarn, checks[1].Status)
+	assert.Equal(t, "ai-launcher ollama pull qwen2.5:14b", checks[1].Hint)
+
+	server.Close()
+	checks = checkOllama(context.Background(), Options{OllamaURL: server.URL})
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatusWarn, checks[0].Status)
+	assert.Contains(t, checks[0].Hint, "ollama serve")
+}
+
+func TestCheckConfig(t *testing.T) {
+	configDir := t.TempDir()
+	existing := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "projects.json"), []byte(fmt.Sprintf(
+		`[{"name":"web","path":%q,"ai_model":"claude_code"},{"name":"old","path":"/no/such/dir","ai_model":"claude_code"},{"name":"odd","path":%q,"ai_model":"cursor"}]`,
+		existing, existing)), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "queue"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "queue", "queue.json"), []byte(`{"jobs": [`), 0644))
+
+	checks := checkConfig(context.Background(), Options{ConfigDir: configDir})
+	assert.Equal(t, StatusPass, findCheck(t, checks, "projects.json").Status)
+	assert.Contains(t, findCheck(t, checks, "projects.json").Message, "schema_version 1, migrated with a backup on next start")
+	assert.Equal(t, StatusFail, findCheck(t, checks, "queue/queue.json").Status)
+	assert.Equal(t, "not created yet", findCheck(t, checks, "schedules.json").Message)
+	assert.Equal(t, StatusPass, findCheck(t, checks, "config.yaml").Status)
+
+	projects := findCheck(t, checks, "projects")
+	assert.Equal(t, StatusWarn, projects.Status)
+	assert.Contains(t, projects.Message, "1 of 3 project paths are missing: old (/no/such/dir)")
+	assert.Contains(t, projects.Message, `unknown AI tool: odd ("cursor")`)
+	assert.Contains(t, projects.Hint, "ai-launcher project rm")
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("server:\n  port: 0\n"), 0644))
+	settings := checkSettings(configDir)
+	assert.Equal(t, StatusFail, settings.Status)
+	assert.Contains(t, settings.Message, "server.port: must be between 1 and 65535")
+}
+
+func TestCheckConfigDir(t *testing.T) {
+	checks := checkConfigDir(context.Background(), Options{ConfigDir: filepath.Join(t.TempDir(), ".ai-launcher")})
+	assert.Equal(t, StatusPass, checks[0].Status)
+
+	// 配置目錄的上級是文件時無法創建
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+	checks = checkConfigDir(context.Background(), Options{ConfigDir: filepath.Join(file, ".ai-launcher")})
+	assert.Equal(t, StatusFail, checks[0].Status)
+	assert.NotEmpty(t, checks[0].Hint)
+}
+
+func TestCheckOrphans(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("orphan detection is not supported on Windows")
+	}
+	original := listProcesses
+	defer func() { listProcesses = original }()
+
+	listProcesses = func(context.Context) ([]process, error) {
+		return []process{
+			{PID: 10, PPID: 1, Command: "/usr/bin/node /usr/local/bin/claude --resume abc"},
+			{PID: 11, PPID: 1, Command: "/usr/sbin/sshd -D"},
+			{PID: 12, PPID: 500, Command: "gemini --yolo"},
+			{PID: 13, PPID: 1, Command: "aider --yes"},
+		}, nil
+	}
+	checks := checkOrphans(context.Background(), Options{})
+	assert.Equal(t, StatusWarn, checks[0].Status)
+	assert.True(t, strings.HasPrefix(checks[0].Message, "2 orphaned AI tool processes: 10 /usr/bin/node /usr/local/bin/claude"), checks[0].Message)
+	assert.Contains(t, checks[0].Message, "13 aider --yes")
+
+	listProcesses = func(context.Context) ([]process, error) { return nil, nil }
+	checks = checkOrphans(context.Background(), Options{})
+	assert.Equal(t, StatusPass, checks[0].Status)
+}
+
+func TestCheckFont_FyneFontEnv(t *testing.T) {
+	font := filepath.Join(t.TempDir(), "font.ttf")
+	require.NoError(t, os.WriteFile(font, nil, 0644))
+	t.Setenv("FYNE_FONT", font)
+	assert.Equal(t, StatusPass, checkFont(context.Background(), Options{})[0].Status)
+
+	t.Setenv("FYNE_FONT", filepath.Join(t.TempDir(), "missing.ttf"))
+	assert.Equal(t, StatusFail, checkFont(context.Background(), Options{})[0].Status)
+}
+
+func TestReport(t *testing.T) {
+	report := &Report{}
+	report.add(Check{Category: "tools", Name: "claude", Status: StatusPass, Message: "1.0.0"})
+	report.add(Check{Category: "tools", Name: "gemini", Status: StatusFail, Message: "not found", Hint: "install it\ncheck PATH"})
+	report.add(Check{Category: "ollama", Name: "service", Status: StatusWarn, Message: "down"})
+
+	assert.Equal(t, "1 passed, 1 warnings, 1 failed", report.Summary())
+	details := report.Details()
+	assert.Contains(t, details, "tools:\n  [PASS] claude           1.0.0\n  [FAIL] gemini           not found\n         -> install it\n         -> check PATH\n")
+	assert.Contains(t, details, "\nollama:\n  [WARN] service          down\n")
+	assert.True(t, strings.HasSuffix(details, "\n1 passed, 1 warnings, 1 failed"))
+}
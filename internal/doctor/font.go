@@ -0,0 +1,104 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// cjkFontCandidates 各平台常見的中日韓字體；Windows 的順序與 gui.EnsureCJKFont 一致，TTF 優先
+var cjkFontCandidates = map[string][]string{
+	"windows": {
+		`C:\Windows\Fonts\simhei.ttf`,
+		`C:\Windows\Fonts\msyh.ttf`,
+		`C:\Windows\Fonts\msyhbd.ttf`,
+		`C:\Windows\Fonts\Microsoft YaHei UI.ttf`,
+		`C:\Windows\Fonts\Deng.ttf`,
+		`C:\Windows\Fonts\Dengb.ttf`,
+		`C:\Windows\Fonts\simsun.ttc`,
+		`C:\Windows\Fonts\msyh.ttc`,
+		`C:\Windows\Fonts\SourceHanSansCN-Regular.otf`,
+	},
+	"darwin": {
+		"/System/Library/Fonts/PingFang.ttc",
+		"/System/Library/Fonts/STHeiti Medium.ttc",
+		"/System/Library/Fonts/Hiragino Sans GB.ttc",
+		"/Library/Fonts/Arial Unicode.ttf",
+	},
+	"linux": {
+		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+		"/usr/share/fonts/truetype/wqy/wqy-zenhei.ttc",
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+	},
+}
+
+// findCJKFont 返回找到的中日韓字體路徑
+func findCJKFont(ctx context.Context) string {
+	for _, path := range cjkFontCandidates[runtime.GOOS] {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	// 其他 Linux 發行版的字體位置各不相同，交給 fontconfig 查詢
+	if _, err := exec.LookPath("fc-list"); err == nil {
+		out, err := exec.CommandContext(ctx, "fc-list", ":lang=zh", "file").Output()
+		if err == nil {
+			for _, line := range strings.Split(string(out), "\n") {
+				if line = strings.TrimSuffix(strings.TrimSpace(line), ":"); line != "" {
+					return line
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// checkFont 檢查圖形界面顯示中文所需的字體；只影響圖形界面，缺失時為警告
+func checkFont(ctx context.Context, opts Options) []Check {
+	c := Check{Category: "fonts", Name: "cjk", Status: StatusPass}
+
+	if env := os.Getenv("FYNE_FONT"); env != "" {
+		if _, err := os.Stat(env); err != nil {
+			c.Status = StatusFail
+			c.Message = fmt.Sprintf("FYNE_FONT points to a missing file: %s", env)
+			c.Hint = "set FYNE_FONT to an existing .ttf or .otf font, or unset it to use the detected font"
+			return []Check{c}
+		}
+		c.Message = fmt.Sprintf("FYNE_FONT=%s", env)
+		return []Check{c}
+	}
+
+	if path := findCJKFont(ctx); path != "" {
+		// 圖形界面只在 Windows 上自動選擇字體，其他平台需要通過 FYNE_FONT 指定
+		if runtime.GOOS == "windows" {
+			c.Message = fmt.Sprintf("%s (selected by the GUI automatically)", path)
+			return []Check{c}
+		}
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("found %s, but the GUI only uses it when FYNE_FONT is set", path)
+		c.Hint = fmt.Sprintf("export FYNE_FONT=%q", path)
+		if strings.EqualFold(filepath.Ext(path), ".ttc") {
+			c.Hint += "\n.ttc collections may fail to load; prefer a .ttf or .otf font if one is installed"
+		}
+		return []Check{c}
+	}
+
+	c.Status = StatusWarn
+	c.Message = "no CJK font found; Chinese text in the GUI may render as boxes"
+	switch runtime.GOOS {
+	case "windows":
+		c.Hint = `set FYNE_FONT to a CJK font such as C:\Windows\Fonts\simhei.ttf`
+	case "darwin":
+		c.Hint = "set FYNE_FONT to a CJK font such as /System/Library/Fonts/PingFang.ttc"
+	default:
+		c.Hint = "install fonts-noto-cjk (Debian/Ubuntu) or google-noto-sans-cjk-fonts (Fedora), or set FYNE_FONT to a CJK .ttf/.otf"
+	}
+	return []Check{c}
+}
@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"ai-launcher/internal/project"
+)
+
+// process 系統進程的簡要信息
+type process struct {
+	PID     int
+	PPID    int
+	Command string // 完整命令行
+}
+
+// listProcesses 列出系統進程；測試中可替換
+var listProcesses = func(ctx context.Context) ([]process, error) {
+	out, err := exec.CommandContext(ctx, "ps", "-eo", "pid=,ppid=,args=").Output()
+	if err != nil {
+		return nil, err
+	}
+	var procs []process
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		procs = append(procs, process{PID: pid, PPID: ppid, Command: strings.Join(fields[2:], " ")})
+	}
+	return procs, nil
+}
+
+// checkOrphans 查找父進程已退出（被 init 收養）的 AI 工具進程，
+// 通常是啟動器崩潰或強制退出後遺留的會話
+func checkOrphans(ctx context.Context, opts Options) []Check {
+	c := Check{Category: "processes", Name: "orphans", Status: StatusPass}
+	if runtime.GOOS == "windows" {
+		c.Message = "not checked on Windows"
+		return []Check{c}
+	}
+
+	procs, err := listProcesses(ctx)
+	if err != nil {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("failed to list processes: %v", err)
+		c.Hint = "make sure `ps` is available"
+		return []Check{c}
+	}
+
+	var orphans []string
+	for _, p := range procs {
+		if p.PPID == 1 && p.PID != os.Getpid() && isToolProcess(p.Command) {
+			orphans = append(orphans, fmt.Sprintf("%d %s", p.PID, truncate(p.Command, 60)))
+		}
+	}
+	if len(orphans) == 0 {
+		c.Message = "no orphaned AI tool processes"
+		return []Check{c}
+	}
+	c.Status = StatusWarn
+	c.Message = fmt.Sprintf("%d orphaned AI tool processes: %s", len(orphans), strings.Join(orphans, "; "))
+	c.Hint = "stop them with `kill <pid>` if they are not sessions you started by hand"
+	return []Check{c}
+}
+
+// isToolProcess 判斷命令行是否為 AI 工具；npm 安裝的工具通過 node 運行，因此同時檢查第二個參數
+func isToolProcess(command string) bool {
+	fields := strings.Fields(command)
+	for i := 0; i < len(fields) && i < 2; i++ {
+		name := strings.TrimSuffix(filepath.Base(fields[i]), ".exe")
+		for _, model := range project.NewConfigManager().GetAvailableModels() {
+			if name == model.GetCommand(false)[0] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+// Package fsutil 提供跨進程共享的狀態文件所需的文件鎖與原子寫入
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockTimeout 等待文件鎖的最長時間
+const LockTimeout = 5 * time.Second
+
+// errLocked 文件鎖正被其他進程或文件句柄持有
+var errLocked = errors.New("file is locked")
+
+// WithLock 持有 path+".lock" 的排他文件鎖執行 fn。鎖由操作系統維護（Unix 上為 flock，
+// Windows 上為 LockFileEx），持有者退出或崩潰時自動釋放，因此鎖文件本身保留在磁盤上，
+// 也不需要按時間判斷殘留的鎖
+func WithLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	lockPath := path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	defer f.Close()
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			return fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	defer unlock(f)
+
+	return fn()
+}
+
+// WriteFileAtomic 寫入同目錄下的臨時文件並同步到磁盤後再重命名，
+// 讀取方不會看到寫了一半的內容，崩潰後也不會留下被截斷的文件
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir 同步目錄項，使重命名在斷電後仍然有效；部分平台（如 Windows）不支持，忽略錯誤
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	d.Close()
+}
@@ -0,0 +1,101 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLock_Serializes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	require.NoError(t, os.WriteFile(path, []byte("0"), 0600))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithLock(path, func() error {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				n, _ := strconv.Atoi(string(data))
+				return WriteFileAtomic(path, []byte(strconv.Itoa(n+1)), 0600)
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "20", string(data))
+}
+
+func TestWithLock_IgnoresLeftoverLockFile(t *testing.T) {
+	// 崩潰的進程留下的鎖文件不持有鎖，不影響後續加鎖
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path+".lock", nil, 0600))
+
+	called := false
+	require.NoError(t, WithLock(path, func() error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+}
+
+func TestWithLock_WaitsForHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	held := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_ = WithLock(path, func() error {
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+
+	// 持有時間再長也不會被其他等待者搶走
+	acquired := make(chan struct{})
+	go func() {
+		_ = WithLock(path, func() error {
+			close(acquired)
+			return nil
+		})
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while still held")
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-acquired:
+	case <-time.After(LockTimeout):
+		t.Fatal("lock not acquired after release")
+	}
+}
+
+func TestWriteFileAtomic_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "file.json")
+	require.NoError(t, WriteFileAtomic(path, []byte("{}"), 0644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+
+	// 不留下臨時文件
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,212 @@
+// Package gates 在 AI 會話結束或空閒時運行項目的質量門禁（測試、lint、構建），
+// 結果記錄到 .addp/gates/reports，失敗時可作為後續提示詞反饋給同一會話
+package gates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"ai-launcher/internal/addp"
+	"ai-launcher/internal/project/detect"
+)
+
+const (
+	DefaultGateTimeout = 10 * time.Minute // 單個門禁命令的默認超時
+	DefaultIdleAfter   = time.Minute      // 輸出停止多久後視為會話空閒
+)
+
+// Trigger 觸發門禁的時機
+type Trigger string
+
+const (
+	TriggerExit   Trigger = "exit"   // 會話退出
+	TriggerIdle   Trigger = "idle"   // 會話空閒
+	TriggerBoth   Trigger = "both"   // 兩者皆可
+	TriggerManual Trigger = "manual" // 手動運行
+)
+
+// Source 門禁定義的來源
+type Source string
+
+const (
+	SourceFile     Source = "file"     // 來自 .ai-launcher/gates.yaml
+	SourceManifest Source = "manifest" // 來自項目清單 .ai-launcher.yaml 的 launch.gates
+	SourceDetected Source = "detected" // 根據 go.mod、package.json 等檢測
+)
+
+// Gate 單個門禁命令，通過系統 shell 執行
+type Gate struct {
+	Name    string        `yaml:"name" json:"name"`
+	Command string        `yaml:"command" json:"command"`
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Config 項目的門禁配置 .ai-launcher/gates.yaml
+type Config struct {
+	Enabled   *bool         `yaml:"enabled,omitempty" json:"enabled,omitempty"` // 是否自動運行，未設置時見 AutoRun
+	Trigger   Trigger       `yaml:"trigger,omitempty" json:"trigger,omitempty"`
+	IdleAfter time.Duration `yaml:"idle_after,omitempty" json:"idle_after,omitempty"`
+	Feedback  bool          `yaml:"feedback,omitempty" json:"feedback,omitempty"` // 失敗時把結果發回會話
+	Gates     []Gate        `yaml:"gates" json:"gates"`
+
+	Source    Source `yaml:"-" json:"source"`
+	Untrusted bool   `yaml:"-" json:"untrusted,omitempty"` // gates.yaml 或項目清單中的門禁未經批准，已被忽略
+}
+
+// TrustFunc 檢查用戶是否批准了項目清單中需要信任的設置，如 project.ConfigManager.ManifestTrusted
+type TrustFunc func(projectPath string) bool
+
+// ConfigPath 返回項目門禁配置文件的路徑
+func ConfigPath(projectPath string) string {
+	return filepath.Join(projectPath, ".ai-launcher", "gates.yaml")
+}
+
+// ManifestPath 返回項目清單的路徑，清單的 launch.gates 段與 gates.yaml 格式相同
+func ManifestPath(projectPath string) string {
+	return filepath.Join(projectPath, ".ai-launcher.yaml")
+}
+
+// LoadConfig 讀取項目門禁配置，gates.yaml 優先於項目清單；都沒有時根據項目文件檢測門禁。
+// gates.yaml 與項目清單都可隨倉庫提交，其中的門禁命令只在 trusted 返回 true 時使用，trusted 為 nil 時視為未批准；
+// 未批准的 gates.yaml 只保留關閉自動運行的設置
+func LoadConfig(projectPath string, trusted TrustFunc) (*Config, error) {
+	cfg := &Config{Source: SourceDetected}
+
+	file, err := LoadFile(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case file != nil && (trusted == nil || !trusted(projectPath)):
+		cfg.Untrusted = true
+		if file.Enabled != nil && !*file.Enabled {
+			cfg.Enabled = file.Enabled
+		}
+	case file != nil:
+		cfg = file
+	default:
+		manifest, err := loadManifestGates(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case manifest == nil:
+		case trusted == nil || !trusted(projectPath):
+			cfg.Untrusted = true
+		default:
+			cfg = manifest
+			cfg.Source = SourceManifest
+		}
+	}
+
+	if cfg.Source == SourceDetected || len(cfg.Gates) == 0 {
+		cfg.Gates = Detect(projectPath)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadFile 讀取並校驗 .ai-launcher/gates.yaml，文件不存在時返回 nil
+func LoadFile(projectPath string) (*Config, error) {
+	data, err := os.ReadFile(ConfigPath(projectPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gates.yaml: %w", err)
+	}
+
+	cfg := &Config{Source: SourceFile}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse gates.yaml: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadManifestGates 讀取項目清單中的門禁；其餘字段由 project.LoadManifest 校驗
+func loadManifestGates(projectPath string) (*Config, error) {
+	data, err := os.ReadFile(ManifestPath(projectPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .ai-launcher.yaml: %w", err)
+	}
+	var manifest struct {
+		Launch struct {
+			Gates *Config `yaml:"gates"`
+		} `yaml:"launch"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse .ai-launcher.yaml: %w", err)
+	}
+	return manifest.Launch.Gates, nil
+}
+
+// Validate 檢查配置，錯誤信息指出出錯的門禁
+func (c *Config) Validate() error {
+	switch c.Trigger {
+	case "", TriggerExit, TriggerIdle, TriggerBoth:
+	default:
+		return fmt.Errorf("invalid trigger %q (exit, idle or both)", c.Trigger)
+	}
+	for i, g := range c.Gates {
+		if g.Command == "" {
+			return fmt.Errorf("gates[%d] %s: command is required", i, g.Name)
+		}
+	}
+	return nil
+}
+
+// AutoRun 是否在會話結束或空閒時自動運行：未顯式設置時，有配置文件、項目清單或已初始化 ADDP 的項目默認開啟
+func (c *Config) AutoRun(projectPath string) bool {
+	if len(c.Gates) == 0 {
+		return false
+	}
+	if c.Enabled != nil {
+		return *c.Enabled
+	}
+	return c.Source == SourceFile || c.Source == SourceManifest || addp.Initialized(projectPath)
+}
+
+// RunsOn 檢查配置是否在指定時機運行
+func (c *Config) RunsOn(t Trigger) bool {
+	return c.Trigger == "" || c.Trigger == TriggerBoth || c.Trigger == t
+}
+
+// IdleDuration 返回空閒判定時間
+func (c *Config) IdleDuration() time.Duration {
+	if c.IdleAfter > 0 {
+		return c.IdleAfter
+	}
+	return DefaultIdleAfter
+}
+
+// Detect 根據項目環境檢測的命令推斷門禁，如 Go 的 build/vet/test、package.json 中已定義的腳本。
+// Python 的命令只在沒有其他語言的門禁時使用，混合項目中的 Python 文件多為輔助腳本
+func Detect(projectPath string) []Gate {
+	var gates, python []Gate
+	for _, c := range detect.Detect(projectPath).Commands {
+		if c.Source == "python" {
+			python = append(python, Gate{Name: c.Name, Command: c.Command})
+		} else {
+			gates = append(gates, Gate{Name: c.Name, Command: c.Command})
+		}
+	}
+	if len(gates) == 0 {
+		return python
+	}
+	return gates
+}
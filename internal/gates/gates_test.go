@@ -0,0 +1,294 @@
+package gates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/terminal"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLoadConfig_Detect(t *testing.T) {
+	goProject := t.TempDir()
+	writeFile(t, goProject, "go.mod", "module demo\n")
+	cfg, err := LoadConfig(goProject, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SourceDetected, cfg.Source)
+	assert.Equal(t, []Gate{
+		{Name: "build", Command: "go build ./..."},
+		{Name: "vet", Command: "go vet ./..."},
+		{Name: "test", Command: "go test ./..."},
+	}, cfg.Gates)
+	assert.False(t, cfg.AutoRun(goProject), "detected gates only run automatically in ADDP projects")
+
+	require.NoError(t, os.Mkdir(filepath.Join(goProject, ".addp"), 0755))
+	assert.True(t, cfg.AutoRun(goProject))
+
+	node := t.TempDir()
+	writeFile(t, node, "package.json", `{"scripts":{"test":"echo \"Error: no test specified\" && exit 1","build":"tsc","lint":"eslint ."}}`)
+	cfg, err = LoadConfig(node, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Gate{
+		{Name: "lint", Command: "npm run lint"},
+		{Name: "build", Command: "npm run build"},
+	}, cfg.Gates)
+
+	python := t.TempDir()
+	writeFile(t, python, "pyproject.toml", "[tool.pytest.ini_options]\n")
+	cfg, err = LoadConfig(python, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Gate{{Name: "test", Command: "pytest"}}, cfg.Gates)
+
+	// 混合項目中只使用其他語言的門禁
+	writeFile(t, python, "go.mod", "module demo\n")
+	cfg, err = LoadConfig(python, nil)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Gates, 3)
+	assert.Equal(t, "go build ./...", cfg.Gates[0].Command)
+
+	empty := t.TempDir()
+	cfg, err = LoadConfig(empty, nil)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Gates)
+	assert.False(t, cfg.AutoRun(empty))
+}
+
+func TestLoadConfig_File(t *testing.T) {
+	project := t.TempDir()
+	writeFile(t, project, "go.mod", "module demo\n")
+	writeFile(t, project, ".ai-launcher/gates.yaml", `
+trigger: idle
+idle_after: 30s
+feedback: true
+gates:
+  - name: lint
+    command: golangci-lint run
+    timeout: 2m
+`)
+
+	// gates.yaml 可隨倉庫提交，未批准時忽略其中的門禁
+	cfg, err := LoadConfig(project, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SourceDetected, cfg.Source)
+	assert.True(t, cfg.Untrusted)
+	assert.Len(t, cfg.Gates, 3)
+	assert.False(t, cfg.AutoRun(project))
+
+	trusted := func(string) bool { return true }
+	cfg, err = LoadConfig(project, trusted)
+	require.NoError(t, err)
+	assert.Equal(t, SourceFile, cfg.Source)
+	assert.Equal(t, []Gate{{Name: "lint", Command: "golangci-lint run", Timeout: 2 * time.Minute}}, cfg.Gates)
+	assert.Equal(t, 30*time.Second, cfg.IdleDuration())
+	assert.True(t, cfg.Feedback)
+	assert.True(t, cfg.AutoRun(project))
+	assert.True(t, cfg.RunsOn(TriggerIdle))
+	assert.False(t, cfg.RunsOn(TriggerExit))
+
+	// 只設置選項時門禁仍然自動檢測
+	writeFile(t, project, ".ai-launcher/gates.yaml", "enabled: false\n")
+	cfg, err = LoadConfig(project, trusted)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Gates, 3)
+	assert.False(t, cfg.AutoRun(project))
+
+	// 未批准時關閉自動運行的設置仍然生效
+	require.NoError(t, os.Mkdir(filepath.Join(project, ".addp"), 0755))
+	cfg, err = LoadConfig(project, nil)
+	require.NoError(t, err)
+	assert.False(t, cfg.AutoRun(project))
+	writeFile(t, project, ".ai-launcher/gates.yaml", "enabled: true\n")
+	cfg, err = LoadConfig(project, nil)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Enabled)
+
+	writeFile(t, project, ".ai-launcher/gates.yaml", "gate: []\n")
+	_, err = LoadConfig(project, nil)
+	assert.Error(t, err)
+
+	writeFile(t, project, ".ai-launcher/gates.yaml", "trigger: sometimes\n")
+	_, err = LoadConfig(project, nil)
+	assert.ErrorContains(t, err, "invalid trigger")
+
+	writeFile(t, project, ".ai-launcher/gates.yaml", "gates:\n  - name: lint\n")
+	_, err = LoadConfig(project, nil)
+	assert.ErrorContains(t, err, "gates[0] lint: command is required")
+}
+
+func TestLoadConfig_Manifest(t *testing.T) {
+	project := t.TempDir()
+	writeFile(t, project, ".ai-launcher.yaml", `
+ollama:
+  default_model: llama3
+launch:
+  tool: codex
+  gates:
+    trigger: exit
+    gates:
+      - name: test
+        command: make test
+`)
+
+	// 未批准的清單門禁被忽略
+	cfg, err := LoadConfig(project, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SourceDetected, cfg.Source)
+	assert.True(t, cfg.Untrusted)
+	assert.Empty(t, cfg.Gates)
+	assert.False(t, cfg.AutoRun(project))
+	cfg, err = LoadConfig(project, func(string) bool { return false })
+	require.NoError(t, err)
+	assert.True(t, cfg.Untrusted)
+
+	trusted := func(path string) bool { return path == project }
+	cfg, err = LoadConfig(project, trusted)
+	require.NoError(t, err)
+	assert.False(t, cfg.Untrusted)
+	assert.Equal(t, SourceManifest, cfg.Source)
+	assert.Equal(t, []Gate{{Name: "test", Command: "make test"}}, cfg.Gates)
+	assert.True(t, cfg.AutoRun(project))
+	assert.False(t, cfg.RunsOn(TriggerIdle))
+
+	// gates.yaml 優先於項目清單
+	writeFile(t, project, ".ai-launcher/gates.yaml", "gates:\n  - name: lint\n    command: make lint\n")
+	cfg, err = LoadConfig(project, trusted)
+	require.NoError(t, err)
+	assert.Equal(t, SourceFile, cfg.Source)
+	assert.Equal(t, "make lint", cfg.Gates[0].Command)
+}
+
+func TestRun(t *testing.T) {
+	project := t.TempDir()
+	writeFile(t, project, "marker", "here")
+
+	cfg := &Config{Gates: []Gate{
+		{Name: "pass", Command: "cat marker"},
+		{Name: "fail", Command: "echo 'main.go:3: undefined: x'; exit 3"},
+		{Name: "slow", Command: "sleep 5", Timeout: 100 * time.Millisecond},
+	}}
+	report := Run(context.Background(), project, cfg, TriggerManual)
+
+	require.Len(t, report.Results, 3)
+	assert.False(t, report.Passed)
+	assert.True(t, report.Results[0].Passed)
+	assert.Equal(t, "here", report.Results[0].Output)
+	assert.Equal(t, 3, report.Results[1].ExitCode)
+	assert.Equal(t, "main.go:3: undefined: x", report.Results[1].Output)
+	assert.True(t, report.Results[2].TimedOut)
+	assert.Less(t, report.Results[2].Duration, 3*time.Second)
+
+	assert.Equal(t, "gates failed: fail, slow (1/3 passed)", report.Summary())
+	assert.Contains(t, report.Details(), "[PASS] cat marker")
+	assert.Contains(t, report.Details(), "    main.go:3: undefined: x")
+
+	prompt := report.FeedbackPrompt()
+	assert.True(t, strings.HasPrefix(prompt, "[Quality gates failed: 2 of 3]"))
+	assert.Contains(t, prompt, "$ echo 'main.go:3: undefined: x'; exit 3\n(exit status 3)\nmain.go:3: undefined: x")
+	assert.Contains(t, prompt, "(timed out)")
+	assert.NotContains(t, prompt, "cat marker")
+
+	passed := Run(context.Background(), project, &Config{Gates: cfg.Gates[:1]}, TriggerManual)
+	assert.True(t, passed.Passed)
+	assert.Empty(t, passed.FeedbackPrompt())
+}
+
+func TestTail(t *testing.T) {
+	var lines []string
+	for i := 0; i < outputTailLines+10; i++ {
+		lines = append(lines, "line")
+	}
+	lines = append(lines, "last")
+	out := tail(strings.Join(lines, "\n") + "\n")
+	assert.True(t, strings.HasPrefix(out, "...\n"))
+	assert.True(t, strings.HasSuffix(out, "\nlast"))
+	assert.Len(t, strings.Split(out, "\n"), outputTailLines+1)
+}
+
+func TestReports(t *testing.T) {
+	project := t.TempDir()
+	reports, err := Reports(project, 0)
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+
+	// 未初始化 ADDP 的項目不創建 .addp
+	assert.ErrorIs(t, (&Report{Project: project, StartedAt: time.Now()}).Save(), ErrNotInitialized)
+	assert.NoDirExists(t, filepath.Join(project, ".addp"))
+	require.NoError(t, os.Mkdir(filepath.Join(project, ".addp"), 0755))
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	for i, trigger := range []Trigger{TriggerExit, TriggerIdle, TriggerManual} {
+		r := &Report{Project: project, Trigger: trigger, StartedAt: start.Add(time.Duration(i) * time.Minute), Passed: i != 1}
+		require.NoError(t, r.Save())
+		assert.FileExists(t, r.Path)
+	}
+	assert.FileExists(t, filepath.Join(project, ".addp", "gates", "reports", "20260102_030405.000_exit.json"))
+
+	reports, err = Reports(project, 2)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, TriggerManual, reports[0].Trigger)
+	assert.False(t, reports[1].Passed)
+
+	latest, err := Latest(project)
+	require.NoError(t, err)
+	assert.Equal(t, TriggerManual, latest.Trigger)
+}
+
+func TestWatch_IdleFeedbackAndExit(t *testing.T) {
+	old := pollInterval
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = old }()
+
+	project := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(project, ".addp"), 0755))
+	tm := terminal.NewTerminalManager()
+	require.NoError(t, tm.StartTerminal(terminal.TerminalConfig{
+		Type:       terminal.TypeCustom,
+		Name:       "session",
+		WorkingDir: project,
+		Command:    []string{"sh", "-c", `echo working; read line; echo "got: $line"`, "sh"},
+	}))
+	defer tm.RemoveTerminal("session")
+
+	cfg := &Config{
+		IdleAfter: 100 * time.Millisecond,
+		Feedback:  true,
+		Gates:     []Gate{{Name: "test", Command: "echo FAIL: TestX; exit 1"}},
+	}
+
+	var mu sync.Mutex
+	var triggers []Trigger
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	Watch(ctx, tm, "session", project, cfg, func(r *Report, err error) {
+		assert.NoError(t, err)
+		assert.Equal(t, "session", r.Session)
+		mu.Lock()
+		triggers = append(triggers, r.Trigger)
+		mu.Unlock()
+	})
+
+	assert.Equal(t, []Trigger{TriggerIdle, TriggerExit}, triggers)
+
+	term, ok := tm.GetTerminal("session")
+	require.True(t, ok)
+	assert.Contains(t, term.Output(), "got: [Quality gates failed: 1 of 1]")
+
+	reports, err := Reports(project, 0)
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+}
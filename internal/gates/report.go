@@ -0,0 +1,89 @@
+package gates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"ai-launcher/internal/addp"
+	"ai-launcher/internal/fsutil"
+)
+
+const reportLayout = "20060102_150405.000" // 報告文件名中的時間戳，按字典序即按時間排序
+
+// ErrNotInitialized 項目沒有 .addp 目錄時不保存報告，避免隱式地把項目變成 ADDP 項目
+var ErrNotInitialized = errors.New("project has no .addp directory, gate report not saved")
+
+// ReportsDir 返回項目門禁報告目錄
+func ReportsDir(projectPath string) string {
+	return filepath.Join(projectPath, addp.DirName, "gates", "reports")
+}
+
+// Save 將報告寫入 .addp/gates/reports/<時間戳>_<觸發方式>.json；項目未初始化 ADDP 時返回 ErrNotInitialized
+func (r *Report) Save() error {
+	if !addp.Initialized(r.Project) {
+		return ErrNotInitialized
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode gate report: %w", err)
+	}
+	name := fmt.Sprintf("%s_%s.json", r.StartedAt.Format(reportLayout), r.Trigger)
+	path := filepath.Join(ReportsDir(r.Project), name)
+	if err := fsutil.WriteFileAtomic(path, data, 0644); err != nil {
+		return err
+	}
+	r.Path = path
+	return nil
+}
+
+// Reports 返回最近的門禁報告，最新的在前；limit <= 0 時返回全部
+func Reports(projectPath string, limit int) ([]*Report, error) {
+	entries, err := os.ReadDir(ReportsDir(projectPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gate reports: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+
+	reports := make([]*Report, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(ReportsDir(projectPath), name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gate report %s: %w", name, err)
+		}
+		var r Report
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse gate report %s: %w", name, err)
+		}
+		r.Path = path
+		reports = append(reports, &r)
+	}
+	return reports, nil
+}
+
+// Latest 返回最近一次門禁報告，沒有報告時返回 nil
+func Latest(projectPath string) (*Report, error) {
+	reports, err := Reports(projectPath, 1)
+	if err != nil || len(reports) == 0 {
+		return nil, err
+	}
+	return reports[0], nil
+}
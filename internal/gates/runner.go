@@ -0,0 +1,219 @@
+package gates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	outputTailLines = 60    // 報告保留的輸出行數
+	outputMaxChars  = 8000  // 報告保留的輸出長度
+	feedbackMaxRune = 12000 // 反饋提示詞的最大長度
+)
+
+// Result 單個門禁的運行結果
+type Result struct {
+	Name     string        `json:"name"`
+	Command  string        `json:"command"`
+	Passed   bool          `json:"passed"`
+	ExitCode int           `json:"exit_code"`
+	TimedOut bool          `json:"timed_out,omitempty"`
+	Error    string        `json:"error,omitempty"` // 命令無法啟動等非退出碼錯誤
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output,omitempty"` // 輸出結尾
+}
+
+// Report 一次門禁運行的報告
+type Report struct {
+	Project   string        `json:"project"`
+	Trigger   Trigger       `json:"trigger"`
+	Session   string        `json:"session,omitempty"` // 觸發門禁的終端名稱
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Passed    bool          `json:"passed"`
+	Results   []Result      `json:"results"`
+
+	Path string `json:"-"` // 報告文件路徑，保存後設置
+}
+
+// Failed 返回未通過的門禁
+func (r *Report) Failed() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Run 在項目目錄中依次運行所有門禁；單個門禁失敗不影響後續門禁運行
+func Run(ctx context.Context, projectPath string, cfg *Config, trigger Trigger) *Report {
+	report := &Report{
+		Project:   projectPath,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+		Passed:    true,
+	}
+	for _, gate := range cfg.Gates {
+		if ctx.Err() != nil {
+			break
+		}
+		res := runGate(ctx, projectPath, gate)
+		report.Results = append(report.Results, res)
+		if !res.Passed {
+			report.Passed = false
+		}
+	}
+	report.Duration = time.Since(report.StartedAt)
+	return report
+}
+
+// runGate 通過系統 shell 運行單個門禁命令
+func runGate(ctx context.Context, projectPath string, gate Gate) Result {
+	timeout := gate.Timeout
+	if timeout <= 0 {
+		timeout = DefaultGateTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name, args := shellCommand(gate.Command)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = projectPath
+	cmd.WaitDelay = time.Second // 子進程繼承輸出管道時不無限等待
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	start := time.Now()
+	err := cmd.Run()
+	res := Result{
+		Name:     gate.Name,
+		Command:  gate.Command,
+		Duration: time.Since(start),
+		Output:   tail(out.String()),
+	}
+	if res.Name == "" {
+		res.Name = gate.Command
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		res.TimedOut = true
+		res.ExitCode = -1
+		res.Error = fmt.Sprintf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		res.ExitCode = -1
+		res.Error = err.Error()
+	default:
+		res.Passed = true
+	}
+	return res
+}
+
+// shellCommand 返回運行命令字串的 shell 調用
+func shellCommand(command string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
+
+// tail 保留輸出結尾的若干行
+func tail(output string) string {
+	output = strings.TrimRight(output, "\r\n\t ")
+	lines := strings.Split(output, "\n")
+	if len(lines) > outputTailLines {
+		lines = lines[len(lines)-outputTailLines:]
+		output = "...\n" + strings.Join(lines, "\n")
+	}
+	if r := []rune(output); len(r) > outputMaxChars {
+		output = "..." + string(r[len(r)-outputMaxChars:])
+	}
+	return output
+}
+
+// Summary 單行摘要，例如 "gates passed (3/3)" 或 "gates failed: test, vet (1/3)"
+func (r *Report) Summary() string {
+	failed := r.Failed()
+	passed := len(r.Results) - len(failed)
+	if len(failed) == 0 {
+		return fmt.Sprintf("gates passed (%d/%d) in %s", passed, len(r.Results), r.Duration.Round(time.Millisecond))
+	}
+	names := make([]string, len(failed))
+	for i, res := range failed {
+		names[i] = res.Name
+	}
+	return fmt.Sprintf("gates failed: %s (%d/%d passed)", strings.Join(names, ", "), passed, len(r.Results))
+}
+
+// Details 多行文本報告：每個門禁一行，失敗的門禁附帶輸出結尾
+func (r *Report) Details() string {
+	var b strings.Builder
+	b.WriteString(r.Summary())
+	b.WriteString("\n")
+	for _, res := range r.Results {
+		mark := "PASS"
+		if !res.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&b, "  [%s] %s (%s)\n", mark, res.Command, res.Duration.Round(time.Millisecond))
+		if res.Passed {
+			continue
+		}
+		if res.Error != "" {
+			fmt.Fprintf(&b, "    %s\n", res.Error)
+		}
+		if res.Output != "" {
+			for _, line := range strings.Split(res.Output, "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// FeedbackPrompt 將失敗的門禁格式化為發回同一會話的後續提示詞；全部通過時返回空字串
+func (r *Report) FeedbackPrompt() string {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Quality gates failed: %d of %d]\n", len(failed), len(r.Results))
+	b.WriteString("The project's quality gates were run after your last changes and some of them failed. Fix the causes, then stop so the gates can run again.\n")
+	for _, res := range failed {
+		fmt.Fprintf(&b, "\n$ %s\n", res.Command)
+		switch {
+		case res.TimedOut:
+			b.WriteString("(timed out)\n")
+		case res.Error != "":
+			fmt.Fprintf(&b, "(%s)\n", res.Error)
+		default:
+			fmt.Fprintf(&b, "(exit status %d)\n", res.ExitCode)
+		}
+		if res.Output != "" {
+			b.WriteString(res.Output)
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("[End of gate results]")
+
+	prompt := b.String()
+	if r := []rune(prompt); len(r) > feedbackMaxRune {
+		prompt = string(r[:feedbackMaxRune]) + "\n...\n[End of gate results]"
+	}
+	return prompt
+}
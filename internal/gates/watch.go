@@ -0,0 +1,74 @@
+package gates
+
+import (
+	"context"
+	"time"
+
+	"ai-launcher/internal/terminal"
+)
+
+// maxFeedbackRounds 連續失敗時自動反饋的最多次數，避免會話與門禁無限往返
+const maxFeedbackRounds = 3
+
+// pollInterval 檢查會話狀態的間隔
+var pollInterval = time.Second
+
+// RunAndSave 運行門禁並保存報告；保存失敗（包括 ErrNotInitialized）時仍返回報告
+func RunAndSave(ctx context.Context, projectPath string, cfg *Config, trigger Trigger, session string) (*Report, error) {
+	report := Run(ctx, projectPath, cfg, trigger)
+	report.Session = session
+	return report, report.Save()
+}
+
+// Watch 監視終端會話，在會話空閒或退出時運行門禁並通過 onReport 回報；
+// 配置了 feedback 時把空閒時的失敗結果作為後續提示詞發回同一會話。會話退出或 ctx 取消後返回
+func Watch(ctx context.Context, tm terminal.Manager, name, projectPath string, cfg *Config, onReport func(*Report, error)) {
+	term, ok := tm.GetTerminal(name)
+	if !ok {
+		return
+	}
+
+	run := func(trigger Trigger) *Report {
+		report, err := RunAndSave(ctx, projectPath, cfg, trigger, name)
+		if onReport != nil {
+			onReport(report, err)
+		}
+		return report
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var lastRun time.Time // 上一次門禁開始的時間；之後有新輸出才會再次運行
+	rounds := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-term.Done():
+			if cfg.RunsOn(TriggerExit) {
+				run(TriggerExit)
+			}
+			return
+		case <-ticker.C:
+			if !cfg.RunsOn(TriggerIdle) {
+				continue
+			}
+			last := term.LastOutputAt()
+			if last.IsZero() || !last.After(lastRun) || time.Since(last) < cfg.IdleDuration() {
+				continue
+			}
+
+			lastRun = time.Now()
+			report := run(TriggerIdle)
+			if report.Passed {
+				rounds = 0
+				continue
+			}
+			if cfg.Feedback && rounds < maxFeedbackRounds && !term.Exited() {
+				rounds++
+				_ = tm.SendCommand(name, report.FeedbackPrompt())
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"ai-launcher/internal/project"
+)
+
+// onDiscoverClicked 扫描 discovery.roots 中的项目并提供批量导入；未配置根目录时先选择一个目录
+func (mw *MainWindow) onDiscoverClicked() {
+	roots := mw.currentSettings().Discovery.RootDirs()
+	if len(roots) > 0 {
+		mw.discoverProjects(roots)
+		return
+	}
+	dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+		if err == nil && uri != nil {
+			mw.discoverProjects([]string{uri.Path()})
+		}
+	}, mw.window)
+}
+
+// discoverProjects 在后台扫描根目录，完成后显示结果
+func (mw *MainWindow) discoverProjects(roots []string) {
+	mw.statusBar.SetMessage(fmt.Sprintf("正在扫描 %s ...", strings.Join(roots, ", ")))
+	settings := mw.currentSettings()
+	opts := project.DiscoverOptions{
+		Roots:    roots,
+		MaxDepth: settings.Discovery.MaxDepth,
+		Ignore:   settings.Discovery.Ignore,
+	}
+	go func() {
+		found, err := mw.projectManager.Discover(opts)
+		if err != nil {
+			mw.statusBar.ShowError(fmt.Sprintf("扫描项目失败: %s", oneLine(err)))
+			return
+		}
+		mw.showDiscovered(found)
+	}()
+}
+
+// showDiscovered 列出新发现的项目，勾选后批量导入
+func (mw *MainWindow) showDiscovered(found []project.DiscoveredProject) {
+	var candidates []project.DiscoveredProject
+	for _, p := range found {
+		if !p.Known {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		mw.statusBar.SetMessage(fmt.Sprintf("找到 %d 个项目，都已在项目列表中", len(found)))
+		return
+	}
+
+	checks := make([]*widget.Check, len(candidates))
+	list := container.NewVBox()
+	for i, p := range candidates {
+		checks[i] = widget.NewCheck(fmt.Sprintf("%s  (%s)  %s", p.Name, strings.Join(p.Markers, ", "), p.Path), nil)
+		checks[i].SetChecked(true)
+		list.Add(checks[i])
+	}
+	selectAll := widget.NewCheck("全选", func(on bool) {
+		for _, c := range checks {
+			c.SetChecked(on)
+		}
+	})
+	selectAll.SetChecked(true)
+
+	content := container.NewBorder(
+		container.NewVBox(widget.NewLabel(fmt.Sprintf("找到 %d 个新项目，勾选要导入的项目：", len(candidates))), selectAll),
+		nil, nil, nil,
+		container.NewVScroll(list),
+	)
+	confirm := dialog.NewCustomConfirm("发现项目", "导入", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		var selected []project.DiscoveredProject
+		for i, c := range checks {
+			if c.Checked {
+				selected = append(selected, candidates[i])
+			}
+		}
+		added, err := mw.projectManager.ImportProjects(selected, "")
+		if err != nil {
+			mw.statusBar.ShowError(fmt.Sprintf("导入项目失败: %s", oneLine(err)))
+			return
+		}
+		mw.projectPanel.Refresh()
+		mw.statusBar.ShowSuccess(fmt.Sprintf("已导入 %d 个项目", len(added)))
+	}, mw.window)
+	confirm.Resize(fyne.NewSize(700, 480))
+	confirm.Show()
+}
+
+// startRescan 按 discovery.rescan_interval 在后台检查项目路径，标记路径已不存在的项目；
+// 配置变化时重新启动，间隔为 0 时停止。启动时与监视 goroutine 中都会调用
+func (mw *MainWindow) startRescan() {
+	mw.settingsMu.Lock()
+	defer mw.settingsMu.Unlock()
+	interval := time.Duration(mw.settings.Discovery.RescanInterval) * time.Minute
+	if mw.stopRescan != nil && interval == mw.rescanInterval {
+		return
+	}
+	if mw.stopRescan != nil {
+		mw.stopRescan()
+		mw.stopRescan = nil
+	}
+	mw.rescanInterval = interval
+	if interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mw.stopRescan = cancel
+	go mw.projectManager.WatchMissing(ctx, interval, func(missing []project.ProjectConfig, err error) {
+		if err != nil {
+			log.Printf("[MainWindow] rescan failed: %v", err)
+			return
+		}
+		mw.projectPanel.Refresh()
+		if len(missing) > 0 {
+			mw.statusBar.ShowWarning(fmt.Sprintf("%d 个项目的路径不存在", len(missing)))
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// historyEntry 支持上下键翻阅历史与 Ctrl+R 反向搜索的输入框
+type historyEntry struct {
+	widget.Entry
+
+	onHistoryUp   func()
+	onHistoryDown func()
+	onSearch      func()
+	onCancel      func()
+}
+
+// newHistoryEntry 创建历史输入框
+func newHistoryEntry() *historyEntry {
+	e := &historyEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedKey 拦截上下键与 Esc，其余按键交给普通输入框处理
+func (e *historyEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		if e.onHistoryUp != nil {
+			e.onHistoryUp()
+			return
+		}
+	case fyne.KeyDown:
+		if e.onHistoryDown != nil {
+			e.onHistoryDown()
+			return
+		}
+	case fyne.KeyEscape:
+		if e.onCancel != nil {
+			e.onCancel()
+			return
+		}
+	}
+	e.Entry.TypedKey(key)
+}
+
+// TypedShortcut 拦截 Ctrl+R 反向搜索
+func (e *historyEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if cs, ok := shortcut.(*desktop.CustomShortcut); ok &&
+		cs.KeyName == fyne.KeyR && cs.Modifier == fyne.KeyModifierControl && e.onSearch != nil {
+		e.onSearch()
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
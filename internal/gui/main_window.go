@@ -1,9 +1,13 @@
 ﻿package gui
 
 import (
+    "context"
     "fmt"
     "log"
     "runtime"
+    "strings"
+    "sync"
+    "time"
 
     "fyne.io/fyne/v2"
     "fyne.io/fyne/v2/app"
@@ -11,7 +15,12 @@ import (
     "fyne.io/fyne/v2/theme"
     "fyne.io/fyne/v2/widget"
 
+    "ai-launcher/internal/config"
+    "ai-launcher/internal/launch"
     "ai-launcher/internal/project"
+    "ai-launcher/internal/queue"
+    "ai-launcher/internal/schedule"
+    "ai-launcher/internal/template"
     "ai-launcher/internal/terminal"
 )
 
@@ -24,6 +33,29 @@ type MainWindow struct {
     projectManager  *project.ConfigManager
     terminalManager *terminal.TerminalManager
 
+    // 任务队列及其工作池
+    taskQueue  *queue.Queue
+    queuePanel *QueuePanel
+    stopQueue  context.CancelFunc
+
+    // 定时任务，到期时投递到任务队列
+    scheduler *schedule.Scheduler
+
+    // 当前生效的配置与模板；文件变化时由 watcher 重新加载
+    settings    *config.Config
+    templates   *template.TemplateManager
+    stopWatcher context.CancelFunc
+
+    // settingsMu 保护 settings 与后台检查状态：配置变化在监视 goroutine 中应用
+    settingsMu sync.RWMutex
+
+    // 后台检查项目路径，间隔取 discovery.rescan_interval
+    stopRescan     context.CancelFunc
+    rescanInterval time.Duration
+
+    // ADDP 工作流阶段面板
+    workflowPanel *WorkflowPanel
+
     // 涓昏 UI 缁勪欢
     menuBar      *fyne.MainMenu
     toolbar      *widget.Toolbar
@@ -63,10 +95,27 @@ func NewMainWindow() *MainWindow {
         }
     }
 
+    terminalManager := terminal.NewTerminalManager()
+    taskQueue := queue.NewQueue(terminalManager)
+    settings := config.Default()
+    if res, err := config.Load(config.Options{}); err != nil {
+        log.Printf("配置无效，使用默认配置:\n%v", err)
+    } else {
+        settings = res.Config
+    }
+    taskQueue.SetMaxConcurrent(settings.Performance.MaxConcurrentTerminals)
+    templates := template.NewTemplateManager()
+    if err := templates.LoadDir(settings.Templates.Dir()); err != nil {
+        log.Printf("加载自定义模板失败: %v", err)
+    }
     return &MainWindow{
         fyneApp:         myApp,
         projectManager:  project.NewConfigManager(),
-        terminalManager: terminal.NewTerminalManager(),
+        terminalManager: terminalManager,
+        taskQueue:       taskQueue,
+        scheduler:       schedule.NewScheduler(taskQueue, templates),
+        settings:        settings,
+        templates:       templates,
         windowState: &WindowState{
             Width:          1200,
             Height:         800,
@@ -101,6 +150,13 @@ func (mw *MainWindow) Run() {
 
     log.Println("窗口创建成功，设置属性...")
     mw.window.SetCloseIntercept(func() {
+        mw.stopQueue()
+        if mw.stopWatcher != nil {
+            mw.stopWatcher()
+        }
+        if mw.stopRescan != nil {
+            mw.stopRescan()
+        }
         mw.saveWindowState()
         mw.fyneApp.Quit()
     })
@@ -113,6 +169,9 @@ func (mw *MainWindow) Run() {
 
     log.Println("鍒濆鍖?UI 缁勪欢...")
     mw.initializeComponents()
+    mw.startQueue()
+    mw.startWatcher()
+    mw.startRescan()
 
     log.Println("鍒涘缓涓诲竷灞€...")
     content := mw.createMainLayout()
@@ -131,9 +190,26 @@ func (mw *MainWindow) initializeComponents() {
     mw.projectPanel = NewProjectHistoryPanel(mw.projectManager, mw.onProjectSelected)
     mw.terminalTabs = NewTerminalTabContainer(mw.terminalManager, func(){ mw.onNewTerminalClicked() })
     mw.statusBar = NewStatusBar()
+    mw.statusBar.SetHealthSource(mw.terminalManager.CheckHealth)
+    mw.statusBar.SetOllamaClient(mw.currentSettings().Ollama.NewClient())
     mw.projectDialog = NewProjectConfigDialog(mw.window, mw.projectManager, mw.onProjectConfigured)
     mw.settingsDialog = NewSettingsDialog(mw.window, mw.onSettingsChanged)
     mw.newTermDialog = NewNewTerminalDialog(mw.window, mw.projectManager, mw.onNewTerminalRequested)
+    mw.queuePanel = NewQueuePanel(mw.fyneApp, mw.taskQueue, mw.projectManager)
+    mw.workflowPanel = NewWorkflowPanel(mw.fyneApp, mw.projectManager, mw.projectPanel.Refresh)
+}
+
+// startQueue 在后台运行任务队列工作池与定时任务，窗口关闭时停止并将运行中的任务重新排队
+func (mw *MainWindow) startQueue() {
+    ctx, cancel := context.WithCancel(context.Background())
+    mw.stopQueue = cancel
+    go func() {
+        if err := mw.taskQueue.Run(ctx); err != nil {
+            log.Printf("[MainWindow] task queue stopped: %v", err)
+            mw.statusBar.SetMessage(fmt.Sprintf("任务队列启动失败: %v", err))
+        }
+    }()
+    go mw.scheduler.Run(ctx)
 }
 
 func (mw *MainWindow) createMainLayout() *fyne.Container {
@@ -164,12 +240,15 @@ func (mw *MainWindow) createMainLayout() *fyne.Container {
 func (mw *MainWindow) createMenuBar() *fyne.MainMenu {
     fileMenu := fyne.NewMenu("文件",
         fyne.NewMenuItem("新建终端", mw.onNewTerminalClicked),
+        fyne.NewMenuItem("发现项目...", mw.onDiscoverClicked),
         fyne.NewMenuItemSeparator(),
         fyne.NewMenuItem("退出", func() { mw.fyneApp.Quit() }),
     )
 
     toolsMenu := fyne.NewMenu("工具",
         fyne.NewMenuItem("监控", mw.onMonitorClicked),
+        fyne.NewMenuItem("任务队列", mw.onQueueClicked),
+        fyne.NewMenuItem("ADDP 工作流", mw.onWorkflowClicked),
         fyne.NewMenuItemSeparator(),
         fyne.NewMenuItem("清理缓存", mw.onClearCacheClicked),
     )
@@ -196,17 +275,17 @@ func (mw *MainWindow) onProjectSelected(proj project.ProjectConfig) {
         mw.statusBar.SetMessage(fmt.Sprintf("宸插垏鎹㈠埌椤圭洰: %s", proj.Name))
         return
     }
-    mw.createNewTerminal(proj, proj.AIModel)
+    mw.createNewTerminal(proj, launch.Options{})
 }
 
-func (mw *MainWindow) onProjectConfigured(proj project.ProjectConfig, aiModel project.AIModelType) {
-    mw.createNewTerminal(proj, aiModel)
+func (mw *MainWindow) onProjectConfigured(proj project.ProjectConfig, opts launch.Options) {
+    mw.createNewTerminal(proj, opts)
     mw.projectPanel.Refresh()
 }
 
-func (mw *MainWindow) onNewTerminalRequested(proj project.ProjectConfig, aiModel project.AIModelType, runInBackground bool) {
-    log.Printf("[MainWindow] new terminal requested: path=%s model=%s yolo=%t bg=%t", proj.Path, aiModel, proj.YoloMode, runInBackground)
-    tab := mw.createNewTerminal(proj, aiModel)
+func (mw *MainWindow) onNewTerminalRequested(proj project.ProjectConfig, aiModel project.AIModelType, runInBackground bool, resume string) {
+    log.Printf("[MainWindow] new terminal requested: path=%s model=%s yolo=%t bg=%t resume=%s", proj.Path, aiModel, proj.YoloMode, runInBackground, resume)
+    tab := mw.createNewTerminal(proj, launch.Options{Tool: aiModel, Resume: resume})
     if !runInBackground && tab != nil {
         mw.terminalTabs.SetActiveTab(tab.GetID())
     }
@@ -231,6 +310,17 @@ func (mw *MainWindow) onMonitorClicked() {
     mw.statusBar.SetMessage("监控功能开发中...")
 }
 
+func (mw *MainWindow) onQueueClicked() { mw.queuePanel.Show() }
+
+// onWorkflowClicked 打开工作流面板，默认显示左侧选中的项目
+func (mw *MainWindow) onWorkflowClicked() {
+    path := ""
+    if proj := mw.projectPanel.GetSelectedProject(); proj != nil {
+        path = proj.Path
+    }
+    mw.workflowPanel.Show(path)
+}
+
 func (mw *MainWindow) onHelpClicked() {
     mw.statusBar.SetMessage("帮助功能开发中...")
 }
@@ -246,21 +336,30 @@ func (mw *MainWindow) onAboutClicked() {
 }
 
 // 缁堢鍒涘缓
-func (mw *MainWindow) createNewTerminal(proj project.ProjectConfig, aiModel project.AIModelType) *TerminalTab {
-    log.Printf("[MainWindow] createNewTerminal name=%s path=%s model=%s yolo=%t", proj.Name, proj.Path, aiModel, proj.YoloMode)
-    termName := fmt.Sprintf("%s(%s)", proj.Name, aiModel.String())
-    termConfig := terminal.TerminalConfig{
-        Type:       mw.getTerminalType(aiModel),
-        Name:       termName,
-        WorkingDir: proj.Path,
-        Command:    aiModel.GetCommand(proj.YoloMode),
-        YoloMode:   proj.YoloMode,
+func (mw *MainWindow) createNewTerminal(proj project.ProjectConfig, opts launch.Options) *TerminalTab {
+    log.Printf("[MainWindow] createNewTerminal name=%s path=%s model=%s profile=%s yolo=%t", proj.Name, proj.Path, opts.Tool, opts.Profile, proj.YoloMode)
+    // 与命令行、Web 启动器共用：记录会话，应用启动配置档，并在 ADDP 项目中注入阶段提示词、交接摘要与项目记忆
+    opts.Templates = mw.templates
+    termConfig, warnings, err := launch.Prepare(mw.projectManager, proj, opts)
+    if err != nil {
+        mw.statusBar.ShowError(oneLine(err))
+        log.Printf("[MainWindow] prepare failed: %v", err)
+        return nil
+    }
+    termName := termConfig.Name
+    if err := launch.RunPreLaunch(context.Background(), termConfig); err != nil {
+        mw.statusBar.ShowError(oneLine(err))
+        log.Printf("[MainWindow] pre-launch hook failed: %v", err)
+        return nil
     }
 
     tab := mw.terminalTabs.CreateTab(termName, termConfig, proj)
     if tab != nil {
         mw.statusBar.SetMessage(fmt.Sprintf("宸插垱寤虹粓绔? %s", termName))
         log.Printf("[MainWindow] tab created id=%s", tab.GetID())
+        if len(warnings) > 0 {
+            mw.statusBar.ShowWarning(strings.Join(warnings, "; "))
+        }
         return tab
     }
     mw.statusBar.SetMessage("鍒涘缓缁堢澶辫触")
@@ -268,19 +367,6 @@ func (mw *MainWindow) createNewTerminal(proj project.ProjectConfig, aiModel proj
     return nil
 }
 
-func (mw *MainWindow) getTerminalType(model project.AIModelType) terminal.TerminalType {
-    switch model {
-    case project.ModelClaudeCode:
-        return terminal.TypeClaudeCode
-    case project.ModelGeminiCLI:
-        return terminal.TypeGeminiCLI
-    case project.ModelCodex:
-        return terminal.TypeCustom
-    default:
-        return terminal.TypeCustom
-    }
-}
-
 func (mw *MainWindow) saveWindowState() {
     log.Println("淇濆瓨绐楀彛鐘舵€?..")
 }
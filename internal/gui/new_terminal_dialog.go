@@ -13,13 +13,19 @@ import (
     "fyne.io/fyne/v2/widget"
 
     "ai-launcher/internal/project"
+    "ai-launcher/internal/terminal"
+)
+
+const (
+    sessionOptionNew    = "新会话"
+    sessionOptionLatest = "继续上次会话"
 )
 
 // NewTerminalDialog 新建终端对话框（目录 + AI CLI + YOLO）
 type NewTerminalDialog struct {
     window              fyne.Window
     projectManager      *project.ConfigManager
-    onTerminalRequested func(project.ProjectConfig, project.AIModelType, bool)
+    onTerminalRequested func(project.ProjectConfig, project.AIModelType, bool, string)
 
     // 对话框
     dialog *dialog.CustomDialog
@@ -30,13 +36,17 @@ type NewTerminalDialog struct {
     modelSelect *widget.RadioGroup
     yoloCheck   *widget.Check
 
+    // 会话恢复：选项文本 -> 会话 ID
+    sessionSelect *widget.Select
+    sessionIDs    map[string]string
+
     // 按钮
     launchButton *widget.Button
     cancelButton *widget.Button
 }
 
 // NewNewTerminalDialog 创建新建终端对话框
-func NewNewTerminalDialog(parent fyne.Window, pm *project.ConfigManager, onRequested func(project.ProjectConfig, project.AIModelType, bool)) *NewTerminalDialog {
+func NewNewTerminalDialog(parent fyne.Window, pm *project.ConfigManager, onRequested func(project.ProjectConfig, project.AIModelType, bool, string)) *NewTerminalDialog {
     d := &NewTerminalDialog{
         window:              parent,
         projectManager:      pm,
@@ -52,7 +62,7 @@ func (d *NewTerminalDialog) initializeUI() {
     d.pathEntry.SetPlaceHolder("选择要开启的目录...")
     d.browseBtn = widget.NewButtonWithIcon("浏览...", theme.FolderOpenIcon(), d.onBrowseClicked)
     // 路径变更即刷新按钮状态
-    d.pathEntry.OnChanged = func(string){ d.updateButtonStates() }
+    d.pathEntry.OnChanged = func(string){ d.refreshSessions(); d.updateButtonStates() }
     // 使用两列网格避免 Border 在某些主题下高度计算为 0 的问题
     pathRow := container.NewGridWithColumns(2, d.pathEntry, d.browseBtn)
 
@@ -62,7 +72,7 @@ func (d *NewTerminalDialog) initializeUI() {
         "Gemini CLI（分析/推荐）",
         "Codex（生成/推荐）",
         "Aider（重构/推荐）",
-    }, func(string) { d.updateButtonStates() })
+    }, func(string) { d.refreshSessions(); d.updateButtonStates() })
     if len(d.modelSelect.Options) > 0 {
         d.modelSelect.SetSelected(d.modelSelect.Options[0])
     }
@@ -71,6 +81,11 @@ func (d *NewTerminalDialog) initializeUI() {
     d.yoloCheck = widget.NewCheck("YOLO 模式（跳过确认，速度优先）", nil)
     d.yoloCheck.SetChecked(true)
 
+    // 会话恢复
+    d.sessionSelect = widget.NewSelect([]string{sessionOptionNew}, nil)
+    d.sessionSelect.SetSelected(sessionOptionNew)
+    d.refreshSessions()
+
     // 底部按钮
     d.launchButton = widget.NewButtonWithIcon("确定", theme.ConfirmIcon(), d.onConfirmClicked)
     d.launchButton.Importance = widget.HighImportance
@@ -84,6 +99,9 @@ func (d *NewTerminalDialog) initializeUI() {
         widget.NewRichTextFromMarkdown("### 选择 AI CLI 工具"),
         d.modelSelect,
         widget.NewSeparator(),
+        widget.NewRichTextFromMarkdown("### 会话"),
+        d.sessionSelect,
+        widget.NewSeparator(),
         d.yoloCheck,
     )
     // 右对齐按钮，去掉中间空位
@@ -91,7 +109,7 @@ func (d *NewTerminalDialog) initializeUI() {
     content := container.NewVBox(form, widget.NewSeparator(), buttons)
 
     d.dialog = dialog.NewCustom("新建终端", "", content, d.window)
-    d.dialog.Resize(fyne.NewSize(600, 420))
+    d.dialog.Resize(fyne.NewSize(600, 500))
     d.updateButtonStates()
 }
 
@@ -104,6 +122,7 @@ func (d *NewTerminalDialog) reset() {
         d.modelSelect.SetSelected(d.modelSelect.Options[0])
     }
     d.yoloCheck.SetChecked(true)
+    d.refreshSessions()
     d.updateButtonStates()
 }
 
@@ -135,13 +154,14 @@ func (d *NewTerminalDialog) onConfirmClicked() {
         YoloMode: d.yoloCheck.Checked,
     }
 
-    log.Printf("[NewTerminalDialog] confirm path=%s model=%s yolo=%t", proj.Path, proj.AIModel, proj.YoloMode)
+    resume := d.selectedResume()
+    log.Printf("[NewTerminalDialog] confirm path=%s model=%s yolo=%t resume=%s", proj.Path, proj.AIModel, proj.YoloMode, resume)
     // 先关闭对话框，避免遮罩未关闭造成界面看似“无响应”
     d.Hide()
     // 将创建请求投递到下一轮 UI 事件循环，避免与对话框关闭产生竞态
     if d.onTerminalRequested != nil {
         // 直接调用回调；已先 Hide() 避免对话框遮罩阻塞
-        d.onTerminalRequested(proj, aiModel, false)
+        d.onTerminalRequested(proj, aiModel, false, resume)
     } else {
         log.Printf("[NewTerminalDialog] onTerminalRequested is nil")
     }
@@ -165,6 +185,56 @@ func (d *NewTerminalDialog) parseAIModel() project.AIModelType {
     }
 }
 
+// refreshSessions 根据目录与工具刷新可恢复的会话列表
+func (d *NewTerminalDialog) refreshSessions() {
+    if d.sessionSelect == nil || d.modelSelect == nil || d.pathEntry == nil { return }
+
+    options := []string{sessionOptionNew}
+    d.sessionIDs = make(map[string]string)
+
+    aiModel := d.parseAIModel()
+    termType := aiModel.TerminalType()
+    if terminal.SupportsResume(termType, false) {
+        options = append(options, sessionOptionLatest)
+    }
+    if d.projectManager != nil && d.pathEntry.Text != "" && terminal.SupportsResume(termType, true) {
+        sessions, _ := d.projectManager.GetSessions(d.pathEntry.Text, aiModel)
+        for _, s := range sessions {
+            label := sessionLabel(s)
+            options = append(options, label)
+            d.sessionIDs[label] = s.ID
+        }
+    }
+
+    d.sessionSelect.Options = options
+    d.sessionSelect.SetSelected(sessionOptionNew)
+    d.sessionSelect.Refresh()
+}
+
+// selectedResume 返回选中的恢复目标；新会话返回空字符串
+func (d *NewTerminalDialog) selectedResume() string {
+    switch selected := d.sessionSelect.Selected; selected {
+    case "", sessionOptionNew:
+        return ""
+    case sessionOptionLatest:
+        return terminal.ResumeLatest
+    default:
+        return d.sessionIDs[selected]
+    }
+}
+
+// sessionLabel 会话在下拉框中的显示文本
+func sessionLabel(s project.SessionRecord) string {
+    title := s.Title
+    if title == "" {
+        title = s.ID
+        if len(title) > 8 {
+            title = title[:8]
+        }
+    }
+    return fmt.Sprintf("%s · %s", s.LastUsed.Local().Format("01-02 15:04"), title)
+}
+
 func (d *NewTerminalDialog) updateButtonStates() {
     if d.launchButton == nil || d.modelSelect == nil || d.pathEntry == nil { return }
     can := d.modelSelect.Selected != "" && d.pathEntry.Text != ""
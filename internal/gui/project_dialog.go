@@ -15,6 +15,7 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"ai-launcher/internal/launch"
 	"ai-launcher/internal/project"
 )
 
@@ -22,7 +23,7 @@ import (
 type ProjectConfigDialog struct {
 	window         fyne.Window
 	projectManager *project.ConfigManager
-	onConfigured   func(project.ProjectConfig, project.AIModelType)
+	onConfigured   func(project.ProjectConfig, launch.Options)
 
 	// 寮圭獥缁勪欢
 	dialog *dialog.CustomDialog
@@ -33,19 +34,22 @@ type ProjectConfigDialog struct {
 	nameLabel     *widget.Label
 	modelSelect   *widget.RadioGroup
 	modeSelect    *widget.RadioGroup
-	envStatus     *widget.RichText
+	envStatus     *widget.RichText
+	profileSelect *widget.Select
 
 	// 鎸夐挳
 	launchButton *widget.Button
 	saveButton   *widget.Button
+	initButton   *widget.Button
 	cancelButton *widget.Button
 
 	// 鐘舵€?
-	selectedProject *project.ProjectConfig
+	selectedProject *project.ProjectConfig
+	profiles        []project.LaunchProfile // 对话框中的配置档，保存项目时写入
 }
 
 // NewProjectConfigDialog 鍒涘缓椤圭洰閰嶇疆寮圭獥
-func NewProjectConfigDialog(parent fyne.Window, pm *project.ConfigManager, onConfigured func(project.ProjectConfig, project.AIModelType)) *ProjectConfigDialog {
+func NewProjectConfigDialog(parent fyne.Window, pm *project.ConfigManager, onConfigured func(project.ProjectConfig, launch.Options)) *ProjectConfigDialog {
 	d := &ProjectConfigDialog{
 		window:         parent,
 		projectManager: pm,
@@ -96,6 +100,8 @@ func (d *ProjectConfigDialog) initializeUI() {
 	d.launchButton.Importance = widget.HighImportance
 
     d.saveButton = widget.NewButtonWithIcon("保存", theme.DocumentSaveIcon(), d.onSaveClicked)
+
+    d.initButton = widget.NewButtonWithIcon("初始化 ADDP", theme.FolderNewIcon(), d.onInitADDPClicked)
 
 	d.cancelButton = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), d.onCancelClicked)
 
@@ -106,6 +112,7 @@ func (d *ProjectConfigDialog) initializeUI() {
 	buttonRow := container.NewHBox(
 		d.launchButton,
 		d.saveButton,
+		d.initButton,
 		layout.NewSpacer(),
 		d.cancelButton,
 	)
@@ -119,7 +126,7 @@ func (d *ProjectConfigDialog) initializeUI() {
 
 	// 鍒涘缓鑷畾涔夊脊绐?
     d.dialog = dialog.NewCustom("打开/新建项目", "", content, d.window)
-	d.dialog.Resize(fyne.NewSize(600, 450))
+	d.dialog.Resize(fyne.NewSize(600, 520))
 }
 
 // createFormLayout 鍒涘缓琛ㄥ崟甯冨眬
@@ -161,6 +168,8 @@ func (d *ProjectConfigDialog) createFormLayout(pathRow *fyne.Container) fyne.Can
 		widget.NewSeparator(),
 		modeInfo,
 		widget.NewSeparator(),
+		d.createProfileSection(),
+		widget.NewSeparator(),
 		envInfo,
 	))
 
@@ -191,6 +200,7 @@ func (d *ProjectConfigDialog) resetForm() {
 // 浜嬩欢澶勭悊鏂规硶
 
 func (d *ProjectConfigDialog) onPathChanged(path string) {
+	d.loadProfiles(path)
 	if path == "" {
         d.nameLabel.SetText("(自动等待目录名)")
         d.envStatus.ParseMarkdown("请先选择项目目录...")
@@ -208,6 +218,9 @@ func (d *ProjectConfigDialog) onPathChanged(path string) {
 
 	// 璁剧疆椤圭洰鍚嶇О
 	projectName := filepath.Base(path)
+	if d.selectedProject != nil {
+		projectName = d.selectedProject.Name
+	}
 	d.nameLabel.SetText(projectName)
 
 	// 鎵ц鐜妫€娴?
@@ -236,7 +249,7 @@ func (d *ProjectConfigDialog) onModeChanged(selected string) {
 func (d *ProjectConfigDialog) onLaunchClicked() {
 	config, model := d.buildProjectConfig()
 	if config != nil && d.onConfigured != nil {
-		d.onConfigured(*config, model)
+		d.onConfigured(*config, d.launchOptions(model))
 		d.Hide()
 	}
 }
@@ -258,45 +271,6 @@ func (d *ProjectConfigDialog) onCancelClicked() {
 
 // 宸ュ叿鏂规硶
 
-func (d *ProjectConfigDialog) performEnvironmentDetection(path string) {
-	var detections []string
-
-	if d.fileExists(filepath.Join(path, "package.json")) {
-		detections = append(detections, "Detected Node.js project (package.json)")
-	}
-	if d.fileExists(filepath.Join(path, "go.mod")) {
-		detections = append(detections, "Detected Go project (go.mod)")
-	}
-	if d.fileExists(filepath.Join(path, "requirements.txt")) || d.fileExists(filepath.Join(path, "pyproject.toml")) {
-		detections = append(detections, "Detected Python project (requirements/pyproject)")
-	}
-	if d.fileExists(filepath.Join(path, ".git")) {
-		detections = append(detections, "Detected Git repository")
-	} else {
-		detections = append(detections, "Git not initialized")
-	}
-	if d.fileExists(filepath.Join(path, "tsconfig.json")) {
-		detections = append(detections, "Detected TypeScript config")
-	}
-	if !d.fileExists(filepath.Join(path, ".env")) {
-		detections = append(detections, "Missing .env file")
-	}
-	if len(detections) == 0 {
-		detections = append(detections, "Generic project folder")
-	}
-
-	statusText := ""
-	for _, detection := range detections {
-		statusText += detection + "\n"
-	}
-	d.envStatus.ParseMarkdown(statusText)
-}
-
-func (d *ProjectConfigDialog) fileExists(path string) bool {
-	_, err := os.Stat(path)
-	return !os.IsNotExist(err)
-}
-
 func (d *ProjectConfigDialog) buildProjectConfig() (*project.ProjectConfig, project.AIModelType) {
 	path := d.pathEntry.Text
 	if path == "" {
@@ -318,12 +292,17 @@ func (d *ProjectConfigDialog) buildProjectConfig() (*project.ProjectConfig, proj
 		return nil, ""
 	}
 	yoloMode := d.parseRunMode()
-	config := &project.ProjectConfig{
-		Name:     projectName,
-		Path:     path,
-		AIModel:  aiModel,
-		YoloMode: yoloMode,
+	// 已保存的项目保留标签、分组与会话记录等其他设置
+	config := &project.ProjectConfig{}
+	if d.selectedProject != nil && d.selectedProject.Path == path {
+		*config = *d.selectedProject
+		projectName = config.Name
 	}
+	config.Name = projectName
+	config.Path = path
+	config.AIModel = aiModel
+	config.YoloMode = yoloMode
+	config.Profiles = d.profiles
 	return config, aiModel
 }
 
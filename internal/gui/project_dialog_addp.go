@@ -0,0 +1,45 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+
+	"ai-launcher/internal/addp"
+)
+
+// onInitADDPClicked 在选中的项目目录中创建 .addp 结构：先预览将要创建的内容，确认后写入
+func (d *ProjectConfigDialog) onInitADDPClicked() {
+	path := strings.TrimSpace(d.pathEntry.Text)
+	if info, err := os.Stat(path); path == "" || err != nil || !info.IsDir() {
+		dialog.ShowError(fmt.Errorf("请先选择有效的项目目录"), d.window)
+		return
+	}
+
+	preview, err := addp.Init(path, addp.Options{DryRun: true})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("初始化 ADDP 失败: %v", err), d.window)
+		return
+	}
+	if len(preview.Directories) == 0 && len(preview.Files) == 0 {
+		dialog.ShowInformation("初始化 ADDP", "该项目的 .addp 结构已完整，无需改动", d.window)
+		return
+	}
+
+	message := fmt.Sprintf("将为项目 '%s'（%s）创建 %d 个目录和 %d 个文件，已有文件不会被覆盖。\n\n继续吗？",
+		preview.ProjectInfo.Name, preview.ProjectInfo.Framework, len(preview.Directories), len(preview.Files))
+	dialog.ShowConfirm("初始化 ADDP", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		result, err := addp.Init(path, addp.Options{})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("初始化 ADDP 失败: %v", err), d.window)
+			return
+		}
+		d.performEnvironmentDetection(path)
+		dialog.ShowInformation("初始化 ADDP", fmt.Sprintf("已创建 %d 个目录和 %d 个文件", len(result.Directories), len(result.Files)), d.window)
+	}, d.window)
+}
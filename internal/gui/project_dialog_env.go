@@ -0,0 +1,17 @@
+package gui
+
+import (
+	"strings"
+
+	"ai-launcher/internal/project/detect"
+)
+
+// performEnvironmentDetection 检测项目环境并在对话框中显示摘要
+func (d *ProjectConfigDialog) performEnvironmentDetection(path string) {
+	result := detect.Detect(path)
+	lines := result.Summary()
+	if len(result.Languages) == 0 {
+		lines = append([]string{"通用项目目录"}, lines...)
+	}
+	d.envStatus.ParseMarkdown("- " + strings.Join(lines, "\n- "))
+}
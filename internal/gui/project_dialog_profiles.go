@@ -0,0 +1,292 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"ai-launcher/internal/launch"
+	"ai-launcher/internal/project"
+)
+
+const (
+	noProfileOption   = "(不使用配置档)"
+	projectToolOption = "(使用项目的工具)"
+	projectYoloOption = "(使用项目的运行模式)"
+	noSandboxOption   = "(不限制)"
+)
+
+// createProfileSection 创建启动配置档区域：选择本次启动使用的配置档，或新建、编辑、删除配置档
+func (d *ProjectConfigDialog) createProfileSection() fyne.CanvasObject {
+	d.profileSelect = widget.NewSelect([]string{noProfileOption}, nil)
+	d.profileSelect.SetSelected(noProfileOption)
+
+	newButton := widget.NewButtonWithIcon("新建", theme.ContentAddIcon(), func() { d.showProfileForm(nil) })
+	editButton := widget.NewButtonWithIcon("编辑", theme.DocumentCreateIcon(), func() {
+		if profile := d.findProfile(d.profileSelect.Selected); profile != nil {
+			d.showProfileForm(profile)
+		}
+	})
+	deleteButton := widget.NewButtonWithIcon("删除", theme.DeleteIcon(), d.onDeleteProfileClicked)
+
+	return container.NewVBox(
+		widget.NewRichTextFromMarkdown("### 启动配置档"),
+		container.NewBorder(nil, nil, nil, container.NewHBox(newButton, editButton, deleteButton), d.profileSelect),
+		widget.NewLabel("配置档可指定工具、参数、环境变量、模板、工作子目录与沙箱，默认配置档会被预先选中"),
+	)
+}
+
+// loadProfiles 读取已保存项目的配置档；新项目没有配置档
+func (d *ProjectConfigDialog) loadProfiles(path string) {
+	d.selectedProject = nil
+	d.profiles = nil
+	if path != "" {
+		if existing, err := d.projectManager.GetProjectByPath(path); err == nil {
+			d.selectedProject = existing
+			d.profiles = append([]project.LaunchProfile(nil), existing.Profiles...)
+		}
+	}
+
+	selected := noProfileOption
+	for _, profile := range d.profiles {
+		if profile.Default {
+			selected = profile.Name
+		}
+	}
+	d.refreshProfiles(selected)
+}
+
+// refreshProfiles 刷新配置档下拉框并选中 selected
+func (d *ProjectConfigDialog) refreshProfiles(selected string) {
+	if d.profileSelect == nil {
+		return
+	}
+	options := []string{noProfileOption}
+	for _, profile := range d.profiles {
+		options = append(options, profile.Name)
+	}
+	d.profileSelect.Options = options
+	if d.findProfile(selected) == nil {
+		selected = noProfileOption
+	}
+	d.profileSelect.SetSelected(selected)
+	d.profileSelect.Refresh()
+}
+
+// findProfile 按名称查找对话框中的配置档
+func (d *ProjectConfigDialog) findProfile(name string) *project.LaunchProfile {
+	for i := range d.profiles {
+		if d.profiles[i].Name == name {
+			return &d.profiles[i]
+		}
+	}
+	return nil
+}
+
+// launchOptions 返回启动选项：工具取表单中的选择，配置档取下拉框中的选择
+func (d *ProjectConfigDialog) launchOptions(model project.AIModelType) launch.Options {
+	opts := launch.Options{Tool: model, Profile: project.NoProfile}
+	if profile := d.findProfile(d.profileSelect.Selected); profile != nil {
+		opts.Profile = profile.Name
+		// 配置档指定了工具时以配置档为准
+		if profile.Tool != "" {
+			opts.Tool = ""
+		}
+	}
+	return opts
+}
+
+// saveProfiles 更新对话框中的配置档；项目已保存时立即写入配置，否则在保存或启动项目时写入
+func (d *ProjectConfigDialog) saveProfiles(update func(p *project.ProjectConfig) error, selected string) error {
+	draft := project.ProjectConfig{Name: d.nameLabel.Text, Profiles: d.profiles}
+	if err := update(&draft); err != nil {
+		return err
+	}
+	if err := project.ValidateProfiles(draft.Profiles); err != nil {
+		return err
+	}
+	if d.selectedProject != nil {
+		if err := d.projectManager.UpdateProject(d.selectedProject.Path, update); err != nil {
+			return err
+		}
+	}
+	d.profiles = draft.Profiles
+	d.refreshProfiles(selected)
+	return nil
+}
+
+func (d *ProjectConfigDialog) onDeleteProfileClicked() {
+	name := d.profileSelect.Selected
+	if d.findProfile(name) == nil {
+		return
+	}
+	dialog.ShowConfirm("删除配置档", fmt.Sprintf("确定删除配置档 '%s' 吗？", name), func(ok bool) {
+		if !ok {
+			return
+		}
+		err := d.saveProfiles(func(p *project.ProjectConfig) error { return p.RemoveProfile(name) }, noProfileOption)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("删除配置档失败: %v", err), d.window)
+		}
+	}, d.window)
+}
+
+// showProfileForm 显示新建或编辑配置档的表单；existing 为 nil 时新建
+func (d *ProjectConfigDialog) showProfileForm(existing *project.LaunchProfile) {
+	profile := project.LaunchProfile{}
+	if existing != nil {
+		profile = *existing
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(profile.Name)
+	nameEntry.SetPlaceHolder("例如 mcp、review")
+
+	toolOptions := []string{projectToolOption}
+	for _, m := range d.projectManager.GetAvailableModels() {
+		toolOptions = append(toolOptions, m.String())
+	}
+	toolSelect := widget.NewSelect(toolOptions, nil)
+	toolSelect.SetSelected(projectToolOption)
+	if profile.Tool != "" {
+		toolSelect.SetSelected(profile.Tool.String())
+	}
+
+	yoloSelect := widget.NewSelect([]string{projectYoloOption, "普通模式", "YOLO 模式"}, nil)
+	yoloSelect.SetSelected(projectYoloOption)
+	if profile.Yolo != nil {
+		yoloSelect.SetSelectedIndex(1)
+		if *profile.Yolo {
+			yoloSelect.SetSelectedIndex(2)
+		}
+	}
+
+	argsEntry := widget.NewEntry()
+	argsEntry.SetText(strings.Join(profile.Args, " "))
+	argsEntry.SetPlaceHolder("--mcp-config mcp.json")
+
+	envEntry := widget.NewMultiLineEntry()
+	envEntry.SetText(formatEnv(profile.Env))
+	envEntry.SetPlaceHolder("每行一个 KEY=VALUE")
+
+	templateEntry := widget.NewEntry()
+	templateEntry.SetText(profile.Template)
+	templateEntry.SetPlaceHolder("应用于第一条提示词的模板 ID")
+
+	dirEntry := widget.NewEntry()
+	dirEntry.SetText(profile.WorkDir)
+	dirEntry.SetPlaceHolder("项目内的相对路径，为空时使用项目根目录")
+
+	sandboxSelect := widget.NewSelect([]string{noSandboxOption, string(project.SandboxReadOnly), string(project.SandboxWorkspace)}, nil)
+	sandboxSelect.SetSelected(noSandboxOption)
+	if profile.Sandbox != project.SandboxNone {
+		sandboxSelect.SetSelected(string(profile.Sandbox))
+	}
+
+	defaultCheck := widget.NewCheck("设为默认配置档", nil)
+	defaultCheck.SetChecked(profile.Default)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("名称", nameEntry),
+		widget.NewFormItem("AI 工具", toolSelect),
+		widget.NewFormItem("运行模式", yoloSelect),
+		widget.NewFormItem("附加参数", argsEntry),
+		widget.NewFormItem("环境变量", envEntry),
+		widget.NewFormItem("提示词模板", templateEntry),
+		widget.NewFormItem("工作目录", dirEntry),
+		widget.NewFormItem("沙箱", sandboxSelect),
+		widget.NewFormItem("", defaultCheck),
+	}
+
+	title := "新建配置档"
+	if existing != nil {
+		title = "编辑配置档"
+	}
+	form := dialog.NewForm(title, "保存", "取消", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		env, err := parseEnv(envEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, d.window)
+			return
+		}
+
+		updated := project.LaunchProfile{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			Args:     strings.Fields(argsEntry.Text),
+			Env:      env,
+			Template: strings.TrimSpace(templateEntry.Text),
+			WorkDir:  strings.TrimSpace(dirEntry.Text),
+			Default:  defaultCheck.Checked,
+		}
+		for _, m := range d.projectManager.GetAvailableModels() {
+			if toolSelect.Selected == m.String() {
+				updated.Tool = m
+			}
+		}
+		if yoloSelect.SelectedIndex() > 0 {
+			yolo := yoloSelect.SelectedIndex() == 2
+			updated.Yolo = &yolo
+		}
+		if sandboxSelect.Selected != noSandboxOption {
+			updated.Sandbox = project.SandboxMode(sandboxSelect.Selected)
+		}
+
+		err = d.saveProfiles(func(p *project.ProjectConfig) error {
+			// 重命名时删除旧名称的配置档
+			if existing != nil && existing.Name != updated.Name {
+				if err := p.RemoveProfile(existing.Name); err != nil {
+					return err
+				}
+			}
+			p.SetProfile(updated)
+			return nil
+		}, updated.Name)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("保存配置档失败: %v", err), d.window)
+		}
+	}, d.window)
+	form.Resize(fyne.NewSize(520, 520))
+	form.Show()
+}
+
+// formatEnv 将环境变量格式化为每行一个 KEY=VALUE
+func formatEnv(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k+"="+env[k])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseEnv 解析每行一个 KEY=VALUE 的环境变量，忽略空行
+func parseEnv(text string) (map[string]string, error) {
+	var env map[string]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("无效的环境变量: %s (应为 KEY=VALUE)", line)
+		}
+		if env == nil {
+			env = make(map[string]string)
+		}
+		env[strings.TrimSpace(key)] = value
+	}
+	return env, nil
+}
@@ -14,31 +14,35 @@ import (
     "fyne.io/fyne/v2/theme"
     "fyne.io/fyne/v2/widget"
 
+    "ai-launcher/internal/launch"
     "ai-launcher/internal/project"
 )
 
 type ProjectConfigDialog struct {
     window         fyne.Window
     projectManager *project.ConfigManager
-    onConfigured   func(project.ProjectConfig, project.AIModelType)
+    onConfigured   func(project.ProjectConfig, launch.Options)
 
     dialog *dialog.CustomDialog
 
-    pathEntry    *widget.Entry
-    browseButton *widget.Button
-    nameLabel    *widget.Label
-    modelSelect  *widget.RadioGroup
-    modeSelect   *widget.RadioGroup
-    envStatus    *widget.RichText
+    pathEntry     *widget.Entry
+    browseButton  *widget.Button
+    nameLabel     *widget.Label
+    modelSelect   *widget.RadioGroup
+    modeSelect    *widget.RadioGroup
+    envStatus     *widget.RichText
+    profileSelect *widget.Select
 
     launchButton *widget.Button
     saveButton   *widget.Button
+    initButton   *widget.Button
     cancelButton *widget.Button
 
     selectedProject *project.ProjectConfig
+    profiles        []project.LaunchProfile // 对话框中的配置档，保存项目时写入
 }
 
-func NewProjectConfigDialog(parent fyne.Window, pm *project.ConfigManager, onConfigured func(project.ProjectConfig, project.AIModelType)) *ProjectConfigDialog {
+func NewProjectConfigDialog(parent fyne.Window, pm *project.ConfigManager, onConfigured func(project.ProjectConfig, launch.Options)) *ProjectConfigDialog {
     d := &ProjectConfigDialog{
         window:         parent,
         projectManager: pm,
@@ -77,10 +81,11 @@ func (d *ProjectConfigDialog) initializeUI() {
     d.launchButton = widget.NewButtonWithIcon("启动", theme.MediaPlayIcon(), d.onLaunchClicked)
     d.launchButton.Importance = widget.HighImportance
     d.saveButton = widget.NewButtonWithIcon("保存", theme.DocumentSaveIcon(), d.onSaveClicked)
+    d.initButton = widget.NewButtonWithIcon("初始化 ADDP", theme.FolderNewIcon(), d.onInitADDPClicked)
     d.cancelButton = widget.NewButtonWithIcon("取消", theme.CancelIcon(), d.onCancelClicked)
 
     form := d.createFormLayout(pathRow)
-    buttons := container.NewHBox(d.launchButton, d.saveButton, layout.NewSpacer(), d.cancelButton)
+    buttons := container.NewHBox(d.launchButton, d.saveButton, d.initButton, layout.NewSpacer(), d.cancelButton)
     content := container.NewVBox(form, widget.NewSeparator(), buttons)
 
     d.dialog = dialog.NewCustom("打开/新建项目", "", content, d.window)
@@ -122,6 +127,8 @@ func (d *ProjectConfigDialog) createFormLayout(pathRow *fyne.Container) fyne.Can
         widget.NewSeparator(),
         selects,
         widget.NewSeparator(),
+        d.createProfileSection(),
+        widget.NewSeparator(),
         envInfo,
     ))
 }
@@ -140,6 +147,7 @@ func (d *ProjectConfigDialog) resetForm() {
 }
 
 func (d *ProjectConfigDialog) onPathChanged(path string) {
+    d.loadProfiles(path)
     if path == "" {
         d.nameLabel.SetText("(自动从目录名获取)")
         d.envStatus.ParseMarkdown("请先选择一个项目文件夹...")
@@ -152,7 +160,11 @@ func (d *ProjectConfigDialog) onPathChanged(path string) {
         d.updateButtonStates()
         return
     }
-    d.nameLabel.SetText(filepath.Base(path))
+    if d.selectedProject != nil {
+        d.nameLabel.SetText(d.selectedProject.Name)
+    } else {
+        d.nameLabel.SetText(filepath.Base(path))
+    }
     d.performEnvironmentDetection(path)
     d.updateButtonStates()
 }
@@ -171,7 +183,7 @@ func (d *ProjectConfigDialog) onModeChanged(_ string)  { d.updateButtonStates()
 func (d *ProjectConfigDialog) onLaunchClicked() {
     cfg, model := d.buildProjectConfig()
     if cfg != nil && d.onConfigured != nil {
-        d.onConfigured(*cfg, model)
+        d.onConfigured(*cfg, d.launchOptions(model))
         d.Hide()
     }
 }
@@ -189,38 +201,6 @@ func (d *ProjectConfigDialog) onSaveClicked() {
 
 func (d *ProjectConfigDialog) onCancelClicked() { d.Hide() }
 
-func (d *ProjectConfigDialog) performEnvironmentDetection(path string) {
-    var detections []string
-    if d.fileExists(filepath.Join(path, "package.json")) {
-        detections = append(detections, "检测到 Node.js 项目")
-    }
-    if d.fileExists(filepath.Join(path, "go.mod")) {
-        detections = append(detections, "检测到 Go 项目")
-    }
-    if d.fileExists(filepath.Join(path, "requirements.txt")) || d.fileExists(filepath.Join(path, "pyproject.toml")) {
-        detections = append(detections, "检测到 Python 项目")
-    }
-    if d.fileExists(filepath.Join(path, ".git")) {
-        detections = append(detections, "检测到 Git 仓库")
-    } else {
-        detections = append(detections, "未初始化 Git")
-    }
-    if d.fileExists(filepath.Join(path, "tsconfig.json")) {
-        detections = append(detections, "检测到 TypeScript 配置")
-    }
-    if !d.fileExists(filepath.Join(path, ".env")) {
-        detections = append(detections, "缺少 .env 文件")
-    }
-    if len(detections) == 0 {
-        detections = append(detections, "通用项目目录")
-    }
-    statusText := ""
-    for _, t := range detections { statusText += t + "\n" }
-    d.envStatus.ParseMarkdown(statusText)
-}
-
-func (d *ProjectConfigDialog) fileExists(path string) bool { _, err := os.Stat(path); return !os.IsNotExist(err) }
-
 func (d *ProjectConfigDialog) buildProjectConfig() (*project.ProjectConfig, project.AIModelType) {
     path := d.pathEntry.Text
     if path == "" {
@@ -244,7 +224,12 @@ func (d *ProjectConfigDialog) buildProjectConfig() (*project.ProjectConfig, proj
     }
 
     yolo := d.parseRunMode()
-    cfg := &project.ProjectConfig{ Name: projectName, Path: path, AIModel: aiModel, YoloMode: yolo }
+    // 已保存的项目保留标签、分组与会话记录等其他设置
+    cfg := &project.ProjectConfig{ Name: projectName }
+    if d.selectedProject != nil && d.selectedProject.Path == path {
+        *cfg = *d.selectedProject
+    }
+    cfg.Path, cfg.AIModel, cfg.YoloMode, cfg.Profiles = path, aiModel, yolo, d.profiles
     return cfg, aiModel
 }
 
@@ -20,6 +20,7 @@ type ProjectHistoryPanel struct {
 	// UI缁勪欢
 	container        *fyne.Container
 	projectList      *widget.List
+	searchEntry      *widget.Entry // 按名称、路径与备注搜索
     // 已移除刷新与自动刷新按钮，避免冗余
 
 	// 鐘舵€?
@@ -46,6 +47,13 @@ func (p *ProjectHistoryPanel) initializeUI() {
     	// 标题
     	title := widget.NewRichTextFromMarkdown("## 项目历史")
     	title.Wrapping = fyne.TextWrapWord
+
+    	// 搜索框：过滤项目列表
+    	p.searchEntry = widget.NewEntry()
+    	p.searchEntry.SetPlaceHolder("搜索名称、路径、备注")
+    	p.searchEntry.OnChanged = func(string) {
+    		p.refreshProjects()
+    	}
 
 	// 椤圭洰鍒楄〃
 	p.projectList = widget.NewList(
@@ -77,6 +85,7 @@ func (p *ProjectHistoryPanel) initializeUI() {
     	// 容器
     	p.container = container.NewVBox(
     		title,
+        	p.searchEntry,
         	widget.NewSeparator(),
         	p.projectList,
         // 左侧仅保留项目列表
@@ -100,6 +109,10 @@ func (p *ProjectHistoryPanel) createProjectCard() fyne.CanvasObject {
 	timeLabel := widget.NewLabel("")
 	timeLabel.TextStyle = fyne.TextStyle{Italic: true}
 
+	// ADDP 工作流阶段（未开始工作流时隐藏）
+	phaseLabel := widget.NewLabel("")
+	phaseLabel.Hide()
+
 	// 鍗＄墖甯冨眬
 	card := container.NewVBox(
 		// 绗竴琛岋細椤圭洰鍚嶇О
@@ -112,6 +125,8 @@ func (p *ProjectHistoryPanel) createProjectCard() fyne.CanvasObject {
 		),
 		// 绗笁琛岋細鏃堕棿
 		timeLabel,
+		// ADDP 工作流阶段
+		phaseLabel,
 		// 鍒嗛殧绾?
 		widget.NewSeparator(),
 	)
@@ -148,7 +163,13 @@ func (p *ProjectHistoryPanel) updateProjectCard(id widget.ListItemID, obj fyne.C
     timeLabel, _ := card.Objects[2].(*widget.Label)
 
     if nameLabel != nil {
-        nameLabel.SetText(fmt.Sprintf("• %s", proj.Name))
+        if proj.Missing {
+            nameLabel.SetText(fmt.Sprintf("⚠ %s (路径不存在)", proj.Name))
+        } else if proj.Pinned {
+            nameLabel.SetText(fmt.Sprintf("📌 %s", proj.Name))
+        } else {
+            nameLabel.SetText(fmt.Sprintf("• %s", proj.Name))
+        }
     }
     if aiLabel != nil {
         aiLabel.SetText(proj.AIModel.String())
@@ -163,6 +184,16 @@ func (p *ProjectHistoryPanel) updateProjectCard(id widget.ListItemID, obj fyne.C
     if timeLabel != nil {
         timeLabel.SetText(p.formatRelativeTime(proj.LastUsed))
     }
+    if len(card.Objects) > 4 {
+        if phaseLabel, ok := card.Objects[3].(*widget.Label); ok {
+            if text := projectPhaseText(proj.Path); text != "" {
+                phaseLabel.SetText(text)
+                phaseLabel.Show()
+            } else {
+                phaseLabel.Hide()
+            }
+        }
+    }
 }
 
 // formatRelativeTime 鏍煎紡鍖栫浉瀵规椂闂?
@@ -197,7 +228,8 @@ func (p *ProjectHistoryPanel) Refresh() {
 // refreshProjects 浠庨厤缃鐞嗗櫒鍒锋柊椤圭洰鍒楄〃
 func (p *ProjectHistoryPanel) refreshProjects() {
 	// 鑾峰彇鏈€杩戦」鐩紙鏈€澶氭樉绀?0涓級
-	p.projects = p.projectManager.GetRecentProjects(10)
+	// 置顶的在前，归档的项目不显示
+	p.projects = p.projectManager.Query(project.Query{Text: p.searchEntry.Text, Limit: 10})
 
 	// 鍒锋柊鍒楄〃UI
 	p.projectList.Refresh()
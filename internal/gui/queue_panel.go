@@ -0,0 +1,241 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+)
+
+// queueRefreshInterval 任务列表自动刷新间隔
+const queueRefreshInterval = 2 * time.Second
+
+// QueuePanel 任务队列面板：查看、添加与取消排队任务
+type QueuePanel struct {
+	app            fyne.App
+	window         fyne.Window
+	queue          *queue.Queue
+	projectManager *project.ConfigManager
+
+	jobs     []*queue.Job
+	selected string
+	list     *widget.List
+	detail   *widget.Label
+	stop     chan struct{}
+}
+
+// NewQueuePanel 创建任务队列面板
+func NewQueuePanel(app fyne.App, q *queue.Queue, pm *project.ConfigManager) *QueuePanel {
+	return &QueuePanel{app: app, queue: q, projectManager: pm}
+}
+
+// Show 打开队列窗口；已打开时将其置前
+func (p *QueuePanel) Show() {
+	if p.window != nil {
+		p.window.RequestFocus()
+		return
+	}
+
+	p.window = p.app.NewWindow("任务队列")
+	p.window.SetContent(p.build())
+	p.window.Resize(fyne.NewSize(760, 520))
+	p.window.SetOnClosed(func() {
+		close(p.stop)
+		p.window = nil
+	})
+
+	p.stop = make(chan struct{})
+	p.refresh()
+	go p.autoRefresh(p.stop)
+	p.window.Show()
+}
+
+func (p *QueuePanel) build() fyne.CanvasObject {
+	p.list = widget.NewList(
+		func() int { return len(p.jobs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(p.jobs) {
+				obj.(*widget.Label).SetText(jobSummary(p.jobs[id]))
+			}
+		},
+	)
+	p.list.OnSelected = func(id widget.ListItemID) {
+		if id < len(p.jobs) {
+			p.selected = p.jobs[id].ID
+			p.detail.SetText(jobDetail(p.jobs[id]))
+		}
+	}
+
+	p.detail = widget.NewLabel("选择任务查看详情")
+	p.detail.Wrapping = fyne.TextWrapWord
+
+	toolbar := widget.NewToolbar(
+		widget.NewToolbarAction(theme.ContentAddIcon(), p.onAddClicked),
+		widget.NewToolbarAction(theme.CancelIcon(), p.onCancelClicked),
+		widget.NewToolbarSeparator(),
+		widget.NewToolbarAction(theme.ViewRefreshIcon(), p.refresh),
+	)
+
+	split := container.NewVSplit(p.list, container.NewVScroll(p.detail))
+	split.Offset = 0.6
+	return container.NewBorder(toolbar, nil, nil, nil, split)
+}
+
+// refresh 从磁盘重新加载任务列表
+func (p *QueuePanel) refresh() {
+	jobs, err := p.queue.List()
+	if err != nil {
+		p.detail.SetText(fmt.Sprintf("加载任务失败: %v", err))
+		return
+	}
+	p.jobs = jobs
+	p.list.Refresh()
+
+	for _, job := range jobs {
+		if job.ID == p.selected {
+			p.detail.SetText(jobDetail(job))
+		}
+	}
+}
+
+func (p *QueuePanel) autoRefresh(stop chan struct{}) {
+	ticker := time.NewTicker(queueRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.refresh()
+		}
+	}
+}
+
+func (p *QueuePanel) onAddClicked() {
+	var paths []string
+	for _, proj := range p.projectManager.Query(project.Query{}) {
+		paths = append(paths, proj.Path)
+	}
+
+	projectSelect := widget.NewSelectEntry(paths)
+	projectSelect.SetPlaceHolder("项目路径")
+	toolSelect := widget.NewSelect([]string{
+		string(project.ModelClaudeCode),
+		string(project.ModelGeminiCLI),
+		string(project.ModelCodex),
+		string(project.ModelAider),
+	}, nil)
+	toolSelect.SetSelected(string(project.ModelClaudeCode))
+	promptEntry := widget.NewMultiLineEntry()
+	promptEntry.SetPlaceHolder("提示词")
+	priorityEntry := widget.NewEntry()
+	priorityEntry.SetText("0")
+	retriesEntry := widget.NewEntry()
+	retriesEntry.SetText("0")
+	yoloCheck := widget.NewCheck("YOLO 模式", nil)
+
+	// 选择已注册项目时带出其默认工具与模式
+	projectSelect.OnChanged = func(path string) {
+		if proj, err := p.projectManager.GetProjectByPath(path); err == nil {
+			toolSelect.SetSelected(string(proj.AIModel))
+			yoloCheck.SetChecked(proj.YoloMode)
+		}
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("项目", projectSelect),
+		widget.NewFormItem("工具", toolSelect),
+		widget.NewFormItem("提示词", promptEntry),
+		widget.NewFormItem("优先级", priorityEntry),
+		widget.NewFormItem("重试次数", retriesEntry),
+		widget.NewFormItem("", yoloCheck),
+	}
+
+	form := dialog.NewForm("添加任务", "添加", "取消", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		priority, _ := strconv.Atoi(strings.TrimSpace(priorityEntry.Text))
+		retries, _ := strconv.Atoi(strings.TrimSpace(retriesEntry.Text))
+		_, err := p.queue.Add(queue.Job{
+			Project:  projectSelect.Text,
+			Tool:     project.AIModelType(toolSelect.Selected),
+			Prompt:   promptEntry.Text,
+			Priority: priority,
+			Options:  queue.JobOptions{YoloMode: yoloCheck.Checked, MaxRetries: retries},
+		})
+		if err != nil {
+			dialog.ShowError(err, p.window)
+			return
+		}
+		p.refresh()
+	}, p.window)
+	form.Resize(fyne.NewSize(560, 420))
+	form.Show()
+}
+
+func (p *QueuePanel) onCancelClicked() {
+	if p.selected == "" {
+		return
+	}
+	if _, err := p.queue.Cancel(p.selected); err != nil {
+		dialog.ShowError(err, p.window)
+		return
+	}
+	p.refresh()
+}
+
+// jobSummary 列表中显示的单行摘要
+func jobSummary(job *queue.Job) string {
+	icon := map[queue.JobStatus]string{
+		queue.JobQueued:    "⏳",
+		queue.JobRunning:   "▶️",
+		queue.JobSucceeded: "✅",
+		queue.JobFailed:    "❌",
+		queue.JobCancelled: "⏹",
+	}[job.Status]
+	return fmt.Sprintf("%s %s  [%s] p=%d  %s  %s", icon, job.ID, job.Tool.String(), job.Priority,
+		shortPath(job.Project), firstLine(job.Prompt))
+}
+
+// jobDetail 任务详情
+func jobDetail(job *queue.Job) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "任务: %s\n状态: %s（第 %d 次）\n项目: %s\n工具: %s\n", job.ID, job.Status, job.Attempts, job.Project, job.Tool.String())
+	fmt.Fprintf(&b, "提示词: %s\n", job.Prompt)
+	if job.Error != "" {
+		fmt.Fprintf(&b, "错误: %s\n", job.Error)
+	}
+	if job.LogPath != "" {
+		fmt.Fprintf(&b, "日志: %s\n", job.LogPath)
+	}
+	if len(job.Output) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(job.Output, "\n"))
+	}
+	return b.String()
+}
+
+func shortPath(path string) string {
+	parts := strings.Split(strings.ReplaceAll(path, "\\", "/"), "/")
+	if len(parts) > 2 {
+		return ".../" + strings.Join(parts[len(parts)-2:], "/")
+	}
+	return path
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + "..."
+	}
+	return s
+}
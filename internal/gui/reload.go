@@ -0,0 +1,68 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"ai-launcher/internal/config"
+)
+
+// startWatcher 监视配置文件、自定义模板与 projects.json，变化时重新加载并刷新界面；
+// 无效的文件被拒绝，继续使用上一次有效的内容并在状态栏显示错误
+func (mw *MainWindow) startWatcher() {
+	watcher, err := config.NewWatcher(mw.currentSettings(), config.WatchOptions{})
+	if err != nil {
+		log.Printf("[MainWindow] config watcher disabled: %v", err)
+		mw.statusBar.ShowWarning(fmt.Sprintf("无法监视配置文件变化: %v", err))
+		return
+	}
+	watcher.Subscribe(mw.onConfigChange)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mw.stopWatcher = cancel
+	go watcher.Run(ctx)
+}
+
+// currentSettings 返回当前生效的配置；配置可能在监视 goroutine 中被替换
+func (mw *MainWindow) currentSettings() *config.Config {
+	mw.settingsMu.RLock()
+	defer mw.settingsMu.RUnlock()
+	return mw.settings
+}
+
+// onConfigChange 应用一次文件变化，在监视 goroutine 中调用
+func (mw *MainWindow) onConfigChange(c config.Change) {
+	switch c.Kind {
+	case config.KindConfig:
+		if c.Err != nil {
+			mw.statusBar.ShowError(fmt.Sprintf("配置文件无效，继续使用上一次的配置: %s", oneLine(c.Err)))
+			return
+		}
+		mw.settingsMu.Lock()
+		mw.settings = c.Config
+		mw.settingsMu.Unlock()
+		mw.taskQueue.SetMaxConcurrent(c.Config.Performance.MaxConcurrentTerminals)
+		mw.statusBar.SetOllamaClient(c.Config.Ollama.NewClient())
+		mw.startRescan()
+		mw.statusBar.ShowSuccess("配置已重新加载")
+	case config.KindTemplates:
+		if err := mw.templates.LoadDir(c.Config.Templates.Dir()); err != nil {
+			mw.statusBar.ShowError(fmt.Sprintf("模板文件无效，继续使用上一次的模板: %s", oneLine(err)))
+			return
+		}
+		mw.statusBar.ShowSuccess("模板已重新加载")
+	case config.KindProjects:
+		if err := mw.projectManager.LoadProjects(); err != nil {
+			mw.statusBar.ShowError(fmt.Sprintf("项目列表无效，继续使用上一次的项目: %s", oneLine(err)))
+			return
+		}
+		mw.projectPanel.Refresh()
+	}
+}
+
+// oneLine 将多行错误合并为一行，适合在状态栏显示
+func oneLine(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", "; ")
+}
@@ -3,12 +3,16 @@ package gui
 import (
     "fmt"
     "runtime"
+    "sync/atomic"
     "time"
 
     "fyne.io/fyne/v2"
     "fyne.io/fyne/v2/container"
     "fyne.io/fyne/v2/layout"
     "fyne.io/fyne/v2/widget"
+
+    "ai-launcher/internal/ollama"
+    "ai-launcher/internal/terminal"
 )
 
 // StatusBar 状态栏组件
@@ -22,11 +26,18 @@ type StatusBar struct {
     memoryLabel  *widget.Label
     networkLabel *widget.Label
     ollamaLabel  *widget.Label
+    healthLabel  *widget.Label
 
     // 状态数据
     currentMessage string
     isRunning      bool
     startTime      time.Time
+
+    // 终端健康报告来源
+    healthSource func() *terminal.HealthReport
+
+    // 提示词优化使用的 Ollama 客户端，配置重新加载时替换
+    ollama atomic.Pointer[ollama.OllamaClient]
 }
 
 // NewStatusBar 创建状态栏
@@ -51,6 +62,7 @@ func (sb *StatusBar) initializeUI() {
     sb.memoryLabel = widget.NewLabel("💾 内存: 0MB")
     sb.networkLabel = widget.NewLabel("🌐 网络: 未知")
     sb.ollamaLabel = widget.NewLabel("🤖 Ollama: 未连接")
+    sb.healthLabel = widget.NewLabel("🩺 终端: 未知")
 
     topRow := container.NewHBox(
         sb.messageLabel,
@@ -68,6 +80,8 @@ func (sb *StatusBar) initializeUI() {
         sb.networkLabel,
         widget.NewSeparator(),
         sb.ollamaLabel,
+        widget.NewSeparator(),
+        sb.healthLabel,
     )
 
     sb.container = container.NewVBox(
@@ -128,6 +142,27 @@ func (sb *StatusBar) updateSystemStatus() {
 
     ollamaStatus := sb.getOllamaStatus()
     sb.ollamaLabel.SetText(fmt.Sprintf("🤖 Ollama: %s", ollamaStatus))
+
+    sb.updateHealthStatus()
+}
+
+// SetHealthSource 设置终端健康报告来源
+func (sb *StatusBar) SetHealthSource(source func() *terminal.HealthReport) {
+    sb.healthSource = source
+}
+
+// updateHealthStatus 刷新终端健康状态
+func (sb *StatusBar) updateHealthStatus() {
+    if sb.healthSource == nil {
+        return
+    }
+    report := sb.healthSource()
+    icon := map[terminal.HealthState]string{
+        terminal.HealthOK:        "🟢",
+        terminal.HealthDegraded:  "🟡",
+        terminal.HealthUnhealthy: "🔴",
+    }[report.State]
+    sb.healthLabel.SetText(fmt.Sprintf("🩺 终端: %s %s", icon, report.Summary()))
 }
 
 func (sb *StatusBar) getCPUUsage() int { return int(time.Now().Unix()%20) + 5 }
@@ -138,8 +173,16 @@ func (sb *StatusBar) getNetworkStatus() string {
 }
 
 func (sb *StatusBar) getOllamaStatus() string {
-    statuses := []string{"qwen2.5:7b 已加载", "未连接", "启动中"}
-    return statuses[int(time.Now().Unix()/10)%len(statuses)]
+    client := sb.ollama.Load()
+    if client == nil {
+        return "未配置"
+    }
+    return fmt.Sprintf("%s @ %s", client.GetModel(), client.BaseURL)
+}
+
+// SetOllamaClient 设置状态栏显示的 Ollama 客户端
+func (sb *StatusBar) SetOllamaClient(client *ollama.OllamaClient) {
+    sb.ollama.Store(client)
 }
 
 func (sb *StatusBar) SetProjectInfo(projectName string, aiModel string, mode string) {
@@ -0,0 +1,90 @@
+package gui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/project"
+)
+
+// onRunGates 手动运行当前项目的质量门禁
+func (tab *TerminalTab) onRunGates() {
+	tab.runGates(gates.TriggerManual, false)
+}
+
+// runGatesOnExit 会话停止后按项目配置自动运行质量门禁
+func (tab *TerminalTab) runGatesOnExit() {
+	tab.runGates(gates.TriggerExit, true)
+}
+
+// runGates 在后台运行门禁，完成后将结果追加到标签页输出；auto 为 true 时只在项目开启自动运行时执行
+func (tab *TerminalTab) runGates(trigger gates.Trigger, auto bool) {
+	path := tab.project.Path
+	if path == "" {
+		if !auto {
+			tab.appendOutput("未选择项目，无法运行质量门禁\n")
+		}
+		return
+	}
+
+	cfg, err := gates.LoadConfig(path, project.NewConfigManager().ManifestTrusted)
+	if err != nil {
+		tab.appendOutput(fmt.Sprintf("读取门禁配置失败: %v\n", err))
+		return
+	}
+	if auto && (!cfg.AutoRun(path) || !cfg.RunsOn(trigger)) {
+		return
+	}
+	if len(cfg.Gates) == 0 {
+		tab.appendOutput(fmt.Sprintf("没有可运行的质量门禁，请在 %s 中定义\n", gates.ConfigPath(path)))
+		return
+	}
+	if tab.gatesRunning {
+		tab.appendOutput("质量门禁正在运行中...\n")
+		return
+	}
+
+	tab.gatesRunning = true
+	tab.statusLabel.SetText("正在运行质量门禁...")
+	tab.appendOutput(fmt.Sprintf("\n正在运行质量门禁（%d 项）...\n", len(cfg.Gates)))
+
+	go func() {
+		report, err := gates.RunAndSave(context.Background(), path, cfg, trigger, tab.name)
+		tab.gatesRunning = false
+		tab.showGateReport(report, err, cfg)
+	}()
+}
+
+// showGateReport 显示门禁结果；失败且配置了自动反馈时发送给当前会话
+func (tab *TerminalTab) showGateReport(report *gates.Report, err error, cfg *gates.Config) {
+	tab.gateReport = report
+	tab.appendOutput("\n```\n" + report.Details() + "\n```\n")
+	if err != nil && !errors.Is(err, gates.ErrNotInitialized) {
+		tab.appendOutput(fmt.Sprintf("保存门禁报告失败: %v\n", err))
+	}
+	tab.statusLabel.SetText(report.Summary())
+
+	if report.Passed {
+		return
+	}
+	if cfg.Feedback && tab.running {
+		tab.onSendGateFeedback()
+		return
+	}
+	tab.appendOutput("可点击工具栏的反馈按钮，将失败结果作为后续提示发送给当前会话\n")
+}
+
+// onSendGateFeedback 将最近一次门禁的失败结果作为后续提示发送给当前会话
+func (tab *TerminalTab) onSendGateFeedback() {
+	if tab.gateReport == nil || tab.gateReport.Passed {
+		tab.appendOutput("没有需要反馈的门禁失败\n")
+		return
+	}
+	if !tab.running {
+		tab.appendOutput("终端未运行，无法发送门禁结果\n")
+		return
+	}
+	tab.onInputSubmitted(tab.gateReport.FeedbackPrompt())
+}
@@ -10,6 +10,8 @@ import (
     "fyne.io/fyne/v2/theme"
     "fyne.io/fyne/v2/widget"
 
+    "ai-launcher/internal/gates"
+    "ai-launcher/internal/history"
     "ai-launcher/internal/project"
     "ai-launcher/internal/terminal"
 )
@@ -17,6 +19,7 @@ import (
 // 终端标签容器
 type TerminalTabContainer struct {
     terminalManager *terminal.TerminalManager
+    history         *history.HistoryStore
 
     // UI
     tabContainer *container.AppTabs
@@ -41,20 +44,31 @@ type TerminalTab struct {
     terminalType terminal.TerminalType
     project      project.ProjectConfig
 
+    // 输入历史
+    history     *history.HistoryStore
+    navigator   *history.Navigator
+    searching   bool   // Ctrl+R 搜索中，输入框内容为关键字
+    searchMatch string // 当前搜索匹配
+
     // UI
     content     *fyne.Container
     outputArea  *widget.RichText
-    inputArea   *widget.Entry
+    inputArea   *historyEntry
     statusLabel *widget.Label
 
     // 状态
     active  bool
     running bool
+
+    // 质量门禁
+    gatesRunning bool
+    gateReport   *gates.Report // 最近一次门禁结果，用于反馈给会话
 }
 
 func NewTerminalTabContainer(tm *terminal.TerminalManager, onNew func()) *TerminalTabContainer {
     tc := &TerminalTabContainer{
         terminalManager: tm,
+        history:         history.NewHistoryStore(),
         tabs:            make(map[string]*TerminalTab),
         nextTabID:       1,
         onRequestNew:    onNew,
@@ -101,6 +115,7 @@ func (tc *TerminalTabContainer) CreateTab(name string, termConfig terminal.Termi
         name:         name,
         terminalType: termConfig.Type,
         project:      proj,
+        history:      tc.history,
     }
     tab.initializeUI()
     tab.startTerminal(termConfig)
@@ -213,9 +228,14 @@ func (tab *TerminalTab) initializeUI() {
     tab.outputArea.Wrapping = fyne.TextWrapWord
     tab.outputArea.Scroll = container.ScrollBoth
 
-    tab.inputArea = widget.NewEntry()
-    tab.inputArea.SetPlaceHolder("输入命令后回车执行...")
+    tab.inputArea = newHistoryEntry()
+    tab.inputArea.SetPlaceHolder("输入命令后回车执行...（↑/↓ 历史，Ctrl+R 搜索）")
     tab.inputArea.OnSubmitted = tab.onInputSubmitted
+    tab.inputArea.onHistoryUp = tab.onHistoryUp
+    tab.inputArea.onHistoryDown = tab.onHistoryDown
+    tab.inputArea.onSearch = tab.onHistorySearch
+    tab.inputArea.onCancel = tab.onHistoryCancel
+    tab.loadHistory()
 
     tab.statusLabel = widget.NewLabel("空闲")
 
@@ -223,6 +243,9 @@ func (tab *TerminalTab) initializeUI() {
         widget.NewToolbarAction(theme.MediaPlayIcon(), tab.onStartTerminal),
         widget.NewToolbarAction(theme.MediaStopIcon(), tab.onStopTerminal),
         widget.NewToolbarSeparator(),
+        widget.NewToolbarAction(theme.ConfirmIcon(), tab.onRunGates),
+        widget.NewToolbarAction(theme.MailReplyIcon(), tab.onSendGateFeedback),
+        widget.NewToolbarSeparator(),
         widget.NewToolbarAction(theme.ViewRefreshIcon(), tab.onClearOutput),
         widget.NewToolbarAction(theme.SettingsIcon(), tab.onTerminalSettings),
     )
@@ -244,6 +267,9 @@ func (tab *TerminalTab) startTerminal(config terminal.TerminalConfig) {
     tab.appendOutput(fmt.Sprintf("工作目录: %s\n", config.WorkingDir))
     mode := map[bool]string{true: "YOLO", false: "普通"}[config.YoloMode]
     tab.appendOutput(fmt.Sprintf("模式: %s\n", mode))
+    if config.InitialPrompt != "" {
+        tab.appendOutput("已注入上次会话的交接摘要\n")
+    }
     tab.statusLabel.SetText("运行中...")
     tab.appendOutput("终端已启动，准备接收命令\n\n")
 }
@@ -252,6 +278,7 @@ func (tab *TerminalTab) stopTerminal() {
     tab.running = false
     tab.statusLabel.SetText("已停止")
     tab.appendOutput("\n终端已停止\n")
+    tab.runGatesOnExit()
 }
 
 func (tab *TerminalTab) GetContent() *fyne.Container { return tab.content }
@@ -273,9 +300,126 @@ func (tab *TerminalTab) onInputSubmitted(input string) {
     if input == "" {
         return
     }
+    if tab.searching {
+        if tab.searchMatch != "" {
+            input = tab.searchMatch
+        }
+        tab.endSearch(false)
+    }
     tab.appendOutput(fmt.Sprintf("> %s\n", input))
     tab.appendOutput("命令已提交，执行中...\n")
     tab.inputArea.SetText("")
+    tab.recordHistory(input)
+}
+
+// loadHistory 加载当前项目与工具的输入历史
+func (tab *TerminalTab) loadHistory() {
+    tab.navigator = history.NewNavigator(nil)
+    if tab.history == nil {
+        return
+    }
+    nav, err := tab.history.Navigator(tab.project.Path, tab.terminalType.String())
+    if err != nil {
+        log.Printf("[TerminalTabs] load history failed: %v", err)
+        return
+    }
+    tab.navigator = nav
+}
+
+// recordHistory 保存已提交的输入（敏感内容不会被保存）
+func (tab *TerminalTab) recordHistory(input string) {
+    if tab.history != nil {
+        saved, err := tab.history.Add(tab.project.Path, tab.terminalType.String(), input)
+        if err != nil {
+            log.Printf("[TerminalTabs] save history failed: %v", err)
+        }
+        if !saved {
+            tab.navigator.Reset()
+            return
+        }
+    }
+    tab.navigator.Push(input)
+}
+
+func (tab *TerminalTab) onHistoryUp() {
+    if tab.searching {
+        tab.endSearch(true)
+        return
+    }
+    if text, ok := tab.navigator.Prev(tab.inputArea.Text); ok {
+        tab.inputArea.SetText(text)
+        tab.inputArea.CursorColumn = len([]rune(text))
+    }
+}
+
+func (tab *TerminalTab) onHistoryDown() {
+    if tab.searching {
+        tab.endSearch(true)
+        return
+    }
+    if text, ok := tab.navigator.Next(); ok {
+        tab.inputArea.SetText(text)
+        tab.inputArea.CursorColumn = len([]rune(text))
+    }
+}
+
+// onHistorySearch 首次 Ctrl+R 进入反向增量搜索，输入框内容作为关键字，输入时实时更新匹配；
+// 再次按下查找更早的匹配
+func (tab *TerminalTab) onHistorySearch() {
+    if !tab.searching {
+        tab.searching = true
+        tab.searchMatch = ""
+        tab.inputArea.OnChanged = tab.onSearchChanged
+        tab.onSearchChanged(tab.inputArea.Text)
+        return
+    }
+    match, ok := tab.navigator.SearchNext()
+    tab.showSearchResult(match, ok)
+}
+
+// onSearchChanged 搜索模式下关键字变化时从当前匹配处继续查找
+func (tab *TerminalTab) onSearchChanged(query string) {
+    match, ok := tab.navigator.Search(query)
+    if query == "" {
+        tab.searchMatch = ""
+    }
+    tab.showSearchResult(match, ok)
+}
+
+// showSearchResult 在状态栏显示关键字与当前匹配；没有更早的匹配时保留上一个匹配
+func (tab *TerminalTab) showSearchResult(match string, ok bool) {
+    query := tab.inputArea.Text
+    if ok {
+        tab.searchMatch = match
+    }
+    switch {
+    case ok:
+        tab.statusLabel.SetText(fmt.Sprintf("(reverse-i-search)`%s': %s", query, match))
+    case tab.searchMatch != "":
+        tab.statusLabel.SetText(fmt.Sprintf("(reverse-i-search)`%s': 无更早的匹配，当前 %s", query, tab.searchMatch))
+    default:
+        tab.statusLabel.SetText(fmt.Sprintf("(reverse-i-search)`%s': 无匹配", query))
+    }
+}
+
+// endSearch 退出搜索模式；accept 为 true 时将当前匹配放入输入框继续编辑
+func (tab *TerminalTab) endSearch(accept bool) {
+    tab.searching = false
+    tab.inputArea.OnChanged = nil
+    if accept && tab.searchMatch != "" {
+        tab.inputArea.SetText(tab.searchMatch)
+        tab.inputArea.CursorColumn = len([]rune(tab.searchMatch))
+    }
+    tab.searchMatch = ""
+    tab.navigator.EndSearch()
+    tab.statusLabel.SetText(map[bool]string{true: "运行中...", false: "已停止"}[tab.running])
+}
+
+// onHistoryCancel Esc 退出搜索并保留当前匹配供编辑
+func (tab *TerminalTab) onHistoryCancel() {
+    if tab.searching {
+        tab.endSearch(true)
+    }
 }
 
 func (tab *TerminalTab) onStartTerminal() {
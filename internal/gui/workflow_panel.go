@@ -0,0 +1,330 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/workflow"
+)
+
+// WorkflowPanel ADDP 工作流面板：查看项目当前阶段，确认质量检查并推进或退回阶段
+type WorkflowPanel struct {
+	app            fyne.App
+	window         fyne.Window
+	projectManager *project.ConfigManager
+	onChanged      func()
+
+	projectPath   string
+	projectSelect *widget.Select
+	phaseLabel    *widget.Label
+	outputsLabel  *widget.Label
+	checks        *fyne.Container
+	historyLabel  *widget.Label
+	startButton   *widget.Button
+	advanceButton *widget.Button
+	returnButton  *widget.Button
+	promptButton  *widget.Button
+}
+
+// NewWorkflowPanel 创建工作流面板；onChanged 在阶段变化后调用（用于刷新项目列表）
+func NewWorkflowPanel(app fyne.App, pm *project.ConfigManager, onChanged func()) *WorkflowPanel {
+	return &WorkflowPanel{app: app, projectManager: pm, onChanged: onChanged}
+}
+
+// Show 打开工作流窗口并显示指定项目；path 为空时保留上次选择
+func (p *WorkflowPanel) Show(path string) {
+	if p.window == nil {
+		p.window = p.app.NewWindow("ADDP 工作流")
+		p.window.SetContent(p.build())
+		p.window.Resize(fyne.NewSize(640, 560))
+		p.window.SetOnClosed(func() { p.window = nil })
+	}
+
+	var paths []string
+	for _, proj := range p.projectManager.Query(project.Query{}) {
+		paths = append(paths, proj.Path)
+	}
+	p.projectSelect.Options = paths
+	if path == "" {
+		path = p.projectPath
+	}
+	if path == "" && len(paths) > 0 {
+		path = paths[0]
+	}
+	if path != "" {
+		p.projectSelect.SetSelected(path)
+	}
+	p.refresh()
+	p.window.Show()
+	p.window.RequestFocus()
+}
+
+func (p *WorkflowPanel) build() fyne.CanvasObject {
+	p.projectSelect = widget.NewSelect(nil, func(path string) {
+		p.projectPath = path
+		p.refresh()
+	})
+	p.projectSelect.PlaceHolder = "选择项目"
+
+	p.phaseLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	p.outputsLabel = widget.NewLabel("")
+	p.outputsLabel.Wrapping = fyne.TextWrapWord
+	p.checks = container.NewVBox()
+	p.historyLabel = widget.NewLabel("")
+	p.historyLabel.Wrapping = fyne.TextWrapWord
+
+	p.startButton = widget.NewButton("开始工作流", p.onStartClicked)
+	p.advanceButton = widget.NewButton("推进到下一阶段", func() { p.advance(false) })
+	p.returnButton = widget.NewButton("退回阶段...", p.onReturnClicked)
+	p.promptButton = widget.NewButton("查看阶段提示词", p.onPromptClicked)
+
+	body := container.NewVBox(
+		p.phaseLabel,
+		widget.NewSeparator(),
+		widget.NewLabel("阶段产出"),
+		p.outputsLabel,
+		widget.NewLabel("质量检查（全部确认后才能推进）"),
+		p.checks,
+		widget.NewSeparator(),
+		widget.NewLabel("历史"),
+		p.historyLabel,
+	)
+	buttons := container.NewHBox(p.startButton, p.advanceButton, p.returnButton, p.promptButton)
+	return container.NewBorder(p.projectSelect, buttons, nil, nil, container.NewVScroll(body))
+}
+
+// refresh 从磁盘重新加载当前项目的工作流状态
+func (p *WorkflowPanel) refresh() {
+	if p.window == nil {
+		return
+	}
+	p.checks.RemoveAll()
+	if p.projectPath == "" {
+		p.showInactive("请选择项目")
+		return
+	}
+
+	m := workflow.NewManager(p.projectPath)
+	status, err := m.Load()
+	if err != nil {
+		p.showInactive(fmt.Sprintf("读取工作流状态失败: %v", err))
+		return
+	}
+	if status == nil {
+		p.showInactive("该项目尚未开始 ADDP 工作流")
+		return
+	}
+
+	phase := status.Current()
+	p.phaseLabel.SetText(fmt.Sprintf("第 %d 轮  %s", status.Iteration, phaseProgress(phase)))
+
+	if outputs := m.Outputs(phase); len(outputs) > 0 {
+		p.outputsLabel.SetText(strings.Join(outputs, "\n"))
+	} else {
+		p.outputsLabel.SetText(fmt.Sprintf("暂无，请将产出保存到 %s", m.PhaseDir(phase)))
+	}
+
+	spec, _ := workflow.SpecFor(phase)
+	confirmed := status.Confirmed(phase)
+	for _, check := range spec.QualityChecks {
+		check := check
+		box := widget.NewCheck(check, nil)
+		box.SetChecked(containsString(confirmed, check))
+		box.OnChanged = func(on bool) {
+			var err error
+			if on {
+				_, err = m.Confirm(check)
+			} else {
+				_, err = m.Unconfirm(check)
+			}
+			if err != nil {
+				dialog.ShowError(err, p.window)
+			}
+		}
+		p.checks.Add(box)
+	}
+
+	var history []string
+	for i := len(status.WorkflowHistory) - 1; i >= 0 && len(history) < 8; i-- {
+		h := status.WorkflowHistory[i]
+		line := fmt.Sprintf("%s  %s  %s", shortTimestamp(h.Timestamp), h.Phase.Label(), h.Status)
+		if h.Duration != "" {
+			line += "  " + h.Duration
+		}
+		if h.Note != "" {
+			line += "  " + h.Note
+		}
+		history = append(history, line)
+	}
+	p.historyLabel.SetText(strings.Join(history, "\n"))
+
+	p.startButton.Disable()
+	p.advanceButton.Enable()
+	p.promptButton.Enable()
+	if phase.Index() > 0 {
+		p.returnButton.Enable()
+	} else {
+		p.returnButton.Disable()
+	}
+}
+
+// showInactive 显示未开始工作流时的状态
+func (p *WorkflowPanel) showInactive(message string) {
+	p.phaseLabel.SetText(message)
+	p.outputsLabel.SetText("")
+	p.historyLabel.SetText("")
+	if p.projectPath != "" {
+		p.startButton.Enable()
+	} else {
+		p.startButton.Disable()
+	}
+	p.advanceButton.Disable()
+	p.returnButton.Disable()
+	p.promptButton.Disable()
+}
+
+func (p *WorkflowPanel) onStartClicked() {
+	if _, err := workflow.NewManager(p.projectPath).Start(); err != nil {
+		dialog.ShowError(err, p.window)
+		return
+	}
+	p.changed()
+}
+
+// advance 推进阶段；门禁未通过时列出未通过项并询问是否强制推进
+func (p *WorkflowPanel) advance(force bool) {
+	_, err := workflow.NewManager(p.projectPath).Advance(force)
+	var gateErr *workflow.GateError
+	if errors.As(err, &gateErr) {
+		var failed []string
+		for _, c := range gateErr.Gate.Failed() {
+			if c.Detail != "" {
+				failed = append(failed, fmt.Sprintf("• %s（%s）", c.Name, c.Detail))
+			} else {
+				failed = append(failed, "• "+c.Name)
+			}
+		}
+		message := fmt.Sprintf("%s阶段门禁未通过：\n%s\n\n仍要强制推进吗？", gateErr.Gate.Phase.Label(), strings.Join(failed, "\n"))
+		dialog.ShowConfirm("门禁未通过", message, func(ok bool) {
+			if ok {
+				p.advance(true)
+			}
+		}, p.window)
+		return
+	}
+	if err != nil {
+		dialog.ShowError(err, p.window)
+		return
+	}
+	p.changed()
+}
+
+func (p *WorkflowPanel) onReturnClicked() {
+	status, err := workflow.NewManager(p.projectPath).Load()
+	if err != nil || status == nil {
+		return
+	}
+
+	var options []string
+	for _, phase := range workflow.Phases[:status.Current().Index()] {
+		options = append(options, phase.Label())
+	}
+	target := widget.NewRadioGroup(options, nil)
+	if len(options) > 0 {
+		target.SetSelected(options[len(options)-1])
+	}
+	note := widget.NewEntry()
+	note.SetPlaceHolder("退回原因（可选）")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("退回到", target),
+		widget.NewFormItem("原因", note),
+	}
+	dialog.ShowForm("退回阶段", "退回", "取消", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		for _, phase := range workflow.Phases {
+			if phase.Label() == target.Selected {
+				if _, err := workflow.NewManager(p.projectPath).Return(phase, note.Text); err != nil {
+					dialog.ShowError(err, p.window)
+					return
+				}
+			}
+		}
+		p.changed()
+	}, p.window)
+}
+
+func (p *WorkflowPanel) onPromptClicked() {
+	prompt, err := workflow.NewManager(p.projectPath).Prompt()
+	if err != nil {
+		dialog.ShowError(err, p.window)
+		return
+	}
+	text := widget.NewMultiLineEntry()
+	text.SetText(prompt)
+	text.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustom("阶段提示词", "关闭", text, p.window)
+	d.Resize(fyne.NewSize(560, 460))
+	d.Show()
+}
+
+// changed 阶段变化后刷新面板并通知外部
+func (p *WorkflowPanel) changed() {
+	p.refresh()
+	if p.onChanged != nil {
+		p.onChanged()
+	}
+}
+
+// phaseProgress 以 “分析 ✓ → [设计] → 开发 → 持久化” 的形式显示阶段进度
+func phaseProgress(current workflow.Phase) string {
+	parts := make([]string, 0, len(workflow.Phases))
+	for i, phase := range workflow.Phases {
+		switch {
+		case i < current.Index():
+			parts = append(parts, phase.Label()+" ✓")
+		case phase == current:
+			parts = append(parts, "["+phase.Label()+"]")
+		default:
+			parts = append(parts, phase.Label())
+		}
+	}
+	return strings.Join(parts, " → ")
+}
+
+// projectPhaseText 项目列表中显示的工作流阶段；未开始工作流时返回空字串
+func projectPhaseText(path string) string {
+	if !workflow.Active(path) {
+		return ""
+	}
+	status, err := workflow.NewManager(path).Load()
+	if err != nil || status == nil {
+		return ""
+	}
+	return fmt.Sprintf("ADDP: %s", status.Current().Label())
+}
+
+func shortTimestamp(ts string) string {
+	if len(ts) >= 16 {
+		return strings.Replace(ts[:16], "T", " ", 1)
+	}
+	return ts
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package history
+
+import "strings"
+
+// Navigator 提供類似 shell 的上下翻閱與反向增量搜索
+type Navigator struct {
+	entries   []string // 從舊到新
+	pos       int      // 當前位置，等於 len(entries) 表示正在編輯的草稿
+	draft     string   // 開始翻閱前輸入框中的內容
+	query     string   // 當前反向搜索的關鍵字
+	searchPos int      // 當前搜索匹配的位置，-1 表示尚未匹配
+}
+
+// NewNavigator 使用從舊到新排列的歷史創建導航器
+func NewNavigator(entries []string) *Navigator {
+	n := &Navigator{entries: append([]string(nil), entries...)}
+	n.Reset()
+	return n
+}
+
+// Len 返回歷史條數
+func (n *Navigator) Len() int {
+	return len(n.entries)
+}
+
+// Push 追加一條新輸入（去重）並重置導航位置
+func (n *Navigator) Push(text string) {
+	if strings.TrimSpace(text) != "" {
+		filtered := n.entries[:0]
+		for _, entry := range n.entries {
+			if entry != text {
+				filtered = append(filtered, entry)
+			}
+		}
+		n.entries = append(filtered, text)
+	}
+	n.Reset()
+}
+
+// Reset 回到草稿位置並結束搜索
+func (n *Navigator) Reset() {
+	n.pos = len(n.entries)
+	n.draft = ""
+	n.EndSearch()
+}
+
+// Prev 向上翻閱一條歷史；current 為輸入框當前內容，首次翻閱時保存為草稿
+func (n *Navigator) Prev(current string) (string, bool) {
+	if len(n.entries) == 0 {
+		return current, false
+	}
+	if n.pos == len(n.entries) {
+		n.draft = current
+	}
+	if n.pos == 0 {
+		return n.entries[0], false
+	}
+	n.pos--
+	return n.entries[n.pos], true
+}
+
+// Next 向下翻閱一條歷史，越過最新記錄後返回草稿
+func (n *Navigator) Next() (string, bool) {
+	if n.pos >= len(n.entries) {
+		return n.draft, false
+	}
+	n.pos++
+	if n.pos == len(n.entries) {
+		return n.draft, true
+	}
+	return n.entries[n.pos], true
+}
+
+// Search 以增量方式反向搜索：關鍵字變長時從當前匹配處繼續向舊記錄查找
+func (n *Navigator) Search(query string) (string, bool) {
+	n.query = query
+	if query == "" {
+		n.searchPos = -1
+		return "", false
+	}
+
+	start := n.searchPos
+	if start < 0 {
+		start = len(n.entries) - 1
+	}
+	return n.searchFrom(start)
+}
+
+// SearchNext 查找更舊的下一個匹配（相當於再次按 Ctrl+R）
+func (n *Navigator) SearchNext() (string, bool) {
+	if n.query == "" {
+		return "", false
+	}
+	if n.searchPos < 0 {
+		return n.searchFrom(len(n.entries) - 1)
+	}
+	return n.searchFrom(n.searchPos - 1)
+}
+
+// SearchQuery 返回當前搜索關鍵字
+func (n *Navigator) SearchQuery() string {
+	return n.query
+}
+
+// EndSearch 結束反向搜索
+func (n *Navigator) EndSearch() {
+	n.query = ""
+	n.searchPos = -1
+}
+
+// searchFrom 從指定位置向舊記錄查找包含關鍵字的條目（不區分大小寫）
+func (n *Navigator) searchFrom(start int) (string, bool) {
+	needle := strings.ToLower(n.query)
+	for i := start; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(n.entries[i]), needle) {
+			n.searchPos = i
+			n.pos = i
+			return n.entries[i], true
+		}
+	}
+	return "", false
+}
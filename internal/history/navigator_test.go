@@ -0,0 +1,97 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNavigator_PrevNext(t *testing.T) {
+	nav := NewNavigator([]string{"first", "second", "third"})
+
+	text, ok := nav.Prev("draft")
+	assert.True(t, ok)
+	assert.Equal(t, "third", text)
+
+	text, _ = nav.Prev(text)
+	assert.Equal(t, "second", text)
+	text, _ = nav.Prev(text)
+	assert.Equal(t, "first", text)
+
+	// 已到最舊記錄
+	text, ok = nav.Prev(text)
+	assert.False(t, ok)
+	assert.Equal(t, "first", text)
+
+	text, _ = nav.Next()
+	assert.Equal(t, "second", text)
+	text, _ = nav.Next()
+	assert.Equal(t, "third", text)
+
+	// 越過最新記錄後恢復草稿
+	text, ok = nav.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "draft", text)
+
+	_, ok = nav.Next()
+	assert.False(t, ok)
+}
+
+func TestNavigator_Empty(t *testing.T) {
+	nav := NewNavigator(nil)
+
+	text, ok := nav.Prev("typing")
+	assert.False(t, ok)
+	assert.Equal(t, "typing", text)
+
+	_, ok = nav.SearchNext()
+	assert.False(t, ok)
+}
+
+func TestNavigator_Push(t *testing.T) {
+	nav := NewNavigator([]string{"a", "b"})
+	nav.Prev("")
+
+	nav.Push("a")
+	assert.Equal(t, 2, nav.Len())
+
+	text, _ := nav.Prev("")
+	assert.Equal(t, "a", text)
+	text, _ = nav.Prev(text)
+	assert.Equal(t, "b", text)
+}
+
+func TestNavigator_ReverseSearch(t *testing.T) {
+	nav := NewNavigator([]string{
+		"git status",
+		"run the tests",
+		"git commit",
+		"explain the diff",
+	})
+
+	// 增量搜索
+	text, ok := nav.Search("g")
+	assert.True(t, ok)
+	assert.Equal(t, "git commit", text)
+
+	text, ok = nav.Search("git s")
+	assert.True(t, ok)
+	assert.Equal(t, "git status", text)
+
+	// 再次 Ctrl+R 查找更舊的匹配
+	nav.EndSearch()
+	nav.Search("the")
+	assert.Equal(t, "the", nav.SearchQuery())
+	text, ok = nav.SearchNext()
+	assert.True(t, ok)
+	assert.Equal(t, "run the tests", text)
+
+	_, ok = nav.SearchNext()
+	assert.False(t, ok)
+
+	_, ok = nav.Search("nothing matches")
+	assert.False(t, ok)
+
+	_, ok = nav.Search("")
+	assert.False(t, ok)
+}
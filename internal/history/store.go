@@ -0,0 +1,274 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/fsutil"
+)
+
+// NewHistoryStore 創建使用 ~/.ai-launcher/history 的歷史存儲
+func NewHistoryStore() *HistoryStore {
+	homeDir, _ := os.UserHomeDir()
+	return NewHistoryStoreWithDir(filepath.Join(homeDir, ".ai-launcher", "history"))
+}
+
+// NewHistoryStoreWithDir 創建使用指定目錄的歷史存儲
+func NewHistoryStoreWithDir(dir string) *HistoryStore {
+	store := &HistoryStore{
+		dir:        dir,
+		maxEntries: DefaultMaxEntries,
+	}
+	// 默認模式均為合法正則，不會出錯
+	_ = store.SetExcludePatterns(DefaultSensitivePatterns)
+	return store
+}
+
+// SetMaxEntries 設置每個項目/工具保留的最大記錄數
+func (s *HistoryStore) SetMaxEntries(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxEntries = n
+}
+
+// SetExcludePatterns 設置敏感輸入的排除規則（正則表達式）
+func (s *HistoryStore) SetExcludePatterns(patterns []string) error {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exclude = compiled
+	return nil
+}
+
+// IsExcluded 檢查輸入是否匹配敏感規則
+func (s *HistoryStore) IsExcluded(text string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isExcluded(text)
+}
+
+func (s *HistoryStore) isExcluded(text string) bool {
+	for _, re := range s.exclude {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add 記錄一條輸入；空白或敏感輸入不會被記錄，返回值表示是否已保存
+func (s *HistoryStore) Add(projectPath, tool, text string) (bool, error) {
+	text = strings.TrimRight(text, "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isExcluded(text) {
+		return false, nil
+	}
+
+	// 多個啟動器實例可能同時記錄同一項目/工具的輸入，讀取與寫回需在文件鎖內完成
+	file := s.filePath(projectPath, tool)
+	err := fsutil.WithLock(file, func() error {
+		entries, err := readEntries(file)
+		if err != nil {
+			return err
+		}
+
+		// 去重：移除舊的相同記錄，最新的放到末尾
+		deduped := entries[:0]
+		for _, entry := range entries {
+			if entry.Text != text {
+				deduped = append(deduped, entry)
+			}
+		}
+		deduped = append(deduped, Entry{
+			Text:    text,
+			Project: projectPath,
+			Tool:    tool,
+			Time:    time.Now(),
+		})
+
+		if s.maxEntries > 0 && len(deduped) > s.maxEntries {
+			deduped = deduped[len(deduped)-s.maxEntries:]
+		}
+		return writeEntries(file, deduped)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Entries 返回項目/工具的全部歷史，按時間從舊到新排列
+func (s *HistoryStore) Entries(projectPath, tool string) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readEntries(s.filePath(projectPath, tool))
+}
+
+// Search 按條件查詢歷史，結果按時間從新到舊排列
+func (s *HistoryStore) Search(q Query) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.matchingFiles(q.Project, q.Tool)
+	if err != nil {
+		return nil, err
+	}
+
+	needle := strings.ToLower(q.Text)
+	results := []Entry{}
+	for _, file := range files {
+		entries, err := readEntries(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if needle == "" || strings.Contains(strings.ToLower(entry.Text), needle) {
+				results = append(results, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time)
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// Clear 刪除項目/工具的全部歷史
+func (s *HistoryStore) Clear(projectPath, tool string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file := s.filePath(projectPath, tool)
+	return fsutil.WithLock(file, func() error {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear history: %w", err)
+		}
+		return nil
+	})
+}
+
+// Navigator 為項目/工具創建一個歷史導航器
+func (s *HistoryStore) Navigator(projectPath, tool string) (*Navigator, error) {
+	entries, err := s.Entries(projectPath, tool)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, len(entries))
+	for i, entry := range entries {
+		texts[i] = entry.Text
+	}
+	return NewNavigator(texts), nil
+}
+
+// filePath 返回項目/工具對應的歷史文件路徑
+func (s *HistoryStore) filePath(projectPath, tool string) string {
+	return filepath.Join(s.dir, projectKey(projectPath), sanitize(tool)+historyFileExt)
+}
+
+// matchingFiles 返回符合項目/工具過濾條件的歷史文件
+func (s *HistoryStore) matchingFiles(projectPath, tool string) ([]string, error) {
+	projectPattern := "*"
+	if projectPath != "" {
+		projectPattern = projectKey(projectPath)
+	}
+	toolPattern := "*"
+	if tool != "" {
+		toolPattern = sanitize(tool)
+	}
+
+	files, err := filepath.Glob(filepath.Join(s.dir, projectPattern, toolPattern+historyFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history files: %w", err)
+	}
+	return files, nil
+}
+
+// projectKey 由項目路徑生成可讀且唯一的目錄名
+func projectKey(projectPath string) string {
+	cleaned := filepath.Clean(projectPath)
+	sum := sha1.Sum([]byte(cleaned))
+	return sanitize(filepath.Base(cleaned)) + "-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// sanitize 將名稱轉換為安全的文件名
+func sanitize(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// readEntries 讀取 JSON Lines 格式的歷史文件，文件不存在時返回空列表
+func readEntries(file string) ([]Entry, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		// 跳過損壞的行，避免一行錯誤導致全部歷史不可用
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// writeEntries 原子地寫入歷史，調用方需持有文件鎖
+func writeEntries(file string, entries []Entry) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+	}
+
+	if err := fsutil.WriteFileAtomic(file, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
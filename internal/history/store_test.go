@@ -0,0 +1,176 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryStore_AddAndEntries(t *testing.T) {
+	store := NewHistoryStoreWithDir(t.TempDir())
+
+	saved, err := store.Add("/work/app", "claude", "explain main.go")
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	_, err = store.Add("/work/app", "claude", "write tests")
+	require.NoError(t, err)
+
+	entries, err := store.Entries("/work/app", "claude")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "explain main.go", entries[0].Text)
+	assert.Equal(t, "write tests", entries[1].Text)
+	assert.Equal(t, "/work/app", entries[0].Project)
+	assert.Equal(t, "claude", entries[0].Tool)
+
+	// 不同工具的歷史互不影響
+	entries, err = store.Entries("/work/app", "gemini")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestHistoryStore_Dedup(t *testing.T) {
+	store := NewHistoryStoreWithDir(t.TempDir())
+
+	for _, text := range []string{"a", "b", "a", "a"} {
+		_, err := store.Add("/p", "claude", text)
+		require.NoError(t, err)
+	}
+
+	entries, err := store.Entries("/p", "claude")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "b", entries[0].Text)
+	assert.Equal(t, "a", entries[1].Text)
+}
+
+func TestHistoryStore_ConcurrentInstances(t *testing.T) {
+	dir := t.TempDir()
+
+	// 兩個實例同時記錄同一項目/工具的輸入時不應丟失記錄
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		store := NewHistoryStoreWithDir(dir)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := store.Add("/work/app", "claude", fmt.Sprintf("input %d-%d", i, j))
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := NewHistoryStoreWithDir(dir).Entries("/work/app", "claude")
+	require.NoError(t, err)
+	assert.Len(t, entries, 40)
+}
+
+func TestHistoryStore_ExcludesSensitive(t *testing.T) {
+	store := NewHistoryStoreWithDir(t.TempDir())
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"refactor the parser", true},
+		{"export OPENAI_API_KEY=abc", false},
+		{"password: hunter2", false},
+		{"use sk-abcdefghijklmnopqrstuvwxyz123", false},
+		{"Authorization: Bearer eyJhbGciOi", false},
+		{"   ", false},
+	}
+
+	for _, tt := range tests {
+		saved, err := store.Add("/p", "claude", tt.input)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, saved, tt.input)
+	}
+
+	require.NoError(t, store.SetExcludePatterns([]string{`^secret`}))
+	saved, _ := store.Add("/p", "claude", "password: now allowed")
+	assert.True(t, saved)
+	saved, _ = store.Add("/p", "claude", "secret stuff")
+	assert.False(t, saved)
+
+	assert.Error(t, store.SetExcludePatterns([]string{"("}))
+}
+
+func TestHistoryStore_MaxEntries(t *testing.T) {
+	store := NewHistoryStoreWithDir(t.TempDir())
+	store.SetMaxEntries(3)
+
+	for _, text := range []string{"1", "2", "3", "4", "5"} {
+		_, err := store.Add("/p", "aider", text)
+		require.NoError(t, err)
+	}
+
+	entries, err := store.Entries("/p", "aider")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "3", entries[0].Text)
+}
+
+func TestHistoryStore_Search(t *testing.T) {
+	store := NewHistoryStoreWithDir(t.TempDir())
+
+	store.Add("/a", "claude", "Fix the login bug")
+	store.Add("/a", "gemini", "review login flow")
+	store.Add("/b", "claude", "add logging")
+	store.Add("/b", "claude", "update README")
+
+	results, err := store.Search(Query{Text: "LOG"})
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "add logging", results[0].Text) // 最新的在前
+
+	results, err = store.Search(Query{Project: "/a", Text: "login"})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	results, err = store.Search(Query{Project: "/a", Tool: "gemini"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "review login flow", results[0].Text)
+
+	results, err = store.Search(Query{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestHistoryStore_ClearAndCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	store := NewHistoryStoreWithDir(dir)
+
+	store.Add("/p", "claude", "hello")
+	file := store.filePath("/p", "claude")
+
+	// 損壞的行應被跳過
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	f.WriteString("{not json\n")
+	f.Close()
+
+	entries, err := store.Entries("/p", "claude")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, store.Clear("/p", "claude"))
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, store.Clear("/p", "claude"))
+}
+
+func TestProjectKey(t *testing.T) {
+	key := projectKey("/home/me/my project")
+	assert.Regexp(t, `^my_project-[0-9a-f]{8}$`, key)
+	assert.NotEqual(t, key, projectKey("/other/my project"))
+	assert.Equal(t, key, projectKey(filepath.Join("/home/me", "my project", ".")))
+}
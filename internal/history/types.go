@@ -0,0 +1,46 @@
+package history
+
+import (
+	"regexp"
+	"sync"
+	"time"
+)
+
+// 默認設置
+const (
+	DefaultMaxEntries = 1000 // 每個項目/工具保留的最大記錄數
+	historyFileExt    = ".jsonl"
+)
+
+// DefaultSensitivePatterns 默認排除的敏感輸入（密鑰、令牌、密碼等）
+var DefaultSensitivePatterns = []string{
+	`(?i)(api[_-]?key|secret|password|passwd|token)\s*[:=]`,
+	`(?i)bearer\s+[a-z0-9._\-]+`,
+	`sk-[A-Za-z0-9_\-]{20,}`,
+	`AKIA[0-9A-Z]{16}`,
+	`gh[pousr]_[A-Za-z0-9]{36}`,
+}
+
+// Entry 一條輸入歷史記錄
+type Entry struct {
+	Text    string    `json:"text"`
+	Project string    `json:"project"` // 項目路徑
+	Tool    string    `json:"tool"`    // 工具名稱，如 claude、gemini
+	Time    time.Time `json:"time"`
+}
+
+// Query 歷史查詢條件，空字段表示不過濾
+type Query struct {
+	Project string `json:"project"`
+	Tool    string `json:"tool"`
+	Text    string `json:"text"`  // 子字符串匹配（不區分大小寫）
+	Limit   int    `json:"limit"` // 0 表示不限制
+}
+
+// HistoryStore 按項目和工具持久化輸入歷史
+type HistoryStore struct {
+	dir        string
+	maxEntries int
+	exclude    []*regexp.Regexp
+	mu         sync.Mutex
+}
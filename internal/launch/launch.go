@@ -0,0 +1,330 @@
+// Package launch 將項目配置轉換為 AI 工具會話的終端配置，
+// 供圖形界面、Web 啟動器與命令行共用，保證各入口的會話行為一致
+package launch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/memory"
+	"ai-launcher/internal/project"
+	addpsync "ai-launcher/internal/sync"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+	"ai-launcher/internal/workflow"
+)
+
+// Options 啟動選項，零值表示使用項目配置與默認配置檔
+type Options struct {
+	Tool    project.AIModelType // 要啟動的工具，為空時使用配置檔或項目配置的模型
+	Yolo    *bool               // 是否以 YOLO 模式啟動，為 nil 時使用配置檔或項目配置
+	Profile string              // 配置檔名稱，為空時使用默認配置檔，project.NoProfile 表示不使用
+	Resume  string              // 要恢復的會話 ID 或 terminal.ResumeLatest
+	Prompt  string              // 第一條提示詞
+
+	Templates *template.TemplateManager // 應用配置檔中的提示詞模板，為 nil 時忽略模板並給出警告
+}
+
+// Request Web 啟動器啟動後台會話的請求體
+type Request struct {
+	Project string              `json:"project"` // 項目名稱或路徑
+	Tool    project.AIModelType `json:"tool,omitempty"`
+	Yolo    *bool               `json:"yolo,omitempty"`
+	Profile string              `json:"profile,omitempty"`
+	Prompt  string              `json:"prompt,omitempty"`
+	Resume  string              `json:"resume,omitempty"`
+}
+
+// Options 返回請求對應的啟動選項
+func (r Request) Options() Options {
+	return Options{Tool: r.Tool, Yolo: r.Yolo, Profile: r.Profile, Resume: r.Resume, Prompt: r.Prompt}
+}
+
+// profile 返回使用的配置檔；默認配置檔指定了其他工具時，按 Tool 啟動不使用它
+func (o Options) profile(proj project.ProjectConfig) (*project.LaunchProfile, error) {
+	profile, err := proj.Profile(o.Profile)
+	if o.Profile == "" && profile != nil && o.Tool != "" && profile.Tool != "" && profile.Tool != o.Tool {
+		return nil, nil
+	}
+	return profile, err
+}
+
+// tool 返回實際啟動的工具
+func (o Options) tool(proj project.ProjectConfig, profile *project.LaunchProfile) project.AIModelType {
+	switch {
+	case o.Tool != "":
+		return o.Tool
+	case profile != nil && profile.Tool != "":
+		return profile.Tool
+	case proj.AIModel != "":
+		return proj.AIModel
+	default:
+		return project.ModelClaudeCode
+	}
+}
+
+// TerminalName 返回項目會話的終端名稱，例如 "web(Claude Code)"
+func TerminalName(proj project.ProjectConfig, tool project.AIModelType) string {
+	return fmt.Sprintf("%s(%s)", proj.Name, tool.String())
+}
+
+// profileTerminalName 返回使用配置檔時的終端名稱，例如 "web(Claude Code/review)"
+func profileTerminalName(proj project.ProjectConfig, tool project.AIModelType, profile *project.LaunchProfile) string {
+	if profile == nil {
+		return TerminalName(proj, tool)
+	}
+	return fmt.Sprintf("%s(%s/%s)", proj.Name, tool.String(), profile.Name)
+}
+
+// TerminalName 返回按這些選項啟動項目時的會話名稱
+func (o Options) TerminalName(proj project.ProjectConfig) string {
+	profile, _ := o.profile(proj)
+	return profileTerminalName(proj, o.tool(proj, profile), profile)
+}
+
+// Resolve 按名稱或路徑查找已保存的項目；未保存的目錄使用項目清單中的工具，未指定時使用 Claude Code。
+// cm 為 nil 時只接受目錄路徑
+func Resolve(cm *project.ConfigManager, nameOrPath string) (project.ProjectConfig, error) {
+	if cm != nil {
+		if p, err := cm.FindProject(nameOrPath); err == nil {
+			return *p, nil
+		}
+	}
+
+	path, err := filepath.Abs(nameOrPath)
+	if err != nil {
+		return project.ProjectConfig{}, err
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return project.ProjectConfig{}, fmt.Errorf("project not found: %s", nameOrPath)
+	}
+	proj := project.ProjectConfig{Name: filepath.Base(path), Path: path, AIModel: project.ModelClaudeCode}
+	if m, err := project.LoadManifest(path); err == nil && m != nil && m.Tool != "" {
+		proj.AIModel = m.Tool
+	}
+	return proj, nil
+}
+
+// Prepare 生成項目會話的終端配置：合併項目清單與配置檔、記錄會話、注入項目記憶、交接摘要與階段提示詞；
+// 返回需要告知用戶的警告，例如清單中未經批准的設置。配置檔不存在、沙箱不受工具支持或工作目錄無效時返回錯誤。
+// cm 為 nil 時不記錄會話，清單視為未批准
+func Prepare(cm *project.ConfigManager, proj project.ProjectConfig, opts Options) (terminal.TerminalConfig, []string, error) {
+	merged, warnings, err := cm.ApplyManifest(proj)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("ignoring %s: %v", project.ManifestFile, err))
+	}
+	profile, err := opts.profile(merged)
+	if err != nil {
+		return terminal.TerminalConfig{}, nil, err
+	}
+
+	tool := opts.tool(merged, profile)
+	yolo := merged.YoloMode
+	if profile != nil && profile.Yolo != nil {
+		yolo = *profile.Yolo
+	}
+	if opts.Yolo != nil {
+		yolo = *opts.Yolo
+	}
+	manifest := merged.Manifest
+	if yolo && manifest != nil && manifest.Yolo != nil && !*manifest.Yolo {
+		yolo = false
+		warnings = append(warnings, fmt.Sprintf("YOLO mode is disabled by %s", project.ManifestFile))
+	}
+	if yolo && profile != nil && profile.Sandbox != project.SandboxNone {
+		yolo = false
+		warnings = append(warnings, fmt.Sprintf("YOLO mode is disabled by the sandbox of profile %q", profile.Name))
+	}
+
+	config := terminal.TerminalConfig{
+		Type:          tool.TerminalType(),
+		Name:          profileTerminalName(merged, tool, profile),
+		WorkingDir:    merged.Path,
+		Command:       tool.GetCommand(yolo),
+		YoloMode:      yolo,
+		Resume:        opts.Resume,
+		InitialPrompt: opts.Prompt,
+	}
+	if manifest != nil {
+		config.Args = manifest.Args
+		config.Environment = manifest.Env
+		config.ReadyPatterns = manifest.ReadyPatterns
+		config.PreLaunch = manifest.Hooks.PreLaunch
+	}
+	if profile != nil {
+		w, err := applyProfile(&config, merged.Path, tool, *profile, opts.Templates)
+		if err != nil {
+			return terminal.TerminalConfig{}, nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+		warnings = append(warnings, w...)
+	}
+	if cm != nil {
+		// 保存個人配置，清單中的設置不寫入 projects.json
+		recordSession(cm, proj, tool, &config)
+		markUsed(cm, proj.Path)
+	}
+
+	// 注入順序：階段提示詞、交接摘要、項目記憶、用戶提示詞
+	memory.Attach(&config, merged.Path)
+	addpsync.Attach(&config, merged.Path, opts.Prompt)
+	workflow.Attach(&config, merged.Path)
+	return config, warnings, nil
+}
+
+// applyProfile 將配置檔的參數、環境變量、沙箱、工作目錄與提示詞模板合併到終端配置
+func applyProfile(config *terminal.TerminalConfig, projectDir string, tool project.AIModelType, profile project.LaunchProfile, templates *template.TemplateManager) ([]string, error) {
+	var warnings []string
+
+	sandboxArgs, err := profile.Sandbox.Args(tool)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{}, config.Args...)
+	config.Args = append(append(args, profile.Args...), sandboxArgs...)
+
+	if len(profile.Env) > 0 {
+		env := make(map[string]string, len(config.Environment)+len(profile.Env))
+		for k, v := range config.Environment {
+			env[k] = v
+		}
+		for k, v := range profile.Env {
+			env[k] = v
+		}
+		config.Environment = env
+	}
+
+	if profile.WorkDir != "" {
+		dir := filepath.Join(projectDir, profile.WorkDir)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("work_dir is not a directory: %s", dir)
+		}
+		config.WorkingDir = dir
+		config.ProjectDir = projectDir
+	}
+
+	if profile.Template != "" && config.InitialPrompt != "" {
+		if templates == nil {
+			return append(warnings, fmt.Sprintf("template %q of profile %q is not applied", profile.Template, profile.Name)), nil
+		}
+		applied, err := templates.ApplyTemplate(profile.Template, config.InitialPrompt, nil)
+		if err != nil {
+			return nil, err
+		}
+		config.InitialPrompt = applied.OptimizedPrompt
+	}
+	return warnings, nil
+}
+
+// projectDir 返回會話所屬的項目根目錄
+func projectDir(config terminal.TerminalConfig) string {
+	if config.ProjectDir != "" {
+		return config.ProjectDir
+	}
+	return config.WorkingDir
+}
+
+// recordSession 為新會話預先分配 ID，並將會話記錄到項目歷史中
+func recordSession(cm *project.ConfigManager, proj project.ProjectConfig, tool project.AIModelType, config *terminal.TerminalConfig) {
+	sessionID := config.Resume
+	if sessionID == "" && terminal.SupportsSessionID(config.Type) {
+		sessionID = terminal.NewSessionID()
+		config.SessionID = sessionID
+	}
+	// 繼續最近一次會話時由工具自行選擇，ID 未知
+	if sessionID == "" || sessionID == terminal.ResumeLatest {
+		return
+	}
+
+	if _, err := cm.GetProjectByPath(proj.Path); err != nil {
+		if err := cm.AddProject(proj); err != nil {
+			log.Printf("[launch] add project failed: %v", err)
+			return
+		}
+	}
+	record := project.SessionRecord{ID: sessionID, AIModel: tool}
+	if err := cm.RecordSession(proj.Path, record); err != nil {
+		log.Printf("[launch] record session failed: %v", err)
+	}
+}
+
+// markUsed 更新已保存項目的使用時間與次數，供按最近使用與使用頻率排序
+func markUsed(cm *project.ConfigManager, path string) {
+	if _, err := cm.GetProjectByPath(path); err != nil {
+		return
+	}
+	if err := cm.UpdateProjectUsage(path); err != nil {
+		log.Printf("[launch] update project usage failed: %v", err)
+	}
+}
+
+// CheckAvailable 同名會話仍在運行時返回錯誤；在 Prepare 之前調用可避免記錄未能啟動的會話
+func CheckAvailable(tm terminal.Manager, name string) error {
+	if existing, ok := tm.GetTerminal(name); ok && !existing.Exited() {
+		return fmt.Errorf("terminal '%s' is already running", name)
+	}
+	return nil
+}
+
+// Start 運行啟動鉤子後啟動會話：同名會話已退出時替換它，仍在運行時返回錯誤；
+// 項目啟用了自動門禁時在後台監視會話，結果交給 onGates（可為 nil）
+func Start(tm terminal.Manager, config terminal.TerminalConfig, onGates func(*gates.Report, error)) error {
+	if err := CheckAvailable(tm, config.Name); err != nil {
+		return err
+	}
+	if _, ok := tm.GetTerminal(config.Name); ok {
+		_ = tm.RemoveTerminal(config.Name)
+	}
+	if err := RunPreLaunch(context.Background(), config); err != nil {
+		return err
+	}
+	if err := tm.StartTerminal(config); err != nil {
+		return err
+	}
+
+	dir := projectDir(config)
+	cfg, err := gates.LoadConfig(dir, project.NewConfigManager().ManifestTrusted)
+	if err != nil {
+		log.Printf("[launch] load gates config failed: %v", err)
+		return nil
+	}
+	if cfg.AutoRun(dir) {
+		go gates.Watch(context.Background(), tm, config.Name, dir, cfg, func(report *gates.Report, err error) {
+			if err != nil && !errors.Is(err, gates.ErrNotInitialized) {
+				log.Printf("[launch] save gates report failed: %v", err)
+			}
+			if onGates != nil {
+				onGates(report, err)
+			}
+		})
+	}
+	return nil
+}
+
+// RunPreLaunch 在項目根目錄中依次運行啟動鉤子，任一失敗時停止並返回包含輸出結尾的錯誤
+func RunPreLaunch(ctx context.Context, config terminal.TerminalConfig) error {
+	for _, hook := range config.PreLaunch {
+		hookConfig := &gates.Config{Gates: []gates.Gate{{Name: "pre_launch", Command: hook}}}
+		report := gates.Run(ctx, projectDir(config), hookConfig, gates.TriggerManual)
+		if len(report.Results) == 0 {
+			return ctx.Err()
+		}
+		res := report.Results[0]
+		if res.Passed {
+			continue
+		}
+		detail := res.Error
+		if detail == "" {
+			detail = fmt.Sprintf("exit code %d", res.ExitCode)
+		}
+		if res.Output != "" {
+			detail += "\n" + res.Output
+		}
+		return fmt.Errorf("pre-launch hook %q failed: %s", hook, detail)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/memory"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+)
+
+// mustPrepare 調用 Prepare 並要求成功
+func mustPrepare(t *testing.T, cm *project.ConfigManager, proj project.ProjectConfig, opts Options) (terminal.TerminalConfig, []string) {
+	t.Helper()
+	config, warnings, err := Prepare(cm, proj, opts)
+	require.NoError(t, err)
+	return config, warnings
+}
+
+func TestPrepare_UsesProjectDefaults(t *testing.T) {
+	proj := project.ProjectConfig{Name: "web", Path: t.TempDir(), AIModel: project.ModelGeminiCLI, YoloMode: true}
+
+	config, _ := mustPrepare(t, nil, proj, Options{})
+	assert.Equal(t, "web(Gemini CLI)", config.Name)
+	assert.Equal(t, terminal.TypeGeminiCLI, config.Type)
+	assert.Equal(t, []string{"gemini", "--yolo"}, config.Command)
+	assert.Equal(t, proj.Path, config.WorkingDir)
+	assert.True(t, config.YoloMode)
+	assert.Empty(t, config.SessionID)
+
+	yolo := false
+	config, _ = mustPrepare(t, nil, proj, Options{Tool: project.ModelCodex, Yolo: &yolo, Prompt: "fix the build"})
+	assert.Equal(t, "web(Codex)", config.Name)
+	assert.Equal(t, []string{"codex"}, config.Command)
+	assert.False(t, config.YoloMode)
+	assert.Equal(t, "fix the build", config.InitialPrompt)
+
+	// 未配置模型時默認使用 Claude Code
+	config, _ = mustPrepare(t, nil, project.ProjectConfig{Name: "api", Path: proj.Path}, Options{})
+	assert.Equal(t, terminal.TypeClaudeCode, config.Type)
+}
+
+func TestPrepare_RecordsSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	cm := project.NewConfigManager()
+	require.NoError(t, cm.LoadProjects())
+
+	proj := project.ProjectConfig{Name: "web", Path: t.TempDir(), AIModel: project.ModelClaudeCode}
+	config, _ := mustPrepare(t, cm, proj, Options{})
+	require.NotEmpty(t, config.SessionID)
+
+	sessions, err := cm.GetSessions(proj.Path, project.ModelClaudeCode)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, config.SessionID, sessions[0].ID)
+
+	// 繼續最近一次會話時 ID 未知，不記錄
+	config, _ = mustPrepare(t, cm, proj, Options{Resume: terminal.ResumeLatest})
+	assert.Empty(t, config.SessionID)
+	sessions, _ = cm.GetSessions(proj.Path, "")
+	assert.Len(t, sessions, 1)
+
+	// 每次啟動都計入使用次數
+	saved, err := cm.GetProjectByPath(proj.Path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved.UseCount)
+}
+
+func TestPrepare_InjectsProjectMemory(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(path, ".addp"), 0755))
+	_, err := memory.NewStore(path).Add(memory.Record{Kind: memory.KindDecision, Title: "Build with make", Tags: []string{"build"}})
+	require.NoError(t, err)
+
+	config, _ := mustPrepare(t, nil, project.ProjectConfig{Name: "web", Path: path}, Options{Prompt: "fix the build"})
+	assert.True(t, strings.HasPrefix(config.InitialPrompt, "[Project memory: 1 relevant entries"), config.InitialPrompt)
+	assert.True(t, strings.HasSuffix(config.InitialPrompt, "\n\nfix the build"))
+}
+
+func TestPrepare_AppliesManifest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	cm := project.NewConfigManager()
+	require.NoError(t, cm.LoadProjects())
+
+	path := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(path, project.ManifestFile), []byte(`
+launch:
+  tool: gemini_cli
+  args: [--sandbox, --yolo]
+  env:
+    NODE_ENV: development
+  yolo: true
+  ready_patterns: ["> "]
+  hooks:
+    pre_launch: ["npm ci"]
+`), 0644))
+
+	// 未批准時只應用無需信任的設置
+	config, warnings := mustPrepare(t, cm, project.ProjectConfig{Name: "web", Path: path}, Options{Resume: terminal.ResumeLatest})
+	assert.Equal(t, terminal.TypeGeminiCLI, config.Type)
+	assert.Equal(t, []string{"gemini"}, config.Command)
+	assert.Empty(t, config.Args)
+	assert.Empty(t, config.Environment)
+	assert.Equal(t, []string{"> "}, config.ReadyPatterns)
+	assert.Empty(t, config.PreLaunch)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "args: --sandbox; args: --yolo")
+	assert.Contains(t, warnings[0], "hooks.pre_launch: npm ci")
+	assert.Contains(t, warnings[0], "env: NODE_ENV=development")
+
+	_, err := cm.TrustManifest(path)
+	require.NoError(t, err)
+	config, warnings = mustPrepare(t, cm, project.ProjectConfig{Name: "web", Path: path}, Options{Resume: terminal.ResumeLatest})
+	assert.Empty(t, warnings)
+	// 清單的 yolo: true 不會替用戶開啟 YOLO
+	assert.Equal(t, []string{"gemini"}, config.Command)
+	assert.False(t, config.YoloMode)
+	assert.Equal(t, []string{"--sandbox", "--yolo"}, config.Args)
+	assert.Equal(t, []string{"npm ci"}, config.PreLaunch)
+	assert.Equal(t, map[string]string{"NODE_ENV": "development"}, config.Environment)
+
+	// 個人配置的工具優先；清單禁止 YOLO 時顯式請求也被拒絕
+	require.NoError(t, os.WriteFile(filepath.Join(path, project.ManifestFile), []byte("launch:\n  tool: gemini_cli\n  yolo: false\n"), 0644))
+	yolo := true
+	config, warnings = mustPrepare(t, nil, project.ProjectConfig{Name: "web", Path: path, AIModel: project.ModelCodex}, Options{Yolo: &yolo})
+	assert.Equal(t, []string{"codex"}, config.Command)
+	assert.False(t, config.YoloMode)
+	assert.Equal(t, []string{"YOLO mode is disabled by .ai-launcher.yaml"}, warnings)
+
+	// 無效的清單被忽略
+	require.NoError(t, os.WriteFile(filepath.Join(path, project.ManifestFile), []byte("launch:\n  tool: vim\n"), 0644))
+	config, warnings = mustPrepare(t, nil, project.ProjectConfig{Name: "web", Path: path}, Options{})
+	assert.Equal(t, terminal.TypeClaudeCode, config.Type)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], `未知的 AI 工具 "vim"`)
+}
+
+func TestPrepare_AppliesProfile(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(path, "web"), 0755))
+	yolo := true
+	proj := project.ProjectConfig{Name: "app", Path: path, AIModel: project.ModelClaudeCode, Profiles: []project.LaunchProfile{
+		{Name: "mcp", Yolo: &yolo, Args: []string{"--mcp-config", "mcp.json"}, Env: map[string]string{"DEBUG": "1"}, Default: true},
+		{Name: "review", Tool: project.ModelCodex, Sandbox: project.SandboxReadOnly, WorkDir: "web", Template: template.TemplateDebug},
+		{Name: "broken", Tool: project.ModelGeminiCLI, Sandbox: project.SandboxReadOnly},
+	}}
+
+	// 未指定時使用默認配置檔
+	config, _ := mustPrepare(t, nil, proj, Options{})
+	assert.Equal(t, "app(Claude Code/mcp)", config.Name)
+	assert.Equal(t, []string{"claude", "--dangerously-skip-permissions"}, config.Command)
+	assert.Equal(t, []string{"--mcp-config", "mcp.json"}, config.Args)
+	assert.Equal(t, map[string]string{"DEBUG": "1"}, config.Environment)
+	assert.Equal(t, "app(Claude Code/mcp)", Options{}.TerminalName(proj))
+
+	// 按其他工具啟動或明確不使用配置檔時不使用默認配置檔
+	config, _ = mustPrepare(t, nil, proj, Options{Profile: project.NoProfile})
+	assert.Equal(t, "app(Claude Code)", config.Name)
+	assert.Empty(t, config.Args)
+
+	// 沙箱配置檔關閉 YOLO、切換到子目錄並套用模板
+	config, warnings := mustPrepare(t, nil, proj, Options{Profile: "review", Yolo: &yolo, Prompt: "main.go", Templates: template.NewTemplateManager()})
+	assert.Equal(t, []string{"codex"}, config.Command)
+	assert.Equal(t, []string{"--sandbox", "read-only"}, config.Args)
+	assert.Equal(t, filepath.Join(path, "web"), config.WorkingDir)
+	assert.Equal(t, path, config.ProjectDir)
+	assert.Contains(t, config.InitialPrompt, "main.go")
+	assert.NotEqual(t, "main.go", config.InitialPrompt)
+	assert.Equal(t, []string{`YOLO mode is disabled by the sandbox of profile "review"`}, warnings)
+
+	_, warnings = mustPrepare(t, nil, proj, Options{Profile: "review", Prompt: "main.go"})
+	assert.Equal(t, []string{fmt.Sprintf("template %q of profile \"review\" is not applied", template.TemplateDebug)}, warnings)
+
+	_, _, err := Prepare(nil, proj, Options{Profile: "missing"})
+	assert.ErrorContains(t, err, "没有配置档")
+	_, _, err = Prepare(nil, proj, Options{Profile: "broken"})
+	assert.ErrorContains(t, err, "不支持沙箱级别")
+}
+
+func TestRunPreLaunch(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	config := terminal.TerminalConfig{WorkingDir: dir, PreLaunch: []string{"touch first", "echo broken; exit 2", "touch never"}}
+
+	err := RunPreLaunch(context.Background(), config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `pre-launch hook "echo broken; exit 2" failed: exit code 2`)
+	assert.Contains(t, err.Error(), "broken")
+	assert.FileExists(t, filepath.Join(dir, "first"))
+	assert.NoFileExists(t, filepath.Join(dir, "never"))
+
+	// 鉤子失敗時不啟動會話
+	tm := terminal.NewTerminalManager()
+	config.Name = "web(Claude Code)"
+	assert.Error(t, Start(tm, config, nil))
+	_, ok := tm.GetTerminal(config.Name)
+	assert.False(t, ok)
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+
+	proj, err := Resolve(nil, dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Base(dir), proj.Name)
+	assert.Equal(t, project.ModelClaudeCode, proj.AIModel)
+
+	_, err = Resolve(nil, "no-such-project")
+	assert.Error(t, err)
+}
+
+func TestStart_ReplacesExitedSession(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	tm := terminal.NewTerminalManager()
+	defer func() {
+		for _, term := range tm.ListTerminals() {
+			tm.RemoveTerminal(term.Name)
+		}
+	}()
+
+	config := terminal.TerminalConfig{Name: "web(Claude Code)", Type: terminal.TypeCustom, Command: []string{"sleep", "0"}, WorkingDir: t.TempDir()}
+	require.NoError(t, Start(tm, config, nil))
+	first, ok := tm.GetTerminal(config.Name)
+	require.True(t, ok)
+	<-first.Done()
+	assert.NoError(t, CheckAvailable(tm, config.Name))
+
+	// 已退出的同名會話被替換
+	config.Command = []string{"sleep", "10"}
+	require.NoError(t, Start(tm, config, nil))
+	second, ok := tm.GetTerminal(config.Name)
+	require.True(t, ok)
+	assert.NotSame(t, first, second)
+
+	assert.EqualError(t, CheckAvailable(tm, config.Name), "terminal 'web(Claude Code)' is already running")
+	assert.Error(t, Start(tm, config, nil))
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 欄位權重：標題與標籤命中比正文更能說明相關性
+const (
+	titleWeight = 3.0
+	tagWeight   = 3.0
+	bodyWeight  = 1.0
+)
+
+// stopWords 不參與檢索的常見英文詞
+var stopWords = map[string]bool{
+	"an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true, "do": true,
+	"for": true, "from": true, "how": true, "in": true, "is": true, "it": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true, "we": true, "what": true,
+	"with": true,
+}
+
+// Index 記憶的倒排索引，按 TF-IDF 對查詢結果排序
+type Index struct {
+	records  []*Record
+	postings map[string]map[int]float64 // 詞 -> 記錄下標 -> 加權詞頻
+	lengths  []float64                  // 每條記錄的加權詞數，用於長度歸一化
+}
+
+// Result 檢索結果
+type Result struct {
+	*Record
+	Score float64 `json:"score"`
+}
+
+// NewIndex 為記錄建立索引
+func NewIndex(records []*Record) *Index {
+	idx := &Index{
+		records:  records,
+		postings: make(map[string]map[int]float64),
+		lengths:  make([]float64, len(records)),
+	}
+	for i, r := range records {
+		idx.add(i, r.Title, titleWeight)
+		idx.add(i, strings.Join(r.Tags, " "), tagWeight)
+		idx.add(i, r.Body, bodyWeight)
+	}
+	return idx
+}
+
+func (idx *Index) add(i int, text string, weight float64) {
+	for _, term := range tokenize(text) {
+		postings := idx.postings[term]
+		if postings == nil {
+			postings = make(map[int]float64)
+			idx.postings[term] = postings
+		}
+		postings[i] += weight
+		idx.lengths[i] += weight
+	}
+}
+
+// Search 返回與查詢相關的記錄，得分高的在前；得分相同時較新的在前
+func (idx *Index) Search(query string, limit int) []Result {
+	scores := make(map[int]float64)
+	n := float64(len(idx.records))
+	for _, term := range uniqueTerms(tokenize(query)) {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + n/float64(len(postings)))
+		for i, tf := range postings {
+			scores[i] += (1 + math.Log(tf)) * idf / math.Sqrt(idx.lengths[i])
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for i, score := range scores {
+		results = append(results, Result{Record: idx.records[i], Score: score})
+	}
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].Created.After(results[b].Created)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// tokenize 將文本切分為小寫詞；中日韓文字沒有空格分詞，使用單字與相鄰二字詞
+func tokenize(text string) []string {
+	var terms []string
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 1 && !stopWords[string(word)] || len(word) == 1 && unicode.IsDigit(word[0]) {
+			terms = append(terms, string(word))
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		for i, r := range cjk {
+			terms = append(terms, string(r))
+			if i+1 < len(cjk) {
+				terms = append(terms, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return terms
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-launcher/internal/terminal"
+)
+
+const (
+	// DefaultRelevant 注入提示詞的記憶條數
+	DefaultRelevant = 3
+
+	promptBodyChars = 800 // 每條記憶注入的最大長度
+)
+
+var kindLabels = map[Kind]string{
+	KindContext:  "Context",
+	KindDecision: "Decision",
+	KindLesson:   "Lesson",
+	KindSession:  "Session",
+}
+
+// Relevant 返回與文本最相關的 n 條記憶；文本為空時返回最新的背景、決策與教訓，沒有命中時返回 nil
+func Relevant(projectPath, text string, n int) []*Record {
+	if n <= 0 {
+		n = DefaultRelevant
+	}
+	store := NewStore(projectPath)
+
+	var records []*Record
+	if strings.TrimSpace(text) == "" {
+		all, err := store.List("")
+		if err != nil {
+			return nil
+		}
+		for _, r := range all {
+			if r.Kind != KindSession && len(records) < n {
+				records = append(records, r)
+			}
+		}
+		return records
+	}
+
+	results, err := store.Search(Query{Text: text, Limit: n})
+	if err != nil {
+		return nil
+	}
+	for _, res := range results {
+		records = append(records, res.Record)
+	}
+	return records
+}
+
+// ContextPrompt 將記憶格式化為注入提示詞的上下文；沒有記憶時返回空字串
+func ContextPrompt(records []*Record) string {
+	if len(records) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Project memory: %d relevant entries from .addp/memory]\n", len(records))
+	for _, r := range records {
+		fmt.Fprintf(&b, "\n## %s: %s (%s", kindLabels[r.Kind], r.Title, r.Created.Format("2006-01-02"))
+		if len(r.Tags) > 0 {
+			fmt.Fprintf(&b, ", tags: %s", strings.Join(r.Tags, ", "))
+		}
+		b.WriteString(")\n")
+		body := r.Body
+		if rs := []rune(body); len(rs) > promptBodyChars {
+			body = string(rs[:promptBodyChars]) + "..."
+		}
+		if body != "" {
+			b.WriteString(body)
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("[End of project memory]")
+	return b.String()
+}
+
+// QueryContext 返回與查詢最相關的記憶，作為 OllamaClient.OptimizeQuery 的上下文
+func QueryContext(projectPath, query string, n int) string {
+	return ContextPrompt(Relevant(projectPath, query, n))
+}
+
+// InjectPrompt 在提示詞前加入最相關的記憶，用於非交互任務
+func InjectPrompt(projectPath, prompt string) string {
+	context := ContextPrompt(Relevant(projectPath, prompt, DefaultRelevant))
+	if context == "" {
+		return prompt
+	}
+	return context + "\n\n" + prompt
+}
+
+// Attach 在新會話的第一條提示詞前加入最相關的記憶；恢復已有會話時不注入
+func Attach(config *terminal.TerminalConfig, projectPath string) {
+	if config.Resume != "" {
+		return
+	}
+	context := ContextPrompt(Relevant(projectPath, config.InitialPrompt, DefaultRelevant))
+	if context == "" {
+		return
+	}
+	if config.InitialPrompt != "" {
+		config.InitialPrompt = context + "\n\n" + config.InitialPrompt
+	} else {
+		config.InitialPrompt = context
+	}
+}
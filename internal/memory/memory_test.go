@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/terminal"
+)
+
+func newProject(t *testing.T) string {
+	t.Helper()
+	project := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(project, ".addp"), 0755))
+	return project
+}
+
+func TestStore_AddAndParse(t *testing.T) {
+	project := newProject(t)
+	store := NewStore(project)
+
+	created := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	r, err := store.Add(Record{
+		Kind:    KindDecision,
+		Title:   "Use SQLite for the job store",
+		Tags:    []string{"storage", "queue"},
+		Tool:    "claude",
+		Created: created,
+		Body:    "JSON files do not scale past a few thousand jobs.\n",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "20260304-050607-use-sqlite-for-the-job-store", r.ID)
+	assert.Equal(t, filepath.Join(project, ".addp", "memory", "decisions", r.ID+".md"), r.Path)
+
+	data, err := os.ReadFile(r.Path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "---\nid: 20260304-050607-use-sqlite-for-the-job-store\nkind: decisions\n"))
+	assert.Contains(t, string(data), "---\n\nJSON files do not scale past a few thousand jobs.\n")
+
+	// 同一秒內同名的記憶使用不同 ID
+	dup, err := store.Add(Record{Kind: KindDecision, Title: r.Title, Created: created})
+	require.NoError(t, err)
+	assert.Equal(t, r.ID+"-2", dup.ID)
+
+	got, err := store.Get(r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, r.Title, got.Title)
+	assert.Equal(t, []string{"storage", "queue"}, got.Tags)
+	assert.Equal(t, "claude", got.Tool)
+	assert.True(t, created.Equal(got.Created))
+	assert.Equal(t, "JSON files do not scale past a few thousand jobs.", got.Body)
+
+	_, err = store.Get("missing")
+	assert.Error(t, err)
+}
+
+func TestStore_AddValidation(t *testing.T) {
+	_, err := NewStore(t.TempDir()).Add(Record{Title: "x"})
+	assert.ErrorIs(t, err, ErrNotInitialized)
+
+	store := NewStore(newProject(t))
+	_, err = store.Add(Record{Kind: "notes", Title: "x"})
+	assert.Error(t, err)
+	_, err = store.Add(Record{Body: "   "})
+	assert.Error(t, err)
+
+	r, err := store.Add(Record{Body: "## Flaky tests on CI\nThe terminal tests depend on timing."})
+	require.NoError(t, err)
+	assert.Equal(t, KindContext, r.Kind)
+	assert.Equal(t, "Flaky tests on CI", r.Title)
+}
+
+func TestStore_HandWrittenFiles(t *testing.T) {
+	project := newProject(t)
+	dir := filepath.Join(project, ".addp", "memory", "lessons")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "windows-paths.md"), []byte("\ufeff# Windows 路徑\r\n\r\n批處理中的路徑必須加引號。\r\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.md"), []byte("---\ntitle: [unterminated\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+	// addp init 生成的 JSON 不是記憶
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".addp", "memory", "context"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".addp", "memory", "context", "project_context.json"), []byte("{}"), 0644))
+
+	records, err := NewStore(project).List("")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "windows-paths", records[0].ID)
+	assert.Equal(t, KindLesson, records[0].Kind)
+	assert.Equal(t, "Windows 路徑", records[0].Title)
+	assert.Equal(t, "# Windows 路徑\n\n批處理中的路徑必須加引號。", records[0].Body)
+	assert.False(t, records[0].Created.IsZero())
+}
+
+func TestStore_ListAndSearch(t *testing.T) {
+	project := newProject(t)
+	store := NewStore(project)
+	base := time.Now().Add(-time.Hour)
+
+	add := func(kind Kind, title, body string, tags ...string) *Record {
+		base = base.Add(time.Minute)
+		r, err := store.Add(Record{Kind: kind, Title: title, Body: body, Tags: tags, Created: base})
+		require.NoError(t, err)
+		return r
+	}
+	sqlite := add(KindDecision, "Use SQLite for the job store", "Queue jobs are stored in SQLite.", "storage", "queue")
+	retry := add(KindLesson, "Retry flaky network calls", "The Ollama API times out under load; retry with backoff.")
+	add(KindSession, "Session 42", "Refactored the queue worker pool.")
+	cjk := add(KindContext, "部署環境", "生產環境使用 Docker 部署，數據庫是 PostgreSQL。")
+
+	records, err := store.List("")
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, cjk.ID, records[0].ID, "newest first")
+
+	lessons, err := store.List(KindLesson)
+	require.NoError(t, err)
+	require.Len(t, lessons, 1)
+	assert.Equal(t, retry.ID, lessons[0].ID)
+
+	results, err := store.Search(Query{Text: "sqlite storage"})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, sqlite.ID, results[0].ID)
+	assert.Greater(t, results[0].Score, 0.0)
+
+	results, err = store.Search(Query{Text: "queue"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, sqlite.ID, results[0].ID, "tag match outranks body match")
+
+	results, err = store.Search(Query{Text: "queue", Kind: KindSession})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	results, err = store.Search(Query{Text: "數據庫用什麼"})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, cjk.ID, results[0].ID)
+
+	results, err = store.Search(Query{Text: "kubernetes"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = store.Search(Query{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	// 外部修改文件後索引自動更新
+	require.NoError(t, os.WriteFile(retry.Path, []byte("# Kubernetes probes\nReadiness probes need a grace period.\n"), 0644))
+	results, err = store.Search(Query{Text: "kubernetes"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, retry.ID, results[0].ID)
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"go", "test", "42"}, tokenize("Go test: a 42 for the"))
+	assert.Equal(t, []string{"使", "使用", "用", "docker"}, tokenize("使用Docker"))
+}
+
+func TestParseKind(t *testing.T) {
+	for in, want := range map[string]Kind{"decision": KindDecision, "Lessons": KindLesson, "context": KindContext, "session": KindSession} {
+		got, err := ParseKind(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseKind("note")
+	assert.Error(t, err)
+}
+
+func TestInjection(t *testing.T) {
+	project := newProject(t)
+	assert.Empty(t, QueryContext(project, "anything", 3))
+	assert.Equal(t, "fix the queue", InjectPrompt(project, "fix the queue"))
+
+	store := NewStore(project)
+	_, err := store.Add(Record{Kind: KindDecision, Title: "Queue retries", Body: "Jobs retry up to three times.", Tags: []string{"queue"}})
+	require.NoError(t, err)
+	_, err = store.Add(Record{Kind: KindSession, Title: "Yesterday", Body: "Worked on the GUI."})
+	require.NoError(t, err)
+
+	prompt := InjectPrompt(project, "fix the queue")
+	assert.True(t, strings.HasPrefix(prompt, "[Project memory: 1 relevant entries from .addp/memory]\n\n## Decision: Queue retries ("))
+	assert.Contains(t, prompt, ", tags: queue)\nJobs retry up to three times.\n[End of project memory]\n\nfix the queue")
+
+	assert.Contains(t, QueryContext(project, "gui", 3), "## Session: Yesterday")
+	assert.Equal(t, "unrelated", InjectPrompt(project, "unrelated"))
+
+	// 沒有提示詞時注入最新的非會話記憶
+	config := terminal.TerminalConfig{}
+	Attach(&config, project)
+	assert.Contains(t, config.InitialPrompt, "Queue retries")
+	assert.NotContains(t, config.InitialPrompt, "Yesterday")
+
+	config = terminal.TerminalConfig{InitialPrompt: "continue the gui work"}
+	Attach(&config, project)
+	assert.True(t, strings.HasSuffix(config.InitialPrompt, "[End of project memory]\n\ncontinue the gui work"))
+
+	config = terminal.TerminalConfig{Resume: terminal.ResumeLatest}
+	Attach(&config, project)
+	assert.Empty(t, config.InitialPrompt)
+}
@@ -0,0 +1,129 @@
+// Package memory 讀寫 ADDP 項目的跨工具記憶（.addp/memory），每條記憶是一個帶 front matter 的 Markdown 文件；
+// 提供全文檢索，並可把最相關的記憶加入查詢優化的上下文或會話的第一條提示詞
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind 記憶類型，對應 .addp/memory 下的子目錄
+type Kind string
+
+const (
+	KindContext  Kind = "context"   // 項目背景與約束
+	KindDecision Kind = "decisions" // 技術決策
+	KindLesson   Kind = "lessons"   // 經驗教訓
+	KindSession  Kind = "sessions"  // 會話記錄
+)
+
+// Kinds 所有記憶類型
+var Kinds = []Kind{KindContext, KindDecision, KindLesson, KindSession}
+
+// ParseKind 解析記憶類型，接受單數形式（decision、lesson、session）
+func ParseKind(s string) (Kind, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, k := range Kinds {
+		if s == string(k) || s+"s" == string(k) {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown memory kind %q (context, decision, lesson or session)", s)
+}
+
+// Record 一條記憶
+type Record struct {
+	ID      string    `yaml:"id" json:"id"`
+	Kind    Kind      `yaml:"kind" json:"kind"`
+	Title   string    `yaml:"title" json:"title"`
+	Tags    []string  `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Tool    string    `yaml:"tool,omitempty" json:"tool,omitempty"` // 記錄記憶的工具
+	Created time.Time `yaml:"created" json:"created"`
+
+	Body string `yaml:"-" json:"body"`
+	Path string `yaml:"-" json:"path"`
+}
+
+const frontMatterDelim = "---"
+
+// Markdown 將記憶格式化為帶 front matter 的 Markdown
+func (r *Record) Markdown() ([]byte, error) {
+	meta, err := yaml.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode front matter: %w", err)
+	}
+	var b bytes.Buffer
+	b.WriteString(frontMatterDelim + "\n")
+	b.Write(meta)
+	b.WriteString(frontMatterDelim + "\n\n")
+	b.WriteString(strings.TrimSpace(r.Body))
+	b.WriteString("\n")
+	return b.Bytes(), nil
+}
+
+var headingPattern = regexp.MustCompile(`(?m)^#\s+(.+)$`)
+
+// parseRecord 解析記憶文件；沒有 front matter 的手寫文件以第一個標題或文件名作為標題，修改時間作為創建時間
+func parseRecord(path string, kind Kind, data []byte) (*Record, error) {
+	r := &Record{}
+	body := string(bytes.TrimPrefix(data, []byte("\ufeff")))
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+
+	if strings.HasPrefix(body, frontMatterDelim+"\n") {
+		rest := body[len(frontMatterDelim):]
+		end := strings.Index(rest, "\n"+frontMatterDelim)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated front matter in %s", filepath.Base(path))
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), r); err != nil {
+			return nil, fmt.Errorf("failed to parse front matter in %s: %w", filepath.Base(path), err)
+		}
+		body = rest[end+1+len(frontMatterDelim):]
+	}
+
+	r.Body = strings.TrimSpace(body)
+	r.Path = path
+	r.Kind = kind // 以所在目錄為準
+	if r.ID == "" {
+		r.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if r.Title == "" {
+		if m := headingPattern.FindStringSubmatch(r.Body); m != nil {
+			r.Title = strings.TrimSpace(m[1])
+		} else {
+			r.Title = r.ID
+		}
+	}
+	if r.Created.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			r.Created = info.ModTime()
+		}
+	}
+	return r, nil
+}
+
+// slug 將標題轉換為文件名的一部分，保留字母、數字與中日韓文字
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if isWordRune(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 48 {
+			break
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
@@ -0,0 +1,244 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-launcher/internal/addp"
+	"ai-launcher/internal/fsutil"
+)
+
+const idLayout = "20060102-150405"
+
+// ErrNotInitialized 項目沒有 .addp 目錄時不寫入記憶，避免隱式地把項目變成 ADDP 項目
+var ErrNotInitialized = errors.New("project has no .addp directory, run ai-launcher init first")
+
+// Query 檢索條件，空字段表示不過濾
+type Query struct {
+	Text  string `json:"text"`
+	Kind  Kind   `json:"kind,omitempty"`
+	Limit int    `json:"limit"` // 0 表示不限制
+}
+
+// Store 管理單個項目的 .addp/memory 目錄；索引在文件變化後自動重建
+type Store struct {
+	projectPath string
+	dir         string
+
+	mu        sync.Mutex
+	index     *Index
+	signature string
+}
+
+// NewStore 創建項目的記憶存儲
+func NewStore(projectPath string) *Store {
+	return &Store{
+		projectPath: projectPath,
+		dir:         filepath.Join(projectPath, addp.DirName, "memory"),
+	}
+}
+
+// Dir 返回記憶目錄
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Add 保存一條記憶並返回保存後的記錄；未指定標題時取正文第一行
+func (s *Store) Add(r Record) (*Record, error) {
+	if !addp.Initialized(s.projectPath) {
+		return nil, ErrNotInitialized
+	}
+	if r.Kind == "" {
+		r.Kind = KindContext
+	}
+	if _, err := ParseKind(string(r.Kind)); err != nil {
+		return nil, err
+	}
+	r.Body = strings.TrimSpace(r.Body)
+	r.Title = strings.TrimSpace(r.Title)
+	if r.Title == "" {
+		r.Title = firstLine(r.Body)
+	}
+	if r.Title == "" {
+		return nil, fmt.Errorf("memory title or body is required")
+	}
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.dir, string(r.Kind))
+	err := fsutil.WithLock(filepath.Join(dir, ".memory"), func() error {
+		base := r.Created.Format(idLayout)
+		if sl := slug(r.Title); sl != "" {
+			base += "-" + sl
+		}
+		r.ID = base
+		for i := 2; ; i++ {
+			if _, err := os.Stat(filepath.Join(dir, r.ID+".md")); os.IsNotExist(err) {
+				break
+			}
+			r.ID = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		data, err := r.Markdown()
+		if err != nil {
+			return err
+		}
+		r.Path = filepath.Join(dir, r.ID+".md")
+		return fsutil.WriteFileAtomic(r.Path, data, 0644)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.index = nil
+	return &r, nil
+}
+
+// List 返回指定類型的記憶，最新的在前；kind 為空時返回全部
+func (s *Store) List(kind Kind) ([]*Record, error) {
+	idx, err := s.currentIndex()
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, r := range idx.records {
+		if kind == "" || r.Kind == kind {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// Get 按 ID 查找記憶
+func (s *Store) Get(id string) (*Record, error) {
+	records, err := s.List("")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("memory not found: %s", id)
+}
+
+// Search 全文檢索記憶；查詢文本為空時按時間返回最新的記憶
+func (s *Store) Search(q Query) ([]Result, error) {
+	idx, err := s.currentIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	if strings.TrimSpace(q.Text) == "" {
+		for _, r := range idx.records {
+			results = append(results, Result{Record: r})
+		}
+	} else {
+		results = idx.Search(q.Text, 0)
+	}
+
+	filtered := results[:0]
+	for _, res := range results {
+		if q.Kind == "" || res.Kind == q.Kind {
+			filtered = append(filtered, res)
+		}
+	}
+	if q.Limit > 0 && len(filtered) > q.Limit {
+		filtered = filtered[:q.Limit]
+	}
+	return filtered, nil
+}
+
+// currentIndex 返回與磁盤內容一致的索引，文件有增刪改時重新加載
+func (s *Store) currentIndex() (*Index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, signature, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+	if s.index != nil && signature == s.signature {
+		return s.index, nil
+	}
+
+	var records []*Record
+	for _, f := range files {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		// 手寫文件格式錯誤時跳過，不影響其他記憶
+		r, err := parseRecord(f.path, f.kind, data)
+		if err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Created.After(records[j].Created)
+	})
+
+	s.index = NewIndex(records)
+	s.signature = signature
+	return s.index, nil
+}
+
+type memoryFile struct {
+	path string
+	kind Kind
+}
+
+// scan 列出所有記憶文件，並以文件名、大小與修改時間生成簽名
+func (s *Store) scan() ([]memoryFile, string, error) {
+	var files []memoryFile
+	var sig strings.Builder
+	for _, kind := range Kinds {
+		dir := filepath.Join(s.dir, string(kind))
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			files = append(files, memoryFile{path: filepath.Join(dir, e.Name()), kind: kind})
+			fmt.Fprintf(&sig, "%s/%s:%d:%d;", kind, e.Name(), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return files, sig.String(), nil
+}
+
+// firstLine 返回正文第一個非空行，去掉 Markdown 標題符號並截斷
+func firstLine(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		if line == "" {
+			continue
+		}
+		if r := []rune(line); len(r) > 60 {
+			line = string(r[:60]) + "..."
+		}
+		return line
+	}
+	return ""
+}
@@ -125,6 +125,59 @@ func (c *OllamaClient) ValidateModel(ctx context.Context, modelName string) bool
 	return false
 }
 
+// PullModel 從模型庫拉取模型，progress 在每條進度消息到達時調用（可為 nil）；
+// 下載耗時較長，只受 ctx 控制而不使用客戶端的超時設置
+func (c *OllamaClient) PullModel(ctx context.Context, name string, progress func(PullProgress)) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("model name is required")
+	}
+	body, err := json.Marshal(PullRequest{Name: name, Stream: true})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/api/pull", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := *c.HTTPClient
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	// 流式響應每行一個 JSON 對象，最後一行的狀態為 success
+	decoder := json.NewDecoder(resp.Body)
+	var last PullProgress
+	for {
+		var p PullProgress
+		if err := decoder.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if p.Error != "" {
+			return fmt.Errorf("pull %s failed: %s", name, p.Error)
+		}
+		if progress != nil {
+			progress(p)
+		}
+		last = p
+	}
+	if last.Status != "success" {
+		return fmt.Errorf("pull %s ended unexpectedly", name)
+	}
+	return nil
+}
+
 // SetModel 設置默認模型
 func (c *OllamaClient) SetModel(model string) {
 	c.Model = model
@@ -2,6 +2,10 @@ package ollama
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -183,4 +187,37 @@ func TestOptimizationResult_Validation(t *testing.T) {
 	assert.Greater(t, result.ProcessingTime, time.Duration(0))
 	assert.Greater(t, result.TokensUsed, 0)
 	assert.True(t, result.Confidence >= 0.0 && result.Confidence <= 1.0)
-}
\ No newline at end of file
+}
+
+func TestOllamaClient_PullModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/pull", r.URL.Path)
+		var req PullRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch req.Name {
+		case "qwen2.5:0.5b":
+			fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+			fmt.Fprintln(w, `{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":40}`)
+			fmt.Fprintln(w, `{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":100}`)
+			fmt.Fprintln(w, `{"status":"success"}`)
+		default:
+			fmt.Fprintln(w, `{"error":"pull model manifest: file does not exist"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	var progress []PullProgress
+	err := client.PullModel(context.Background(), "qwen2.5:0.5b", func(p PullProgress) {
+		progress = append(progress, p)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, progress, 4)
+	assert.Equal(t, int64(40), progress[1].Completed)
+	assert.Equal(t, "success", progress[3].Status)
+
+	err = client.PullModel(context.Background(), "missing", nil)
+	assert.ErrorContains(t, err, "file does not exist")
+
+	assert.Error(t, client.PullModel(context.Background(), " ", nil))
+}
@@ -55,4 +55,19 @@ type GenerateResponse struct {
 // ListModelsResponse 模型列表響應
 type ListModelsResponse struct {
 	Models []ModelInfo `json:"models"`
-}
\ No newline at end of file
+}
+
+// PullRequest 拉取模型請求
+type PullRequest struct {
+	Name   string `json:"name"`
+	Stream bool   `json:"stream"`
+}
+
+// PullProgress 拉取模型的進度，Total 為 0 時表示當前階段沒有大小信息
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
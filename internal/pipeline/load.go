@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir 返回項目中存放流水線定義的目錄
+func Dir(projectPath string) string {
+	return filepath.Join(projectPath, ".ai-launcher", "pipelines")
+}
+
+// Load 讀取並驗證流水線定義文件；未寫 name 時使用文件名
+func Load(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline: %w", err)
+	}
+
+	var p Pipeline
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	p.Path = path
+
+	// 結構體中的 0 表示未設置，文件中顯式寫出的 0 同樣超出範圍
+	var explicit struct {
+		MaxIterations *int `yaml:"max_iterations"`
+	}
+	if yaml.Unmarshal(data, &explicit) == nil && explicit.MaxIterations != nil && *explicit.MaxIterations == 0 {
+		return nil, fmt.Errorf("%s: pipeline %s: max_iterations must be between 1 and %d", filepath.Base(path), p.Name, MaxIterations)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return &p, nil
+}
+
+// List 加載項目中的所有流水線，按名稱排序；無效的定義通過 errs 返回而不影響其他文件
+func List(projectPath string) (pipelines []*Pipeline, errs []error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, _ := filepath.Glob(filepath.Join(Dir(projectPath), pattern))
+		files = append(files, matches...)
+	}
+
+	for _, file := range files {
+		p, err := Load(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pipelines = append(pipelines, p)
+	}
+	sort.Slice(pipelines, func(i, j int) bool { return pipelines[i].Name < pipelines[j].Name })
+	return pipelines, errs
+}
+
+// Find 按名稱或文件路徑查找流水線
+func Find(projectPath, name string) (*Pipeline, error) {
+	if strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+		if _, err := os.Stat(name); err == nil {
+			return Load(name)
+		}
+	}
+
+	pipelines, errs := List(projectPath)
+	for _, p := range pipelines {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	// 同名文件存在但無效時返回具體錯誤
+	for _, err := range errs {
+		if strings.HasPrefix(err.Error(), name+".") {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("pipeline not found: %s (looked in %s)", name, Dir(projectPath))
+}
+
+// Example 示例流水線：Claude 實現、Gemini 評審、Claude 處理評審意見，直到評審通過
+const Example = `# Claude 實現、Gemini 評審、Claude 處理評審意見，評審回覆 LGTM 時結束
+name: implement-review
+description: Claude implements, Gemini reviews, Claude addresses the review
+max_iterations: 3
+repeat_from: review
+stages:
+  - name: implement
+    tool: claude_code
+    prompt: "{{.Task}}"
+  - name: review
+    tool: gemini_cli
+    input: git_diff
+    prompt: |
+      Review the following diff for bugs, missing tests and style problems.
+      If nothing needs to change, reply with a single line: LGTM
+
+      {{.Input}}
+    stop_when: "(?m)^\\s*LGTM\\s*$"
+  - name: address
+    tool: claude_code
+    input: review
+    prompt: |
+      A reviewer left the following comments on your change. Address them.
+
+      {{.Input}}
+`
+
+// WriteExample 在項目中寫入示例流水線，文件已存在時返回錯誤
+func WriteExample(projectPath string) (string, error) {
+	path := filepath.Join(Dir(projectPath), "implement-review.yaml")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(Dir(projectPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create pipeline directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(Example), 0644); err != nil {
+		return "", fmt.Errorf("failed to write example pipeline: %w", err)
+	}
+	return path, nil
+}
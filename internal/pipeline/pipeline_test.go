@@ -0,0 +1,325 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	projectconfig "ai-launcher/internal/project"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+)
+
+// echoCommand 將提示詞原樣輸出的階段命令
+var echoCommand = []string{"sh", "-c", `printf '%s\n' "$1"`, "sh"}
+
+func writePipeline(t *testing.T, project, name, content string) string {
+	t.Helper()
+	dir := Dir(project)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func newTestRunner() *Runner {
+	r := NewRunner(terminal.NewTerminalManager(), template.NewTemplateManager())
+	r.SetTrust(func(string, *Pipeline) bool { return true })
+	return r
+}
+
+func TestLoad(t *testing.T) {
+	project := t.TempDir()
+	path := writePipeline(t, project, "review-loop.yaml", `
+description: Claude implements, Gemini reviews, Claude addresses review
+max_iterations: 3
+repeat_from: review
+stages:
+  - name: implement
+    tool: claude_code
+    prompt: "{{.Task}}"
+  - name: review
+    tool: gemini_cli
+    input: git_diff
+    prompt: |
+      Review this diff and reply LGTM if nothing needs to change:
+      {{.Input}}
+    stop_when: "(?m)^LGTM"
+    timeout: 5m
+  - name: address
+    tool: claude_code
+    input: review
+    prompt: "Address this review:\n{{.Input}}"
+`)
+
+	p, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "review-loop", p.Name)
+	assert.Equal(t, 3, p.Iterations())
+	assert.Equal(t, 1, p.repeatIndex())
+	require.Len(t, p.Stages, 3)
+	assert.Equal(t, "5m0s", p.Stages[1].Timeout.String())
+
+	found, err := Find(project, "review-loop")
+	require.NoError(t, err)
+	assert.Equal(t, path, found.Path)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":  "stages:\n  - name: a\n    tool: claude_code\n    prompt: x\n    bogus: 1\n",
+		"no stages":      "name: empty\n",
+		"no tool":        "stages:\n  - name: a\n    prompt: x\n",
+		"duplicate":      "stages:\n  - {name: a, tool: claude_code, prompt: x}\n  - {name: a, tool: claude_code, prompt: y}\n",
+		"later input":    "stages:\n  - {name: a, tool: claude_code, prompt: x, input: b}\n  - {name: b, tool: claude_code, prompt: y}\n",
+		"bad stop_when":  "stages:\n  - {name: a, tool: claude_code, prompt: x, stop_when: '('}\n",
+		"bad prompt":     "stages:\n  - {name: a, tool: claude_code, prompt: '{{.Task'}\n",
+		"bad repeat":     "repeat_from: z\nstages:\n  - {name: a, tool: claude_code, prompt: x}\n",
+		"too many iters": "max_iterations: 99\nstages:\n  - {name: a, tool: claude_code, prompt: x}\n",
+		"zero iters":     "max_iterations: 0\nstages:\n  - {name: a, tool: claude_code, prompt: x}\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			project := t.TempDir()
+			path := writePipeline(t, project, "p.yaml", content)
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestList_SkipsInvalid(t *testing.T) {
+	project := t.TempDir()
+	writePipeline(t, project, "good.yaml", "stages:\n  - {name: a, tool: claude_code, prompt: x}\n")
+	writePipeline(t, project, "bad.yml", "stages: []\n")
+
+	pipelines, errs := List(project)
+	require.Len(t, pipelines, 1)
+	assert.Equal(t, "good", pipelines[0].Name)
+	assert.Len(t, errs, 1)
+
+	_, err := Find(project, "bad")
+	assert.Error(t, err)
+	_, err = Find(project, "missing")
+	assert.Error(t, err)
+}
+
+func TestRunner_ChainsOutputs(t *testing.T) {
+	p := &Pipeline{
+		Name: "chain",
+		Stages: []Stage{
+			{Name: "implement", Command: echoCommand, Prompt: "implement: {{.Task}}"},
+			{Name: "review", Command: echoCommand, Input: InputPrevious, Prompt: "review of [{{.Input}}]"},
+			{Name: "address", Command: echoCommand, Input: "implement", Prompt: "{{.Input}} / {{index .Outputs \"review\"}}"},
+		},
+	}
+
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir(), Task: "add login"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, result.Status)
+	require.Len(t, result.Stages, 3)
+	assert.Equal(t, "implement: add login", result.Stages[0].Output)
+	assert.Equal(t, "review of [implement: add login]", result.Stages[1].Output)
+	assert.Equal(t, "implement: add login / review of [implement: add login]", result.Stages[2].Output)
+}
+
+func TestRunner_StopConditionAndIterations(t *testing.T) {
+	// 第二輪評審通過
+	review := []string{"sh", "-c", `case "$1" in *"round 2"*) echo LGTM;; *) echo "please fix";; esac`, "sh"}
+	p := &Pipeline{
+		Name:          "loop",
+		MaxIterations: 5,
+		RepeatFrom:    "review",
+		Stages: []Stage{
+			{Name: "implement", Command: echoCommand, Prompt: "implement"},
+			{Name: "review", Command: review, Prompt: "review round {{.Iteration}}", StopWhen: "(?m)^LGTM$"},
+			{Name: "address", Command: echoCommand, Input: "review", Prompt: "address: {{.Input}}"},
+		},
+	}
+
+	var seen []string
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{
+		Project: t.TempDir(),
+		OnStage: func(sr StageResult) { seen = append(seen, sr.Stage) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusStopped, result.Status)
+	assert.Equal(t, 2, result.Iterations)
+	assert.Equal(t, []string{"implement", "review", "address", "review"}, seen)
+	assert.True(t, result.Stages[3].Stopped)
+
+	// 沒有停止條件時按最大迭代次數結束
+	p.Stages[1].StopWhen = ""
+	result, err = newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir(), MaxIterations: 2})
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, result.Status)
+	assert.Len(t, result.Stages, 5)
+}
+
+func TestRunner_FullStageOutput(t *testing.T) {
+	// 超過終端保留行數的輸出完整傳給後續階段
+	p := &Pipeline{
+		Name: "long",
+		Stages: []Stage{
+			{Name: "generate", Command: []string{"sh", "-c", "seq 1 5000", "sh"}, Prompt: "x"},
+			{Name: "count", Command: []string{"sh", "-c", `printf '%s\n' "$1" | wc -l | tr -d ' '`, "sh"}, Input: InputPrevious},
+		},
+	}
+
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir()})
+	require.NoError(t, err)
+	require.Len(t, result.Stages, 2)
+	assert.True(t, strings.HasPrefix(result.Stages[0].Output, "1\n2\n3\n"))
+	assert.Equal(t, "5000", result.Stages[1].Output)
+}
+
+func TestRunner_StageFailure(t *testing.T) {
+	fail := []string{"sh", "-c", `echo "broken: $1"; exit 3`, "sh"}
+	p := &Pipeline{
+		Name: "fail",
+		Stages: []Stage{
+			{Name: "a", Command: fail, Prompt: "x", ContinueOnError: true},
+			{Name: "b", Command: fail, Prompt: "y"},
+			{Name: "c", Command: echoCommand, Prompt: "never"},
+		},
+	}
+
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, result.Status)
+	require.Len(t, result.Stages, 2)
+	assert.Equal(t, 3, result.Stages[0].ExitCode)
+	assert.Equal(t, "broken: y", result.Stages[1].Output)
+	assert.Contains(t, result.Error, "stage b")
+}
+
+func TestRunner_GitDiffInput(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	project := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = project
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(project, "main.go"), []byte("package main\n"), 0644))
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	require.NoError(t, os.WriteFile(filepath.Join(project, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "util.go"), []byte("package main\n\nfunc helper() {}\n"), 0644))
+
+	p := &Pipeline{
+		Name:   "diff",
+		Stages: []Stage{{Name: "review", Command: echoCommand, Input: InputGitDiff}},
+	}
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: project})
+	require.NoError(t, err)
+	require.Len(t, result.Stages, 1)
+	assert.Contains(t, result.Stages[0].Output, "+func main() {}")
+	// 未跟蹤的新文件同樣需要評審，且不應被加入用戶的暫存區
+	assert.Contains(t, result.Stages[0].Output, "+func helper() {}")
+	status := exec.Command("git", "status", "--porcelain")
+	status.Dir = project
+	out, err := status.Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "?? util.go")
+}
+
+func TestRunner_RequiresTrust(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	project := t.TempDir()
+	path := writePipeline(t, project, "custom.yaml", "stages:\n  - {name: a, command: [echo], prompt: x}\n")
+	p, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stages.a.command: echo"}, p.TrustRequired())
+
+	// 隨倉庫提交的自定義命令在批准前不運行
+	runner := NewRunner(terminal.NewTerminalManager(), nil)
+	_, err = runner.Run(context.Background(), p, RunOptions{Project: project})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires approval")
+
+	cm := projectconfig.NewConfigManager()
+	require.NoError(t, cm.TrustPipeline(project, p.Path, p.TrustRequired()))
+	result, err := runner.Run(context.Background(), p, RunOptions{Project: project})
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, result.Status)
+
+	// 批准後修改命令需要重新批准
+	p.Stages[0].Command = []string{"sh", "-c", "echo changed"}
+	_, err = runner.Run(context.Background(), p, RunOptions{Project: project})
+	assert.Error(t, err)
+}
+
+func TestRunner_Template(t *testing.T) {
+	p := &Pipeline{
+		Name:   "tmpl",
+		Stages: []Stage{{Name: "debug", Command: echoCommand, Prompt: "{{.Task}}", Template: template.TemplateDebug}},
+	}
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir(), Task: "nil pointer in handler"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Stages[0].Prompt, "nil pointer in handler")
+	assert.NotEqual(t, "nil pointer in handler", result.Stages[0].Prompt)
+}
+
+func TestResult_Transcript(t *testing.T) {
+	p := &Pipeline{
+		Name: "chain",
+		Stages: []Stage{
+			{Name: "implement", Command: echoCommand, Prompt: "write code"},
+			{Name: "review", Command: echoCommand, Input: InputPrevious, Prompt: "```\n{{.Input}}\n```"},
+		},
+	}
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir()})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteMarkdown(&buf))
+	md := buf.String()
+	assert.Contains(t, md, "# Pipeline: chain")
+	assert.Contains(t, md, "## 1. implement (iteration 1)")
+	assert.Contains(t, md, "## 2. review (iteration 1)")
+	assert.Contains(t, md, "````\n```\nwrite code\n```\n````")
+	assert.Equal(t, 2, strings.Count(md, "### Output"))
+
+	dir := filepath.Join(t.TempDir(), "run")
+	require.NoError(t, result.Save(dir))
+	assert.FileExists(t, filepath.Join(dir, "transcript.md"))
+	assert.FileExists(t, filepath.Join(dir, "result.json"))
+}
+
+func TestWriteExample(t *testing.T) {
+	project := t.TempDir()
+	path, err := WriteExample(project)
+	require.NoError(t, err)
+
+	p, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "implement-review", p.Name)
+	assert.Regexp(t, p.Stages[1].StopWhen, "looks fine\n  LGTM \n")
+
+	_, err = WriteExample(project)
+	assert.Error(t, err)
+}
+
+func TestRunner_RecordsDuration(t *testing.T) {
+	p := &Pipeline{
+		Name:   "slow",
+		Stages: []Stage{{Name: "wait", Command: []string{"sh", "-c", "sleep 0.05", "sh"}, Prompt: "x"}},
+	}
+	result, err := newTestRunner().Run(context.Background(), p, RunOptions{Project: t.TempDir()})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.Stages[0].DurationMs, int64(50))
+}
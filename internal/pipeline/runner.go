@@ -0,0 +1,351 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"ai-launcher/internal/project"
+	prompttemplate "ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+)
+
+// RunOptions 單次運行的參數
+type RunOptions struct {
+	Project       string // 項目目錄，作為各階段的工作目錄
+	Task          string // 任務描述，在提示詞中以 .Task 引用
+	ForceYolo     bool   // 所有階段都以 YOLO 模式運行
+	MaxIterations int    // 大於 0 時覆蓋流水線定義
+
+	// OnStage 每個階段結束時回調，可用於實時顯示進度
+	OnStage func(StageResult)
+
+	noYolo bool // 項目清單禁止 YOLO，由 Run 填充
+}
+
+// TrustFunc 判斷用戶是否批准了項目中流水線需要信任的設置
+type TrustFunc func(projectPath string, p *Pipeline) bool
+
+// promptData 提示詞模板可用的數據
+type promptData struct {
+	Task      string
+	Input     string
+	Iteration int
+	Project   string
+	Outputs   map[string]string
+}
+
+// Runner 通過 TerminalManager 依次運行流水線階段
+type Runner struct {
+	terminals *terminal.TerminalManager
+	templates *prompttemplate.TemplateManager
+	trusted   TrustFunc
+	seq       int64
+}
+
+// NewRunner 創建流水線運行器；templates 可為 nil，此時不能使用 template 字段。
+// 默認按 ai-launcher pipeline trust 的批准記錄判斷流水線是否可信
+func NewRunner(tm *terminal.TerminalManager, templates *prompttemplate.TemplateManager) *Runner {
+	cm := project.NewConfigManager()
+	return &Runner{
+		terminals: tm,
+		templates: templates,
+		trusted: func(projectPath string, p *Pipeline) bool {
+			return cm.PipelineTrusted(projectPath, p.Path, p.TrustRequired())
+		},
+	}
+}
+
+// SetTrust 設置判斷流水線是否已批准的函數，需在 Run 之前調用
+func (r *Runner) SetTrust(trusted TrustFunc) {
+	r.trusted = trusted
+}
+
+// Run 運行流水線直到所有迭代完成、滿足停止條件或某個階段失敗
+func (r *Runner) Run(ctx context.Context, p *Pipeline, opts RunOptions) (*Result, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Project == "" {
+		return nil, fmt.Errorf("project path is required")
+	}
+	if settings := p.TrustRequired(); len(settings) > 0 && !r.trusted(opts.Project, p) {
+		return nil, fmt.Errorf("pipeline %s requires approval before it can run (ai-launcher pipeline trust %s): %s",
+			p.Name, p.Name, strings.Join(settings, "; "))
+	}
+	// 與啟動會話相同，項目清單禁止 YOLO 時所有階段都不以 YOLO 運行
+	manifest, err := project.LoadManifest(opts.Project)
+	if err != nil {
+		return nil, err
+	}
+	opts.noYolo = manifest != nil && manifest.Yolo != nil && !*manifest.Yolo
+
+	iterations := p.Iterations()
+	if opts.MaxIterations > 0 {
+		iterations = opts.MaxIterations
+	}
+
+	result := &Result{
+		Pipeline:  p.Name,
+		Project:   opts.Project,
+		Task:      opts.Task,
+		Status:    StatusCompleted,
+		StartedAt: time.Now(),
+	}
+	defer func() { result.FinishedAt = time.Now() }()
+
+	outputs := make(map[string]string)
+	previous := ""
+	runID := fmt.Sprintf("pipeline-%s-%d", p.Name, atomic.AddInt64(&r.seq, 1))
+
+	for iter := 1; iter <= iterations; iter++ {
+		result.Iterations = iter
+		start := 0
+		if iter > 1 {
+			start = p.repeatIndex()
+		}
+
+		for _, stage := range p.Stages[start:] {
+			if ctx.Err() != nil {
+				result.Status = StatusCancelled
+				result.Error = ctx.Err().Error()
+				return result, nil
+			}
+
+			sr := r.runStage(ctx, p, stage, opts, promptData{
+				Task:      opts.Task,
+				Iteration: iter,
+				Project:   opts.Project,
+				Outputs:   outputs,
+			}, previous, fmt.Sprintf("%s-%s-%d", runID, stage.Name, iter))
+			result.Stages = append(result.Stages, sr)
+			if opts.OnStage != nil {
+				opts.OnStage(sr)
+			}
+
+			outputs[stage.Name] = sr.Output
+			previous = sr.Output
+
+			if sr.Error != "" && !stage.ContinueOnError {
+				result.Status = StatusFailed
+				if ctx.Err() != nil {
+					result.Status = StatusCancelled
+				}
+				result.Error = fmt.Sprintf("stage %s: %s", stage.Name, sr.Error)
+				return result, nil
+			}
+			if sr.Stopped {
+				result.Status = StatusStopped
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// runStage 準備輸入與提示詞並運行一個階段
+func (r *Runner) runStage(ctx context.Context, p *Pipeline, stage Stage, opts RunOptions, data promptData, previous, name string) (sr StageResult) {
+	sr = StageResult{
+		Stage:     stage.Name,
+		Iteration: data.Iteration,
+		Tool:      stage.Tool,
+		Input:     stage.Input,
+		ExitCode:  -1,
+		StartedAt: time.Now(),
+	}
+	defer func() { sr.DurationMs = time.Since(sr.StartedAt).Milliseconds() }()
+
+	input, err := stageInput(ctx, stage, opts.Project, previous, data.Outputs)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	data.Input = input
+
+	prompt, err := r.renderPrompt(stage, data)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	sr.Prompt = prompt
+
+	timeout := stage.Timeout
+	if timeout <= 0 {
+		timeout = DefaultStageTimeout
+	}
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output, exitCode, err := r.execute(stageCtx, p, stage, opts, prompt, name)
+	sr.Output = output
+	sr.ExitCode = exitCode
+	if err != nil {
+		if errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("stage timed out after %s", timeout)
+		}
+		sr.Error = err.Error()
+		return sr
+	}
+
+	if stage.StopWhen != "" {
+		sr.Stopped = regexp.MustCompile(stage.StopWhen).MatchString(output)
+	}
+	return sr
+}
+
+// execute 通過 TerminalManager 以非交互方式運行階段並收集輸出
+func (r *Runner) execute(ctx context.Context, p *Pipeline, stage Stage, opts RunOptions, prompt, name string) (string, int, error) {
+	yolo := (stage.Yolo || opts.ForceYolo) && !opts.noYolo
+	var args []string
+	if len(stage.Command) > 0 {
+		args = append(append([]string(nil), stage.Command...), prompt)
+	} else {
+		var err error
+		if args, err = terminal.HeadlessCommand(stage.Tool.TerminalType(), prompt, yolo); err != nil {
+			return "", -1, err
+		}
+	}
+
+	// 終端只保留最近的輸出行，階段輸出需要完整傳給後續階段
+	var (
+		mu    sync.Mutex
+		lines []string
+	)
+	collected := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return strings.Join(lines, "\n")
+	}
+
+	err := r.terminals.StartTerminalWithContext(ctx, terminal.TerminalConfig{
+		Type:        stage.Tool.TerminalType(),
+		Name:        name,
+		WorkingDir:  opts.Project,
+		Environment: p.Environment,
+		Command:     args,
+		YoloMode:    yolo,
+		OnOutput: func(line string) {
+			mu.Lock()
+			lines = append(lines, line)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		return "", -1, err
+	}
+	defer r.terminals.RemoveTerminal(name)
+
+	term, _ := r.terminals.GetTerminal(name)
+	_ = r.terminals.CloseInput(name)
+
+	select {
+	case <-term.Done():
+	case <-ctx.Done():
+		_ = r.terminals.StopTerminal(name)
+		return collected(), -1, ctx.Err()
+	}
+
+	output := collected()
+	if err := term.OutputErr(); err != nil {
+		return output, -1, fmt.Errorf("stage output truncated: %w", err)
+	}
+	exitCode := term.ExitCode()
+	if exitCode != 0 {
+		return output, exitCode, fmt.Errorf("exit status %d", exitCode)
+	}
+	return output, 0, nil
+}
+
+// renderPrompt 渲染階段提示詞；未寫提示詞時直接使用輸入
+func (r *Runner) renderPrompt(stage Stage, data promptData) (string, error) {
+	prompt := data.Input
+	if stage.Prompt != "" {
+		tmpl, err := parsePrompt(stage)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to render prompt: %w", err)
+		}
+		prompt = buf.String()
+	}
+
+	if stage.Template != "" {
+		if r.templates == nil {
+			return "", fmt.Errorf("templates are not available")
+		}
+		applied, err := r.templates.ApplyTemplate(stage.Template, prompt, map[string]string{"project": data.Project})
+		if err != nil {
+			return "", err
+		}
+		prompt = applied.OptimizedPrompt
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		return "", fmt.Errorf("prompt is empty")
+	}
+	return prompt, nil
+}
+
+// parsePrompt 解析階段提示詞模板
+func parsePrompt(stage Stage) (*template.Template, error) {
+	return template.New(stage.Name).Option("missingkey=zero").Parse(stage.Prompt)
+}
+
+// stageInput 按 input 字段獲取階段輸入
+func stageInput(ctx context.Context, stage Stage, projectPath, previous string, outputs map[string]string) (string, error) {
+	switch stage.Input {
+	case InputNone:
+		return "", nil
+	case InputPrevious:
+		return previous, nil
+	case InputGitDiff:
+		return gitDiff(ctx, projectPath)
+	default:
+		return outputs[stage.Input], nil
+	}
+}
+
+// gitDiff 返回工作區相對 HEAD 的改動，包括已暫存的改動與未被忽略的未跟蹤文件；尚無提交時返回所有文件。
+// 未跟蹤文件通過臨時索引以 intent-to-add 方式加入，不影響用戶的暫存區
+func gitDiff(ctx context.Context, projectPath string) (string, error) {
+	dir, err := os.MkdirTemp("", "ai-launcher-diff-")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git diff: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = projectPath
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(dir, "index"))
+		return cmd.Output()
+	}
+
+	base := []string{"diff", "HEAD"}
+	if _, err := git("read-tree", "HEAD"); err != nil {
+		if _, err := git("read-tree", "--empty"); err != nil {
+			return "", fmt.Errorf("failed to get git diff: %w", err)
+		}
+		base = []string{"diff"}
+	}
+	if _, err := git("add", "--all", "--intent-to-add"); err != nil {
+		return "", fmt.Errorf("failed to get git diff: %w", err)
+	}
+	out, err := git(base...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get git diff: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultTranscriptDir 返回項目中保存運行記錄的默認目錄
+func DefaultTranscriptDir(projectPath, name string, t time.Time) string {
+	return filepath.Join(Dir(projectPath), "runs", fmt.Sprintf("%s-%s", name, t.Format("20060102-150405")))
+}
+
+// WriteMarkdown 輸出運行記錄，每個階段一節，包含提示詞與輸出
+func (r *Result) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Pipeline: %s\n\n", r.Pipeline)
+	fmt.Fprintf(&b, "- **Project:** `%s`\n", r.Project)
+	if r.Task != "" {
+		fmt.Fprintf(&b, "- **Task:** %s\n", r.Task)
+	}
+	fmt.Fprintf(&b, "- **Status:** %s\n", r.Status)
+	fmt.Fprintf(&b, "- **Iterations:** %d\n", r.Iterations)
+	fmt.Fprintf(&b, "- **Started:** %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Duration:** %s\n", r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	if r.Error != "" {
+		fmt.Fprintf(&b, "- **Error:** %s\n", r.Error)
+	}
+
+	for i, stage := range r.Stages {
+		fmt.Fprintf(&b, "\n## %d. %s (iteration %d)", i+1, stage.Stage, stage.Iteration)
+		if stage.Tool != "" {
+			fmt.Fprintf(&b, " — %s", stage.Tool.String())
+		}
+		b.WriteString("\n\n")
+
+		fmt.Fprintf(&b, "- **Exit code:** %d\n", stage.ExitCode)
+		fmt.Fprintf(&b, "- **Duration:** %s\n", (time.Duration(stage.DurationMs) * time.Millisecond).Round(time.Millisecond))
+		if stage.Input != "" {
+			fmt.Fprintf(&b, "- **Input:** %s\n", stage.Input)
+		}
+		if stage.Stopped {
+			b.WriteString("- **Stop condition met**\n")
+		}
+		if stage.Error != "" {
+			fmt.Fprintf(&b, "- **Error:** %s\n", stage.Error)
+		}
+
+		if stage.Prompt != "" {
+			fmt.Fprintf(&b, "\n### Prompt\n\n%s\n", fence(stage.Prompt))
+		}
+		fmt.Fprintf(&b, "\n### Output\n\n%s\n", fence(stage.Output))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON 以 JSON 格式輸出運行結果
+func (r *Result) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	return nil
+}
+
+// Save 將運行記錄寫入目錄下的 transcript.md 與 result.json
+func (r *Result) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+
+	for name, write := range map[string]func(io.Writer) error{
+		"transcript.md": r.WriteMarkdown,
+		"result.json":   r.WriteJSON,
+	} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		err = write(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// fence 用代碼塊包裹文本，內容本身含有 ``` 時使用更長的圍欄
+func fence(s string) string {
+	marker := "```"
+	for strings.Contains(s, marker) {
+		marker += "`"
+	}
+	return marker + "\n" + strings.TrimRight(s, "\n") + "\n" + marker
+}
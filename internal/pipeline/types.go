@@ -0,0 +1,186 @@
+// Package pipeline 按順序運行多個 AI 工具階段，將前一階段的輸出傳給後續階段
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/project"
+)
+
+const (
+	DefaultStageTimeout = 30 * time.Minute // 單個階段的默認超時
+	MaxIterations       = 20               // 允許配置的最大迭代次數
+)
+
+// 階段輸入來源；其他值表示前面某個階段的名稱
+const (
+	InputNone     = ""         // 無輸入
+	InputPrevious = "previous" // 上一個運行的階段的輸出
+	InputGitDiff  = "git_diff" // 項目當前相對 HEAD 的 git diff
+)
+
+// Pipeline 在項目中以 YAML 定義的多階段流程
+type Pipeline struct {
+	Name          string            `yaml:"name" json:"name"`
+	Description   string            `yaml:"description,omitempty" json:"description,omitempty"`
+	MaxIterations int               `yaml:"max_iterations,omitempty" json:"max_iterations,omitempty"`
+	RepeatFrom    string            `yaml:"repeat_from,omitempty" json:"repeat_from,omitempty"` // 第二輪起從此階段開始，默認第一個階段
+	Environment   map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Stages        []Stage           `yaml:"stages" json:"stages"`
+
+	// Path 定義文件路徑，加載時填充
+	Path string `yaml:"-" json:"path,omitempty"`
+}
+
+// Stage 流水線中的一個階段
+type Stage struct {
+	Name            string              `yaml:"name" json:"name"`
+	Tool            project.AIModelType `yaml:"tool" json:"tool"`
+	Prompt          string              `yaml:"prompt" json:"prompt"`                         // Go text/template，可用 .Task .Input .Iteration .Project .Outputs
+	Template        string              `yaml:"template,omitempty" json:"template,omitempty"` // 查詢模板 ID，渲染後的提示詞作為其輸入
+	Input           string              `yaml:"input,omitempty" json:"input,omitempty"`
+	StopWhen        string              `yaml:"stop_when,omitempty" json:"stop_when,omitempty"` // 輸出匹配此正則時結束流水線
+	ContinueOnError bool                `yaml:"continue_on_error,omitempty" json:"continue_on_error,omitempty"`
+	Yolo            bool                `yaml:"yolo,omitempty" json:"yolo,omitempty"`
+	Timeout         time.Duration       `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Command         []string            `yaml:"command,omitempty" json:"command,omitempty"` // 自定義命令，提示詞作為最後一個參數追加
+}
+
+// Iterations 返回生效的迭代次數
+func (p *Pipeline) Iterations() int {
+	if p.MaxIterations <= 0 {
+		return 1
+	}
+	return p.MaxIterations
+}
+
+// Validate 檢查流水線定義，錯誤信息指明出錯的階段
+func (p *Pipeline) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("pipeline name is required")
+	}
+	if len(p.Stages) == 0 {
+		return fmt.Errorf("pipeline %s: at least one stage is required", p.Name)
+	}
+	// 0 表示未設置，使用一輪
+	if p.MaxIterations < 0 || p.MaxIterations > MaxIterations {
+		return fmt.Errorf("pipeline %s: max_iterations must be between 1 and %d", p.Name, MaxIterations)
+	}
+
+	seen := make(map[string]bool)
+	for i, stage := range p.Stages {
+		where := fmt.Sprintf("pipeline %s: stages[%d]", p.Name, i)
+		if stage.Name == "" {
+			return fmt.Errorf("%s: name is required", where)
+		}
+		where = fmt.Sprintf("pipeline %s: stage %s", p.Name, stage.Name)
+		if seen[stage.Name] {
+			return fmt.Errorf("%s: duplicate stage name", where)
+		}
+		if stage.Name == InputPrevious || stage.Name == InputGitDiff {
+			return fmt.Errorf("%s: name is reserved", where)
+		}
+		if stage.Tool == "" && len(stage.Command) == 0 {
+			return fmt.Errorf("%s: tool or command is required", where)
+		}
+		if stage.Prompt == "" && stage.Input == InputNone {
+			return fmt.Errorf("%s: prompt or input is required", where)
+		}
+		switch stage.Input {
+		case InputNone, InputPrevious, InputGitDiff:
+		default:
+			if !seen[stage.Input] {
+				return fmt.Errorf("%s: input %q must be an earlier stage, %q or %q", where, stage.Input, InputPrevious, InputGitDiff)
+			}
+		}
+		if stage.StopWhen != "" {
+			if _, err := regexp.Compile(stage.StopWhen); err != nil {
+				return fmt.Errorf("%s: invalid stop_when: %w", where, err)
+			}
+		}
+		if _, err := parsePrompt(stage); err != nil {
+			return fmt.Errorf("%s: invalid prompt: %w", where, err)
+		}
+		seen[stage.Name] = true
+	}
+
+	if p.RepeatFrom != "" && !seen[p.RepeatFrom] {
+		return fmt.Errorf("pipeline %s: repeat_from %q is not a stage", p.Name, p.RepeatFrom)
+	}
+	return nil
+}
+
+// TrustRequired 列出需要用戶批准才能運行的設置：流水線定義隨倉庫提交，其中的 YOLO 階段、
+// 自定義命令與環境變量會在本機不經確認地執行
+func (p *Pipeline) TrustRequired() []string {
+	var items []string
+	keys := make([]string, 0, len(p.Environment))
+	for k := range p.Environment {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("environment: %s=%s", k, p.Environment[k]))
+	}
+	for _, stage := range p.Stages {
+		if stage.Yolo {
+			items = append(items, fmt.Sprintf("stages.%s.yolo: true", stage.Name))
+		}
+		if len(stage.Command) > 0 {
+			items = append(items, fmt.Sprintf("stages.%s.command: %s", stage.Name, strings.Join(stage.Command, " ")))
+		}
+	}
+	return items
+}
+
+// repeatIndex 返回第二輪起的起始階段
+func (p *Pipeline) repeatIndex() int {
+	for i, stage := range p.Stages {
+		if stage.Name == p.RepeatFrom {
+			return i
+		}
+	}
+	return 0
+}
+
+// Status 流水線運行結果
+type Status string
+
+const (
+	StatusCompleted Status = "completed" // 所有迭代運行完畢
+	StatusStopped   Status = "stopped"   // 滿足停止條件提前結束
+	StatusFailed    Status = "failed"    // 某個階段失敗
+	StatusCancelled Status = "cancelled" // 被取消
+)
+
+// StageResult 單個階段一次運行的結果
+type StageResult struct {
+	Stage      string              `json:"stage"`
+	Iteration  int                 `json:"iteration"`
+	Tool       project.AIModelType `json:"tool,omitempty"`
+	Input      string              `json:"input,omitempty"`
+	Prompt     string              `json:"prompt"`
+	Output     string              `json:"output"`
+	ExitCode   int                 `json:"exit_code"`
+	Error      string              `json:"error,omitempty"`
+	Stopped    bool                `json:"stopped,omitempty"` // 輸出滿足 stop_when
+	StartedAt  time.Time           `json:"started_at"`
+	DurationMs int64               `json:"duration_ms"`
+}
+
+// Result 一次流水線運行的記錄
+type Result struct {
+	Pipeline   string        `json:"pipeline"`
+	Project    string        `json:"project"`
+	Task       string        `json:"task,omitempty"`
+	Status     Status        `json:"status"`
+	Iterations int           `json:"iterations"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Stages     []StageResult `json:"stages"`
+}
@@ -1,11 +1,16 @@
 package project
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"ai-launcher/internal/fsutil"
+	"ai-launcher/internal/terminal"
 )
 
 // ProjectConfig 项目配置
@@ -16,6 +21,17 @@ type ProjectConfig struct {
 	YoloMode    bool              `json:"yolo_mode"`
 	LastUsed    time.Time         `json:"last_used"`
 	Preferences map[string]string `json:"preferences"`
+	Tags        []string          `json:"tags,omitempty"`
+	Group       string            `json:"group,omitempty"`     // 分组名称
+	Pinned      bool              `json:"pinned,omitempty"`    // 置顶，列表中排在最前
+	Archived    bool              `json:"archived,omitempty"`  // 归档，默认不在列表中显示
+	Notes       string            `json:"notes,omitempty"`     // 备注，可被搜索
+	UseCount    int               `json:"use_count,omitempty"` // 启动次数，用于按使用频率排序
+	Missing     bool              `json:"missing,omitempty"`   // 路径已不存在，由 CheckMissing 标记
+	Profiles    []LaunchProfile   `json:"profiles,omitempty"`  // 命名启动配置档
+	Sessions    []SessionRecord   `json:"sessions,omitempty"`
+
+	Manifest *Manifest `json:"-"` // 合并后的项目清单，由 ApplyManifest 设置
 }
 
 // AIModelType AI模型类型
@@ -75,6 +91,22 @@ func (a AIModelType) GetCommand(yoloMode bool) []string {
 	}
 }
 
+// TerminalType 获取模型对应的终端类型
+func (a AIModelType) TerminalType() terminal.TerminalType {
+	switch a {
+	case ModelClaudeCode:
+		return terminal.TypeClaudeCode
+	case ModelGeminiCLI:
+		return terminal.TypeGeminiCLI
+	case ModelCodex:
+		return terminal.TypeCodex
+	case ModelAider:
+		return terminal.TypeAider
+	default:
+		return terminal.TypeCustom
+	}
+}
+
 // GetIcon 获取模型图标
 func (a AIModelType) GetIcon() string {
 	switch a {
@@ -94,10 +126,16 @@ func (a AIModelType) GetIcon() string {
 }
 
 // ConfigManager 配置管理器
+// 可以在多个 goroutine 中使用；修改在文件锁内基于文件的最新内容进行，
+// 同时运行的图形界面、Web 启动器与命令行的修改会合并而不是相互覆盖
 type ConfigManager struct {
 	configDir  string
 	configFile string
-	projects   []ProjectConfig
+
+	mu          sync.Mutex // 保护以下字段
+	projects    []ProjectConfig
+	newerSchema int    // 文件由更新版本的启动器写入时的版本，此时拒绝覆盖
+	backup      string // 最近一次迁移前的备份文件
 }
 
 // NewConfigManager 创建新的配置管理器
@@ -115,88 +153,196 @@ func NewConfigManager() *ConfigManager {
 
 // LoadProjects 加载项目配置
 func (cm *ConfigManager) LoadProjects() error {
-	// 确保配置目录存在
-	if err := os.MkdirAll(cm.configDir, 0755); err != nil {
-		return fmt.Errorf("创建配置目录失败: %v", err)
-	}
-
-	// 检查配置文件是否存在
-	if _, err := os.Stat(cm.configFile); os.IsNotExist(err) {
-		// 文件不存在，创建空配置
-		return cm.SaveProjects()
-	}
+	return cm.update(nil)
+}
 
-	// 读取配置文件
+// readFile 读取文件中的项目列表，需持有 cm.mu 与文件锁；返回是否需要写回。
+// 文件不存在时以内存中的列表为基础；旧版本的文件先备份，写回时迁移到当前版本
+func (cm *ConfigManager) readFile() ([]ProjectConfig, bool, error) {
 	data, err := os.ReadFile(cm.configFile)
+	if os.IsNotExist(err) {
+		return append([]ProjectConfig(nil), cm.projects...), true, nil
+	}
 	if err != nil {
-		return fmt.Errorf("读取配置文件失败: %v", err)
+		return nil, false, fmt.Errorf("读取配置文件失败: %v", err)
 	}
 
-	// 解析JSON
-	if err := json.Unmarshal(data, &cm.projects); err != nil {
-		return fmt.Errorf("解析配置文件失败: %v", err)
+	// 解析失败时保留当前的项目列表
+	projects, version, err := DecodeProjects(data)
+	if version > SchemaVersion {
+		cm.newerSchema = version
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("解析配置文件失败: %v", err)
 	}
+	cm.newerSchema = 0
 
-	return nil
+	if version < SchemaVersion {
+		backup, err := cm.backupFile(data, version)
+		if err != nil {
+			return nil, false, err
+		}
+		cm.backup = backup
+		return projects, true, nil
+	}
+	return projects, false, nil
 }
 
-// SaveProjects 保存项目配置
-func (cm *ConfigManager) SaveProjects() error {
+// update 在文件锁内重新读取项目列表，对最新内容应用 fn 后原子写回；
+// fn 为 nil 时只重新加载，fn 返回错误时不修改文件
+func (cm *ConfigManager) update(fn func(projects []ProjectConfig) ([]ProjectConfig, error)) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
 	// 确保配置目录存在
 	if err := os.MkdirAll(cm.configDir, 0755); err != nil {
 		return fmt.Errorf("创建配置目录失败: %v", err)
 	}
 
+	return fsutil.WithLock(cm.configFile, func() error {
+		projects, dirty, err := cm.readFile()
+		if err != nil {
+			return err
+		}
+		if fn != nil {
+			if projects, err = fn(projects); err != nil {
+				return err
+			}
+			dirty = true
+		}
+		if dirty {
+			if err := cm.writeFile(projects); err != nil {
+				return err
+			}
+		}
+		cm.projects = projects
+		return nil
+	})
+}
+
+// MigrationBackup 返回最近一次迁移 projects.json 前的备份文件，没有迁移时返回空字符串
+func (cm *ConfigManager) MigrationBackup() string {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.backup
+}
+
+// SaveProjects 在文件锁内将内存中的项目按路径合并到最新的配置文件：同一路径以内存中的为准，
+// 其他进程新增的项目保留。删除项目使用 RemoveProject
+func (cm *ConfigManager) SaveProjects() error {
+	cm.mu.Lock()
+	current := append([]ProjectConfig(nil), cm.projects...)
+	cm.mu.Unlock()
+
+	return cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+	next:
+		for _, project := range current {
+			project.Manifest = nil
+			for i, p := range projects {
+				if p.Path == project.Path {
+					projects[i] = project
+					continue next
+				}
+			}
+			projects = append(projects, project)
+		}
+		return projects, nil
+	})
+}
+
+// writeFile 原子写入项目列表，需持有 cm.mu 与文件锁
+func (cm *ConfigManager) writeFile(projects []ProjectConfig) error {
+	if cm.newerSchema > 0 {
+		return fmt.Errorf("配置文件由更新版本的启动器写入 (schema_version %d)，拒绝覆盖", cm.newerSchema)
+	}
+
 	// 序列化为JSON
-	data, err := json.MarshalIndent(cm.projects, "", "  ")
+	data, err := EncodeProjects(projects)
 	if err != nil {
 		return fmt.Errorf("序列化配置失败: %v", err)
 	}
 
-	// 写入文件
-	if err := os.WriteFile(cm.configFile, data, 0644); err != nil {
+	// 先写临时文件再重命名，崩溃时不会留下被截断的文件
+	if err := fsutil.WriteFileAtomic(cm.configFile, data, 0644); err != nil {
 		return fmt.Errorf("写入配置文件失败: %v", err)
 	}
-
 	return nil
 }
 
 // AddProject 添加项目配置
+// 未指定 AI 工具时使用项目清单中的工具，清单也未指定时使用 Claude Code
 func (cm *ConfigManager) AddProject(project ProjectConfig) error {
-	// 检查项目是否已存在
-	for i, p := range cm.projects {
-		if p.Path == project.Path {
-			// 更新现有项目
-			cm.projects[i] = project
-			return cm.SaveProjects()
+	if project.AIModel == "" {
+		manifest, err := LoadManifest(project.Path)
+		if err != nil {
+			return err
 		}
+		project.AIModel = ModelClaudeCode
+		if manifest != nil && manifest.Tool != "" {
+			project.AIModel = manifest.Tool
+		}
+	}
+	if err := ValidateProfiles(project.Profiles); err != nil {
+		return err
 	}
+	project.Manifest = nil
+
+	return cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+		// 检查项目是否已存在
+		for i, p := range projects {
+			if p.Path == project.Path {
+				// 更新现有项目（未提供会话记录时保留原有记录）
+				if project.Sessions == nil {
+					project.Sessions = p.Sessions
+				}
+				projects[i] = project
+				return projects, nil
+			}
+		}
 
-	// 添加新项目
-	project.LastUsed = time.Now()
-	cm.projects = append(cm.projects, project)
-	return cm.SaveProjects()
+		// 添加新项目
+		project.LastUsed = time.Now()
+		return append(projects, project), nil
+	})
+}
+
+// UpdateProject 在文件锁内基于最新内容修改已保存的项目；fn 返回错误时不保存
+func (cm *ConfigManager) UpdateProject(path string, fn func(p *ProjectConfig) error) error {
+	return cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+		for i := range projects {
+			if projects[i].Path != path {
+				continue
+			}
+			p := projects[i]
+			if err := fn(&p); err != nil {
+				return nil, err
+			}
+			if err := ValidateProfiles(p.Profiles); err != nil {
+				return nil, err
+			}
+			p.Path = path
+			p.Manifest = nil
+			projects[i] = p
+			return projects, nil
+		}
+		return nil, fmt.Errorf("项目不存在: %s", path)
+	})
 }
 
 // GetProjects 获取所有项目
 func (cm *ConfigManager) GetProjects() []ProjectConfig {
-	return cm.projects
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return append([]ProjectConfig(nil), cm.projects...)
 }
 
-// GetRecentProjects 获取最近使用的项目
+// GetRecentProjects 获取最近使用的项目（包括归档项目，不考虑置顶）
 func (cm *ConfigManager) GetRecentProjects(limit int) []ProjectConfig {
 	// 按最后使用时间排序
-	projects := make([]ProjectConfig, len(cm.projects))
-	copy(projects, cm.projects)
-
-	// 简单的排序，实际可以使用sort包
-	for i := 0; i < len(projects)-1; i++ {
-		for j := i + 1; j < len(projects); j++ {
-			if projects[i].LastUsed.Before(projects[j].LastUsed) {
-				projects[i], projects[j] = projects[j], projects[i]
-			}
-		}
-	}
+	projects := cm.GetProjects()
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].LastUsed.After(projects[j].LastUsed)
+	})
 
 	// 限制返回数量
 	if limit > 0 && limit < len(projects) {
@@ -208,29 +354,35 @@ func (cm *ConfigManager) GetRecentProjects(limit int) []ProjectConfig {
 
 // RemoveProject 删除项目配置
 func (cm *ConfigManager) RemoveProject(path string) error {
-	for i, p := range cm.projects {
-		if p.Path == path {
-			// 删除项目
-			cm.projects = append(cm.projects[:i], cm.projects[i+1:]...)
-			return cm.SaveProjects()
+	return cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+		for i, p := range projects {
+			if p.Path == path {
+				// 删除项目
+				return append(projects[:i], projects[i+1:]...), nil
+			}
 		}
-	}
-	return fmt.Errorf("项目不存在: %s", path)
+		return nil, fmt.Errorf("项目不存在: %s", path)
+	})
 }
 
-// UpdateProjectUsage 更新项目使用时间
+// UpdateProjectUsage 更新项目使用时间与启动次数
 func (cm *ConfigManager) UpdateProjectUsage(path string) error {
-	for i, p := range cm.projects {
-		if p.Path == path {
-			cm.projects[i].LastUsed = time.Now()
-			return cm.SaveProjects()
+	return cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+		for i, p := range projects {
+			if p.Path == path {
+				projects[i].LastUsed = time.Now()
+				projects[i].UseCount++
+				return projects, nil
+			}
 		}
-	}
-	return fmt.Errorf("项目不存在: %s", path)
+		return nil, fmt.Errorf("项目不存在: %s", path)
+	})
 }
 
 // GetProjectByPath 根据路径获取项目
 func (cm *ConfigManager) GetProjectByPath(path string) (*ProjectConfig, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	for _, p := range cm.projects {
 		if p.Path == path {
 			return &p, nil
@@ -239,6 +391,25 @@ func (cm *ConfigManager) GetProjectByPath(path string) (*ProjectConfig, error) {
 	return nil, fmt.Errorf("项目不存在: %s", path)
 }
 
+// FindProject 按名称（不区分大小写）或路径查找项目，供命令行与 Web 接口使用
+func (cm *ConfigManager) FindProject(nameOrPath string) (*ProjectConfig, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for _, p := range cm.projects {
+		if strings.EqualFold(p.Name, nameOrPath) {
+			return &p, nil
+		}
+	}
+	if abs, err := filepath.Abs(nameOrPath); err == nil {
+		for _, p := range cm.projects {
+			if filepath.Clean(p.Path) == abs {
+				return &p, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("项目不存在: %s", nameOrPath)
+}
+
 // ValidateProjectPath 验证项目路径
 func (cm *ConfigManager) ValidateProjectPath(path string) error {
 	// 检查路径是否存在
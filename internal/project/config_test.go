@@ -1,8 +1,12 @@
 package project
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -443,4 +447,146 @@ func TestConfigManager_GetProjectByPath(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when getting nonexistent project")
 	}
+}
+
+func TestConfigManager_FindProject(t *testing.T) {
+	dir := t.TempDir()
+	cm := &ConfigManager{
+		projects: []ProjectConfig{
+			{Name: "Web-App", Path: dir},
+			{Name: "api", Path: "/test/api"},
+		},
+	}
+
+	// 按名称查找，不区分大小写
+	project, err := cm.FindProject("web-app")
+	if err != nil {
+		t.Fatalf("Failed to find project by name: %v", err)
+	}
+	if project.Path != dir {
+		t.Errorf("Expected path %s, got %s", dir, project.Path)
+	}
+
+	// 按路径查找
+	project, err = cm.FindProject(dir + string(filepath.Separator))
+	if err != nil {
+		t.Fatalf("Failed to find project by path: %v", err)
+	}
+	if project.Name != "Web-App" {
+		t.Errorf("Expected project name 'Web-App', got %s", project.Name)
+	}
+
+	if _, err := cm.FindProject("missing"); err == nil {
+		t.Error("Expected error when finding nonexistent project")
+	}
+}
+
+// writeConcurrently 在多个 goroutine 中添加、更新与删除 worker 的项目
+func writeConcurrently(t *testing.T, cm *ConfigManager, worker string) {
+	var wg sync.WaitGroup
+	for g := 0; g < 2; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				path := fmt.Sprintf("/%s/%d/%d", worker, g, i)
+				if err := cm.AddProject(ProjectConfig{Name: path, Path: path, AIModel: ModelCodex}); err != nil {
+					t.Errorf("Failed to add %s: %v", path, err)
+				}
+				if err := cm.UpdateProjectUsage("/shared"); err != nil {
+					t.Errorf("Failed to update shared project: %v", err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := cm.RemoveProject("/remove/" + worker); err != nil {
+		t.Errorf("Failed to remove project: %v", err)
+	}
+}
+
+func TestConfigManager_SaveProjectsMergesLatest(t *testing.T) {
+	cm := newTestConfigManager(t)
+	other := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := other.AddProject(ProjectConfig{Name: "other", Path: "/other", AIModel: ModelCodex}); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+
+	// 内存中的列表已过期，保存时不能覆盖其他实例新增的项目
+	cm.projects = []ProjectConfig{{Name: "mine", Path: "/mine", AIModel: ModelClaudeCode}}
+	if err := cm.SaveProjects(); err != nil {
+		t.Fatalf("Failed to save projects: %v", err)
+	}
+	if err := other.LoadProjects(); err != nil {
+		t.Fatalf("Failed to reload projects: %v", err)
+	}
+	if got := other.GetProjects(); len(got) != 2 {
+		t.Errorf("Expected both projects to be kept, got %+v", got)
+	}
+}
+
+// TestHelperProcess 作为 TestConfigManager_ConcurrentProcesses 的子进程运行，直接运行时跳过
+func TestHelperProcess(t *testing.T) {
+	file := os.Getenv("AI_LAUNCHER_TEST_PROJECTS")
+	if file == "" {
+		t.Skip("only runs as a child process")
+	}
+	cm := &ConfigManager{configDir: filepath.Dir(file), configFile: file}
+	writeConcurrently(t, cm, os.Getenv("AI_LAUNCHER_TEST_WORKER"))
+}
+
+func TestConfigManager_ConcurrentProcesses(t *testing.T) {
+	cm := newTestConfigManager(t)
+	workers := []string{"main", "p1", "p2", "p3"}
+	seed := []ProjectConfig{{Name: "shared", Path: "/shared", AIModel: ModelClaudeCode}}
+	for _, w := range workers {
+		seed = append(seed, ProjectConfig{Name: "remove-" + w, Path: "/remove/" + w, AIModel: ModelClaudeCode})
+	}
+	cm.projects = seed
+	if err := cm.SaveProjects(); err != nil {
+		t.Fatalf("Failed to save projects: %v", err)
+	}
+
+	// 其他进程与当前进程同时修改同一个文件
+	var wg sync.WaitGroup
+	for _, w := range workers[1:] {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = append(os.Environ(), "AI_LAUNCHER_TEST_PROJECTS="+cm.configFile, "AI_LAUNCHER_TEST_WORKER="+w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("Child process failed: %v\n%s", err, out)
+			}
+		}()
+	}
+	writeConcurrently(t, cm, workers[0])
+	wg.Wait()
+
+	// 所有进程的修改都应保留
+	reloaded := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := reloaded.LoadProjects(); err != nil {
+		t.Fatalf("Failed to reload projects: %v", err)
+	}
+	projects := reloaded.GetProjects()
+	if want := 1 + len(workers)*10; len(projects) != want {
+		t.Errorf("Expected %d projects, got %d", want, len(projects))
+	}
+	for _, p := range projects {
+		if strings.HasPrefix(p.Path, "/remove/") {
+			t.Errorf("Removed project came back: %s", p.Path)
+		}
+		// 每个进程的每个 goroutine 各更新 5 次使用次数，不能丢失任何一次
+		if want := len(workers) * 2 * 5; p.Path == "/shared" && p.UseCount != want {
+			t.Errorf("Expected shared project use count %d, got %d", want, p.UseCount)
+		}
+	}
+
+	// 不留下临时文件；锁文件由操作系统加锁，保留在磁盘上
+	entries, _ := os.ReadDir(cm.configDir)
+	for _, e := range entries {
+		if e.Name() != filepath.Base(cm.configFile) && e.Name() != filepath.Base(cm.configFile)+".lock" {
+			t.Errorf("Unexpected file left behind: %s", e.Name())
+		}
+	}
 }
\ No newline at end of file
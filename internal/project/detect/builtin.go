@@ -0,0 +1,383 @@
+package detect
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// builtins 内置检测器，按语言生态与仓库设施划分
+func builtins() []Detector {
+	return []Detector{
+		New("git", detectGit),
+		New("node", detectNode),
+		New("python", detectPython),
+		New("jvm", detectJVM),
+		New("go", detectGo),
+		New("rust", detectRust),
+		New("ci", detectCI),
+		New("container", detectContainers),
+		New("ai-config", detectAIConfigs),
+	}
+}
+
+func detectGit(dir *Dir, r *Result) {
+	if dir.Exists(".git") {
+		r.Git = true
+		r.Markers = append(r.Markers, ".git")
+	}
+}
+
+// goFrameworks go.mod 依赖与框架名称的对应
+var goFrameworks = []struct{ module, name string }{
+	{"github.com/gin-gonic/gin", "gin"},
+	{"github.com/labstack/echo", "echo"},
+	{"github.com/gofiber/fiber", "fiber"},
+	{"github.com/go-chi/chi", "chi"},
+	{"fyne.io/fyne", "fyne"},
+	{"github.com/charmbracelet/bubbletea", "bubbletea"},
+	{"github.com/spf13/cobra", "cobra"},
+}
+
+func detectGo(dir *Dir, r *Result) {
+	if work, ok := dir.ReadFile("go.work"); ok {
+		r.Markers = append(r.Markers, "go.work")
+		r.Languages = append(r.Languages, "Go")
+		r.MonorepoTools = append(r.MonorepoTools, "go work")
+		r.Workspaces = append(r.Workspaces, goWorkUses(work)...)
+	}
+	mod, ok := dir.ReadFile("go.mod")
+	if !ok {
+		return
+	}
+	r.Markers = append(r.Markers, "go.mod")
+	r.Languages = append(r.Languages, "Go")
+	r.PackageManagers = append(r.PackageManagers, "go modules")
+	for _, f := range goFrameworks {
+		if bytes.Contains(mod, []byte(f.module)) {
+			r.Frameworks = append(r.Frameworks, f.name)
+		}
+	}
+	r.Commands = append(r.Commands,
+		Command{Name: "build", Kind: KindBuild, Command: "go build ./..."},
+		Command{Name: "vet", Kind: KindLint, Command: "go vet ./..."},
+		Command{Name: "test", Kind: KindTest, Command: "go test ./..."},
+	)
+}
+
+// goWorkUses 解析 go.work 中的 use 指令，支持单行与块形式
+func goWorkUses(data []byte) []string {
+	var uses []string
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			uses = append(uses, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(line[len("use "):]), `"`))
+		}
+	}
+	return uses
+}
+
+// packageJSON package.json 中检测用到的字段
+type packageJSON struct {
+	PackageManager  string            `json:"packageManager"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Workspaces      interface{}       `json:"workspaces"` // 数组，或含 packages 的对象
+}
+
+// nodeFrameworks 依赖与框架名称的对应，按优先级排列
+var nodeFrameworks = []struct{ dependency, name string }{
+	{"react", "react"},
+	{"vue", "vue"},
+	{"@angular/core", "angular"},
+	{"angular", "angular"}, // AngularJS
+	{"next", "nextjs"},
+	{"svelte", "svelte"},
+	{"@nestjs/core", "nestjs"},
+	{"express", "express"},
+	{"electron", "electron"},
+}
+
+// nodeScripts 作为项目命令的 npm 脚本，顺序与质量门禁一致
+var nodeScripts = []struct {
+	name string
+	kind CommandKind
+}{
+	{"lint", KindLint},
+	{"build", KindBuild},
+	{"test", KindTest},
+}
+
+func detectNode(dir *Dir, r *Result) {
+	if !dir.Exists("package.json") {
+		if dir.Exists("tsconfig.json") {
+			r.Markers = append(r.Markers, "tsconfig.json")
+			r.Languages = append(r.Languages, "TypeScript")
+		}
+		return
+	}
+	r.Markers = append(r.Markers, "package.json")
+
+	var pkg packageJSON
+	dir.ReadJSON("package.json", &pkg)
+	hasDep := func(name string) bool {
+		_, ok := pkg.Dependencies[name]
+		if !ok {
+			_, ok = pkg.DevDependencies[name]
+		}
+		return ok
+	}
+
+	if dir.Exists("tsconfig.json") {
+		r.Markers = append(r.Markers, "tsconfig.json")
+	}
+	if dir.Exists("tsconfig.json") || hasDep("typescript") {
+		r.Languages = append(r.Languages, "TypeScript")
+	} else {
+		r.Languages = append(r.Languages, "JavaScript")
+	}
+	for _, f := range nodeFrameworks {
+		if hasDep(f.dependency) {
+			r.Frameworks = append(r.Frameworks, f.name)
+		}
+	}
+
+	manager := nodePackageManager(dir, pkg.PackageManager)
+	r.PackageManagers = append(r.PackageManagers, manager)
+	for _, s := range nodeScripts {
+		script, ok := pkg.Scripts[s.name]
+		// npm init 生成的默认 test 脚本只会报错退出
+		if !ok || strings.Contains(script, "no test specified") {
+			continue
+		}
+		r.Commands = append(r.Commands, Command{Name: s.name, Kind: s.kind, Command: manager + " run " + s.name})
+	}
+
+	if workspaces := packageWorkspaces(pkg.Workspaces); len(workspaces) > 0 {
+		r.Workspaces = append(r.Workspaces, workspaces...)
+		r.MonorepoTools = append(r.MonorepoTools, manager+" workspaces")
+	}
+	if data, ok := dir.ReadFile("pnpm-workspace.yaml"); ok {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &ws) == nil {
+			r.Markers = append(r.Markers, "pnpm-workspace.yaml")
+			r.Workspaces = append(r.Workspaces, ws.Packages...)
+			r.MonorepoTools = append(r.MonorepoTools, "pnpm workspaces")
+		}
+	}
+	var lerna struct {
+		Packages []string `json:"packages"`
+	}
+	if dir.ReadJSON("lerna.json", &lerna) {
+		r.Workspaces = append(r.Workspaces, lerna.Packages...)
+		r.MonorepoTools = append(r.MonorepoTools, "lerna")
+	}
+	for _, tool := range []struct{ file, name string }{{"turbo.json", "turbo"}, {"nx.json", "nx"}} {
+		if dir.Exists(tool.file) {
+			r.MonorepoTools = append(r.MonorepoTools, tool.name)
+		}
+	}
+}
+
+// nodePackageManager 优先取 package.json 的 packageManager 字段，其次按锁文件判断，默认 npm
+func nodePackageManager(dir *Dir, field string) string {
+	if name, _, _ := strings.Cut(field, "@"); name != "" {
+		return name
+	}
+	switch dir.First("pnpm-lock.yaml", "yarn.lock", "bun.lockb", "bun.lock") {
+	case "pnpm-lock.yaml":
+		return "pnpm"
+	case "yarn.lock":
+		return "yarn"
+	case "bun.lockb", "bun.lock":
+		return "bun"
+	}
+	return "npm"
+}
+
+// packageWorkspaces 解析 package.json 的 workspaces 字段
+func packageWorkspaces(v interface{}) []string {
+	if m, ok := v.(map[string]interface{}); ok {
+		v = m["packages"]
+	}
+	list, _ := v.([]interface{})
+	var workspaces []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			workspaces = append(workspaces, s)
+		}
+	}
+	return workspaces
+}
+
+// pythonFrameworkPattern 在依赖声明中查找的 Python 框架
+var pythonFrameworkPattern = regexp.MustCompile(`(?im)^[\s"']*(django|fastapi|flask)\b`)
+
+func detectPython(dir *Dir, r *Result) {
+	var deps []byte
+	for _, marker := range []string{"pyproject.toml", "requirements.txt", "setup.py", "Pipfile"} {
+		if data, ok := dir.ReadFile(marker); ok {
+			r.Markers = append(r.Markers, marker)
+			deps = append(append(deps, data...), '\n')
+		}
+	}
+	if deps == nil {
+		return
+	}
+	r.Languages = append(r.Languages, "Python")
+	pyproject, _ := dir.ReadFile("pyproject.toml")
+
+	switch {
+	case dir.Exists("poetry.lock") || bytes.Contains(pyproject, []byte("[tool.poetry]")):
+		r.PackageManagers = append(r.PackageManagers, "poetry")
+	case dir.Exists("uv.lock"):
+		r.PackageManagers = append(r.PackageManagers, "uv")
+	case dir.Exists("Pipfile"):
+		r.PackageManagers = append(r.PackageManagers, "pipenv")
+	case dir.Exists("pdm.lock"):
+		r.PackageManagers = append(r.PackageManagers, "pdm")
+	default:
+		r.PackageManagers = append(r.PackageManagers, "pip")
+	}
+	for _, m := range pythonFrameworkPattern.FindAllSubmatch(deps, -1) {
+		r.Frameworks = append(r.Frameworks, strings.ToLower(string(m[1])))
+	}
+
+	if dir.Exists("ruff.toml") || dir.Exists(".ruff.toml") || bytes.Contains(pyproject, []byte("[tool.ruff")) {
+		r.Commands = append(r.Commands, Command{Name: "lint", Kind: KindLint, Command: "ruff check ."})
+	}
+	if dir.IsDir("tests") || dir.Exists("pytest.ini") || dir.Exists("conftest.py") || bytes.Contains(pyproject, []byte("[tool.pytest")) {
+		r.Commands = append(r.Commands, Command{Name: "test", Kind: KindTest, Command: "pytest"})
+	}
+}
+
+func detectJVM(dir *Dir, r *Result) {
+	if build := dir.First("build.gradle.kts", "build.gradle"); build != "" {
+		r.Markers = append(r.Markers, build)
+		data, _ := dir.ReadFile(build)
+		if build == "build.gradle.kts" || dir.IsDir("src/main/kotlin") || bytes.Contains(data, []byte("kotlin")) {
+			r.Languages = append(r.Languages, "Kotlin")
+		} else {
+			r.Languages = append(r.Languages, "Java")
+		}
+		r.PackageManagers = append(r.PackageManagers, "gradle")
+		if bytes.Contains(data, []byte("com.android")) {
+			r.Frameworks = append(r.Frameworks, "android")
+		}
+		if bytes.Contains(data, []byte("org.springframework.boot")) {
+			r.Frameworks = append(r.Frameworks, "spring-boot")
+		}
+		gradle := "gradle"
+		if dir.Exists("gradlew") {
+			gradle = "./gradlew"
+		}
+		r.Commands = append(r.Commands,
+			Command{Name: "build", Kind: KindBuild, Command: gradle + " build"},
+			Command{Name: "test", Kind: KindTest, Command: gradle + " test"},
+		)
+		return
+	}
+
+	pom, ok := dir.ReadFile("pom.xml")
+	if !ok {
+		return
+	}
+	r.Markers = append(r.Markers, "pom.xml")
+	r.Languages = append(r.Languages, "Java")
+	r.PackageManagers = append(r.PackageManagers, "maven")
+	if bytes.Contains(pom, []byte("spring-boot")) {
+		r.Frameworks = append(r.Frameworks, "spring-boot")
+	}
+	r.Commands = append(r.Commands,
+		Command{Name: "build", Kind: KindBuild, Command: "mvn package"},
+		Command{Name: "test", Kind: KindTest, Command: "mvn test"},
+	)
+}
+
+// cargoMembersPattern 匹配 Cargo.toml [workspace] 中的 members 数组
+var cargoMembersPattern = regexp.MustCompile(`(?s)\[workspace\].*?members\s*=\s*\[(.*?)\]`)
+
+func detectRust(dir *Dir, r *Result) {
+	cargo, ok := dir.ReadFile("Cargo.toml")
+	if !ok {
+		return
+	}
+	r.Markers = append(r.Markers, "Cargo.toml")
+	r.Languages = append(r.Languages, "Rust")
+	r.PackageManagers = append(r.PackageManagers, "cargo")
+	if m := cargoMembersPattern.FindSubmatch(cargo); m != nil {
+		r.MonorepoTools = append(r.MonorepoTools, "cargo workspace")
+		for _, member := range strings.Split(string(m[1]), ",") {
+			r.Workspaces = append(r.Workspaces, strings.Trim(strings.TrimSpace(member), `"'`))
+		}
+	}
+	r.Commands = append(r.Commands,
+		Command{Name: "build", Kind: KindBuild, Command: "cargo build"},
+		Command{Name: "test", Kind: KindTest, Command: "cargo test"},
+	)
+}
+
+// ciSystems CI 配置文件与系统名称，模式相对于项目目录
+var ciSystems = []struct{ pattern, name string }{
+	{".github/workflows/*.yml", "GitHub Actions"},
+	{".github/workflows/*.yaml", "GitHub Actions"},
+	{".gitlab-ci.yml", "GitLab CI"},
+	{".circleci/config.yml", "CircleCI"},
+	{"Jenkinsfile", "Jenkins"},
+	{"azure-pipelines.yml", "Azure Pipelines"},
+	{".travis.yml", "Travis CI"},
+	{"bitbucket-pipelines.yml", "Bitbucket Pipelines"},
+}
+
+func detectCI(dir *Dir, r *Result) {
+	for _, ci := range ciSystems {
+		if len(dir.Glob(ci.pattern)) > 0 {
+			r.CI = append(r.CI, ci.name)
+		}
+	}
+}
+
+// containerPatterns 容器相关文件
+var containerPatterns = []string{
+	"Dockerfile", "Dockerfile.*", "*.Dockerfile", "Containerfile",
+	"docker-compose.yml", "docker-compose.yaml", "docker-compose.*.yml", "compose.yml", "compose.yaml",
+	".devcontainer/devcontainer.json", ".devcontainer.json",
+}
+
+func detectContainers(dir *Dir, r *Result) {
+	for _, pattern := range containerPatterns {
+		r.Containers = append(r.Containers, dir.Glob(pattern)...)
+	}
+}
+
+// AIConfigFiles 各 AI 工具读取的项目说明文件
+var AIConfigFiles = []string{
+	"CLAUDE.md", "GEMINI.md", "AGENTS.md", ".cursorrules", ".cursor/rules",
+	".github/copilot-instructions.md", ".windsurfrules", "CONVENTIONS.md",
+}
+
+func detectAIConfigs(dir *Dir, r *Result) {
+	for _, name := range AIConfigFiles {
+		if dir.Exists(name) {
+			r.AIConfigs = append(r.AIConfigs, name)
+		}
+	}
+}
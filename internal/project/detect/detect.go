@@ -0,0 +1,318 @@
+// Package detect 检测项目环境：语言、框架、包管理器、测试/构建/lint 命令、monorepo 工作区、
+// CI、容器文件与已有的 AI 配置文件。项目对话框、init、质量门禁与提示词上下文共用同一套规则，
+// 新的检测器通过 Register 加入
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProjectMarkers 标记项目根目录的文件或目录，项目发现以此判断目录是否为项目
+var ProjectMarkers = []string{".git", "go.mod", "package.json", "pyproject.toml", "requirements.txt", "tsconfig.json", "Cargo.toml", "build.gradle", "build.gradle.kts", "pom.xml"}
+
+// CommandKind 命令类别
+type CommandKind string
+
+const (
+	KindBuild CommandKind = "build"
+	KindLint  CommandKind = "lint"
+	KindTest  CommandKind = "test"
+)
+
+// Command 检测到的项目命令，Name 同时作为质量门禁的名称
+type Command struct {
+	Name    string      `json:"name"`
+	Kind    CommandKind `json:"kind"`
+	Command string      `json:"command"`
+	Source  string      `json:"source"` // 产生命令的检测器，未设置时由 Run 填写
+}
+
+// Result 检测结果；列表按检测器的顺序排列且不重复，路径均相对于项目目录
+type Result struct {
+	Path            string    `json:"path"`
+	Markers         []string  `json:"markers,omitempty"`          // 检测依据的标记文件
+	Languages       []string  `json:"languages,omitempty"`        // 如 Go、TypeScript、Python
+	Frameworks      []string  `json:"frameworks,omitempty"`       // 如 react、fastapi、gin
+	PackageManagers []string  `json:"package_managers,omitempty"` // 如 go modules、pnpm、poetry
+	Commands        []Command `json:"commands,omitempty"`
+	Workspaces      []string  `json:"workspaces,omitempty"`     // monorepo 子项目的目录或模式
+	MonorepoTools   []string  `json:"monorepo_tools,omitempty"` // 如 pnpm workspaces、turbo、go work
+	CI              []string  `json:"ci,omitempty"`             // 如 GitHub Actions
+	Containers      []string  `json:"containers,omitempty"`     // Dockerfile、compose 与 devcontainer 文件
+	AIConfigs       []string  `json:"ai_configs,omitempty"`     // CLAUDE.md、GEMINI.md、AGENTS.md 等
+	Git             bool      `json:"git"`
+}
+
+// Detector 检测器：读取项目目录并补充检测结果。无法读取或解析的文件应被忽略
+type Detector interface {
+	Name() string
+	Detect(dir *Dir, r *Result)
+}
+
+type detectorFunc struct {
+	name string
+	fn   func(dir *Dir, r *Result)
+}
+
+func (d detectorFunc) Name() string               { return d.name }
+func (d detectorFunc) Detect(dir *Dir, r *Result) { d.fn(dir, r) }
+
+// New 用函数创建检测器
+func New(name string, fn func(dir *Dir, r *Result)) Detector {
+	return detectorFunc{name: name, fn: fn}
+}
+
+var (
+	mu        sync.RWMutex
+	detectors = builtins()
+)
+
+// Register 注册检测器，在已有检测器之后运行；同名检测器被替换，可用于覆盖内置规则
+func Register(d Detector) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, existing := range detectors {
+		if existing.Name() == d.Name() {
+			detectors[i] = d
+			return
+		}
+	}
+	detectors = append(detectors, d)
+}
+
+// Detectors 返回已注册的检测器
+func Detectors() []Detector {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Detector(nil), detectors...)
+}
+
+// Detect 用已注册的检测器检测项目；目录不存在时返回空结果
+func Detect(projectPath string) *Result {
+	return Run(projectPath, Detectors()...)
+}
+
+// Run 依次运行指定的检测器
+func Run(projectPath string, detectors ...Detector) *Result {
+	dir := &Dir{Path: projectPath, files: make(map[string][]byte)}
+	r := &Result{Path: projectPath}
+	for _, d := range detectors {
+		n := len(r.Commands)
+		d.Detect(dir, r)
+		for i := n; i < len(r.Commands); i++ {
+			if r.Commands[i].Source == "" {
+				r.Commands[i].Source = d.Name()
+			}
+		}
+	}
+	r.normalize()
+	return r
+}
+
+// normalize 去除重复项，保持首次出现的顺序
+func (r *Result) normalize() {
+	for _, list := range []*[]string{&r.Markers, &r.Languages, &r.Frameworks, &r.PackageManagers, &r.Workspaces, &r.MonorepoTools, &r.CI, &r.Containers, &r.AIConfigs} {
+		*list = unique(*list)
+	}
+	seen := make(map[string]bool)
+	commands := r.Commands[:0]
+	for _, c := range r.Commands {
+		if !seen[c.Command] {
+			seen[c.Command] = true
+			commands = append(commands, c)
+		}
+	}
+	r.Commands = commands
+}
+
+func unique(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	out := list[:0]
+	for _, s := range list {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// HasMarker 检查是否检测到标记文件
+func (r *Result) HasMarker(name string) bool {
+	return contains(r.Markers, name)
+}
+
+// HasFramework 检查是否检测到框架
+func (r *Result) HasFramework(name string) bool {
+	return contains(r.Frameworks, name)
+}
+
+// CommandsOf 返回指定类别的命令
+func (r *Result) CommandsOf(kind CommandKind) []Command {
+	var commands []Command
+	for _, c := range r.Commands {
+		if c.Kind == kind {
+			commands = append(commands, c)
+		}
+	}
+	return commands
+}
+
+// Empty 没有检测到任何内容
+func (r *Result) Empty() bool {
+	return len(r.Markers) == 0 && len(r.Languages) == 0 && len(r.CI) == 0 && len(r.Containers) == 0 && len(r.AIConfigs) == 0 && !r.Git
+}
+
+// Summary 返回适合在界面中逐行显示的摘要
+func (r *Result) Summary() []string {
+	var lines []string
+	add := func(label string, values []string) {
+		if len(values) > 0 {
+			lines = append(lines, label+": "+strings.Join(values, ", "))
+		}
+	}
+	add("语言", r.Languages)
+	add("框架", r.Frameworks)
+	add("包管理器", r.PackageManagers)
+	for _, kind := range []struct {
+		kind  CommandKind
+		label string
+	}{{KindTest, "测试"}, {KindBuild, "构建"}, {KindLint, "检查"}} {
+		var commands []string
+		for _, c := range r.CommandsOf(kind.kind) {
+			commands = append(commands, "`"+c.Command+"`")
+		}
+		add(kind.label, commands)
+	}
+	if len(r.Workspaces) > 0 {
+		add("工作区", r.Workspaces)
+	}
+	add("Monorepo", r.MonorepoTools)
+	add("CI", r.CI)
+	add("容器", r.Containers)
+	add("AI 配置", r.AIConfigs)
+	if r.Git {
+		lines = append(lines, "Git 仓库")
+	} else {
+		lines = append(lines, "未初始化 Git")
+	}
+	return lines
+}
+
+// ContextPrompt 返回传给模型的项目环境上下文；没有检测到语言时返回空字符串
+func (r *Result) ContextPrompt() string {
+	if len(r.Languages) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("[Project environment]\n")
+	line := func(label string, values []string) {
+		if len(values) > 0 {
+			fmt.Fprintf(&b, "%s: %s\n", label, strings.Join(values, ", "))
+		}
+	}
+	line("Languages", r.Languages)
+	line("Frameworks", r.Frameworks)
+	line("Package managers", r.PackageManagers)
+	for _, kind := range []CommandKind{KindTest, KindBuild, KindLint} {
+		var commands []string
+		for _, c := range r.CommandsOf(kind) {
+			commands = append(commands, c.Command)
+		}
+		line(strings.ToUpper(string(kind[:1]))+string(kind[1:])+" commands", commands)
+	}
+	line("Workspaces", r.Workspaces)
+	line("Existing AI instructions", r.AIConfigs)
+	b.WriteString("[End of project environment]")
+	return b.String()
+}
+
+// TemplateVars 返回模板变量 language 与 framework 的默认值
+func (r *Result) TemplateVars() map[string]string {
+	vars := make(map[string]string)
+	if len(r.Languages) > 0 {
+		vars["language"] = r.Languages[0]
+	}
+	if len(r.Frameworks) > 0 {
+		vars["framework"] = r.Frameworks[0]
+	}
+	return vars
+}
+
+// Dir 检测器读取的项目目录，读取过的文件会被缓存
+type Dir struct {
+	Path  string
+	files map[string][]byte
+}
+
+// Exists 检查文件或目录是否存在，name 为相对路径
+func (d *Dir) Exists(name string) bool {
+	_, err := os.Stat(filepath.Join(d.Path, filepath.FromSlash(name)))
+	return err == nil
+}
+
+// IsDir 检查目录是否存在
+func (d *Dir) IsDir(name string) bool {
+	info, err := os.Stat(filepath.Join(d.Path, filepath.FromSlash(name)))
+	return err == nil && info.IsDir()
+}
+
+// ReadFile 读取文件，不存在或无法读取时返回 false
+func (d *Dir) ReadFile(name string) ([]byte, bool) {
+	if data, ok := d.files[name]; ok {
+		return data, data != nil
+	}
+	data, err := os.ReadFile(filepath.Join(d.Path, filepath.FromSlash(name)))
+	if err != nil {
+		data = nil
+	}
+	d.files[name] = data
+	return data, data != nil
+}
+
+// ReadJSON 读取并解析 JSON 文件
+func (d *Dir) ReadJSON(name string, v interface{}) bool {
+	data, ok := d.ReadFile(name)
+	return ok && json.Unmarshal(data, v) == nil
+}
+
+// Glob 返回匹配模式的相对路径，按名称排序
+func (d *Dir) Glob(pattern string) []string {
+	matches, _ := filepath.Glob(filepath.Join(d.Path, filepath.FromSlash(pattern)))
+	var names []string
+	for _, m := range matches {
+		if rel, err := filepath.Rel(d.Path, m); err == nil {
+			names = append(names, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// First 返回第一个存在的文件，都不存在时返回空字符串
+func (d *Dir) First(names ...string) string {
+	for _, name := range names {
+		if d.Exists(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetect_Fixtures(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected Result
+	}{
+		{"go-service", Result{
+			Markers:         []string{"go.mod"},
+			Languages:       []string{"Go"},
+			Frameworks:      []string{"gin"},
+			PackageManagers: []string{"go modules"},
+			Commands: []Command{
+				{Name: "build", Kind: KindBuild, Command: "go build ./...", Source: "go"},
+				{Name: "vet", Kind: KindLint, Command: "go vet ./...", Source: "go"},
+				{Name: "test", Kind: KindTest, Command: "go test ./...", Source: "go"},
+			},
+			CI:         []string{"GitHub Actions"},
+			Containers: []string{"Dockerfile"},
+			AIConfigs:  []string{"CLAUDE.md", "AGENTS.md"},
+		}},
+		{"go-workspace", Result{
+			Markers:       []string{"go.work"},
+			Languages:     []string{"Go"},
+			Workspaces:    []string{"./api", "./worker", "./tools"},
+			MonorepoTools: []string{"go work"},
+		}},
+		{"node-monorepo", Result{
+			Markers:         []string{"package.json", "pnpm-workspace.yaml"},
+			Languages:       []string{"TypeScript"},
+			Frameworks:      []string{"react", "nextjs"},
+			PackageManagers: []string{"pnpm"},
+			Commands: []Command{
+				{Name: "lint", Kind: KindLint, Command: "pnpm run lint", Source: "node"},
+				{Name: "build", Kind: KindBuild, Command: "pnpm run build", Source: "node"},
+			},
+			Workspaces:    []string{"packages/*", "apps/*"},
+			MonorepoTools: []string{"pnpm workspaces", "turbo"},
+			CI:            []string{"GitLab CI"},
+			Containers:    []string{"docker-compose.yml"},
+			AIConfigs:     []string{"GEMINI.md"},
+		}},
+		{"python-app", Result{
+			Markers:         []string{"pyproject.toml"},
+			Languages:       []string{"Python"},
+			Frameworks:      []string{"fastapi"},
+			PackageManagers: []string{"poetry"},
+			Commands: []Command{
+				{Name: "lint", Kind: KindLint, Command: "ruff check .", Source: "python"},
+				{Name: "test", Kind: KindTest, Command: "pytest", Source: "python"},
+			},
+			Containers: []string{".devcontainer/devcontainer.json"},
+			AIConfigs:  []string{".github/copilot-instructions.md"},
+		}},
+		{"rust-workspace", Result{
+			Markers:         []string{"Cargo.toml"},
+			Languages:       []string{"Rust"},
+			PackageManagers: []string{"cargo"},
+			Commands: []Command{
+				{Name: "build", Kind: KindBuild, Command: "cargo build", Source: "rust"},
+				{Name: "test", Kind: KindTest, Command: "cargo test", Source: "rust"},
+			},
+			Workspaces:    []string{"crates/core", "crates/cli"},
+			MonorepoTools: []string{"cargo workspace"},
+			CI:            []string{"Jenkins"},
+		}},
+		{"jvm-app", Result{
+			Markers:         []string{"build.gradle.kts"},
+			Languages:       []string{"Kotlin"},
+			Frameworks:      []string{"android"},
+			PackageManagers: []string{"gradle"},
+			Commands: []Command{
+				{Name: "build", Kind: KindBuild, Command: "./gradlew build", Source: "jvm"},
+				{Name: "test", Kind: KindTest, Command: "./gradlew test", Source: "jvm"},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.fixture)
+			result := Detect(path)
+			tt.expected.Path = path
+			if !reflect.DeepEqual(*result, tt.expected) {
+				t.Errorf("Unexpected result\n got: %+v\nwant: %+v", *result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetect_Git(t *testing.T) {
+	dir := t.TempDir()
+	if result := Detect(dir); result.Git || !result.Empty() {
+		t.Errorf("Expected empty result, got %+v", result)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if result := Detect(dir); !result.Git || !result.HasMarker(".git") {
+		t.Errorf("Expected git repository, got %+v", result)
+	}
+	if result := Detect(filepath.Join(dir, "missing")); !result.Empty() {
+		t.Errorf("Expected empty result for missing directory, got %+v", result)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	saved := Detectors()
+	defer func() {
+		mu.Lock()
+		detectors = saved
+		mu.Unlock()
+	}()
+
+	Register(New("make", func(dir *Dir, r *Result) {
+		if dir.Exists("go.mod") {
+			r.Commands = append(r.Commands, Command{Name: "check", Kind: KindLint, Command: "make check"})
+		}
+	}))
+	// 同名检测器替换内置规则
+	Register(New("ai-config", func(dir *Dir, r *Result) {
+		r.AIConfigs = append(r.AIConfigs, "TEAM.md")
+	}))
+	if len(Detectors()) != len(saved)+1 {
+		t.Fatalf("Expected %d detectors, got %d", len(saved)+1, len(Detectors()))
+	}
+
+	result := Detect(filepath.Join("testdata", "go-service"))
+	last := result.Commands[len(result.Commands)-1]
+	if last.Command != "make check" || last.Source != "make" {
+		t.Errorf("Expected custom command, got %+v", last)
+	}
+	if !reflect.DeepEqual(result.AIConfigs, []string{"TEAM.md"}) {
+		t.Errorf("Expected replaced detector, got %v", result.AIConfigs)
+	}
+}
+
+func TestResult_Prompts(t *testing.T) {
+	result := Detect(filepath.Join("testdata", "node-monorepo"))
+
+	vars := result.TemplateVars()
+	if vars["language"] != "TypeScript" || vars["framework"] != "react" {
+		t.Errorf("Unexpected template vars: %v", vars)
+	}
+
+	prompt := result.ContextPrompt()
+	for _, want := range []string{"[Project environment]", "Frameworks: react, nextjs", "Build commands: pnpm run build", "Existing AI instructions: GEMINI.md"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Context prompt should contain %q:\n%s", want, prompt)
+		}
+	}
+	if prompt := Detect(t.TempDir()).ContextPrompt(); prompt != "" {
+		t.Errorf("Expected empty context prompt, got %q", prompt)
+	}
+
+	summary := strings.Join(result.Summary(), "\n")
+	if !strings.Contains(summary, "包管理器: pnpm") || !strings.Contains(summary, "未初始化 Git") {
+		t.Errorf("Unexpected summary:\n%s", summary)
+	}
+}
@@ -0,0 +1,235 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/project/detect"
+)
+
+// ProjectMarkers 标记项目根目录的文件或目录，与项目环境检测一致
+var ProjectMarkers = detect.ProjectMarkers
+
+// DefaultDiscoverDepth 未指定深度时根目录之下的最大扫描深度
+const DefaultDiscoverDepth = 3
+
+// DiscoverOptions 项目发现选项
+type DiscoverOptions struct {
+	Roots    []string // 扫描的根目录
+	MaxDepth int      // 根目录之下的最大深度，0 时使用 DefaultDiscoverDepth
+	Ignore   []string // 跳过的目录名，支持 * 通配符
+}
+
+// DiscoveredProject 扫描到的项目目录
+type DiscoveredProject struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Markers []string `json:"markers"`
+	Known   bool     `json:"known"` // 已在项目列表中
+}
+
+// Discover 在根目录下查找含项目标记的目录，按路径排序。找到项目后不再深入其子目录，
+// 不跟随符号链接；无法读取的子目录被跳过，根目录不存在时返回错误
+func Discover(opts DiscoverOptions) ([]DiscoveredProject, error) {
+	depth := opts.MaxDepth
+	if depth <= 0 {
+		depth = DefaultDiscoverDepth
+	}
+	for _, pattern := range opts.Ignore {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("无效的忽略模式 %q", pattern)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var found []DiscoveredProject
+	for _, root := range opts.Roots {
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("根目录不存在: %s", root)
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root {
+					return err
+				}
+				return fs.SkipDir
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if path != root && ignored(d.Name(), opts.Ignore) {
+				return fs.SkipDir
+			}
+
+			if markers := projectMarkers(path); len(markers) > 0 {
+				if !seen[path] {
+					seen[path] = true
+					found = append(found, DiscoveredProject{Name: filepath.Base(path), Path: path, Markers: markers})
+				}
+				return fs.SkipDir
+			}
+			if rel, _ := filepath.Rel(root, path); rel != "." && len(strings.Split(rel, string(filepath.Separator))) >= depth {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+	return found, nil
+}
+
+// ignored 检查目录名是否匹配忽略模式
+func ignored(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// projectMarkers 返回目录中存在的项目标记
+func projectMarkers(dir string) []string {
+	var markers []string
+	for _, marker := range ProjectMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			markers = append(markers, marker)
+		}
+	}
+	return markers
+}
+
+// Discover 查找项目并标记已在项目列表中的目录
+func (cm *ConfigManager) Discover(opts DiscoverOptions) ([]DiscoveredProject, error) {
+	found, err := Discover(opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		_, err := cm.GetProjectByPath(found[i].Path)
+		found[i].Known = err == nil
+	}
+	return found, nil
+}
+
+// ImportProjects 批量添加项目，已存在的路径被跳过；工具取 .ai-launcher.yaml 中的 tool，
+// 未设置时为 model。返回新添加的项目
+func (cm *ConfigManager) ImportProjects(found []DiscoveredProject, model AIModelType) ([]ProjectConfig, error) {
+	if model == "" {
+		model = ModelClaudeCode
+	}
+	candidates := make([]ProjectConfig, 0, len(found))
+	for _, d := range found {
+		p := ProjectConfig{Name: d.Name, Path: d.Path, AIModel: model}
+		if manifest, err := LoadManifest(d.Path); err == nil && manifest != nil && manifest.Tool != "" {
+			p.AIModel = manifest.Tool
+		}
+		candidates = append(candidates, p)
+	}
+
+	var added []ProjectConfig
+	err := cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+		added = nil
+		existing := make(map[string]bool, len(projects))
+		for _, p := range projects {
+			existing[p.Path] = true
+		}
+		for _, p := range candidates {
+			if existing[p.Path] {
+				continue
+			}
+			existing[p.Path] = true
+			added = append(added, p)
+			projects = append(projects, p)
+		}
+		return projects, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// CheckMissing 检查项目路径是否仍存在并更新 Missing 标记，返回路径不存在的项目。
+// 标记没有变化时不写入文件
+func (cm *ConfigManager) CheckMissing() ([]ProjectConfig, error) {
+	changed := false
+	for _, p := range cm.GetProjects() {
+		if pathMissing(p.Path) != p.Missing {
+			changed = true
+			break
+		}
+	}
+	if changed {
+		err := cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+			for i := range projects {
+				projects[i].Missing = pathMissing(projects[i].Path)
+			}
+			return projects, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var missing []ProjectConfig
+	for _, p := range cm.GetProjects() {
+		if p.Missing {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
+
+// pathMissing 路径确定不存在时返回 true；无权限等其他错误不视为不存在
+func pathMissing(path string) bool {
+	_, err := os.Stat(path)
+	return os.IsNotExist(err)
+}
+
+// WatchMissing 立即并每隔 interval 检查项目路径，路径不存在的项目数量变化或检查失败时调用 onChange。
+// ctx 取消后返回
+func (cm *ConfigManager) WatchMissing(ctx context.Context, interval time.Duration, onChange func(missing []ProjectConfig, err error)) {
+	previous := 0
+	for _, p := range cm.GetProjects() {
+		if p.Missing {
+			previous++
+		}
+	}
+	check := func() {
+		missing, err := cm.CheckMissing()
+		if onChange != nil && (err != nil || len(missing) != previous) {
+			onChange(missing, err)
+		}
+		if err == nil {
+			previous = len(missing)
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
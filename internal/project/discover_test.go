@@ -0,0 +1,153 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// makeTree 在 root 下创建文件，以 / 结尾的路径创建目录
+func makeTree(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if p[len(p)-1] == '/' {
+			if err := os.MkdirAll(full, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root,
+		"api/.git/",
+		"api/go.mod",
+		"api/services/billing/go.mod", // 项目内的子目录不再扫描
+		"web/package.json",
+		"group/ml/pyproject.toml",
+		"group/deep/a/b/go.mod", // 超出深度
+		"node_modules/pkg/package.json",
+		".cache/tool/go.mod",
+		"notes/readme.md",
+	)
+
+	found, err := Discover(DiscoverOptions{Roots: []string{root, root}, MaxDepth: 3, Ignore: []string{"node_modules", ".*"}})
+	if err != nil {
+		t.Fatalf("Failed to discover: %v", err)
+	}
+	var paths []string
+	for _, p := range found {
+		rel, _ := filepath.Rel(root, p.Path)
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	if expected := []string{"api", "group/ml", "web"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+	if !reflect.DeepEqual(found[0].Markers, []string{".git", "go.mod"}) || found[0].Name != "api" {
+		t.Errorf("Unexpected project: %+v", found[0])
+	}
+
+	if _, err := Discover(DiscoverOptions{Roots: []string{filepath.Join(root, "missing")}}); err == nil {
+		t.Error("Expected error for missing root")
+	}
+	if _, err := Discover(DiscoverOptions{Roots: []string{root}, Ignore: []string{"["}}); err == nil {
+		t.Error("Expected error for invalid ignore pattern")
+	}
+}
+
+func TestConfigManager_ImportProjects(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, "api/go.mod", "web/package.json")
+	if err := os.WriteFile(filepath.Join(root, "web", ManifestFile), []byte("launch:\n  tool: gemini_cli\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := newTestConfigManager(t)
+	apiPath := filepath.Join(root, "api")
+	if err := cm.AddProject(ProjectConfig{Name: "API", Path: apiPath, AIModel: ModelCodex, Tags: []string{"go"}}); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+
+	found, err := cm.Discover(DiscoverOptions{Roots: []string{root}})
+	if err != nil {
+		t.Fatalf("Failed to discover: %v", err)
+	}
+	if len(found) != 2 || !found[0].Known || found[1].Known {
+		t.Fatalf("Unexpected discovered projects: %+v", found)
+	}
+
+	added, err := cm.ImportProjects(found, ModelClaudeCode)
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	if len(added) != 1 || added[0].Name != "web" || added[0].AIModel != ModelGeminiCLI {
+		t.Errorf("Unexpected imported projects: %+v", added)
+	}
+	// 已有的项目保持不变
+	if p, _ := cm.GetProjectByPath(apiPath); p.Name != "API" || p.AIModel != ModelCodex || len(p.Tags) != 1 {
+		t.Errorf("Existing project should not change: %+v", p)
+	}
+	if len(cm.GetProjects()) != 2 {
+		t.Errorf("Expected 2 projects, got %d", len(cm.GetProjects()))
+	}
+}
+
+func TestConfigManager_CheckMissing(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, "api/", "web/")
+
+	cm := newTestConfigManager(t)
+	for _, name := range []string{"api", "web"} {
+		if err := cm.AddProject(ProjectConfig{Name: name, Path: filepath.Join(root, name), AIModel: ModelClaudeCode}); err != nil {
+			t.Fatalf("Failed to add project: %v", err)
+		}
+	}
+
+	if missing, err := cm.CheckMissing(); err != nil || len(missing) != 0 {
+		t.Fatalf("Expected no missing projects, got %+v, %v", missing, err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, "web")); err != nil {
+		t.Fatal(err)
+	}
+	missing, err := cm.CheckMissing()
+	if err != nil || len(missing) != 1 || missing[0].Name != "web" {
+		t.Fatalf("Expected web to be missing, got %+v, %v", missing, err)
+	}
+	reloaded := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := reloaded.LoadProjects(); err != nil {
+		t.Fatalf("Failed to reload projects: %v", err)
+	}
+	if p, _ := reloaded.GetProjectByPath(filepath.Join(root, "web")); !p.Missing {
+		t.Error("Missing flag should be saved")
+	}
+
+	// 路径恢复后清除标记
+	makeTree(t, root, "web/")
+	changes := make(chan int, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cm.WatchMissing(ctx, time.Hour, func(missing []ProjectConfig, err error) {
+		changes <- len(missing)
+	})
+	select {
+	case n := <-changes:
+		if n != 0 {
+			t.Errorf("Expected no missing projects after restore, got %d", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchMissing did not report the change")
+	}
+}
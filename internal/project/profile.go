@@ -0,0 +1,163 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// NoProfile 启动时不使用任何配置档（包括默认配置档）
+const NoProfile = "none"
+
+// SandboxMode 会话的沙箱级别，启动时转换为各工具的参数
+type SandboxMode string
+
+const (
+	SandboxNone      SandboxMode = ""                // 使用工具的默认设置
+	SandboxReadOnly  SandboxMode = "read_only"       // 只读，适合代码审查
+	SandboxWorkspace SandboxMode = "workspace_write" // 只能修改工作目录
+)
+
+// sandboxArgs 各工具支持的沙箱参数
+var sandboxArgs = map[AIModelType]map[SandboxMode][]string{
+	ModelClaudeCode: {SandboxReadOnly: {"--permission-mode", "plan"}},
+	ModelGeminiCLI:  {SandboxWorkspace: {"--sandbox"}},
+	ModelCodex: {
+		SandboxReadOnly:  {"--sandbox", "read-only"},
+		SandboxWorkspace: {"--sandbox", "workspace-write"},
+	},
+	ModelAider: {SandboxReadOnly: {"--dry-run"}},
+}
+
+// Args 返回工具在该沙箱级别下需要追加的参数，工具不支持时返回错误
+func (m SandboxMode) Args(tool AIModelType) ([]string, error) {
+	if m == SandboxNone {
+		return nil, nil
+	}
+	if m != SandboxReadOnly && m != SandboxWorkspace {
+		return nil, fmt.Errorf("未知的沙箱级别 %q (read_only, workspace_write)", string(m))
+	}
+	args, ok := sandboxArgs[tool][m]
+	if !ok {
+		return nil, fmt.Errorf("%s 不支持沙箱级别 %s", tool.String(), m)
+	}
+	return args, nil
+}
+
+// LaunchProfile 项目的命名启动配置，例如“Claude YOLO + MCP”“Gemini 只读审查”
+type LaunchProfile struct {
+	Name     string            `json:"name"`
+	Tool     AIModelType       `json:"tool,omitempty"`     // 为空时使用项目的工具
+	Yolo     *bool             `json:"yolo,omitempty"`     // 为空时使用项目设置
+	Args     []string          `json:"args,omitempty"`     // 追加到工具命令的参数
+	Env      map[string]string `json:"env,omitempty"`      // 会话的环境变量
+	Template string            `json:"template,omitempty"` // 应用于第一条提示词的模板 ID
+	WorkDir  string            `json:"work_dir,omitempty"` // 工作目录，项目内的相对路径
+	Sandbox  SandboxMode       `json:"sandbox,omitempty"`
+	Default  bool              `json:"default,omitempty"` // 未指定配置档时使用
+}
+
+// Validate 检查配置档
+func (p LaunchProfile) Validate() error {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return fmt.Errorf("配置档名称不能为空")
+	}
+	if name == NoProfile {
+		return fmt.Errorf("配置档名称 %q 已保留", NoProfile)
+	}
+	if p.Tool != "" && !isKnownModel(p.Tool) {
+		return fmt.Errorf("配置档 %s: 未知的 AI 工具 %q", p.Name, string(p.Tool))
+	}
+	if p.WorkDir != "" && !filepath.IsLocal(p.WorkDir) {
+		return fmt.Errorf("配置档 %s: work_dir 必须是项目内的相对路径: %s", p.Name, p.WorkDir)
+	}
+	if p.Sandbox != SandboxNone {
+		if p.Yolo != nil && *p.Yolo {
+			return fmt.Errorf("配置档 %s: 沙箱与 YOLO 模式不能同时启用", p.Name)
+		}
+		if p.Tool != "" {
+			if _, err := p.Sandbox.Args(p.Tool); err != nil {
+				return fmt.Errorf("配置档 %s: %v", p.Name, err)
+			}
+		} else if p.Sandbox != SandboxReadOnly && p.Sandbox != SandboxWorkspace {
+			return fmt.Errorf("配置档 %s: 未知的沙箱级别 %q", p.Name, string(p.Sandbox))
+		}
+	}
+	for key := range p.Env {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			return fmt.Errorf("配置档 %s: 无效的环境变量名 %q", p.Name, key)
+		}
+	}
+	return nil
+}
+
+// ValidateProfiles 检查项目的全部配置档：名称唯一，最多一个默认配置档
+func ValidateProfiles(profiles []LaunchProfile) error {
+	names := make(map[string]bool)
+	defaults := 0
+	for _, p := range profiles {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if names[p.Name] {
+			return fmt.Errorf("配置档名称重复: %s", p.Name)
+		}
+		names[p.Name] = true
+		if p.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("最多只能有一个默认配置档")
+	}
+	return nil
+}
+
+// Profile 按名称查找配置档；name 为空时返回默认配置档，没有默认配置档或 name 为 NoProfile 时返回 nil
+func (p ProjectConfig) Profile(name string) (*LaunchProfile, error) {
+	if name == NoProfile {
+		return nil, nil
+	}
+	for i := range p.Profiles {
+		if (name == "" && p.Profiles[i].Default) || (name != "" && p.Profiles[i].Name == name) {
+			profile := p.Profiles[i]
+			return &profile, nil
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("项目 %s 没有配置档 %q", p.Name, name)
+}
+
+// SetProfile 添加或替换同名配置档；设为默认时取消其他配置档的默认标记
+func (p *ProjectConfig) SetProfile(profile LaunchProfile) {
+	profiles := make([]LaunchProfile, 0, len(p.Profiles)+1)
+	replaced := false
+	for _, existing := range p.Profiles {
+		if profile.Default {
+			existing.Default = false
+		}
+		if existing.Name == profile.Name {
+			existing = profile
+			replaced = true
+		}
+		profiles = append(profiles, existing)
+	}
+	if !replaced {
+		profiles = append(profiles, profile)
+	}
+	p.Profiles = profiles
+}
+
+// RemoveProfile 删除配置档
+func (p *ProjectConfig) RemoveProfile(name string) error {
+	for i, existing := range p.Profiles {
+		if existing.Name == name {
+			p.Profiles = append(p.Profiles[:i:i], p.Profiles[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("项目 %s 没有配置档 %q", p.Name, name)
+}
@@ -0,0 +1,122 @@
+package project
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLaunchProfile_Validate(t *testing.T) {
+	yolo := true
+	for _, tt := range []struct {
+		profile LaunchProfile
+		want    string
+	}{
+		{LaunchProfile{}, "名称不能为空"},
+		{LaunchProfile{Name: NoProfile}, "已保留"},
+		{LaunchProfile{Name: "a", Tool: "vim"}, `未知的 AI 工具 "vim"`},
+		{LaunchProfile{Name: "a", WorkDir: "../other"}, "work_dir 必须是项目内的相对路径"},
+		{LaunchProfile{Name: "a", Sandbox: SandboxReadOnly, Yolo: &yolo}, "不能同时启用"},
+		{LaunchProfile{Name: "a", Tool: ModelGeminiCLI, Sandbox: SandboxReadOnly}, "不支持沙箱级别"},
+		{LaunchProfile{Name: "a", Sandbox: "chroot"}, "未知的沙箱级别"},
+		{LaunchProfile{Name: "a", Env: map[string]string{"A=B": "1"}}, "无效的环境变量名"},
+	} {
+		if err := tt.profile.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected error containing %q for %+v, got %v", tt.want, tt.profile, err)
+		}
+	}
+
+	valid := LaunchProfile{Name: "review", Tool: ModelCodex, Sandbox: SandboxWorkspace, WorkDir: "web", Env: map[string]string{"CI": "1"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateProfiles([]LaunchProfile{valid, valid}); err == nil {
+		t.Error("Expected error for duplicate names")
+	}
+	if err := ValidateProfiles([]LaunchProfile{{Name: "a", Default: true}, {Name: "b", Default: true}}); err == nil {
+		t.Error("Expected error for two default profiles")
+	}
+}
+
+func TestProjectConfig_Profiles(t *testing.T) {
+	p := ProjectConfig{Name: "web"}
+	p.SetProfile(LaunchProfile{Name: "mcp", Default: true})
+	p.SetProfile(LaunchProfile{Name: "review", Tool: ModelGeminiCLI})
+
+	if profile, err := p.Profile(""); err != nil || profile == nil || profile.Name != "mcp" {
+		t.Fatalf("Expected default profile, got %+v, %v", profile, err)
+	}
+	if profile, err := p.Profile(NoProfile); err != nil || profile != nil {
+		t.Errorf("Expected no profile, got %+v, %v", profile, err)
+	}
+	if _, err := p.Profile("missing"); err == nil {
+		t.Error("Expected error for missing profile")
+	}
+
+	// 设为默认时取消其他配置档的默认标记；同名配置档被替换
+	p.SetProfile(LaunchProfile{Name: "review", Tool: ModelCodex, Default: true})
+	if profile, _ := p.Profile(""); profile.Name != "review" || profile.Tool != ModelCodex || len(p.Profiles) != 2 {
+		t.Errorf("Unexpected profiles: %+v", p.Profiles)
+	}
+
+	if err := p.RemoveProfile("mcp"); err != nil {
+		t.Fatalf("Failed to remove profile: %v", err)
+	}
+	if err := p.RemoveProfile("mcp"); err == nil {
+		t.Error("Expected error when removing missing profile")
+	}
+	if len(p.Profiles) != 1 {
+		t.Errorf("Expected one profile left, got %+v", p.Profiles)
+	}
+}
+
+func TestSandboxMode_Args(t *testing.T) {
+	args, err := SandboxReadOnly.Args(ModelCodex)
+	if err != nil || !reflect.DeepEqual(args, []string{"--sandbox", "read-only"}) {
+		t.Errorf("Unexpected codex args: %v, %v", args, err)
+	}
+	if args, err := SandboxNone.Args(ModelAider); err != nil || args != nil {
+		t.Errorf("Expected no args, got %v, %v", args, err)
+	}
+	if _, err := SandboxWorkspace.Args(ModelClaudeCode); err == nil {
+		t.Error("Expected error for unsupported sandbox")
+	}
+}
+
+func TestConfigManager_UpdateProject(t *testing.T) {
+	cm := newTestConfigManager(t)
+	if err := cm.AddProject(ProjectConfig{Name: "web", Path: "/web", AIModel: ModelClaudeCode, Tags: []string{"ts"}}); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+
+	err := cm.UpdateProject("/web", func(p *ProjectConfig) error {
+		p.SetProfile(LaunchProfile{Name: "review", Tool: ModelCodex, Sandbox: SandboxReadOnly})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	reloaded := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := reloaded.LoadProjects(); err != nil {
+		t.Fatalf("Failed to reload projects: %v", err)
+	}
+	p, _ := reloaded.GetProjectByPath("/web")
+	if len(p.Profiles) != 1 || p.Profiles[0].Sandbox != SandboxReadOnly || len(p.Tags) != 1 {
+		t.Errorf("Unexpected project after update: %+v", p)
+	}
+
+	// 无效的配置档不会被保存
+	err = cm.UpdateProject("/web", func(p *ProjectConfig) error {
+		p.SetProfile(LaunchProfile{Name: "bad", Tool: ModelGeminiCLI, Sandbox: SandboxReadOnly})
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error for invalid profile")
+	}
+	if p, _ := cm.GetProjectByPath("/web"); len(p.Profiles) != 1 {
+		t.Errorf("Invalid profile should not be saved: %+v", p.Profiles)
+	}
+	if err := cm.UpdateProject("/missing", func(*ProjectConfig) error { return nil }); err == nil {
+		t.Error("Expected error for missing project")
+	}
+}
@@ -0,0 +1,139 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SortOrder 项目排序方式
+type SortOrder string
+
+const (
+	SortLastUsed  SortOrder = "last_used" // 最近使用的在前（默认）
+	SortName      SortOrder = "name"      // 按名称，不区分大小写
+	SortFrequency SortOrder = "frequency" // 使用次数多的在前
+)
+
+// ParseSortOrder 解析排序方式，为空时返回 SortLastUsed
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch order := SortOrder(s); order {
+	case "":
+		return SortLastUsed, nil
+	case SortLastUsed, SortName, SortFrequency:
+		return order, nil
+	default:
+		return "", fmt.Errorf("未知的排序方式 %q (last_used, name, frequency)", s)
+	}
+}
+
+// ArchiveFilter 归档项目的筛选方式
+type ArchiveFilter string
+
+const (
+	ArchiveExclude ArchiveFilter = ""        // 不返回归档项目（默认）
+	ArchiveInclude ArchiveFilter = "include" // 同时返回归档项目
+	ArchiveOnly    ArchiveFilter = "only"    // 只返回归档项目
+)
+
+// Query 项目查询条件，零值返回全部未归档的项目：置顶的在前，其余按最近使用排序
+type Query struct {
+	Tags     []string      `json:"tags,omitempty"`      // 须包含全部标签，不区分大小写
+	Group    string        `json:"group,omitempty"`     // 分组名称，不区分大小写
+	Model    AIModelType   `json:"model,omitempty"`     // AI 工具
+	PathGlob string        `json:"path_glob,omitempty"` // 路径模式，见 MatchPath
+	Text     string        `json:"text,omitempty"`      // 在名称、路径与备注中搜索，空格分隔的词须全部出现
+	Pinned   bool          `json:"pinned,omitempty"`    // 只返回置顶项目
+	Archived ArchiveFilter `json:"archived,omitempty"`
+	Sort     SortOrder     `json:"sort,omitempty"`
+	Limit    int           `json:"limit,omitempty"` // 最多返回的数量，0 表示不限制
+}
+
+// Match 检查项目是否符合筛选条件（不考虑排序与数量限制）
+func (q Query) Match(p ProjectConfig) bool {
+	switch {
+	case q.Archived == ArchiveExclude && p.Archived,
+		q.Archived == ArchiveOnly && !p.Archived,
+		q.Pinned && !p.Pinned,
+		q.Model != "" && p.AIModel != q.Model,
+		q.Group != "" && !strings.EqualFold(p.Group, q.Group),
+		q.PathGlob != "" && !MatchPath(q.PathGlob, p.Path):
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !p.HasTag(tag) {
+			return false
+		}
+	}
+
+	text := strings.ToLower(strings.Join([]string{p.Name, p.Path, p.Notes}, "\n"))
+	for _, word := range strings.Fields(strings.ToLower(q.Text)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply 返回符合条件的项目：置顶的在前，再按 Sort 排序并截取 Limit 个
+func (q Query) Apply(projects []ProjectConfig) []ProjectConfig {
+	selected := make([]ProjectConfig, 0, len(projects))
+	for _, p := range projects {
+		if q.Match(p) {
+			selected = append(selected, p)
+		}
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i], selected[j]
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		switch q.Sort {
+		case SortName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case SortFrequency:
+			if a.UseCount != b.UseCount {
+				return a.UseCount > b.UseCount
+			}
+		}
+		return a.LastUsed.After(b.LastUsed)
+	})
+
+	if q.Limit > 0 && q.Limit < len(selected) {
+		selected = selected[:q.Limit]
+	}
+	return selected
+}
+
+// Query 按条件查询项目，供图形界面、Web 启动器与命令行共用
+func (cm *ConfigManager) Query(q Query) []ProjectConfig {
+	return q.Apply(cm.GetProjects())
+}
+
+// HasTag 不区分大小写地检查项目是否带有标签
+func (p ProjectConfig) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPath 不含路径分隔符的模式只匹配目录名，否则匹配完整路径（支持 ~ 开头）
+func MatchPath(pattern, path string) bool {
+	if strings.HasPrefix(pattern, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			pattern = filepath.Join(homeDir, pattern[2:])
+		}
+	}
+	target := path
+	if !strings.ContainsAny(pattern, `/\`) {
+		target = filepath.Base(path)
+	}
+	matched, err := filepath.Match(pattern, target)
+	return err == nil && matched
+}
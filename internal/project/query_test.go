@@ -0,0 +1,62 @@
+package project
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuery_Apply(t *testing.T) {
+	now := time.Now()
+	projects := []ProjectConfig{
+		{Name: "api", Path: "/work/api", AIModel: ModelClaudeCode, Tags: []string{"go", "backend"}, Group: "Platform", LastUsed: now.Add(-time.Hour), UseCount: 9},
+		{Name: "Web", Path: "/work/web", AIModel: ModelGeminiCLI, Tags: []string{"ts"}, Notes: "Customer dashboard", LastUsed: now, UseCount: 2},
+		{Name: "tools", Path: "/home/tools", AIModel: ModelClaudeCode, Tags: []string{"Go"}, Pinned: true, LastUsed: now.Add(-48 * time.Hour)},
+		{Name: "legacy", Path: "/work/legacy", AIModel: ModelClaudeCode, Archived: true, LastUsed: now.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{"Default", Query{}, []string{"tools", "Web", "api"}},
+		{"Name", Query{Sort: SortName}, []string{"tools", "api", "Web"}},
+		{"Frequency", Query{Sort: SortFrequency}, []string{"tools", "api", "Web"}},
+		{"Limit", Query{Limit: 2}, []string{"tools", "Web"}},
+		{"Tag", Query{Tags: []string{"go"}}, []string{"tools", "api"}},
+		{"All tags", Query{Tags: []string{"go", "backend"}}, []string{"api"}},
+		{"Group", Query{Group: "platform"}, []string{"api"}},
+		{"Model", Query{Model: ModelGeminiCLI}, []string{"Web"}},
+		{"Path glob", Query{PathGlob: "/work/*"}, []string{"Web", "api"}},
+		{"Pinned", Query{Pinned: true}, []string{"tools"}},
+		{"Text in notes", Query{Text: "DASHBOARD"}, []string{"Web"}},
+		{"Text words", Query{Text: "work a"}, []string{"Web", "api"}},
+		{"Include archived", Query{Archived: ArchiveInclude, PathGlob: "/work/*"}, []string{"legacy", "Web", "api"}},
+		{"Only archived", Query{Archived: ArchiveOnly}, []string{"legacy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, p := range tt.query.Apply(projects) {
+				names = append(names, p.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	if order, err := ParseSortOrder(""); err != nil || order != SortLastUsed {
+		t.Errorf("Expected default sort order, got %q, %v", order, err)
+	}
+	if order, err := ParseSortOrder("frequency"); err != nil || order != SortFrequency {
+		t.Errorf("Expected frequency, got %q, %v", order, err)
+	}
+	if _, err := ParseSortOrder("size"); err == nil {
+		t.Error("Expected error for unknown sort order")
+	}
+}
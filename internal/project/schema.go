@@ -0,0 +1,96 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SchemaVersion projects.json 的当前格式版本
+const SchemaVersion = 2
+
+// projectsFile projects.json 的文件格式
+type projectsFile struct {
+	SchemaVersion int             `json:"schema_version"`
+	Projects      []ProjectConfig `json:"projects"`
+}
+
+// migration 将某个版本的文件内容转换为下一个版本
+type migration func(data []byte) ([]byte, error)
+
+// migrations 下标 i 的迁移把版本 i+1 转换为版本 i+2，新增版本时在末尾追加
+var migrations = []migration{
+	migrateV1,
+}
+
+// migrateV1 版本 1 是不带版本号的项目数组，包装为带 schema_version 的对象
+func migrateV1(data []byte) ([]byte, error) {
+	var projects []json.RawMessage
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		projects = []json.RawMessage{}
+	}
+	return json.Marshal(map[string]interface{}{"schema_version": 2, "projects": projects})
+}
+
+// schemaVersion 返回文件内容的格式版本
+func schemaVersion(data []byte) (int, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		return 1, nil
+	}
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(trimmed, &header); err != nil {
+		return 0, err
+	}
+	if header.SchemaVersion < 1 {
+		return 0, fmt.Errorf("缺少 schema_version")
+	}
+	return header.SchemaVersion, nil
+}
+
+// DecodeProjects 解析 projects.json，旧版本的内容在内存中迁移到当前版本；返回文件原来的版本。
+// 文件由更新版本的启动器写入时返回错误
+func DecodeProjects(data []byte) ([]ProjectConfig, int, error) {
+	version, err := schemaVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > SchemaVersion {
+		return nil, version, fmt.Errorf("schema_version %d 高于支持的版本 %d，请升级启动器", version, SchemaVersion)
+	}
+
+	for v := version; v < SchemaVersion; v++ {
+		if data, err = migrations[v-1](data); err != nil {
+			return nil, version, fmt.Errorf("从版本 %d 迁移失败: %v", v, err)
+		}
+	}
+	var file projectsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, version, err
+	}
+	return file.Projects, version, nil
+}
+
+// EncodeProjects 按当前版本序列化项目列表
+func EncodeProjects(projects []ProjectConfig) ([]byte, error) {
+	if projects == nil {
+		projects = []ProjectConfig{}
+	}
+	return json.MarshalIndent(projectsFile{SchemaVersion: SchemaVersion, Projects: projects}, "", "  ")
+}
+
+// backupFile 迁移前备份原文件，例如 projects.json.v1-20250101-150405.bak
+func (cm *ConfigManager) backupFile(data []byte, version int) (string, error) {
+	path := fmt.Sprintf("%s.v%d-%s.bak", cm.configFile, version, time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("备份配置文件失败: %v", err)
+	}
+	return path, nil
+}
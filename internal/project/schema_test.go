@@ -0,0 +1,92 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigManager_MigratesLegacyProjects(t *testing.T) {
+	cm := newTestConfigManager(t)
+	legacy := `[{"name":"web","path":"/web","ai_model":"gemini_cli","yolo_mode":true,"last_used":"2025-01-02T03:04:05Z","preferences":{"theme":"dark"}}]`
+	if err := os.WriteFile(cm.configFile, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+
+	if err := cm.LoadProjects(); err != nil {
+		t.Fatalf("Failed to load legacy projects: %v", err)
+	}
+	if len(cm.projects) != 1 || cm.projects[0].AIModel != ModelGeminiCLI || cm.projects[0].Preferences["theme"] != "dark" {
+		t.Fatalf("Unexpected projects after migration: %+v", cm.projects)
+	}
+
+	// 原文件备份后以当前版本写回
+	backup := cm.MigrationBackup()
+	if !strings.HasPrefix(filepath.Base(backup), "test_projects.json.v1-") {
+		t.Fatalf("Unexpected backup file: %q", backup)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != legacy {
+		t.Errorf("Backup should keep the original content, got %q, %v", data, err)
+	}
+	data, err := os.ReadFile(cm.configFile)
+	if err != nil {
+		t.Fatalf("Failed to read migrated file: %v", err)
+	}
+	var file struct {
+		SchemaVersion int               `json:"schema_version"`
+		Projects      []json.RawMessage `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil || file.SchemaVersion != SchemaVersion || len(file.Projects) != 1 {
+		t.Errorf("Unexpected migrated file: %s", data)
+	}
+
+	// 已是当前版本时不再迁移
+	cm2 := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := cm2.LoadProjects(); err != nil {
+		t.Fatalf("Failed to reload projects: %v", err)
+	}
+	if cm2.MigrationBackup() != "" || len(cm2.projects) != 1 {
+		t.Errorf("Current file should load without migration: %q %+v", cm2.MigrationBackup(), cm2.projects)
+	}
+}
+
+func TestConfigManager_RefusesNewerSchema(t *testing.T) {
+	cm := newTestConfigManager(t)
+	newer := `{"schema_version": 99, "projects": [], "workspaces": []}`
+	if err := os.WriteFile(cm.configFile, []byte(newer), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := cm.LoadProjects(); err == nil || !strings.Contains(err.Error(), "schema_version 99") {
+		t.Fatalf("Expected schema error, got %v", err)
+	}
+	if err := cm.AddProject(ProjectConfig{Name: "web", Path: "/web", AIModel: ModelClaudeCode}); err == nil {
+		t.Error("Saving over a newer file should fail")
+	}
+	if data, _ := os.ReadFile(cm.configFile); string(data) != newer {
+		t.Errorf("Newer file was overwritten: %s", data)
+	}
+}
+
+func TestDecodeProjects(t *testing.T) {
+	for _, data := range []string{`{}`, `{"schema_version": 0}`, `not json`} {
+		if _, _, err := DecodeProjects([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+
+	projects, version, err := DecodeProjects([]byte("  []"))
+	if err != nil || version != 1 || len(projects) != 0 {
+		t.Errorf("Unexpected result for empty legacy file: %v %d %v", projects, version, err)
+	}
+
+	data, err := EncodeProjects(nil)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if !strings.Contains(string(data), `"projects": []`) {
+		t.Errorf("Empty list should encode as an array: %s", data)
+	}
+}
@@ -0,0 +1,96 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxSessionsPerProject 每个项目保留的会话记录上限
+const MaxSessionsPerProject = 20
+
+// SessionRecord AI 工具会话记录，用于恢复之前的会话
+type SessionRecord struct {
+	ID        string      `json:"id"`
+	AIModel   AIModelType `json:"ai_model"`
+	Title     string      `json:"title,omitempty"`
+	StartedAt time.Time   `json:"started_at"`
+	LastUsed  time.Time   `json:"last_used"`
+}
+
+// RecordSession 记录项目的一次会话；相同 ID 的记录会被更新
+func (cm *ConfigManager) RecordSession(path string, session SessionRecord) error {
+	return cm.update(func(projects []ProjectConfig) ([]ProjectConfig, error) {
+		for i, p := range projects {
+			if p.Path != path {
+				continue
+			}
+
+			now := time.Now()
+			if session.StartedAt.IsZero() {
+				session.StartedAt = now
+			}
+			session.LastUsed = now
+
+			sessions := make([]SessionRecord, 0, len(p.Sessions)+1)
+			for _, s := range p.Sessions {
+				if s.ID == session.ID {
+					// 保留原始开始时间与标题
+					session.StartedAt = s.StartedAt
+					if session.Title == "" {
+						session.Title = s.Title
+					}
+					continue
+				}
+				sessions = append(sessions, s)
+			}
+			sessions = append(sessions, session)
+
+			// 超出上限时丢弃最久未使用的记录
+			sortSessions(sessions)
+			if len(sessions) > MaxSessionsPerProject {
+				sessions = sessions[:MaxSessionsPerProject]
+			}
+
+			projects[i].Sessions = sessions
+			return projects, nil
+		}
+		return nil, fmt.Errorf("项目不存在: %s", path)
+	})
+}
+
+// GetSessions 获取项目的会话记录（最近使用的在前）；model 为空时返回全部
+func (cm *ConfigManager) GetSessions(path string, model AIModelType) ([]SessionRecord, error) {
+	p, err := cm.GetProjectByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]SessionRecord, 0, len(p.Sessions))
+	for _, s := range p.Sessions {
+		if model == "" || s.AIModel == model {
+			sessions = append(sessions, s)
+		}
+	}
+	sortSessions(sessions)
+	return sessions, nil
+}
+
+// LastSession 获取项目在指定模型下最近使用的会话
+func (cm *ConfigManager) LastSession(path string, model AIModelType) (*SessionRecord, error) {
+	sessions, err := cm.GetSessions(path, model)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("没有可恢复的会话: %s", path)
+	}
+	return &sessions[0], nil
+}
+
+// sortSessions 按最后使用时间从新到旧排序
+func sortSessions(sessions []SessionRecord) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastUsed.After(sessions[j].LastUsed)
+	})
+}
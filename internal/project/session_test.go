@@ -0,0 +1,104 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestConfigManager(t *testing.T) *ConfigManager {
+	tempDir := t.TempDir()
+	return &ConfigManager{
+		configDir:  tempDir,
+		configFile: filepath.Join(tempDir, "test_projects.json"),
+		projects:   make([]ProjectConfig, 0),
+	}
+}
+
+func TestConfigManager_RecordSession(t *testing.T) {
+	cm := newTestConfigManager(t)
+	if err := cm.AddProject(ProjectConfig{Name: "demo", Path: "/demo", AIModel: ModelClaudeCode}); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+
+	if err := cm.RecordSession("/demo", SessionRecord{ID: "s1", AIModel: ModelClaudeCode, Title: "first"}); err != nil {
+		t.Fatalf("Failed to record session: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := cm.RecordSession("/demo", SessionRecord{ID: "s2", AIModel: ModelCodex}); err != nil {
+		t.Fatalf("Failed to record session: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// 再次使用 s1：更新最后使用时间，保留标题
+	if err := cm.RecordSession("/demo", SessionRecord{ID: "s1", AIModel: ModelClaudeCode}); err != nil {
+		t.Fatalf("Failed to record session: %v", err)
+	}
+
+	sessions, err := cm.GetSessions("/demo", "")
+	if err != nil {
+		t.Fatalf("Failed to get sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].ID != "s1" || sessions[0].Title != "first" {
+		t.Errorf("Expected s1 with title first, got %+v", sessions[0])
+	}
+
+	last, err := cm.LastSession("/demo", ModelCodex)
+	if err != nil {
+		t.Fatalf("Failed to get last session: %v", err)
+	}
+	if last.ID != "s2" {
+		t.Errorf("Expected s2, got %s", last.ID)
+	}
+
+	if _, err := cm.LastSession("/demo", ModelGeminiCLI); err == nil {
+		t.Error("Expected error when no session exists")
+	}
+
+	if err := cm.RecordSession("/missing", SessionRecord{ID: "x"}); err == nil {
+		t.Error("Expected error for missing project")
+	}
+}
+
+func TestConfigManager_RecordSessionLimit(t *testing.T) {
+	cm := newTestConfigManager(t)
+	cm.AddProject(ProjectConfig{Name: "demo", Path: "/demo"})
+
+	for i := 0; i < MaxSessionsPerProject+5; i++ {
+		if err := cm.RecordSession("/demo", SessionRecord{ID: fmt.Sprintf("s%d", i), AIModel: ModelClaudeCode}); err != nil {
+			t.Fatalf("Failed to record session: %v", err)
+		}
+	}
+
+	sessions, _ := cm.GetSessions("/demo", ModelClaudeCode)
+	if len(sessions) != MaxSessionsPerProject {
+		t.Errorf("Expected %d sessions, got %d", MaxSessionsPerProject, len(sessions))
+	}
+}
+
+func TestConfigManager_SessionsPersistAcrossUpdates(t *testing.T) {
+	cm := newTestConfigManager(t)
+	cm.AddProject(ProjectConfig{Name: "demo", Path: "/demo"})
+	cm.RecordSession("/demo", SessionRecord{ID: "s1", AIModel: ModelClaudeCode})
+
+	// 项目对话框更新配置时不应丢失会话记录
+	if err := cm.AddProject(ProjectConfig{Name: "renamed", Path: "/demo", AIModel: ModelGeminiCLI}); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+
+	reloaded := &ConfigManager{configDir: cm.configDir, configFile: cm.configFile}
+	if err := reloaded.LoadProjects(); err != nil {
+		t.Fatalf("Failed to load projects: %v", err)
+	}
+	sessions, err := reloaded.GetSessions("/demo", "")
+	if err != nil {
+		t.Fatalf("Failed to get sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "s1" {
+		t.Errorf("Expected session s1 to persist, got %+v", sessions)
+	}
+}
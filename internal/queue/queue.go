@@ -0,0 +1,590 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/memory"
+	"ai-launcher/internal/project"
+	addpsync "ai-launcher/internal/sync"
+	"ai-launcher/internal/terminal"
+	"ai-launcher/internal/workflow"
+)
+
+// cancelReason 本地任務被中止的原因
+type cancelReason int
+
+const (
+	reasonNone     cancelReason = iota
+	reasonCancel                // 用戶取消
+	reasonShutdown              // 工作池退出，任務重新排隊
+)
+
+// runningJob 本進程正在執行的任務
+type runningJob struct {
+	terminal string
+	cancel   context.CancelFunc
+	reason   cancelReason
+}
+
+// Queue 基於 TerminalManager 的持久化任務隊列
+type Queue struct {
+	store     *store
+	logDir    string
+	terminals *terminal.TerminalManager
+	worker    string
+	trusted   gates.TrustFunc
+
+	maxConcurrent int
+	pollInterval  time.Duration
+	retryDelay    time.Duration
+
+	mu      sync.Mutex
+	running map[string]*runningJob
+	wg      sync.WaitGroup
+	wake    chan struct{}
+}
+
+// NewQueue 創建使用 ~/.ai-launcher/queue 的任務隊列
+func NewQueue(tm *terminal.TerminalManager) *Queue {
+	homeDir, _ := os.UserHomeDir()
+	return NewQueueWithDir(filepath.Join(homeDir, ".ai-launcher", "queue"), tm)
+}
+
+// NewQueueWithDir 創建使用指定目錄的任務隊列
+func NewQueueWithDir(dir string, tm *terminal.TerminalManager) *Queue {
+	hostname, _ := os.Hostname()
+	return &Queue{
+		store:         &store{path: filepath.Join(dir, "queue.json")},
+		logDir:        filepath.Join(dir, "logs"),
+		terminals:     tm,
+		worker:        fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		trusted:       project.NewConfigManager().ManifestTrusted,
+		maxConcurrent: DefaultMaxConcurrent,
+		pollInterval:  DefaultPollInterval,
+		retryDelay:    DefaultRetryDelay,
+		running:       make(map[string]*runningJob),
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// SetMaxConcurrent 設置同時運行的終端上限（包括非隊列啟動的終端）
+func (q *Queue) SetMaxConcurrent(n int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.maxConcurrent = n
+}
+
+// SetPollInterval 設置工作池檢查隊列的間隔
+func (q *Queue) SetPollInterval(d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pollInterval = d
+}
+
+// SetRetryDelay 設置首次重試的等待時間
+func (q *Queue) SetRetryDelay(d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retryDelay = d
+}
+
+// SetManifestTrust 設置判斷項目清單是否已批准的函數；未批准的清單門禁不會在任務結束後運行
+func (q *Queue) SetManifestTrust(trusted gates.TrustFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.trusted = trusted
+}
+
+// Add 將任務加入隊列並返回保存後的任務
+func (q *Queue) Add(job Job) (*Job, error) {
+	if job.Project == "" {
+		return nil, fmt.Errorf("job project is required")
+	}
+	if strings.TrimSpace(job.Prompt) == "" {
+		return nil, fmt.Errorf("job prompt is required")
+	}
+	if job.Tool == "" {
+		job.Tool = project.ModelClaudeCode
+	}
+	if _, err := commandFor(job); err != nil {
+		return nil, err
+	}
+
+	job.ID = newJobID()
+	job.Status = JobQueued
+	job.Attempts = 0
+	job.CreatedAt = time.Now()
+
+	err := q.store.update(func(jobs []*Job) ([]*Job, error) {
+		return append(jobs, &job), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	q.notify()
+	return &job, nil
+}
+
+// List 返回全部任務：運行中在前，其次按執行順序排列的等待任務，最後是最近結束的任務
+func (q *Queue) List() ([]*Job, error) {
+	jobs, err := q.store.load()
+	if err != nil {
+		return nil, err
+	}
+
+	rank := func(j *Job) int {
+		switch j.Status {
+		case JobRunning:
+			return 0
+		case JobQueued:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(jobs, func(a, b int) bool {
+		ja, jb := jobs[a], jobs[b]
+		if rank(ja) != rank(jb) {
+			return rank(ja) < rank(jb)
+		}
+		if ja.Status.IsFinal() {
+			return ja.FinishedAt.After(jb.FinishedAt)
+		}
+		return ja.before(jb)
+	})
+	return jobs, nil
+}
+
+// Get 按 ID（或唯一前綴）查找任務
+func (q *Queue) Get(id string) (*Job, error) {
+	jobs, err := q.store.load()
+	if err != nil {
+		return nil, err
+	}
+	job, err := findJob(jobs, id)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Cancel 取消任務：等待中的任務立即取消，運行中的任務會被終止
+func (q *Queue) Cancel(id string) (*Job, error) {
+	var cancelled Job
+	err := q.store.update(func(jobs []*Job) ([]*Job, error) {
+		job, err := findJob(jobs, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status.IsFinal() {
+			return nil, fmt.Errorf("job %s already %s", job.ID, job.Status)
+		}
+
+		job.CancelRequested = true
+		if job.Status == JobQueued {
+			job.Status = JobCancelled
+			job.FinishedAt = time.Now()
+		}
+		cancelled = *job
+		return jobs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 由本進程運行時立即終止，其他進程會在下次檢查時處理
+	q.abort(cancelled.ID, reasonCancel)
+	return &cancelled, nil
+}
+
+// Run 啟動工作池並阻塞直到 ctx 結束；運行中的任務會被重新排隊
+func (q *Queue) Run(ctx context.Context) error {
+	if err := q.tick(ctx); err != nil {
+		return err
+	}
+
+	for {
+		q.mu.Lock()
+		interval := q.pollInterval
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			ids := make([]string, 0, len(q.running))
+			for id := range q.running {
+				ids = append(ids, id)
+			}
+			q.mu.Unlock()
+			for _, id := range ids {
+				q.abort(id, reasonShutdown)
+			}
+			q.wg.Wait()
+			return nil
+		case <-q.wake:
+		case <-time.After(interval):
+		}
+
+		// 單次檢查失敗（如鎖超時）不中止工作池
+		_ = q.tick(ctx)
+	}
+}
+
+// tick 更新心跳、回收中斷的任務並按可用名額領取新任務
+func (q *Queue) tick(ctx context.Context) error {
+	now := time.Now()
+
+	q.mu.Lock()
+	slots := q.maxConcurrent - len(q.running) - q.otherTerminals()
+	owned := make(map[string]bool, len(q.running))
+	for id := range q.running {
+		owned[id] = true
+	}
+	q.mu.Unlock()
+
+	var claimed []Job
+	var toCancel []string
+	err := q.store.update(func(jobs []*Job) ([]*Job, error) {
+		var candidates []*Job
+		for _, job := range jobs {
+			switch {
+			case job.Status == JobRunning && owned[job.ID]:
+				job.HeartbeatAt = now
+				if job.CancelRequested {
+					toCancel = append(toCancel, job.ID)
+				}
+			case job.Status == JobRunning && now.Sub(job.HeartbeatAt) > staleAfter:
+				// 執行該任務的進程已退出，重新排隊
+				job.Status = JobQueued
+				job.Worker = ""
+				job.Error = "worker stopped responding"
+				if job.CancelRequested {
+					job.Status = JobCancelled
+					job.FinishedAt = now
+				}
+			}
+			if job.runnable(now) {
+				candidates = append(candidates, job)
+			}
+		}
+
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].before(candidates[b])
+		})
+		for i := 0; i < len(candidates) && i < slots; i++ {
+			job := candidates[i]
+			job.Status = JobRunning
+			job.Attempts++
+			job.Worker = q.worker
+			job.Error = ""
+			job.StartedAt = now
+			job.HeartbeatAt = now
+			claimed = append(claimed, *job)
+		}
+		return jobs, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range toCancel {
+		q.abort(id, reasonCancel)
+	}
+	for _, job := range claimed {
+		q.start(ctx, job)
+	}
+	return nil
+}
+
+// otherTerminals 統計不屬於本隊列的運行中終端（調用方需持有 q.mu）
+func (q *Queue) otherTerminals() int {
+	if q.terminals == nil {
+		return 0
+	}
+	ours := make(map[string]bool, len(q.running))
+	for _, r := range q.running {
+		ours[r.terminal] = true
+	}
+
+	count := 0
+	for _, t := range q.terminals.ListTerminals() {
+		if t.IsRunning() && !t.Exited() && !ours[t.Name] {
+			count++
+		}
+	}
+	return count
+}
+
+// start 在後台執行已領取的任務
+func (q *Queue) start(ctx context.Context, job Job) {
+	// 工作池退出時由 abort 標記原因後再終止任務，避免誤判為執行失敗
+	jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), job.Options.Timeout())
+	running := &runningJob{
+		terminal: fmt.Sprintf("job-%s-%d", job.ID, job.Attempts),
+		cancel:   cancel,
+	}
+
+	q.mu.Lock()
+	q.running[job.ID] = running
+	q.mu.Unlock()
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		defer cancel()
+
+		log := q.openLog(job)
+		exitCode, output, err := q.execute(jobCtx, job, running.terminal, log)
+		logPath := log.close(err)
+
+		q.mu.Lock()
+		reason := running.reason
+		delete(q.running, job.ID)
+		q.mu.Unlock()
+
+		q.finish(job, reason, exitCode, output, logPath, err)
+		q.notify()
+	}()
+}
+
+// execute 通過 TerminalManager 運行任務並等待結束；完整輸出逐行寫入 log，返回的輸出只包括終端保留的最近行
+func (q *Queue) execute(ctx context.Context, job Job, name string, log *jobLog) (int, []string, error) {
+	// ADDP 項目中帶上當前階段的提示詞、其他工具留下的交接摘要與相關的項目記憶，結束時留下本次的摘要
+	task := job.Prompt
+	job.Prompt = workflow.InjectPrompt(job.Project, addpsync.InjectPrompt(job.Project, memory.InjectPrompt(job.Project, job.Prompt)))
+	args, err := commandFor(job)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	config := terminal.TerminalConfig{
+		Type:        job.Tool.TerminalType(),
+		Name:        name,
+		WorkingDir:  job.Project,
+		Environment: job.Options.Environment,
+		Command:     args,
+		YoloMode:    job.Options.YoloMode,
+		OnOutput:    log.writeLine,
+	}
+	addpsync.CaptureOnExit(&config, job.Project, task)
+
+	err = q.terminals.StartTerminalWithContext(ctx, config)
+	if err != nil {
+		return -1, nil, err
+	}
+	defer q.terminals.RemoveTerminal(name)
+
+	term, _ := q.terminals.GetTerminal(name)
+	// 非交互命令不需要輸入，關閉 stdin 避免工具等待
+	_ = q.terminals.CloseInput(name)
+
+	select {
+	case <-term.Done():
+	case <-ctx.Done():
+		_ = q.terminals.StopTerminal(name)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return -1, term.Output(), fmt.Errorf("job timed out after %s", job.Options.Timeout())
+		}
+		return -1, term.Output(), ctx.Err()
+	}
+
+	exitCode := term.ExitCode()
+	output := term.Output()
+	if err := term.OutputErr(); err != nil {
+		log.writeLine(fmt.Sprintf("[output truncated: %v]", err))
+	}
+	q.mu.Lock()
+	trusted := q.trusted
+	q.mu.Unlock()
+	gateLines := runGates(ctx, job.Project, name, trusted)
+	for _, line := range gateLines {
+		log.writeLine(line)
+	}
+	output = append(output, gateLines...)
+	if exitCode != 0 {
+		return exitCode, output, fmt.Errorf("exit status %d", exitCode)
+	}
+	return 0, output, nil
+}
+
+// runGates 任務結束後運行項目的質量門禁，返回追加到任務輸出的結果；門禁結果不影響任務狀態
+func runGates(ctx context.Context, projectPath, session string, trusted gates.TrustFunc) []string {
+	cfg, err := gates.LoadConfig(projectPath, trusted)
+	if err != nil {
+		return []string{"", "[gates] " + err.Error()}
+	}
+	if !cfg.AutoRun(projectPath) || !cfg.RunsOn(gates.TriggerExit) {
+		return nil
+	}
+
+	report, err := gates.RunAndSave(ctx, projectPath, cfg, gates.TriggerExit, session)
+	lines := append([]string{""}, strings.Split("[gates] "+report.Details(), "\n")...)
+	if err != nil && !errors.Is(err, gates.ErrNotInitialized) {
+		lines = append(lines, "[gates] "+err.Error())
+	}
+	return lines
+}
+
+// finish 保存任務結果，失敗時按配置重新排隊
+func (q *Queue) finish(job Job, reason cancelReason, exitCode int, output []string, logPath string, runErr error) {
+	q.mu.Lock()
+	retryDelay := q.retryDelay
+	q.mu.Unlock()
+
+	_ = q.store.update(func(jobs []*Job) ([]*Job, error) {
+		stored, err := findJob(jobs, job.ID)
+		if err != nil {
+			// 任務已被刪除
+			return jobs, nil
+		}
+
+		now := time.Now()
+		stored.ExitCode = exitCode
+		stored.Output = tail(output, maxOutputLines)
+		stored.LogPath = logPath
+		stored.Worker = ""
+		stored.Error = ""
+		if runErr != nil {
+			stored.Error = runErr.Error()
+		}
+
+		switch {
+		case reason == reasonShutdown && !stored.CancelRequested:
+			// 工作池退出不計入重試次數
+			stored.Status = JobQueued
+			stored.Attempts--
+		case reason == reasonCancel || stored.CancelRequested:
+			stored.Status = JobCancelled
+			stored.FinishedAt = now
+		case runErr == nil:
+			stored.Status = JobSucceeded
+			stored.FinishedAt = now
+		case stored.Attempts <= stored.Options.MaxRetries:
+			stored.Status = JobQueued
+			stored.NextRunAt = now.Add(retryDelay * time.Duration(stored.Attempts))
+		default:
+			stored.Status = JobFailed
+			stored.FinishedAt = now
+		}
+		return jobs, nil
+	})
+}
+
+// abort 終止本進程正在運行的任務
+func (q *Queue) abort(id string, reason cancelReason) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if running, ok := q.running[id]; ok {
+		running.reason = reason
+		running.cancel()
+	}
+}
+
+// notify 喚醒工作池立即檢查隊列
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// jobLog 任務日誌；終端只保留最近的輸出行，完整輸出在運行時逐行追加
+type jobLog struct {
+	mu   sync.Mutex
+	f    *os.File
+	path string
+}
+
+// openLog 打開任務日誌並寫入本次嘗試的標題；無法打開時返回的日誌忽略寫入
+func (q *Queue) openLog(job Job) *jobLog {
+	log := &jobLog{}
+	if err := os.MkdirAll(q.logDir, 0755); err != nil {
+		return log
+	}
+	path := filepath.Join(q.logDir, job.ID+".log")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return log
+	}
+	log.f, log.path = f, path
+	fmt.Fprintf(f, "=== attempt %d (%s) ===\n", job.Attempts, time.Now().Format(time.RFC3339))
+	return log
+}
+
+// writeLine 追加一行輸出，可在終端的輸出協程中調用
+func (l *jobLog) writeLine(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f != nil {
+		fmt.Fprintln(l.f, line)
+	}
+}
+
+// close 寫入本次嘗試的錯誤並關閉日誌，返回日誌路徑
+func (l *jobLog) close(runErr error) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return ""
+	}
+	if runErr != nil {
+		fmt.Fprintf(l.f, "error: %v\n", runErr)
+	}
+	l.f.Close()
+	l.f = nil
+	return l.path
+}
+
+// commandFor 生成任務的非交互命令
+func commandFor(job Job) ([]string, error) {
+	if len(job.Options.Command) > 0 {
+		return append(append([]string(nil), job.Options.Command...), job.Prompt), nil
+	}
+	return terminal.HeadlessCommand(job.Tool.TerminalType(), job.Prompt, job.Options.YoloMode)
+}
+
+// findJob 按完整 ID 或唯一前綴查找任務
+func findJob(jobs []*Job, id string) (*Job, error) {
+	var match *Job
+	for _, job := range jobs {
+		if job.ID == id {
+			return job, nil
+		}
+		if id != "" && strings.HasPrefix(job.ID, id) {
+			if match != nil {
+				return nil, fmt.Errorf("job id %q is ambiguous", id)
+			}
+			match = job
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("job %q not found", id)
+	}
+	return match, nil
+}
+
+// newJobID 生成 12 位十六進制任務 ID
+func newJobID() string {
+	var b [6]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// tail 返回最後 n 行
+func tail(lines []string, n int) []string {
+	if len(lines) > n {
+		return append([]string(nil), lines[len(lines)-n:]...)
+	}
+	return lines
+}
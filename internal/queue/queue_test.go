@@ -0,0 +1,314 @@
+package queue
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/gates"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/terminal"
+)
+
+// shellJob 創建以 sh 執行腳本的任務，提示詞作為 $1 傳入
+func shellJob(dir, script, prompt string) Job {
+	return Job{
+		Project: dir,
+		Tool:    project.ModelClaudeCode,
+		Prompt:  prompt,
+		Options: JobOptions{Command: []string{"sh", "-c", script, "sh"}},
+	}
+}
+
+func newTestQueue(t *testing.T) (*Queue, *terminal.TerminalManager) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	tm := terminal.NewTerminalManager()
+	q := NewQueueWithDir(t.TempDir(), tm)
+	q.SetPollInterval(20 * time.Millisecond)
+	q.SetRetryDelay(10 * time.Millisecond)
+	return q, tm
+}
+
+// runQueue 在後台運行工作池，返回停止函數
+func runQueue(t *testing.T, q *Queue) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, q.Run(ctx))
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// waitForStatus 等待任務進入指定狀態
+func waitForStatus(t *testing.T, q *Queue, id string, status JobStatus) *Job {
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = q.Get(id)
+		return err == nil && job.Status == status
+	}, 5*time.Second, 10*time.Millisecond, "job %s never reached %s", id, status)
+	return job
+}
+
+func TestQueue_AddValidation(t *testing.T) {
+	q := NewQueueWithDir(t.TempDir(), terminal.NewTerminalManager())
+
+	_, err := q.Add(Job{Prompt: "hi"})
+	assert.Error(t, err)
+
+	_, err = q.Add(Job{Project: "/tmp", Prompt: "  "})
+	assert.Error(t, err)
+
+	_, err = q.Add(Job{Project: "/tmp", Prompt: "hi", Tool: project.ModelCustom})
+	assert.Error(t, err)
+
+	job, err := q.Add(Job{Project: "/tmp", Prompt: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, project.ModelClaudeCode, job.Tool)
+	assert.Equal(t, JobQueued, job.Status)
+	assert.Len(t, job.ID, 12)
+}
+
+func TestQueue_RunsByPriority(t *testing.T) {
+	q, _ := newTestQueue(t)
+	q.SetMaxConcurrent(1)
+	dir := t.TempDir()
+
+	script := `echo "$1" >> order.txt; echo "done $1"`
+	low, err := q.Add(shellJob(dir, script, "low"))
+	require.NoError(t, err)
+	highJob := shellJob(dir, script, "high")
+	highJob.Priority = 10
+	high, err := q.Add(highJob)
+	require.NoError(t, err)
+
+	stop := runQueue(t, q)
+	defer stop()
+
+	waitForStatus(t, q, low.ID, JobSucceeded)
+	job := waitForStatus(t, q, high.ID, JobSucceeded)
+
+	data, err := os.ReadFile(filepath.Join(dir, "order.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "high\nlow\n", string(data))
+
+	assert.Equal(t, []string{"done high"}, job.Output)
+	assert.Equal(t, 1, job.Attempts)
+	logData, err := os.ReadFile(job.LogPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "done high")
+}
+
+func TestQueue_LogKeepsFullOutput(t *testing.T) {
+	q, _ := newTestQueue(t)
+
+	// 終端只保留最近的輸出行，日誌需要完整保存
+	added, err := q.Add(shellJob(t.TempDir(), `seq 1 3000`, "x"))
+	require.NoError(t, err)
+
+	stop := runQueue(t, q)
+	defer stop()
+
+	job := waitForStatus(t, q, added.ID, JobSucceeded)
+	logData, err := os.ReadFile(job.LogPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 3001)
+	assert.Equal(t, "1", lines[1])
+	assert.Equal(t, "3000", lines[3000])
+}
+
+func TestQueue_Retries(t *testing.T) {
+	q, _ := newTestQueue(t)
+
+	job := shellJob(t.TempDir(), `echo "try"; exit 2`, "x")
+	job.Options.MaxRetries = 2
+	added, err := q.Add(job)
+	require.NoError(t, err)
+
+	stop := runQueue(t, q)
+	defer stop()
+
+	failed := waitForStatus(t, q, added.ID, JobFailed)
+	assert.Equal(t, 3, failed.Attempts)
+	assert.Equal(t, 2, failed.ExitCode)
+	assert.Equal(t, "exit status 2", failed.Error)
+
+	logData, err := os.ReadFile(failed.LogPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(logData), "=== attempt"))
+}
+
+func TestQueue_RunsGatesAfterJob(t *testing.T) {
+	q, _ := newTestQueue(t)
+	q.SetManifestTrust(func(string) bool { return true })
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ai-launcher"), 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".addp"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ai-launcher", "gates.yaml"), []byte("gates:\n  - name: check\n    command: echo broken; exit 1\n"), 0644))
+
+	added, err := q.Add(shellJob(dir, `echo done`, "x"))
+	require.NoError(t, err)
+
+	stop := runQueue(t, q)
+	defer stop()
+
+	// 門禁失敗只記錄在輸出中，不影響任務狀態
+	job := waitForStatus(t, q, added.ID, JobSucceeded)
+	assert.Contains(t, job.Output, "[gates] gates failed: check (0/1 passed)")
+	assert.Contains(t, job.Output, "    broken")
+
+	report, err := gates.Latest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, gates.TriggerExit, report.Trigger)
+	assert.False(t, report.Passed)
+}
+
+func TestQueue_SkipsUntrustedManifestGates(t *testing.T) {
+	q, _ := newTestQueue(t)
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".addp"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ai-launcher.yaml"), []byte("launch:\n  gates:\n    gates:\n      - name: check\n        command: touch gate-ran\n"), 0644))
+
+	q.SetManifestTrust(func(string) bool { return false })
+
+	stop := runQueue(t, q)
+	defer stop()
+
+	// 未批准的清單門禁不運行
+	added, err := q.Add(shellJob(dir, `echo done`, "x"))
+	require.NoError(t, err)
+	job := waitForStatus(t, q, added.ID, JobSucceeded)
+	assert.Equal(t, []string{"done"}, job.Output)
+	assert.NoFileExists(t, filepath.Join(dir, "gate-ran"))
+
+	q.SetManifestTrust(func(path string) bool { return path == dir })
+	added, err = q.Add(shellJob(dir, `echo done`, "y"))
+	require.NoError(t, err)
+	job = waitForStatus(t, q, added.ID, JobSucceeded)
+	assert.Contains(t, strings.Join(job.Output, "\n"), "[PASS] touch gate-ran")
+	assert.FileExists(t, filepath.Join(dir, "gate-ran"))
+}
+
+func TestQueue_Cancel(t *testing.T) {
+	q, tm := newTestQueue(t)
+	q.SetMaxConcurrent(1)
+	dir := t.TempDir()
+
+	running, err := q.Add(shellJob(dir, `exec sleep 10`, "x"))
+	require.NoError(t, err)
+	queued, err := q.Add(shellJob(dir, `echo never`, "y"))
+	require.NoError(t, err)
+
+	stop := runQueue(t, q)
+	defer stop()
+	waitForStatus(t, q, running.ID, JobRunning)
+
+	// 等待中的任務立即取消
+	cancelled, err := q.Cancel(queued.ID[:6])
+	require.NoError(t, err)
+	assert.Equal(t, JobCancelled, cancelled.Status)
+
+	// 運行中的任務被終止
+	_, err = q.Cancel(running.ID)
+	require.NoError(t, err)
+	waitForStatus(t, q, running.ID, JobCancelled)
+	assert.Eventually(t, func() bool { return len(tm.ListTerminals()) == 0 }, 2*time.Second, 10*time.Millisecond)
+
+	_, err = q.Cancel(running.ID)
+	assert.Error(t, err)
+	_, err = q.Cancel("missing")
+	assert.Error(t, err)
+}
+
+func TestQueue_ShutdownRequeues(t *testing.T) {
+	q, _ := newTestQueue(t)
+
+	added, err := q.Add(shellJob(t.TempDir(), `exec sleep 10`, "x"))
+	require.NoError(t, err)
+
+	stop := runQueue(t, q)
+	waitForStatus(t, q, added.ID, JobRunning)
+	stop()
+
+	job, err := q.Get(added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobQueued, job.Status)
+	assert.Equal(t, 0, job.Attempts)
+}
+
+func TestQueue_RecoversStaleJobs(t *testing.T) {
+	q, _ := newTestQueue(t)
+
+	added, err := q.Add(shellJob(t.TempDir(), `echo recovered`, "x"))
+	require.NoError(t, err)
+
+	// 模擬其他進程領取後崩潰
+	require.NoError(t, q.store.update(func(jobs []*Job) ([]*Job, error) {
+		jobs[0].Status = JobRunning
+		jobs[0].Attempts = 1
+		jobs[0].Worker = "other:1"
+		jobs[0].HeartbeatAt = time.Now().Add(-2 * staleAfter)
+		return jobs, nil
+	}))
+
+	// 新的隊列實例從磁盤恢復狀態
+	restarted := NewQueueWithDir(filepath.Dir(q.store.path), terminal.NewTerminalManager())
+	restarted.SetPollInterval(20 * time.Millisecond)
+	stop := runQueue(t, restarted)
+	defer stop()
+
+	job := waitForStatus(t, restarted, added.ID, JobSucceeded)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, []string{"recovered"}, job.Output)
+}
+
+func TestQueue_RespectsRunningTerminals(t *testing.T) {
+	q, tm := newTestQueue(t)
+	q.SetMaxConcurrent(1)
+
+	// 已有一個交互終端佔用唯一名額
+	require.NoError(t, tm.StartTerminal(terminal.TerminalConfig{Type: terminal.TypeCustom, Name: "interactive", Command: []string{"cat"}}))
+
+	added, err := q.Add(shellJob(t.TempDir(), `echo hi`, "x"))
+	require.NoError(t, err)
+	require.NoError(t, q.tick(context.Background()))
+
+	job, err := q.Get(added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobQueued, job.Status)
+
+	require.NoError(t, tm.RemoveTerminal("interactive"))
+	stop := runQueue(t, q)
+	defer stop()
+	waitForStatus(t, q, added.ID, JobSucceeded)
+}
+
+func TestQueue_ListOrder(t *testing.T) {
+	q := NewQueueWithDir(t.TempDir(), terminal.NewTerminalManager())
+
+	a, _ := q.Add(Job{Project: "/a", Prompt: "a"})
+	b, _ := q.Add(Job{Project: "/b", Prompt: "b", Priority: 5})
+	c, _ := q.Add(Job{Project: "/c", Prompt: "c"})
+	_, err := q.Cancel(c.ID)
+	require.NoError(t, err)
+
+	jobs, err := q.List()
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+	assert.Equal(t, []string{b.ID, a.ID, c.ID}, []string{jobs[0].ID, jobs[1].ID, jobs[2].ID})
+}
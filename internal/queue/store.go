@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"ai-launcher/internal/fsutil"
+)
+
+// store 以 JSON 文件保存隊列狀態，跨進程讀寫通過鎖文件串行化
+type store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// load 讀取全部任務
+func (s *store) load() ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []*Job
+	err := fsutil.WithLock(s.path, func() error {
+		var err error
+		jobs, err = s.read()
+		return err
+	})
+	return jobs, err
+}
+
+// update 在鎖內讀取、修改並原子地寫回任務列表
+func (s *store) update(fn func(jobs []*Job) ([]*Job, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fsutil.WithLock(s.path, func() error {
+		jobs, err := s.read()
+		if err != nil {
+			return err
+		}
+		jobs, err = fn(jobs)
+		if err != nil {
+			return err
+		}
+		return s.write(jobs)
+	})
+}
+
+func (s *store) read() ([]*Job, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return []*Job{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	jobs := []*Job{}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse queue: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s *store) write(jobs []*Job) error {
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode queue: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data, 0600)
+}
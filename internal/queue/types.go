@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"time"
+
+	"ai-launcher/internal/project"
+)
+
+const (
+	DefaultMaxConcurrent = 5                // 與 performance.max_concurrent_terminals 默認值一致
+	DefaultPollInterval  = 2 * time.Second  // 工作池檢查隊列的間隔
+	DefaultRetryDelay    = 30 * time.Second // 首次重試的等待時間，之後按次數遞增
+	DefaultTimeout       = 30 * time.Minute // 單個任務的默認超時
+	staleAfter           = time.Minute      // 運行中任務超過此時間沒有心跳即視為中斷
+	maxOutputLines       = 200              // 任務記錄中保留的輸出行數
+)
+
+// JobStatus 任務狀態
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"    // 等待執行（包括等待重試）
+	JobRunning   JobStatus = "running"   // 正在執行
+	JobSucceeded JobStatus = "succeeded" // 執行成功
+	JobFailed    JobStatus = "failed"    // 重試耗盡後仍失敗
+	JobCancelled JobStatus = "cancelled" // 已取消
+)
+
+// IsFinal 檢查狀態是否為終態
+func (s JobStatus) IsFinal() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// JobOptions 任務執行選項
+type JobOptions struct {
+	YoloMode       bool              `json:"yolo_mode,omitempty"`
+	MaxRetries     int               `json:"max_retries,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+	Environment    map[string]string `json:"environment,omitempty"`
+	Command        []string          `json:"command,omitempty"` // 自定義命令，提示詞作為最後一個參數追加
+}
+
+// Timeout 返回任務超時，未設置時使用默認值
+func (o JobOptions) Timeout() time.Duration {
+	if o.TimeoutSeconds > 0 {
+		return time.Duration(o.TimeoutSeconds) * time.Second
+	}
+	return DefaultTimeout
+}
+
+// Job 排隊執行的 AI 任務
+type Job struct {
+	ID       string              `json:"id"`
+	Project  string              `json:"project"`
+	Tool     project.AIModelType `json:"tool"`
+	Prompt   string              `json:"prompt"`
+	Options  JobOptions          `json:"options"`
+	Priority int                 `json:"priority"` // 數值越大越先執行
+
+	Status          JobStatus `json:"status"`
+	Attempts        int       `json:"attempts"`
+	CancelRequested bool      `json:"cancel_requested,omitempty"`
+	ExitCode        int       `json:"exit_code"`
+	Error           string    `json:"error,omitempty"`
+	Output          []string  `json:"output,omitempty"`
+	LogPath         string    `json:"log_path,omitempty"`
+	Worker          string    `json:"worker,omitempty"`
+
+	CreatedAt   time.Time `json:"created_at"`
+	NextRunAt   time.Time `json:"next_run_at,omitempty"`
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	HeartbeatAt time.Time `json:"heartbeat_at,omitempty"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+}
+
+// runnable 檢查任務當前是否可以被領取
+func (j *Job) runnable(now time.Time) bool {
+	return j.Status == JobQueued && !j.CancelRequested && !j.NextRunAt.After(now)
+}
+
+// before 按優先級（高在前）與創建時間（早在前）排序
+func (j *Job) before(other *Job) bool {
+	if j.Priority != other.Priority {
+		return j.Priority > other.Priority
+	}
+	return j.CreatedAt.Before(other.CreatedAt)
+}
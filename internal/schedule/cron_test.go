@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	require.NoError(t, err)
+	return v
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+	} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	tests := []struct {
+		spec     string
+		after    string
+		expected string
+	}{
+		{"* * * * *", "2024-03-01 10:15", "2024-03-01 10:16"},
+		{"0 7 * * 1-5", "2024-03-01 07:00", "2024-03-04 07:00"}, // 週五之後是週一
+		{"0 7 * * mon-fri", "2024-03-04 06:59", "2024-03-04 07:00"},
+		{"*/15 * * * *", "2024-03-01 10:16", "2024-03-01 10:30"},
+		{"5/20 * * * *", "2024-03-01 10:26", "2024-03-01 10:45"},
+		{"0 0 1 * *", "2024-01-31 12:00", "2024-02-01 00:00"},
+		{"0 12 29 2 *", "2024-03-01 00:00", "2028-02-29 12:00"},
+		{"30 9 1,15 * *", "2024-03-02 00:00", "2024-03-15 09:30"},
+		{"0 0 * * 7", "2024-03-01 00:00", "2024-03-03 00:00"},  // 7 表示星期日
+		{"0 0 13 * 5", "2024-09-01 00:00", "2024-09-06 00:00"}, // 日與星期取並集
+		{"@daily", "2024-12-31 23:59", "2025-01-01 00:00"},
+		{"0 9 * jan,jul *", "2024-02-01 00:00", "2024-07-01 09:00"},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseCron(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, mustTime(t, tt.expected), c.Next(mustTime(t, tt.after)), tt.spec)
+	}
+}
+
+func TestCron_NextImpossible(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, c.Next(mustTime(t, "2024-01-01 00:00")).IsZero())
+}
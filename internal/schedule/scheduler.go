@@ -0,0 +1,547 @@
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-launcher/internal/fsutil"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+	"ai-launcher/internal/template"
+)
+
+// Scheduler 管理 ~/.ai-launcher/schedules.json 中的計劃，到期時將任務投遞到任務隊列，
+// 由隊列通過 TerminalManager 啟動並記錄輸出
+type Scheduler struct {
+	path        string
+	historyPath string
+	queue       *queue.Queue
+	templates   *template.TemplateManager
+
+	pollInterval time.Duration
+	now          func() time.Time
+	mu           sync.Mutex
+}
+
+// NewScheduler 創建使用 ~/.ai-launcher 的計劃器；q 為 nil 時只能管理計劃而不能觸發
+func NewScheduler(q *queue.Queue, templates *template.TemplateManager) *Scheduler {
+	homeDir, _ := os.UserHomeDir()
+	return NewSchedulerWithDir(filepath.Join(homeDir, ".ai-launcher"), q, templates)
+}
+
+// NewSchedulerWithDir 創建使用指定目錄的計劃器
+func NewSchedulerWithDir(dir string, q *queue.Queue, templates *template.TemplateManager) *Scheduler {
+	return &Scheduler{
+		path:         filepath.Join(dir, "schedules.json"),
+		historyPath:  filepath.Join(dir, "schedule-history.json"),
+		queue:        q,
+		templates:    templates,
+		pollInterval: DefaultPollInterval,
+		now:          time.Now,
+	}
+}
+
+// SetPollInterval 設置檢查到期計劃的間隔
+func (s *Scheduler) SetPollInterval(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pollInterval = d
+}
+
+// Add 驗證並保存新計劃，返回計算好下一次觸發時間的副本
+func (s *Scheduler) Add(sc Schedule) (*Schedule, error) {
+	if strings.TrimSpace(sc.Project) == "" {
+		return nil, fmt.Errorf("project path is required")
+	}
+	if strings.TrimSpace(sc.Prompt) == "" && sc.Template == "" {
+		return nil, fmt.Errorf("prompt or template is required")
+	}
+	if sc.Template != "" {
+		if s.templates == nil {
+			return nil, fmt.Errorf("templates are not available")
+		}
+		if _, ok := s.templates.GetTemplate(sc.Template); !ok {
+			return nil, fmt.Errorf("template not found: %s", sc.Template)
+		}
+	}
+	if err := sc.Missed.Validate(); err != nil {
+		return nil, err
+	}
+	if sc.Tool == "" {
+		sc.Tool = project.ModelClaudeCode
+	}
+
+	now := s.now()
+	next, err := sc.next(now)
+	if err != nil {
+		return nil, err
+	}
+	if next.IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", sc.Cron)
+	}
+
+	sc.ID = newScheduleID()
+	sc.CreatedAt = now
+	sc.LastRunAt = time.Time{}
+	sc.NextRunAt = next
+	if sc.Paused {
+		sc.NextRunAt = time.Time{}
+	}
+
+	err = s.update(func(schedules []*Schedule, runs []Run) ([]*Schedule, []Run, error) {
+		return append(schedules, &sc), runs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+// List 返回所有計劃，按下一次觸發時間排序，暫停的排在最後
+func (s *Scheduler) List() ([]*Schedule, error) {
+	schedules, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(schedules, func(i, j int) bool {
+		a, b := schedules[i], schedules[j]
+		if a.NextRunAt.IsZero() != b.NextRunAt.IsZero() {
+			return !a.NextRunAt.IsZero()
+		}
+		return a.NextRunAt.Before(b.NextRunAt)
+	})
+	return schedules, nil
+}
+
+// Get 根據 ID 或唯一前綴獲取計劃
+func (s *Scheduler) Get(id string) (*Schedule, error) {
+	schedules, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return findSchedule(schedules, id)
+}
+
+// Remove 刪除計劃，運行記錄保留
+func (s *Scheduler) Remove(id string) (*Schedule, error) {
+	var removed *Schedule
+	err := s.update(func(schedules []*Schedule, runs []Run) ([]*Schedule, []Run, error) {
+		sc, err := findSchedule(schedules, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		removed = sc
+		kept := schedules[:0]
+		for _, other := range schedules {
+			if other.ID != sc.ID {
+				kept = append(kept, other)
+			}
+		}
+		return kept, runs, nil
+	})
+	return removed, err
+}
+
+// SetPaused 暫停或恢復計劃；恢復時從當前時間重新計算，不補跑暫停期間的觸發
+func (s *Scheduler) SetPaused(id string, paused bool) (*Schedule, error) {
+	var result Schedule
+	err := s.update(func(schedules []*Schedule, runs []Run) ([]*Schedule, []Run, error) {
+		sc, err := findSchedule(schedules, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		sc.Paused = paused
+		sc.NextRunAt = time.Time{}
+		if !paused {
+			if sc.NextRunAt, err = sc.next(s.now()); err != nil {
+				return nil, nil, err
+			}
+		}
+		result = *sc
+		return schedules, runs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Trigger 立即投遞一次計劃任務，不影響下一次觸發時間
+func (s *Scheduler) Trigger(id string) (*Run, error) {
+	sc, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	run := s.enqueue(sc, now, now)
+	if err := s.record([]Run{run}, now); err != nil {
+		return nil, err
+	}
+	if run.Status == RunError {
+		return &run, fmt.Errorf("%s", run.Error)
+	}
+	return &run, nil
+}
+
+// History 返回運行記錄，最新的在前；id 為空時返回所有計劃的記錄，limit <= 0 表示不限制
+func (s *Scheduler) History(id string, limit int) ([]Run, error) {
+	schedules, runs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		// 已刪除的計劃仍可按完整 ID 查詢
+		if sc, err := findSchedule(schedules, id); err == nil {
+			id = sc.ID
+		}
+	}
+
+	result := []Run{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if id != "" && runs[i].ScheduleID != id {
+			continue
+		}
+		result = append(result, runs[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Run 定期檢查並觸發到期的計劃，直到 ctx 被取消；啟動時立即處理錯過的觸發
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	interval := s.pollInterval
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.tick(s.now()); err != nil {
+			fmt.Fprintf(os.Stderr, "schedule: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 觸發所有到期的計劃；多個進程同時運行時由文件鎖保證只觸發一次。
+// 鎖內只推進下一次觸發時間，釋放計劃鎖後再投遞任務，避免同時持有計劃鎖與隊列鎖
+func (s *Scheduler) tick(now time.Time) error {
+	schedules, _, err := s.load()
+	if err != nil {
+		return err
+	}
+	if !anyDue(schedules, now) {
+		return nil
+	}
+
+	var (
+		fired   []Run
+		targets = make(map[string]Schedule)
+	)
+	err = s.update(func(schedules []*Schedule, runs []Run) ([]*Schedule, []Run, error) {
+		for _, sc := range schedules {
+			if due(sc, now) {
+				targets[sc.ID] = *sc
+				fired = append(fired, fire(sc, now)...)
+			}
+		}
+		return schedules, runs, nil
+	})
+	if err != nil || len(fired) == 0 {
+		return err
+	}
+
+	for i, run := range fired {
+		if run.Status == runPending {
+			sc := targets[run.ScheduleID]
+			fired[i] = s.enqueue(&sc, run.ScheduledFor, now)
+			fired[i].Missed = run.Missed
+		}
+	}
+	return s.record(fired, now)
+}
+
+// record 保存運行記錄，並更新成功投遞任務的計劃的上次運行時間
+func (s *Scheduler) record(fired []Run, now time.Time) error {
+	return s.update(func(schedules []*Schedule, runs []Run) ([]*Schedule, []Run, error) {
+		for _, run := range fired {
+			if run.Status != RunQueued {
+				continue
+			}
+			for _, sc := range schedules {
+				if sc.ID == run.ScheduleID {
+					sc.LastRunAt = now
+				}
+			}
+		}
+		return schedules, append(runs, fired...), nil
+	})
+}
+
+// fire 按錯過策略處理一個到期計劃並計算下一次觸發時間；需要投遞的觸發以 runPending 狀態返回
+func fire(sc *Schedule, now time.Time) []Run {
+	c, loc, err := sc.compile()
+	occurrences, total := []time.Time{sc.NextRunAt}, 1
+	if err == nil {
+		occurrences, total = occurrencesBetween(c, loc, sc.NextRunAt, now)
+	}
+	last := occurrences[len(occurrences)-1]
+	pending := func(t time.Time) Run {
+		return Run{ScheduleID: sc.ID, ScheduledFor: t, TriggeredAt: now, Status: runPending}
+	}
+
+	var runs []Run
+	switch sc.policy() {
+	case MissedSkip:
+		onTime := now.Sub(last) <= missedGrace
+		skipped := total
+		if onTime {
+			skipped--
+		}
+		if skipped > 0 {
+			runs = append(runs, Run{ScheduleID: sc.ID, ScheduledFor: sc.NextRunAt, TriggeredAt: now, Status: RunSkipped, Missed: skipped})
+		}
+		if onTime {
+			runs = append(runs, pending(last))
+		}
+	case MissedRunAll:
+		if total > len(occurrences) {
+			runs = append(runs, Run{ScheduleID: sc.ID, ScheduledFor: sc.NextRunAt, TriggeredAt: now, Status: RunSkipped, Missed: total - len(occurrences)})
+		}
+		for _, t := range occurrences {
+			runs = append(runs, pending(t))
+		}
+	default:
+		run := pending(last)
+		run.Missed = total - 1
+		runs = append(runs, run)
+	}
+
+	sc.NextRunAt = time.Time{}
+	if err != nil {
+		// 表達式在保存後變為無效（例如手動編輯），停止觸發並記錄錯誤
+		runs = append(runs, Run{ScheduleID: sc.ID, ScheduledFor: now, TriggeredAt: now, Status: RunError, Error: err.Error()})
+	} else {
+		sc.NextRunAt = c.Next(now.In(loc))
+	}
+	return runs
+}
+
+// occurrencesBetween 返回 first 到 now 之間的觸發時間（最多保留最近 maxCatchUp 次）及總次數。
+// 錯過超過 maxMissedScan 次時不再逐次統計，直接跳到 now 之前按最近 maxCatchUp 次觸發的間隔估算的位置
+// 繼續查找，此時總次數只是下限
+func occurrencesBetween(c *Cron, loc *time.Location, first, now time.Time) ([]time.Time, int) {
+	times := []time.Time{first}
+	total := 1
+	for t := first; ; total++ {
+		if total == maxMissedScan {
+			// 回溯兩倍的時間段，確保窗口中保留的都是最近的觸發
+			if from := now.Add(-2 * times[len(times)-1].Sub(times[0])); from.After(t) {
+				t = from
+			}
+		}
+		next := c.Next(t.In(loc))
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		t = next
+		times = append(times, t)
+		if len(times) > maxCatchUp {
+			times = times[1:]
+		}
+	}
+	return times, total
+}
+
+// enqueue 將計劃作為任務投遞到隊列
+func (s *Scheduler) enqueue(sc *Schedule, scheduledFor, now time.Time) Run {
+	run := Run{ScheduleID: sc.ID, ScheduledFor: scheduledFor, TriggeredAt: now}
+	if s.queue == nil {
+		run.Status = RunError
+		run.Error = "task queue is not available"
+		return run
+	}
+
+	prompt, err := s.prompt(sc)
+	if err == nil {
+		var job *queue.Job
+		job, err = s.queue.Add(queue.Job{
+			Project:  sc.Project,
+			Tool:     sc.Tool,
+			Prompt:   prompt,
+			Options:  sc.Options,
+			Priority: sc.Priority,
+		})
+		if err == nil {
+			run.JobID = job.ID
+		}
+	}
+	if err != nil {
+		run.Status = RunError
+		run.Error = err.Error()
+		return run
+	}
+	run.Status = RunQueued
+	return run
+}
+
+// prompt 生成任務提示詞；引用模板時以提示詞（或計劃名稱）作為模板的 query
+func (s *Scheduler) prompt(sc *Schedule) (string, error) {
+	if sc.Template == "" {
+		return sc.Prompt, nil
+	}
+	if s.templates == nil {
+		return "", fmt.Errorf("templates are not available")
+	}
+
+	query := sc.Prompt
+	if strings.TrimSpace(query) == "" {
+		query = sc.Name
+	}
+	if strings.TrimSpace(query) == "" {
+		query = sc.Template
+	}
+	result, err := s.templates.ApplyTemplate(sc.Template, query, map[string]string{
+		"project": sc.Project,
+		"tool":    string(sc.Tool),
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.OptimizedPrompt, nil
+}
+
+// load 讀取計劃與運行記錄
+func (s *Scheduler) load() ([]*Schedule, []Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		schedules []*Schedule
+		runs      []Run
+	)
+	err := fsutil.WithLock(s.path, func() error {
+		var err error
+		schedules, runs, err = s.read()
+		return err
+	})
+	return schedules, runs, err
+}
+
+// update 在鎖內讀取、修改並寫回計劃與運行記錄
+func (s *Scheduler) update(fn func(schedules []*Schedule, runs []Run) ([]*Schedule, []Run, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fsutil.WithLock(s.path, func() error {
+		schedules, runs, err := s.read()
+		if err != nil {
+			return err
+		}
+		schedules, runs, err = fn(schedules, runs)
+		if err != nil {
+			return err
+		}
+		if len(runs) > maxHistory {
+			runs = runs[len(runs)-maxHistory:]
+		}
+		if err := writeJSON(s.path, schedules); err != nil {
+			return err
+		}
+		return writeJSON(s.historyPath, runs)
+	})
+}
+
+func (s *Scheduler) read() ([]*Schedule, []Run, error) {
+	schedules := []*Schedule{}
+	if err := readJSON(s.path, &schedules); err != nil {
+		return nil, nil, err
+	}
+	runs := []Run{}
+	if err := readJSON(s.historyPath, &runs); err != nil {
+		return nil, nil, err
+	}
+	return schedules, runs, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	return fsutil.WriteFileAtomic(path, data, 0600)
+}
+
+func due(sc *Schedule, now time.Time) bool {
+	return !sc.Paused && !sc.NextRunAt.IsZero() && !sc.NextRunAt.After(now)
+}
+
+func anyDue(schedules []*Schedule, now time.Time) bool {
+	for _, sc := range schedules {
+		if due(sc, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// findSchedule 按完整 ID 或唯一前綴查找計劃
+func findSchedule(schedules []*Schedule, id string) (*Schedule, error) {
+	if id == "" {
+		return nil, fmt.Errorf("schedule id is required")
+	}
+
+	var match *Schedule
+	for _, sc := range schedules {
+		if sc.ID == id {
+			return sc, nil
+		}
+		if strings.HasPrefix(sc.ID, id) {
+			if match != nil {
+				return nil, fmt.Errorf("ambiguous schedule id %q", id)
+			}
+			match = sc
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("schedule not found: %s", id)
+	}
+	return match, nil
+}
+
+// newScheduleID 生成隨機計劃 ID
+func newScheduleID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,300 @@
+package schedule
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+)
+
+// fakeClock 可手動推進的時鐘
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestScheduler(t *testing.T, tm *terminal.TerminalManager) (*Scheduler, *queue.Queue, *fakeClock) {
+	t.Helper()
+	dir := t.TempDir()
+	q := queue.NewQueueWithDir(filepath.Join(dir, "queue"), tm)
+	s := NewSchedulerWithDir(dir, q, template.NewTemplateManager())
+	clock := &fakeClock{t: time.Date(2024, 3, 4, 6, 0, 0, 0, time.UTC)} // 週一
+	s.now = clock.now
+	return s, q, clock
+}
+
+func weekdaySchedule(dir string) Schedule {
+	return Schedule{
+		Name:     "dependency audit",
+		Cron:     "0 7 * * 1-5",
+		Timezone: "UTC",
+		Project:  dir,
+		Tool:     project.ModelGeminiCLI,
+		Prompt:   "audit dependencies",
+	}
+}
+
+func TestScheduler_AddValidation(t *testing.T) {
+	s, _, _ := newTestScheduler(t, nil)
+	dir := t.TempDir()
+
+	_, err := s.Add(Schedule{Cron: "0 7 * * *", Prompt: "x"})
+	assert.Error(t, err)
+	_, err = s.Add(Schedule{Cron: "0 7 * * *", Project: dir})
+	assert.Error(t, err)
+	_, err = s.Add(Schedule{Cron: "bad", Project: dir, Prompt: "x"})
+	assert.Error(t, err)
+	_, err = s.Add(Schedule{Cron: "0 0 30 2 *", Project: dir, Prompt: "x"})
+	assert.Error(t, err)
+	_, err = s.Add(Schedule{Cron: "0 7 * * *", Project: dir, Prompt: "x", Missed: "sometimes"})
+	assert.Error(t, err)
+	_, err = s.Add(Schedule{Cron: "0 7 * * *", Project: dir, Template: "missing"})
+	assert.Error(t, err)
+	_, err = s.Add(Schedule{Cron: "0 7 * * *", Project: dir, Prompt: "x", Timezone: "Nowhere/City"})
+	assert.Error(t, err)
+
+	sc, err := s.Add(Schedule{Cron: "0 7 * * *", Project: dir, Prompt: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, project.ModelClaudeCode, sc.Tool)
+	assert.NotEmpty(t, sc.ID)
+}
+
+func TestScheduler_FiresWhenDue(t *testing.T) {
+	s, q, clock := newTestScheduler(t, nil)
+	sc, err := s.Add(weekdaySchedule(t.TempDir()))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC), sc.NextRunAt.UTC())
+
+	// 未到期不觸發
+	require.NoError(t, s.tick(clock.now()))
+	jobs, err := q.List()
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	clock.advance(time.Hour + 10*time.Second)
+	require.NoError(t, s.tick(clock.now()))
+
+	jobs, err = q.List()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, project.ModelGeminiCLI, jobs[0].Tool)
+	assert.Equal(t, "audit dependencies", jobs[0].Prompt)
+
+	runs, err := s.History(sc.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, RunQueued, runs[0].Status)
+	assert.Equal(t, jobs[0].ID, runs[0].JobID)
+
+	got, err := s.Get(sc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 5, 7, 0, 0, 0, time.UTC), got.NextRunAt.UTC())
+
+	// 同一時刻重複檢查不會再次觸發
+	require.NoError(t, s.tick(clock.now()))
+	jobs, err = q.List()
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestScheduler_MissedPolicies(t *testing.T) {
+	tests := []struct {
+		policy   MissedPolicy
+		jobs     int
+		statuses []RunStatus
+	}{
+		{MissedRunOnce, 1, []RunStatus{RunQueued}},
+		{MissedSkip, 0, []RunStatus{RunSkipped}},
+		{MissedRunAll, 3, []RunStatus{RunQueued, RunQueued, RunQueued}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			s, q, clock := newTestScheduler(t, nil)
+			sc := weekdaySchedule(t.TempDir())
+			sc.Missed = tt.policy
+			added, err := s.Add(sc)
+			require.NoError(t, err)
+
+			// 啟動器關閉了三個工作日（週一至週三），週三晚上才重新運行
+			clock.t = time.Date(2024, 3, 6, 20, 0, 0, 0, time.UTC)
+			require.NoError(t, s.tick(clock.now()))
+
+			jobs, err := q.List()
+			require.NoError(t, err)
+			assert.Len(t, jobs, tt.jobs)
+
+			runs, err := s.History(added.ID, 0)
+			require.NoError(t, err)
+			var statuses []RunStatus
+			for _, run := range runs {
+				statuses = append(statuses, run.Status)
+			}
+			assert.Equal(t, tt.statuses, statuses)
+
+			got, err := s.Get(added.ID)
+			require.NoError(t, err)
+			assert.Equal(t, time.Date(2024, 3, 7, 7, 0, 0, 0, time.UTC), got.NextRunAt.UTC())
+		})
+	}
+}
+
+func TestScheduler_RunOnceRecordsMissedCount(t *testing.T) {
+	s, _, clock := newTestScheduler(t, nil)
+	added, err := s.Add(weekdaySchedule(t.TempDir()))
+	require.NoError(t, err)
+
+	clock.t = time.Date(2024, 3, 6, 20, 0, 0, 0, time.UTC)
+	require.NoError(t, s.tick(clock.now()))
+
+	runs, err := s.History(added.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, 2, runs[0].Missed)
+	assert.Equal(t, time.Date(2024, 3, 6, 7, 0, 0, 0, time.UTC), runs[0].ScheduledFor.UTC())
+}
+
+func TestScheduler_LongDowntimeCatchUp(t *testing.T) {
+	s, q, clock := newTestScheduler(t, nil)
+	sc := weekdaySchedule(t.TempDir())
+	sc.Cron = "* * * * *"
+	sc.Missed = MissedRunAll
+	added, err := s.Add(sc)
+	require.NoError(t, err)
+
+	// 停機一年後只補跑最近的 maxCatchUp 次，不逐分鐘遍歷
+	clock.t = clock.t.AddDate(1, 0, 0)
+	require.NoError(t, s.tick(clock.now()))
+
+	jobs, err := q.List()
+	require.NoError(t, err)
+	assert.Len(t, jobs, maxCatchUp)
+
+	runs, err := s.History(added.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, maxCatchUp+1)
+	assert.Equal(t, clock.now(), runs[0].ScheduledFor.UTC())
+	assert.Equal(t, clock.now().Add(-time.Duration(maxCatchUp-1)*time.Minute), runs[maxCatchUp-1].ScheduledFor.UTC())
+	assert.Equal(t, RunSkipped, runs[maxCatchUp].Status)
+	assert.GreaterOrEqual(t, runs[maxCatchUp].Missed, maxMissedScan-maxCatchUp)
+
+	got, err := s.Get(added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, clock.now().Add(time.Minute), got.NextRunAt.UTC())
+}
+
+func TestScheduler_SkipRunsOnTimeTrigger(t *testing.T) {
+	s, q, clock := newTestScheduler(t, nil)
+	sc := weekdaySchedule(t.TempDir())
+	sc.Missed = MissedSkip
+	_, err := s.Add(sc)
+	require.NoError(t, err)
+
+	clock.t = time.Date(2024, 3, 4, 7, 0, 30, 0, time.UTC)
+	require.NoError(t, s.tick(clock.now()))
+
+	jobs, err := q.List()
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestScheduler_PauseAndResume(t *testing.T) {
+	s, q, clock := newTestScheduler(t, nil)
+	added, err := s.Add(weekdaySchedule(t.TempDir()))
+	require.NoError(t, err)
+
+	paused, err := s.SetPaused(added.ID[:4], true)
+	require.NoError(t, err)
+	assert.True(t, paused.NextRunAt.IsZero())
+
+	clock.t = time.Date(2024, 3, 6, 20, 0, 0, 0, time.UTC)
+	require.NoError(t, s.tick(clock.now()))
+	jobs, err := q.List()
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	// 恢復後不補跑暫停期間的觸發
+	resumed, err := s.SetPaused(added.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 7, 7, 0, 0, 0, time.UTC), resumed.NextRunAt.UTC())
+}
+
+func TestScheduler_TemplateAndTrigger(t *testing.T) {
+	s, q, _ := newTestScheduler(t, nil)
+	sc := weekdaySchedule(t.TempDir())
+	sc.Prompt = "check for outdated modules"
+	sc.Template = template.TemplateDebug
+	added, err := s.Add(sc)
+	require.NoError(t, err)
+
+	run, err := s.Trigger(added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, RunQueued, run.Status)
+
+	job, err := q.Get(run.JobID)
+	require.NoError(t, err)
+	assert.Contains(t, job.Prompt, "check for outdated modules")
+	assert.NotEqual(t, "check for outdated modules", job.Prompt)
+
+	got, err := s.Get(added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, added.NextRunAt.UTC(), got.NextRunAt.UTC())
+}
+
+func TestScheduler_RemoveKeepsHistory(t *testing.T) {
+	s, _, _ := newTestScheduler(t, nil)
+	added, err := s.Add(weekdaySchedule(t.TempDir()))
+	require.NoError(t, err)
+	_, err = s.Trigger(added.ID)
+	require.NoError(t, err)
+
+	_, err = s.Remove(added.ID)
+	require.NoError(t, err)
+	_, err = s.Get(added.ID)
+	assert.Error(t, err)
+
+	runs, err := s.History(added.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
+
+func TestScheduler_RunsThroughTerminalManager(t *testing.T) {
+	tm := terminal.NewTerminalManager()
+	s, q, clock := newTestScheduler(t, tm)
+	q.SetPollInterval(20 * time.Millisecond)
+	s.SetPollInterval(20 * time.Millisecond)
+
+	sc := weekdaySchedule(t.TempDir())
+	sc.Options.Command = []string{"sh", "-c", `echo "ran: $1"`, "sh"}
+	added, err := s.Add(sc)
+	require.NoError(t, err)
+	clock.t = added.NextRunAt
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	go s.Run(ctx)
+
+	var runs []Run
+	require.Eventually(t, func() bool {
+		runs, _ = s.History(added.ID, 0)
+		return len(runs) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		job, err := q.Get(runs[0].JobID)
+		return err == nil && job.Status == queue.JobSucceeded
+	}, 5*time.Second, 20*time.Millisecond)
+
+	job, err := q.Get(runs[0].JobID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ran: audit dependencies"}, job.Output)
+}
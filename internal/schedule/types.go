@@ -0,0 +1,120 @@
+// Package schedule 按 cron 表達式定期將 AI 任務投遞到任務隊列
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/queue"
+)
+
+const (
+	DefaultPollInterval = 30 * time.Second // 檢查到期計劃的間隔
+	missedGrace         = 2 * time.Minute  // 遲到超過此時間的觸發視為錯過
+	maxCatchUp          = 24               // run_all 策略下單次最多補跑的次數
+	maxMissedScan       = 1000             // 逐次統計錯過觸發的上限，超過後只查找最近的觸發
+	maxHistory          = 1000             // 保留的運行記錄條數
+)
+
+// MissedPolicy 啟動器未運行期間錯過觸發時的處理策略
+type MissedPolicy string
+
+const (
+	MissedRunOnce MissedPolicy = "run_once" // 補跑一次（默認）
+	MissedSkip    MissedPolicy = "skip"     // 跳過所有錯過的觸發
+	MissedRunAll  MissedPolicy = "run_all"  // 每次錯過的觸發都補跑
+)
+
+// Validate 檢查策略是否有效，空值表示默認策略
+func (p MissedPolicy) Validate() error {
+	switch p {
+	case "", MissedRunOnce, MissedSkip, MissedRunAll:
+		return nil
+	}
+	return fmt.Errorf("invalid missed-run policy %q (expected run_once, skip or run_all)", p)
+}
+
+// Schedule 定期執行的 AI 任務
+type Schedule struct {
+	ID       string              `json:"id"`
+	Name     string              `json:"name,omitempty"`
+	Cron     string              `json:"cron"`
+	Timezone string              `json:"timezone,omitempty"` // IANA 時區名，空值使用本地時區
+	Project  string              `json:"project"`
+	Tool     project.AIModelType `json:"tool"`
+	Prompt   string              `json:"prompt,omitempty"`
+	Template string              `json:"template,omitempty"` // 查詢模板 ID，提示詞作為模板的 query
+	Options  queue.JobOptions    `json:"options"`
+	Priority int                 `json:"priority,omitempty"`
+	Missed   MissedPolicy        `json:"missed_policy,omitempty"`
+	Paused   bool                `json:"paused,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	LastRunAt time.Time `json:"last_run_at,omitempty"`
+	NextRunAt time.Time `json:"next_run_at,omitempty"`
+}
+
+// policy 返回生效的錯過策略
+func (s *Schedule) policy() MissedPolicy {
+	if s.Missed == "" {
+		return MissedRunOnce
+	}
+	return s.Missed
+}
+
+// location 返回計劃使用的時區
+func (s *Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// compile 解析 cron 表達式與時區，需要多次計算觸發時間時只解析一次
+func (s *Schedule) compile() (*Cron, *time.Location, error) {
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := s.location()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, loc, nil
+}
+
+// next 計算 after 之後的下一次觸發時間
+func (s *Schedule) next(after time.Time) (time.Time, error) {
+	c, loc, err := s.compile()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.Next(after.In(loc)), nil
+}
+
+// RunStatus 單次觸發的結果
+type RunStatus string
+
+const (
+	RunQueued  RunStatus = "queued"  // 已投遞到任務隊列
+	RunSkipped RunStatus = "skipped" // 按錯過策略跳過
+	RunError   RunStatus = "error"   // 投遞失敗（例如模板不存在）
+
+	runPending RunStatus = "" // 已到期、等待釋放計劃鎖後投遞，不會被保存
+)
+
+// Run 計劃的一次觸發記錄；任務的執行狀態與輸出通過 JobID 在任務隊列中查詢
+type Run struct {
+	ScheduleID   string    `json:"schedule_id"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+	TriggeredAt  time.Time `json:"triggered_at"`
+	Status       RunStatus `json:"status"`
+	JobID        string    `json:"job_id,omitempty"`
+	Missed       int       `json:"missed,omitempty"` // run_once 策略合併的錯過次數
+	Error        string    `json:"error,omitempty"`
+}
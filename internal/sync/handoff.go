@@ -0,0 +1,235 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/fsutil"
+	"ai-launcher/internal/terminal"
+)
+
+const (
+	handoffKey      = "handoff"      // 工具狀態中的交接摘要
+	lastHandoffKey  = "last_handoff" // 通用狀態中最近一次的交接摘要
+	summaryLines    = 40             // 摘要保留的輸出行數
+	summaryMaxChars = 4000           // 摘要的最大長度
+	maxChangedFiles = 50             // 摘要列出的改動文件數量
+
+	// MaxHandoffAge 超過此時間的交接摘要不再注入
+	MaxHandoffAge = 7 * 24 * time.Hour
+)
+
+// Handoff 會話結束時留給下一個工具的交接摘要
+type Handoff struct {
+	FromTool     string   `json:"from_tool"`
+	SessionID    string   `json:"session_id,omitempty"`
+	Task         string   `json:"task,omitempty"`
+	Summary      string   `json:"summary"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	ExitCode     int      `json:"exit_code"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// ToolName 將終端類型映射為同步目錄中的工具名稱；不支持的類型返回空字串
+func ToolName(t terminal.TerminalType) string {
+	switch t {
+	case terminal.TypeClaudeCode:
+		return "claude"
+	case terminal.TypeGeminiCLI:
+		return "gemini"
+	case terminal.TypeCursor:
+		return "cursor"
+	case terminal.TypeCodex:
+		return "codex"
+	case terminal.TypeAider:
+		return "aider"
+	default:
+		return ""
+	}
+}
+
+// CaptureHandoff 根據會話輸出的結尾與工作區改動生成交接摘要
+func (m *Manager) CaptureHandoff(tool, task string, output []string, exitCode int, sessionID string) *Handoff {
+	return &Handoff{
+		FromTool:     tool,
+		SessionID:    sessionID,
+		Task:         strings.TrimSpace(task),
+		Summary:      summarize(output),
+		ChangedFiles: m.changedFiles(),
+		ExitCode:     exitCode,
+		CreatedAt:    timestamp(time.Now()),
+	}
+}
+
+// RecordHandoff 將交接摘要保存到來源工具的狀態與通用狀態中
+func (m *Manager) RecordHandoff(h *Handoff) error {
+	if !isSupported(h.FromTool) || h.FromTool == universalTool {
+		return fmt.Errorf("unsupported tool: %s", h.FromTool)
+	}
+	if h.CreatedAt == "" {
+		h.CreatedAt = timestamp(time.Now())
+	}
+
+	return fsutil.WithLock(m.universalPath(), func() error {
+		state := m.currentState(h.FromTool)
+		state[handoffKey] = h
+		return m.saveState(h.FromTool, state, "handoff")
+	})
+}
+
+// LatestHandoff 返回最近一次的交接摘要；沒有時返回 nil
+func (m *Manager) LatestHandoff() (*Handoff, error) {
+	universal, err := m.LoadUniversal()
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := universal[lastHandoffKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handoff: %w", err)
+	}
+	var h Handoff
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("failed to parse handoff: %w", err)
+	}
+	return &h, nil
+}
+
+// PendingHandoff 返回可注入新會話的交接摘要：項目未啟用同步、沒有摘要或摘要過舊時返回 nil
+func PendingHandoff(projectPath string) *Handoff {
+	if !Enabled(projectPath) {
+		return nil
+	}
+	h, err := NewManager(projectPath).LatestHandoff()
+	if err != nil || h == nil || h.Summary == "" && h.Task == "" {
+		return nil
+	}
+	if created, err := parseTimestamp(h.CreatedAt); err != nil || time.Since(created) > MaxHandoffAge {
+		return nil
+	}
+	return h
+}
+
+// ContextPrompt 將交接摘要格式化為注入下一個工具的上下文
+func (h *Handoff) ContextPrompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Handoff from %s, %s]\n", h.FromTool, h.CreatedAt)
+	b.WriteString("A previous AI session on this project has ended. Continue from where it left off.\n")
+	if h.Task != "" {
+		fmt.Fprintf(&b, "\nTask:\n%s\n", h.Task)
+	}
+	if h.ExitCode != 0 {
+		fmt.Fprintf(&b, "\nThe previous session exited with status %d.\n", h.ExitCode)
+	}
+	if len(h.ChangedFiles) > 0 {
+		b.WriteString("\nUncommitted changes:\n")
+		for _, f := range h.ChangedFiles {
+			fmt.Fprintf(&b, "- %s\n", f)
+		}
+	}
+	if h.Summary != "" {
+		fmt.Fprintf(&b, "\nEnd of the previous session:\n%s\n", h.Summary)
+	}
+	b.WriteString("[End of handoff]")
+	return b.String()
+}
+
+// InjectPrompt 在提示詞前加入待交接的上下文，用於非交互任務
+func InjectPrompt(projectPath, prompt string) string {
+	h := PendingHandoff(projectPath)
+	if h == nil {
+		return prompt
+	}
+	return h.ContextPrompt() + "\n\n" + prompt
+}
+
+// CaptureOnExit 讓終端退出時把輸出記錄為交接摘要；項目未啟用同步或工具不支持時不做任何事
+func CaptureOnExit(config *terminal.TerminalConfig, projectPath, task string) {
+	tool := ToolName(config.Type)
+	if tool == "" || !Enabled(projectPath) {
+		return
+	}
+
+	previous := config.OnExit
+	config.OnExit = func(t *terminal.Terminal) {
+		if previous != nil {
+			previous(t)
+		}
+		m := NewManager(projectPath)
+		_ = m.RecordHandoff(m.CaptureHandoff(tool, task, t.Output(), t.ExitCode(), t.SessionID()))
+	}
+}
+
+// Attach 為新的交互式會話注入最近的交接摘要，並在會話結束時記錄新的摘要；
+// 恢復已有會話時工具自帶上下文，不再注入
+func Attach(config *terminal.TerminalConfig, projectPath, task string) {
+	if config.Resume == "" {
+		if h := PendingHandoff(projectPath); h != nil {
+			if config.InitialPrompt != "" {
+				config.InitialPrompt = h.ContextPrompt() + "\n\n" + config.InitialPrompt
+			} else {
+				config.InitialPrompt = h.ContextPrompt()
+			}
+		}
+	}
+	CaptureOnExit(config, projectPath, task)
+}
+
+// summarize 取輸出結尾的非空行作為摘要
+func summarize(output []string) string {
+	var lines []string
+	for i := len(output) - 1; i >= 0 && len(lines) < summaryLines; i-- {
+		if line := strings.TrimRight(output[i], " \t\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	summary := strings.Join(lines, "\n")
+	if r := []rune(summary); len(r) > summaryMaxChars {
+		summary = "..." + string(r[len(r)-summaryMaxChars:])
+	}
+	return summary
+}
+
+// changedFiles 返回工作區中未提交的改動（忽略 .addp 目錄）；不是 git 倉庫時返回 nil。
+// 使用 -z 格式，路徑不會被加引號轉義，重命名與複製取新路徑
+func (m *Manager) changedFiles() []string {
+	cmd := exec.Command("git", "status", "--porcelain", "-z")
+	cmd.Dir = m.projectPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		entry := fields[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// 重命名與複製的下一個字段是原路徑
+		if strings.ContainsAny(entry[:2], "RC") {
+			i++
+		}
+		path := entry[3:]
+		if strings.HasPrefix(path, ".addp/") || path == ".addp" {
+			continue
+		}
+		files = append(files, path)
+		if len(files) >= maxChangedFiles {
+			break
+		}
+	}
+	return files
+}
@@ -0,0 +1,387 @@
+// Package sync 讀寫 ADDP 項目的跨工具同步狀態（.addp/sync），與 Python SyncManager 使用相同的目錄結構，
+// 並在工具之間傳遞會話交接摘要
+package sync
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ai-launcher/internal/fsutil"
+)
+
+const (
+	stateVersion    = "1.0.0"
+	timestampLayout = "2006-01-02T15:04:05.000000" // 與 Python datetime.isoformat() 一致
+	universalTool   = "universal"
+)
+
+// SupportedTools 擁有獨立狀態目錄的工具；前四個與 Python SyncManager 相同
+var SupportedTools = []string{"claude", "gemini", "cursor", universalTool, "codex", "aider"}
+
+// ToolState 工具狀態文件 <tool>/current_state.json
+type ToolState struct {
+	Tool         string                 `json:"tool"`
+	Timestamp    string                 `json:"timestamp"`
+	Version      string                 `json:"version"`
+	State        map[string]interface{} `json:"state"`
+	SyncMetadata map[string]interface{} `json:"sync_metadata"`
+}
+
+// HistoryEntry 同步歷史 sync_history.jsonl 中的一行
+type HistoryEntry struct {
+	Timestamp string `json:"timestamp"`
+	Tool      string `json:"tool"`
+	Action    string `json:"action"`
+	DataSize  int    `json:"data_size"`
+	Success   bool   `json:"success"`
+}
+
+// Status 各工具的同步狀態
+type Status struct {
+	SyncEnabled             bool              `json:"sync_enabled"`
+	SupportedTools          []string          `json:"supported_tools"`
+	ToolStatus              map[string]string `json:"tool_status"` // active, error, not_synced
+	LastSyncTimes           map[string]string `json:"last_sync_times"`
+	UniversalStateAvailable bool              `json:"universal_state_available"`
+	LastHandoff             *Handoff          `json:"last_handoff,omitempty"`
+}
+
+// Manager 管理單個項目的 .addp/sync 目錄
+type Manager struct {
+	projectPath string
+	dir         string
+}
+
+// NewManager 創建項目的同步管理器
+func NewManager(projectPath string) *Manager {
+	return &Manager{
+		projectPath: projectPath,
+		dir:         filepath.Join(projectPath, ".addp", "sync"),
+	}
+}
+
+// Enabled 檢查項目是否已初始化 ADDP 結構（存在 .addp 目錄）；未初始化的項目不寫入同步狀態
+func Enabled(projectPath string) bool {
+	info, err := os.Stat(filepath.Join(projectPath, ".addp"))
+	return err == nil && info.IsDir()
+}
+
+// Dir 返回同步目錄
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// SaveState 保存工具狀態並更新通用狀態與同步歷史；state 為 nil 時生成默認狀態
+func (m *Manager) SaveState(tool string, state map[string]interface{}) error {
+	if !isSupported(tool) || tool == universalTool {
+		return fmt.Errorf("unsupported tool: %s", tool)
+	}
+
+	return fsutil.WithLock(m.universalPath(), func() error {
+		if state == nil {
+			state = m.defaultState(tool)
+		}
+		return m.saveState(tool, state, "save")
+	})
+}
+
+// saveState 在鎖內寫入工具狀態
+func (m *Manager) saveState(tool string, state map[string]interface{}, action string) error {
+	now := timestamp(time.Now())
+	full := ToolState{
+		Tool:      tool,
+		Timestamp: now,
+		Version:   stateVersion,
+		State:     state,
+		SyncMetadata: map[string]interface{}{
+			"last_sync":    now,
+			"sync_source":  tool,
+			"sync_version": 1,
+		},
+	}
+
+	data, err := json.MarshalIndent(full, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s state: %w", tool, err)
+	}
+	if err := fsutil.WriteFileAtomic(m.statePath(tool), data, 0644); err != nil {
+		return err
+	}
+	if err := m.updateUniversal(tool, &full); err != nil {
+		return err
+	}
+	m.appendHistory(tool, action, len(data))
+	return nil
+}
+
+// LoadState 讀取工具狀態；尚未保存時返回 nil
+func (m *Manager) LoadState(tool string) (*ToolState, error) {
+	if !isSupported(tool) {
+		return nil, fmt.Errorf("unsupported tool: %s", tool)
+	}
+
+	var state ToolState
+	found, err := readJSON(m.statePath(tool), &state)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// LoadUniversal 讀取通用狀態 universal/shared_state.json；不存在時返回空映射
+func (m *Manager) LoadUniversal() (map[string]interface{}, error) {
+	universal := map[string]interface{}{}
+	if _, err := readJSON(m.universalPath(), &universal); err != nil {
+		return nil, err
+	}
+	return universal, nil
+}
+
+// Status 返回各工具的同步狀態
+func (m *Manager) Status() (*Status, error) {
+	status := &Status{
+		SyncEnabled:    Enabled(m.projectPath),
+		SupportedTools: SupportedTools,
+		ToolStatus:     make(map[string]string),
+		LastSyncTimes:  make(map[string]string),
+	}
+
+	for _, tool := range SupportedTools {
+		state, err := m.LoadState(tool)
+		switch {
+		case err != nil:
+			status.ToolStatus[tool] = "error"
+		case state == nil:
+			status.ToolStatus[tool] = "not_synced"
+		default:
+			status.ToolStatus[tool] = "active"
+			status.LastSyncTimes[tool] = state.Timestamp
+		}
+	}
+
+	if _, err := os.Stat(m.universalPath()); err == nil {
+		status.UniversalStateAvailable = true
+	}
+	handoff, err := m.LatestHandoff()
+	if err != nil {
+		return nil, err
+	}
+	status.LastHandoff = handoff
+	return status, nil
+}
+
+// updateUniversal 與 Python 實現相同地合並通用狀態，保留未知字段
+func (m *Manager) updateUniversal(tool string, full *ToolState) error {
+	universal, err := m.LoadUniversal()
+	if err != nil {
+		return err
+	}
+
+	toolStates, _ := universal["tool_states"].(map[string]interface{})
+	if toolStates == nil {
+		toolStates = map[string]interface{}{}
+	}
+	toolStates[tool] = map[string]interface{}{
+		"last_sync": full.Timestamp,
+		"version":   full.Version,
+		"status":    "active",
+	}
+
+	universal["last_update"] = timestamp(time.Now())
+	universal["last_update_source"] = tool
+	universal["project_info"] = valueOr(full.State["project_info"])
+	universal["workflow_state"] = valueOr(full.State["workflow_state"])
+	universal["shared_memory"] = valueOr(full.State["memory"])
+	universal["tool_states"] = toolStates
+	if handoff, ok := full.State[handoffKey]; ok {
+		universal[lastHandoffKey] = handoff
+	}
+
+	data, err := json.MarshalIndent(universal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode universal state: %w", err)
+	}
+	return fsutil.WriteFileAtomic(m.universalPath(), data, 0644)
+}
+
+// appendHistory 追加同步歷史；失敗不影響同步本身
+func (m *Manager) appendHistory(tool, action string, size int) {
+	f, err := os.OpenFile(filepath.Join(m.dir, "sync_history.jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	line, _ := json.Marshal(HistoryEntry{
+		Timestamp: timestamp(time.Now()),
+		Tool:      tool,
+		Action:    action,
+		DataSize:  size,
+		Success:   true,
+	})
+	f.Write(append(line, '\n'))
+}
+
+// History 讀取同步歷史，最早的在前
+func (m *Manager) History() ([]HistoryEntry, error) {
+	f, err := os.Open(filepath.Join(m.dir, "sync_history.jsonl"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// currentState 返回工具當前的狀態內容；沒有時由通用狀態適配生成
+func (m *Manager) currentState(tool string) map[string]interface{} {
+	if existing, err := m.LoadState(tool); err == nil && existing != nil && existing.State != nil {
+		return existing.State
+	}
+
+	universal, err := m.LoadUniversal()
+	if err != nil || len(universal) == 0 {
+		return m.defaultState(tool)
+	}
+	return map[string]interface{}{
+		"project_info":   valueOr(universal["project_info"]),
+		"workflow_state": valueOr(universal["workflow_state"]),
+		"memory":         valueOr(universal["shared_memory"]),
+		"adapted_for":    tool,
+	}
+}
+
+// defaultState 與 Python _generate_default_state 相同的默認狀態
+func (m *Manager) defaultState(tool string) map[string]interface{} {
+	state := map[string]interface{}{
+		"project_info": m.projectInfo(),
+		"current_session": map[string]interface{}{
+			"start_time": timestamp(time.Now()),
+			"tool":       tool,
+			"mode":       "development",
+			"context":    "universal_ai_coding_framework",
+		},
+		"workflow_state": map[string]interface{}{
+			"current_phase":    "initialized",
+			"completed_phases": []interface{}{},
+			"next_actions":     []interface{}{},
+		},
+		"memory": map[string]interface{}{
+			"decisions": []interface{}{},
+			"lessons":   []interface{}{},
+			"context":   map[string]interface{}{},
+		},
+	}
+
+	switch tool {
+	case "claude":
+		state["claude_specific"] = map[string]interface{}{
+			"subagents_enabled":   true,
+			"mcp_tools_available": true,
+			"output_style":        "orchestrator",
+		}
+	case "gemini":
+		state["gemini_specific"] = map[string]interface{}{
+			"context_window":  "1M_tokens",
+			"mcp_integration": "native",
+			"workflow_mode":   "addp",
+		}
+	case "cursor":
+		state["cursor_specific"] = map[string]interface{}{
+			"agent_mode":   true,
+			"mcp_config":   ".cursor/mcp.json",
+			"rules_system": "enabled",
+		}
+	}
+	return state
+}
+
+// projectInfo 依次從 .addp/metadata.json 與項目記憶中讀取項目信息
+func (m *Manager) projectInfo() map[string]interface{} {
+	for _, rel := range []string{"metadata.json", filepath.Join("memory", "context", "project_context.json")} {
+		var doc map[string]interface{}
+		if found, err := readJSON(filepath.Join(m.projectPath, ".addp", rel), &doc); err == nil && found {
+			if info, ok := doc["project_info"].(map[string]interface{}); ok {
+				return info
+			}
+			return map[string]interface{}{}
+		}
+	}
+
+	return map[string]interface{}{
+		"name":        filepath.Base(m.projectPath),
+		"path":        m.projectPath,
+		"framework":   "unknown",
+		"initialized": false,
+	}
+}
+
+func (m *Manager) statePath(tool string) string {
+	return filepath.Join(m.dir, tool, "current_state.json")
+}
+
+func (m *Manager) universalPath() string {
+	return filepath.Join(m.dir, universalTool, "shared_state.json")
+}
+
+func isSupported(tool string) bool {
+	for _, t := range SupportedTools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// readJSON 讀取 JSON 文件，文件不存在時返回 false
+func readJSON(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return true, nil
+}
+
+// valueOr 缺失的字段與 Python dict.get(key, {}) 一樣以空對象代替
+func valueOr(v interface{}) interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	return v
+}
+
+func timestamp(t time.Time) string {
+	return t.Format(timestampLayout)
+}
+
+// parseTimestamp 解析 Python 或 RFC 3339 格式的時間
+func parseTimestamp(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(timestampLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
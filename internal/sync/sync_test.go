@@ -0,0 +1,213 @@
+package sync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/terminal"
+)
+
+// newADDPProject 創建帶有 .addp 目錄的臨時項目
+func newADDPProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".addp"), 0755))
+	return dir
+}
+
+func TestManager_SaveStateLayout(t *testing.T) {
+	project := newADDPProject(t)
+	m := NewManager(project)
+
+	require.NoError(t, m.SaveState("gemini", nil))
+
+	state, err := m.LoadState("gemini")
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, "gemini", state.Tool)
+	assert.Equal(t, stateVersion, state.Version)
+	assert.Contains(t, state.State, "gemini_specific")
+	assert.Equal(t, "gemini", state.SyncMetadata["sync_source"])
+	_, err = parseTimestamp(state.Timestamp)
+	assert.NoError(t, err)
+
+	universal, err := m.LoadUniversal()
+	require.NoError(t, err)
+	assert.Equal(t, "gemini", universal["last_update_source"])
+	assert.Contains(t, universal["tool_states"], "gemini")
+	info := universal["project_info"].(map[string]interface{})
+	assert.Equal(t, filepath.Base(project), info["name"])
+
+	history, err := m.History()
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "save", history[0].Action)
+
+	assert.Error(t, m.SaveState("universal", nil))
+	assert.Error(t, m.SaveState("vim", nil))
+}
+
+func TestManager_ReadsPythonState(t *testing.T) {
+	project := newADDPProject(t)
+	m := NewManager(project)
+	data, err := os.ReadFile("testdata/shared_state.json")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(m.Dir(), "universal"), 0755))
+	require.NoError(t, os.WriteFile(m.universalPath(), data, 0644))
+
+	// 沒有工具狀態時由通用狀態適配，交接後保留 Python 寫入的字段
+	require.NoError(t, m.RecordHandoff(&Handoff{FromTool: "claude", Summary: "done"}))
+
+	universal, err := m.LoadUniversal()
+	require.NoError(t, err)
+	assert.Equal(t, "kept", universal["custom_field"])
+	assert.Equal(t, "2024-05-01T09:30:00.123456", universal["created"])
+	assert.Equal(t, "design", universal["workflow_state"].(map[string]interface{})["current_phase"])
+	assert.Equal(t, "fastapi", universal["project_info"].(map[string]interface{})["framework"])
+
+	state, err := m.LoadState("claude")
+	require.NoError(t, err)
+	assert.Equal(t, "claude", state.State["adapted_for"])
+}
+
+func TestManager_Handoff(t *testing.T) {
+	project := newADDPProject(t)
+	m := NewManager(project)
+
+	h, err := m.LatestHandoff()
+	require.NoError(t, err)
+	assert.Nil(t, h)
+	assert.Nil(t, PendingHandoff(project))
+
+	output := []string{"Reading files...", "", "Implemented login handler.", "TODO: add rate limiting"}
+	captured := m.CaptureHandoff("claude", "add login", output, 0, "sess-1")
+	assert.Equal(t, "Reading files...\nImplemented login handler.\nTODO: add rate limiting", captured.Summary)
+	require.NoError(t, m.RecordHandoff(captured))
+
+	pending := PendingHandoff(project)
+	require.NotNil(t, pending)
+	assert.Equal(t, "claude", pending.FromTool)
+	assert.Equal(t, "sess-1", pending.SessionID)
+
+	prompt := pending.ContextPrompt()
+	assert.Contains(t, prompt, "[Handoff from claude")
+	assert.Contains(t, prompt, "add login")
+	assert.Contains(t, prompt, "TODO: add rate limiting")
+
+	injected := InjectPrompt(project, "review the change")
+	assert.True(t, strings.HasSuffix(injected, "\n\nreview the change"))
+	assert.Contains(t, injected, "Implemented login handler.")
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	assert.True(t, status.SyncEnabled)
+	assert.Equal(t, "active", status.ToolStatus["claude"])
+	assert.Equal(t, "not_synced", status.ToolStatus["gemini"])
+	require.NotNil(t, status.LastHandoff)
+}
+
+func TestManager_ChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	project := newADDPProject(t)
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = project
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(project, "old.go"), []byte("package main\n"), 0644))
+	git("add", "old.go")
+	git("commit", "-q", "-m", "init")
+
+	// 重命名取新路徑，含空格與非 ASCII 字符的路徑保持原樣
+	git("mv", "old.go", "new.go")
+	require.NoError(t, os.WriteFile(filepath.Join(project, "my notes.md"), []byte("x\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "說明.md"), []byte("x\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".addp", "state.json"), []byte("{}"), 0644))
+
+	files := NewManager(project).changedFiles()
+	assert.ElementsMatch(t, []string{"new.go", "my notes.md", "說明.md"}, files)
+}
+
+func TestPendingHandoff_Stale(t *testing.T) {
+	project := newADDPProject(t)
+	m := NewManager(project)
+	require.NoError(t, m.RecordHandoff(&Handoff{
+		FromTool:  "gemini",
+		Summary:   "old",
+		CreatedAt: timestamp(time.Now().Add(-MaxHandoffAge - time.Hour)),
+	}))
+	assert.Nil(t, PendingHandoff(project))
+}
+
+func TestInjectPrompt_DisabledProject(t *testing.T) {
+	project := t.TempDir()
+	assert.Equal(t, "hello", InjectPrompt(project, "hello"))
+
+	config := terminal.TerminalConfig{Type: terminal.TypeClaudeCode}
+	Attach(&config, project, "")
+	assert.Empty(t, config.InitialPrompt)
+	assert.Nil(t, config.OnExit)
+	_, err := os.Stat(filepath.Join(project, ".addp"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSummarize_Truncates(t *testing.T) {
+	var output []string
+	for i := 0; i < 100; i++ {
+		output = append(output, strings.Repeat("x", 200))
+	}
+	summary := summarize(output)
+	assert.True(t, strings.HasPrefix(summary, "..."))
+	assert.LessOrEqual(t, len([]rune(summary)), summaryMaxChars+3)
+}
+
+func TestAttach_SwitchesTools(t *testing.T) {
+	project := newADDPProject(t)
+	tm := terminal.NewTerminalManager()
+
+	// Claude 會話結束時記錄交接摘要
+	claude := terminal.TerminalConfig{
+		Type:       terminal.TypeClaudeCode,
+		Name:       "claude",
+		WorkingDir: project,
+		Command:    []string{"sh", "-c", "echo 'implemented the parser, tests still failing'"},
+	}
+	Attach(&claude, project, "write the parser")
+	assert.Empty(t, claude.InitialPrompt)
+
+	done := make(chan struct{})
+	inner := claude.OnExit
+	claude.OnExit = func(t *terminal.Terminal) {
+		inner(t)
+		close(done)
+	}
+	require.NoError(t, tm.StartTerminal(claude))
+	defer tm.RemoveTerminal("claude")
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not exit")
+	}
+
+	// 下一個工具啟動時注入摘要
+	gemini := terminal.TerminalConfig{Type: terminal.TypeGeminiCLI, Name: "gemini", WorkingDir: project}
+	Attach(&gemini, project, "")
+	assert.Contains(t, gemini.InitialPrompt, "write the parser")
+	assert.Contains(t, gemini.InitialPrompt, "implemented the parser, tests still failing")
+
+	// 恢復會話時不注入
+	resumed := terminal.TerminalConfig{Type: terminal.TypeGeminiCLI, Resume: terminal.ResumeLatest}
+	Attach(&resumed, project, "")
+	assert.Empty(t, resumed.InitialPrompt)
+}
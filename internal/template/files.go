@@ -0,0 +1,140 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateExts 模板目錄中識別的文件擴展名
+var templateExts = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// variableRegex 匹配提示詞中的 {{.name}} 變量
+var variableRegex = regexp.MustCompile(`\{\{\.(\w+)\}\}`)
+
+// LoadDir 從目錄加載自定義模板，每個 YAML 或 JSON 文件一個模板，ID 默認為文件名；
+// 可同時加載多個目錄（如用戶模板與項目清單中的團隊模板），模板 ID 不能重複。
+// 加載結果整體替換上一次從目錄加載的模板；任一文件無效時返回錯誤並保留現有模板。
+// 目錄不存在或為空字符串時跳過，全部不存在時移除之前從目錄加載的模板。
+func (tm *TemplateManager) LoadDir(dirs ...string) error {
+	loaded := make(map[string]*QueryTemplate)
+	files := make(map[string]string)
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read template directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !templateExts[strings.ToLower(filepath.Ext(entry.Name()))] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			tmpl, err := readTemplateFile(path)
+			if err != nil {
+				return err
+			}
+			if other, ok := files[tmpl.ID]; ok {
+				return fmt.Errorf("%s: template %q is already defined in %s", path, tmpl.ID, other)
+			}
+			loaded[tmpl.ID] = tmpl
+			files[tmpl.ID] = path
+		}
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for id, path := range files {
+		if existing, ok := tm.templates[id]; ok && tm.files[id] == "" {
+			kind := "custom"
+			if existing.IsBuiltIn {
+				kind = "built-in"
+			}
+			return fmt.Errorf("%s: template %q conflicts with a %s template", path, id, kind)
+		}
+	}
+	for id := range tm.files {
+		delete(tm.templates, id)
+	}
+	for id, tmpl := range loaded {
+		tm.templates[id] = tmpl
+	}
+	tm.files = files
+	// 緩存鍵不能區分內容相近的模板，重新加載後清空
+	tm.templateCache = make(map[string]*template.Template)
+	return nil
+}
+
+// TemplateFile 返回從模板目錄加載的模板文件路徑
+func (tm *TemplateManager) TemplateFile(templateID string) (string, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	path, ok := tm.files[templateID]
+	return path, ok
+}
+
+// readTemplateFile 讀取並校驗單個模板文件；未聲明變量時從提示詞中提取
+func readTemplateFile(path string) (*QueryTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &QueryTemplate{}
+	if err := yaml.Unmarshal(data, tmpl); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse template: %w", path, err)
+	}
+
+	if strings.TrimSpace(tmpl.ID) == "" {
+		tmpl.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if tmpl.Name == "" {
+		tmpl.Name = tmpl.ID
+	}
+	if strings.TrimSpace(tmpl.Prompt) == "" {
+		return nil, fmt.Errorf("%s: prompt must not be empty", path)
+	}
+	if _, err := template.New(tmpl.ID).Parse(tmpl.Prompt); err != nil {
+		return nil, fmt.Errorf("%s: invalid prompt: %w", path, err)
+	}
+
+	used := make(map[string]bool)
+	for _, match := range variableRegex.FindAllStringSubmatch(tmpl.Prompt, -1) {
+		used[match[1]] = true
+	}
+	if len(tmpl.Variables) == 0 {
+		for name := range used {
+			tmpl.Variables = append(tmpl.Variables, name)
+		}
+		sort.Strings(tmpl.Variables)
+	}
+	declared := make(map[string]bool)
+	for _, name := range tmpl.Variables {
+		declared[name] = true
+	}
+	for name := range used {
+		if !declared[name] {
+			return nil, fmt.Errorf("%s: variable %q is used in the prompt but not declared in variables", path, name)
+		}
+	}
+
+	tmpl.UpdatedAt = time.Now()
+	if info, err := os.Stat(path); err == nil {
+		tmpl.UpdatedAt = info.ModTime()
+	}
+	if tmpl.CreatedAt.IsZero() {
+		tmpl.CreatedAt = tmpl.UpdatedAt
+	}
+	tmpl.IsBuiltIn = false
+	return tmpl, nil
+}
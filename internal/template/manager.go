@@ -2,7 +2,6 @@ package template
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 	"text/template"
 	"time"
@@ -349,8 +348,7 @@ func (tm *TemplateManager) renderTemplate(promptTemplate string, data map[string
 // validateTemplateVariables 驗證模板變量
 func (tm *TemplateManager) validateTemplateVariables(tmpl *QueryTemplate) bool {
 	// 從模板中提取變量
-	re := regexp.MustCompile(`\{\{\.(\w+)\}\}`)
-	matches := re.FindAllStringSubmatch(tmpl.Prompt, -1)
+	matches := variableRegex.FindAllStringSubmatch(tmpl.Prompt, -1)
 
 	templateVars := make(map[string]bool)
 	for _, match := range matches {
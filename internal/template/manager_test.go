@@ -1,6 +1,8 @@
 package template
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -253,6 +255,62 @@ func TestTemplateManager_CustomTemplate(t *testing.T) {
 	assert.Contains(t, result.OptimizedPrompt, "Go")
 }
 
+func TestTemplateManager_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("review.yaml", "name: Review\nprompt: \"Review {{.query}} in {{.language}}\"\n")
+	write("notes.txt", "ignored")
+	manager := NewTemplateManager()
+	builtIn := len(manager.GetAvailableTemplates())
+
+	require.NoError(t, manager.LoadDir(dir))
+	tmpl, ok := manager.GetTemplate("review")
+	require.True(t, ok)
+	assert.Equal(t, []string{"language", "query"}, tmpl.Variables, "variables are taken from the prompt when omitted")
+	assert.Len(t, manager.GetAvailableTemplates(), builtIn+1)
+	result, err := manager.ApplyTemplate("review", "main.go", map[string]string{"language": "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, "Review main.go in Go", result.OptimizedPrompt)
+
+	// 內容變化後重新加載，不使用舊的編譯緩存
+	write("review.yaml", "name: Review\nprompt: \"Check {{.query}} in {{.language}}\"\n")
+	require.NoError(t, manager.LoadDir(dir))
+	result, err = manager.ApplyTemplate("review", "main.go", map[string]string{"language": "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, "Check main.go in Go", result.OptimizedPrompt)
+
+	// 無效文件整體拒絕，保留上一次加載的模板
+	write("broken.json", `{"id": "broken", "prompt": "{{.query"}`)
+	err = manager.LoadDir(dir)
+	assert.ErrorContains(t, err, "broken.json: invalid prompt")
+	_, ok = manager.GetTemplate("review")
+	assert.True(t, ok)
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.json")))
+
+	write("coding.yaml", "prompt: \"{{.query}}\"\n")
+	assert.ErrorContains(t, manager.LoadDir(dir), "conflicts with a built-in template")
+	require.NoError(t, os.Remove(filepath.Join(dir, "coding.yaml")))
+
+	// 同時加載項目的團隊模板目錄，ID 不能重複
+	team := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(team, "release.yaml"), []byte("prompt: \"Release notes for {{.query}}\"\n"), 0644))
+	require.NoError(t, manager.LoadDir(dir, team, ""))
+	path, ok := manager.TemplateFile("release")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(team, "release.yaml"), path)
+	require.NoError(t, os.WriteFile(filepath.Join(team, "review.yaml"), []byte("prompt: \"{{.query}}\"\n"), 0644))
+	assert.ErrorContains(t, manager.LoadDir(dir, team), `template "review" is already defined in `+filepath.Join(dir, "review.yaml"))
+
+	// 文件刪除後對應模板隨之移除
+	require.NoError(t, os.Remove(filepath.Join(dir, "review.yaml")))
+	require.NoError(t, manager.LoadDir(dir))
+	_, ok = manager.GetTemplate("review")
+	assert.False(t, ok)
+	assert.Len(t, manager.GetAvailableTemplates(), builtIn)
+}
+
 func TestTemplateManager_ConcurrentAccess(t *testing.T) {
 	manager := NewTemplateManager()
 
@@ -53,6 +53,7 @@ type TemplateManager struct {
 	templateCache map[string]*template.Template // 已編譯的模板緩存
 	stats        map[string]*TemplateStats     // 使用統計
 	config       *TemplateConfig               // 配置
+	files        map[string]string             // 從模板目錄加載的模板 ID 及其文件
 	mu           sync.RWMutex
 }
 
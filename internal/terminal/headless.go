@@ -0,0 +1,47 @@
+package terminal
+
+import "fmt"
+
+// HeadlessCommand 返回以非交互方式執行單個提示詞的命令，輸出為純文本
+func HeadlessCommand(t TerminalType, prompt string, yoloMode bool) ([]string, error) {
+	switch t {
+	case TypeClaudeCode:
+		cmd := []string{"claude", "-p", prompt}
+		if yoloMode {
+			cmd = append(cmd, "--dangerously-skip-permissions")
+		}
+		return cmd, nil
+	case TypeGeminiCLI:
+		cmd := []string{"gemini", "-p", prompt}
+		if yoloMode {
+			cmd = append(cmd, "--yolo")
+		}
+		return cmd, nil
+	case TypeCodex:
+		cmd := []string{"codex", "exec"}
+		if yoloMode {
+			cmd = append(cmd, "--dangerously-bypass-approvals-and-sandbox")
+		}
+		return append(cmd, prompt), nil
+	case TypeAider:
+		cmd := []string{"aider", "--message", prompt, "--no-pretty"}
+		if yoloMode {
+			cmd = append(cmd, "--yes-always")
+		}
+		return cmd, nil
+	default:
+		return nil, fmt.Errorf("headless mode not supported for terminal type %s", t.String())
+	}
+}
+
+// InitialPromptArgs 返回讓交互式會話以指定提示詞開始的參數；工具不支持時返回 false
+func InitialPromptArgs(t TerminalType, prompt string) ([]string, bool) {
+	switch t {
+	case TypeClaudeCode, TypeCodex:
+		return []string{prompt}, true
+	case TypeGeminiCLI:
+		return []string{"--prompt-interactive", prompt}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,308 @@
+package terminal
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// HealthState 表示健康檢查的結果等級
+type HealthState string
+
+const (
+	HealthOK        HealthState = "ok"        // 正常
+	HealthDegraded  HealthState = "degraded"  // 可用但存在問題
+	HealthUnhealthy HealthState = "unhealthy" // 不可用
+)
+
+// severity 返回狀態的嚴重程度，用於取最差結果
+func (s HealthState) severity() int {
+	switch s {
+	case HealthDegraded:
+		return 1
+	case HealthUnhealthy:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// worse 返回兩個狀態中較嚴重的一個
+func worse(a, b HealthState) HealthState {
+	if b.severity() > a.severity() {
+		return b
+	}
+	return a
+}
+
+// HealthOptions 健康檢查的時間閾值
+type HealthOptions struct {
+	StartTimeout    time.Duration // 啟動調用超過此時間仍未返回視為卡住
+	ReadyTimeout    time.Duration // 啟動後超過此時間仍未出現就緒標誌
+	ResponseTimeout time.Duration // 發送輸入後超過此時間仍無輸出
+	StopTimeout     time.Duration // 停止終端時等待進程回收的時間
+}
+
+// DefaultHealthOptions 返回默認的健康檢查閾值
+func DefaultHealthOptions() HealthOptions {
+	return HealthOptions{
+		StartTimeout:    10 * time.Second,
+		ReadyTimeout:    60 * time.Second,
+		ResponseTimeout: 2 * time.Minute,
+		StopTimeout:     5 * time.Second,
+	}
+}
+
+// HealthCheck 單項檢查結果
+type HealthCheck struct {
+	Name    string      `json:"name"`
+	State   HealthState `json:"state"`
+	Message string      `json:"message"`
+}
+
+// TerminalHealth 單個終端的健康狀態
+type TerminalHealth struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Status     string        `json:"status"`
+	State      HealthState   `json:"state"`
+	Ready      bool          `json:"ready"`
+	StartedAt  time.Time     `json:"started_at"`
+	LastOutput time.Time     `json:"last_output"`
+	ExitCode   int           `json:"exit_code"`
+	SessionID  string        `json:"session_id,omitempty"`
+	WorkingDir string        `json:"working_dir,omitempty"`
+	Checks     []HealthCheck `json:"checks"`
+}
+
+// HealthReport 終端管理器及其所有終端的健康報告
+type HealthReport struct {
+	State      HealthState      `json:"state"`
+	CheckedAt  time.Time        `json:"checked_at"`
+	Goroutines int              `json:"goroutines"`
+	Workers    int              `json:"workers"`
+	Checks     []HealthCheck    `json:"checks"`
+	Terminals  []TerminalHealth `json:"terminals"`
+}
+
+// Summary 返回適合狀態欄顯示的單行摘要
+func (r *HealthReport) Summary() string {
+	counts := make(map[HealthState]int)
+	for _, th := range r.Terminals {
+		counts[th.State]++
+	}
+
+	summary := fmt.Sprintf("%s: %d terminals", r.State, len(r.Terminals))
+	if n := counts[HealthDegraded]; n > 0 {
+		summary += fmt.Sprintf(", %d degraded", n)
+	}
+	if n := counts[HealthUnhealthy]; n > 0 {
+		summary += fmt.Sprintf(", %d unhealthy", n)
+	}
+	return summary
+}
+
+// Issues 返回所有非正常檢查項的描述
+func (r *HealthReport) Issues() []string {
+	var issues []string
+	for _, check := range r.Checks {
+		if check.State != HealthOK {
+			issues = append(issues, fmt.Sprintf("manager: %s: %s", check.Name, check.Message))
+		}
+	}
+	for _, th := range r.Terminals {
+		for _, check := range th.Checks {
+			if check.State != HealthOK {
+				issues = append(issues, fmt.Sprintf("%s: %s: %s", th.Name, check.Name, check.Message))
+			}
+		}
+	}
+	return issues
+}
+
+// SetHealthOptions 設置健康檢查閾值
+func (tm *TerminalManager) SetHealthOptions(opts HealthOptions) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.healthOptions = opts
+}
+
+// CheckHealth 生成管理器及所有終端的健康報告
+func (tm *TerminalManager) CheckHealth() *HealthReport {
+	now := time.Now()
+
+	tm.mu.RLock()
+	opts := tm.healthOptions
+	terminals := make([]*Terminal, 0, len(tm.terminals))
+	for _, terminal := range tm.terminals {
+		terminals = append(terminals, terminal)
+	}
+	tm.mu.RUnlock()
+
+	sort.Slice(terminals, func(i, j int) bool {
+		return terminals[i].Name < terminals[j].Name
+	})
+
+	report := &HealthReport{
+		State:      HealthOK,
+		CheckedAt:  now,
+		Goroutines: runtime.NumGoroutine(),
+		Workers:    int(atomic.LoadInt64(&tm.workers)),
+		Terminals:  make([]TerminalHealth, 0, len(terminals)),
+	}
+
+	report.Checks = append(report.Checks, tm.checkStuckStarts(now, opts), tm.checkLeaks(terminals))
+	for _, check := range report.Checks {
+		report.State = worse(report.State, check.State)
+	}
+
+	// 單個終端的問題只會讓管理器降級，而不是不可用
+	for _, terminal := range terminals {
+		th := checkTerminal(terminal, now, opts)
+		if th.State != HealthOK {
+			report.State = worse(report.State, HealthDegraded)
+		}
+		report.Terminals = append(report.Terminals, th)
+	}
+
+	return report
+}
+
+// checkStuckStarts 檢查長時間未返回的啟動調用
+func (tm *TerminalManager) checkStuckStarts(now time.Time, opts HealthOptions) HealthCheck {
+	tm.startMu.Lock()
+	defer tm.startMu.Unlock()
+
+	var stuck []string
+	for name, since := range tm.pendingStarts {
+		if now.Sub(since) > opts.StartTimeout {
+			stuck = append(stuck, name)
+		}
+	}
+	if len(stuck) == 0 {
+		return HealthCheck{Name: "stuck_starts", State: HealthOK, Message: "no stuck starts"}
+	}
+
+	sort.Strings(stuck)
+	return HealthCheck{
+		Name:    "stuck_starts",
+		State:   HealthUnhealthy,
+		Message: fmt.Sprintf("start not returned after %s: %s", opts.StartTimeout, strings.Join(stuck, ", ")),
+	}
+}
+
+// checkLeaks 檢查已停止但監控 goroutine 仍未退出的終端
+func (tm *TerminalManager) checkLeaks(terminals []*Terminal) HealthCheck {
+	var leaked []string
+	for _, terminal := range terminals {
+		if terminal.GetStatus() == StatusStopped && terminal.done != nil && !terminal.Exited() {
+			leaked = append(leaked, terminal.Name)
+		}
+	}
+	if len(leaked) == 0 {
+		return HealthCheck{Name: "goroutine_leaks", State: HealthOK, Message: "no leaked monitors"}
+	}
+
+	return HealthCheck{
+		Name:    "goroutine_leaks",
+		State:   HealthUnhealthy,
+		Message: fmt.Sprintf("monitor still running after stop: %s", strings.Join(leaked, ", ")),
+	}
+}
+
+// checkTerminal 檢查單個終端的進程、輸入、就緒與輸出狀態
+func checkTerminal(terminal *Terminal, now time.Time, opts HealthOptions) TerminalHealth {
+	status := terminal.GetStatus()
+	exited := terminal.Exited()
+
+	terminal.mu.RLock()
+	th := TerminalHealth{
+		Name:       terminal.Name,
+		Type:       terminal.Type.String(),
+		Status:     status.String(),
+		State:      HealthOK,
+		Ready:      terminal.ready,
+		StartedAt:  terminal.startedAt,
+		LastOutput: terminal.lastOutput,
+		ExitCode:   -1,
+		SessionID:  terminal.sessionID,
+	}
+	if terminal.Process != nil {
+		th.WorkingDir = terminal.Process.Dir
+	}
+	if exited {
+		th.ExitCode = terminal.exitCode
+	}
+	stdinAvailable := terminal.Stdin != nil
+	stdinErr := terminal.stdinErr
+	patterns := len(terminal.readyPatterns)
+	lastInput := time.Unix(terminal.LastUsed, 0)
+	terminal.mu.RUnlock()
+
+	// 已停止的終端不再做進一步檢查
+	if status == StatusStopped {
+		th.Checks = []HealthCheck{{Name: "process", State: HealthOK, Message: "stopped"}}
+		return th
+	}
+
+	// 進程存活
+	switch {
+	case exited:
+		th.Checks = append(th.Checks, HealthCheck{Name: "process", State: HealthUnhealthy,
+			Message: fmt.Sprintf("process exited with code %d", th.ExitCode)})
+	case terminal.Process == nil || terminal.Process.Process == nil:
+		th.Checks = append(th.Checks, HealthCheck{Name: "process", State: HealthUnhealthy, Message: "process not started"})
+	default:
+		th.Checks = append(th.Checks, HealthCheck{Name: "process", State: HealthOK,
+			Message: fmt.Sprintf("pid %d alive", terminal.Process.Process.Pid)})
+	}
+
+	// 標準輸入可寫
+	switch {
+	case !stdinAvailable:
+		th.Checks = append(th.Checks, HealthCheck{Name: "stdin", State: HealthUnhealthy, Message: "stdin not available"})
+	case exited:
+		th.Checks = append(th.Checks, HealthCheck{Name: "stdin", State: HealthUnhealthy, Message: "stdin closed"})
+	case stdinErr != nil:
+		th.Checks = append(th.Checks, HealthCheck{Name: "stdin", State: HealthUnhealthy,
+			Message: fmt.Sprintf("last write failed: %v", stdinErr)})
+	default:
+		th.Checks = append(th.Checks, HealthCheck{Name: "stdin", State: HealthOK, Message: "writable"})
+	}
+
+	// 就緒狀態
+	switch {
+	case patterns == 0:
+		th.Checks = append(th.Checks, HealthCheck{Name: "readiness", State: HealthOK, Message: "no readiness patterns configured"})
+	case th.Ready:
+		th.Checks = append(th.Checks, HealthCheck{Name: "readiness", State: HealthOK, Message: "ready"})
+	case !th.StartedAt.IsZero() && now.Sub(th.StartedAt) > opts.ReadyTimeout:
+		th.Checks = append(th.Checks, HealthCheck{Name: "readiness", State: HealthDegraded,
+			Message: fmt.Sprintf("not ready after %s", opts.ReadyTimeout)})
+	default:
+		th.Checks = append(th.Checks, HealthCheck{Name: "readiness", State: HealthOK, Message: "waiting for readiness"})
+	}
+
+	// 最近輸出：只有在發送輸入後長時間無響應才視為異常
+	switch {
+	case th.LastOutput.IsZero() && lastInput.After(th.StartedAt) && now.Sub(lastInput) > opts.ResponseTimeout:
+		th.Checks = append(th.Checks, HealthCheck{Name: "output", State: HealthDegraded,
+			Message: fmt.Sprintf("no output %s after input", opts.ResponseTimeout)})
+	case th.LastOutput.IsZero():
+		th.Checks = append(th.Checks, HealthCheck{Name: "output", State: HealthOK, Message: "no output yet"})
+	case lastInput.After(th.LastOutput) && now.Sub(lastInput) > opts.ResponseTimeout:
+		th.Checks = append(th.Checks, HealthCheck{Name: "output", State: HealthDegraded,
+			Message: fmt.Sprintf("no output since input %s ago", now.Sub(lastInput).Round(time.Second))})
+	default:
+		th.Checks = append(th.Checks, HealthCheck{Name: "output", State: HealthOK,
+			Message: fmt.Sprintf("last output %s ago", now.Sub(th.LastOutput).Round(time.Second))})
+	}
+
+	for _, check := range th.Checks {
+		th.State = worse(th.State, check.State)
+	}
+	return th
+}
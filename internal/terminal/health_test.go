@@ -0,0 +1,174 @@
+package terminal
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findCheck 在檢查列表中查找指定名稱的檢查
+func findCheck(checks []HealthCheck, name string) (HealthCheck, bool) {
+	for _, check := range checks {
+		if check.Name == name {
+			return check, true
+		}
+	}
+	return HealthCheck{}, false
+}
+
+func TestTerminalManager_CheckHealth_Empty(t *testing.T) {
+	manager := NewTerminalManager()
+
+	report := manager.CheckHealth()
+	assert.Equal(t, HealthOK, report.State)
+	assert.Empty(t, report.Terminals)
+	assert.Empty(t, report.Issues())
+	assert.Equal(t, "ok: 0 terminals", report.Summary())
+}
+
+func TestTerminalManager_CheckHealth_RunningTerminal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires cat")
+	}
+
+	manager := NewTerminalManager()
+	err := manager.StartTerminal(TerminalConfig{
+		Type:          TypeCustom,
+		Name:          "echo-back",
+		Command:       []string{"cat"},
+		ReadyPatterns: []string{"ready!"},
+	})
+	require.NoError(t, err)
+	defer manager.StopTerminal("echo-back")
+
+	// 就緒前仍視為健康
+	report := manager.CheckHealth()
+	require.Len(t, report.Terminals, 1)
+	assert.Equal(t, HealthOK, report.Terminals[0].State)
+	assert.False(t, report.Terminals[0].Ready)
+
+	require.NoError(t, manager.SendCommand("echo-back", "ready!"))
+
+	terminal, _ := manager.GetTerminal("echo-back")
+	assert.Eventually(t, terminal.IsReady, 2*time.Second, 10*time.Millisecond)
+	assert.Contains(t, terminal.Output(), "ready!")
+
+	report = manager.CheckHealth()
+	assert.Equal(t, HealthOK, report.State)
+	assert.Equal(t, 1, report.Workers)
+	check, ok := findCheck(report.Terminals[0].Checks, "readiness")
+	require.True(t, ok)
+	assert.Equal(t, "ready", check.Message)
+}
+
+func TestTerminalManager_CheckHealth_ExitedProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires false")
+	}
+
+	manager := NewTerminalManager()
+	err := manager.StartTerminal(TerminalConfig{
+		Type:    TypeCustom,
+		Name:    "exits",
+		Command: []string{"false"},
+	})
+	require.NoError(t, err)
+
+	terminal, _ := manager.GetTerminal("exits")
+	select {
+	case <-terminal.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("process did not exit")
+	}
+
+	report := manager.CheckHealth()
+	assert.Equal(t, HealthDegraded, report.State)
+	assert.True(t, manager.IsHealthy())
+
+	th := report.Terminals[0]
+	assert.Equal(t, HealthUnhealthy, th.State)
+	assert.Equal(t, 1, th.ExitCode)
+	check, _ := findCheck(th.Checks, "process")
+	assert.Equal(t, HealthUnhealthy, check.State)
+	assert.NotEmpty(t, report.Issues())
+
+	// 已退出的進程應可正常停止
+	require.NoError(t, manager.StopTerminal("exits"))
+	report = manager.CheckHealth()
+	assert.Equal(t, HealthOK, report.State)
+}
+
+func TestTerminalManager_CheckHealth_ReadyTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires cat")
+	}
+
+	manager := NewTerminalManager()
+	opts := DefaultHealthOptions()
+	opts.ReadyTimeout = 10 * time.Millisecond
+	manager.SetHealthOptions(opts)
+
+	err := manager.StartTerminal(TerminalConfig{
+		Type:          TypeCustom,
+		Name:          "never-ready",
+		Command:       []string{"cat"},
+		ReadyPatterns: []string{"never printed"},
+	})
+	require.NoError(t, err)
+	defer manager.StopTerminal("never-ready")
+
+	time.Sleep(20 * time.Millisecond)
+	report := manager.CheckHealth()
+	assert.Equal(t, HealthDegraded, report.Terminals[0].State)
+	check, _ := findCheck(report.Terminals[0].Checks, "readiness")
+	assert.Equal(t, HealthDegraded, check.State)
+}
+
+func TestTerminalManager_CheckHealth_StuckStart(t *testing.T) {
+	manager := NewTerminalManager()
+	manager.pendingStarts["slow"] = time.Now().Add(-time.Minute)
+
+	report := manager.CheckHealth()
+	assert.Equal(t, HealthUnhealthy, report.State)
+	assert.False(t, manager.IsHealthy())
+
+	check, ok := findCheck(report.Checks, "stuck_starts")
+	require.True(t, ok)
+	assert.Contains(t, check.Message, "slow")
+}
+
+func TestTerminalManager_CheckHealth_LeakedMonitor(t *testing.T) {
+	manager := NewTerminalManager()
+	manager.terminals["leaky"] = &Terminal{
+		Name:   "leaky",
+		Type:   TypeCustom,
+		Status: StatusStopped,
+		done:   make(chan struct{}),
+	}
+
+	report := manager.CheckHealth()
+	assert.Equal(t, HealthUnhealthy, report.State)
+	check, _ := findCheck(report.Checks, "goroutine_leaks")
+	assert.Contains(t, check.Message, "leaky")
+}
+
+func TestTerminalManager_StartingNameReserved(t *testing.T) {
+	manager := NewTerminalManager()
+	// 啟動期間不持有管理器鎖，同名終端由 starting 佔用
+	manager.starting["busy"] = true
+
+	err := manager.StartTerminal(TerminalConfig{Name: "busy", Type: TypeCustom, Command: []string{"true"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	report := manager.CheckHealth()
+	assert.Empty(t, report.Terminals)
+
+	// 啟動失敗後釋放名稱
+	err = manager.StartTerminal(TerminalConfig{Name: "missing", Type: TypeCustom, Command: []string{"/nonexistent/ai-launcher-test"}})
+	require.Error(t, err)
+	assert.NotContains(t, manager.starting, "missing")
+}
@@ -4,23 +4,34 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // TerminalManager 實現 Manager 接口
 type TerminalManager struct {
 	terminals map[string]*Terminal
+	starting  map[string]bool // 正在啟動、尚未加入 terminals 的終端名稱
 	mu        sync.RWMutex
-	healthy   bool
+
+	// 健康檢查相關狀態
+	healthOptions HealthOptions
+	pendingStarts map[string]time.Time // 尚未返回的啟動調用
+	startMu       sync.Mutex
+	workers       int64 // 運行中的監控 goroutine 數量
 }
 
 // NewTerminalManager 創建一個新的終端管理器
 func NewTerminalManager() *TerminalManager {
 	return &TerminalManager{
-		terminals: make(map[string]*Terminal),
-		healthy:   true,
+		terminals:     make(map[string]*Terminal),
+		starting:      make(map[string]bool),
+		healthOptions: DefaultHealthOptions(),
+		pendingStarts: make(map[string]time.Time),
 	}
 }
 
@@ -31,38 +42,43 @@ func (tm *TerminalManager) StartTerminal(config TerminalConfig) error {
 
 // StartTerminalWithContext 使用上下文啟動指定的終端
 func (tm *TerminalManager) StartTerminalWithContext(ctx context.Context, config TerminalConfig) error {
+	// 啟動進程最長可能等待數秒，期間只佔用名稱而不持有鎖，避免阻塞健康檢查與其他終端的操作
 	tm.mu.Lock()
-	defer tm.mu.Unlock()
-
-	// 檢查終端是否已存在
-	if _, exists := tm.terminals[config.Name]; exists {
+	if _, exists := tm.terminals[config.Name]; exists || tm.starting[config.Name] {
+		tm.mu.Unlock()
 		return fmt.Errorf("terminal '%s' already exists", config.Name)
 	}
+	tm.starting[config.Name] = true
+	tm.mu.Unlock()
+
+	added := false
+	defer func() {
+		if !added {
+			tm.mu.Lock()
+			delete(tm.starting, config.Name)
+			tm.mu.Unlock()
+		}
+	}()
 
 	// 創建新的終端實例
 	terminal := &Terminal{
-		Name:     config.Name,
-		Type:     config.Type,
-		Status:   StatusStarting,
-		LastUsed: time.Now().Unix(),
+		Name:          config.Name,
+		Type:          config.Type,
+		Status:        StatusStarting,
+		LastUsed:      time.Now().Unix(),
+		readyPatterns: config.ReadyPatterns,
+		done:          make(chan struct{}),
+		sessionID:     config.SessionID,
+		onOutput:      config.OnOutput,
+		onExit:        config.OnExit,
 	}
-
-	// 創建命令
-	cmd := tm.createCommand(config)
-	if cmd == nil {
-		return fmt.Errorf("failed to create command for terminal type %s", config.Type.String())
-	}
-
-	// 設置工作目錄
-	if config.WorkingDir != "" {
-		cmd.Dir = config.WorkingDir
+	if config.Resume != "" && config.Resume != ResumeLatest {
+		terminal.sessionID = config.Resume
 	}
 
-	// 設置環境變量
-	if config.Environment != nil {
-		for key, value := range config.Environment {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
-		}
+	cmd, pendingInput, err := tm.BuildCommand(config)
+	if err != nil {
+		return err
 	}
 
 	terminal.Process = cmd
@@ -77,19 +93,38 @@ func (tm *TerminalManager) StartTerminalWithContext(ctx context.Context, config
 		return fmt.Errorf("failed to start process: %w", err)
 	}
 
-	// 添加到管理器
-	tm.terminals[config.Name] = terminal
 	terminal.SetStatus(StatusRunning)
+	if pendingInput != "" {
+		_, err := terminal.Stdin.WriteString(pendingInput + "\n")
+		if err == nil {
+			err = terminal.Stdin.Flush()
+		}
+		terminal.setStdinErr(err)
+	}
+
+	// 添加到管理器；寫入初始輸入後再公開，避免與其他調用者的輸入交錯
+	tm.mu.Lock()
+	delete(tm.starting, config.Name)
+	tm.terminals[config.Name] = terminal
+	tm.mu.Unlock()
+	added = true
+
+	// 監控輸出與進程退出
+	terminal.mu.Lock()
+	terminal.startedAt = time.Now()
+	terminal.mu.Unlock()
+	atomic.AddInt64(&tm.workers, 1)
+	go tm.monitorTerminal(terminal)
 
 	return nil
 }
 
 // StopTerminal 停止指定名稱的終端
 func (tm *TerminalManager) StopTerminal(name string) error {
-	tm.mu.Lock()
-	defer tm.mu.Unlock()
-
+	// 只在查找時持有鎖，等待進程退出期間不阻塞健康檢查與其他終端的操作
+	tm.mu.RLock()
 	terminal, exists := tm.terminals[name]
+	tm.mu.RUnlock()
 	if !exists {
 		return fmt.Errorf("terminal '%s' not found", name)
 	}
@@ -97,17 +132,20 @@ func (tm *TerminalManager) StopTerminal(name string) error {
 	// 設置停止狀態
 	terminal.SetStatus(StatusStopping)
 
-	// 停止進程
-	if terminal.Process != nil && terminal.Process.Process != nil {
+	// 停止進程（已自行退出的進程無需再殺）
+	if terminal.Process != nil && terminal.Process.Process != nil && !terminal.Exited() {
 		if err := terminal.Process.Process.Kill(); err != nil {
 			terminal.SetStatus(StatusError)
 			return fmt.Errorf("failed to kill process: %w", err)
 		}
 	}
 
-	// 等待進程結束
-	if terminal.Process != nil {
-		_ = terminal.Process.Wait()
+	// 等待監控 goroutine 回收進程；超時則由健康檢查報告洩漏
+	if terminal.done != nil {
+		select {
+		case <-terminal.done:
+		case <-time.After(tm.healthOptions.StopTimeout):
+		}
 	}
 
 	terminal.SetStatus(StatusStopped)
@@ -139,13 +177,53 @@ func (tm *TerminalManager) SendCommand(name string, command string) error {
 	}
 
 	if _, err := terminal.Stdin.WriteString(command + "\n"); err != nil {
+		terminal.setStdinErr(err)
 		return fmt.Errorf("failed to write command: %w", err)
 	}
 
 	if err := terminal.Stdin.Flush(); err != nil {
+		terminal.setStdinErr(err)
 		return fmt.Errorf("failed to flush command: %w", err)
 	}
 
+	terminal.setStdinErr(nil)
+	return nil
+}
+
+// CloseInput 關閉指定終端的標準輸入，讓讀取 stdin 的非交互命令收到 EOF
+func (tm *TerminalManager) CloseInput(name string) error {
+	tm.mu.RLock()
+	terminal, exists := tm.terminals[name]
+	tm.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("terminal '%s' not found", name)
+	}
+	if terminal.stdin == nil {
+		return fmt.Errorf("terminal '%s' stdin not available", name)
+	}
+	if err := terminal.stdin.Close(); err != nil {
+		return fmt.Errorf("failed to close stdin: %w", err)
+	}
+	return nil
+}
+
+// RemoveTerminal 停止並從管理器中移除指定名稱的終端
+func (tm *TerminalManager) RemoveTerminal(name string) error {
+	terminal, exists := tm.GetTerminal(name)
+	if !exists {
+		return fmt.Errorf("terminal '%s' not found", name)
+	}
+
+	if terminal.GetStatus() != StatusStopped {
+		if err := tm.StopTerminal(name); err != nil {
+			return err
+		}
+	}
+
+	tm.mu.Lock()
+	delete(tm.terminals, name)
+	tm.mu.Unlock()
 	return nil
 }
 
@@ -173,9 +251,38 @@ func (tm *TerminalManager) ListTerminals() []*Terminal {
 
 // IsHealthy 檢查終端管理器是否健康
 func (tm *TerminalManager) IsHealthy() bool {
-	tm.mu.RLock()
-	defer tm.mu.RUnlock()
-	return tm.healthy
+	return tm.CheckHealth().State != HealthUnhealthy
+}
+
+// BuildCommand 生成會話的啟動命令，包括恢復會話、預設會話 ID 與初始提示詞參數；
+// 工具不支持以參數傳入提示詞時，返回需要在啟動後寫入標準輸入的內容。
+// 命令行在前台運行工具時使用同一套參數
+func (tm *TerminalManager) BuildCommand(config TerminalConfig) (*exec.Cmd, string, error) {
+	// 創建命令
+	cmd := tm.createCommand(config)
+	if cmd == nil {
+		return nil, "", fmt.Errorf("failed to create command for terminal type %s", config.Type.String())
+	}
+
+	// 添加恢復會話或預設會話 ID 的參數
+	args, err := withSessionArgs(cmd.Args, config)
+	if err != nil {
+		return nil, "", err
+	}
+	cmd.Args = args
+
+	// 初始提示詞優先通過命令行參數傳入，工具不支持時在啟動後寫入標準輸入
+	pendingInput := ""
+	if config.InitialPrompt != "" {
+		if extra, ok := InitialPromptArgs(config.Type, config.InitialPrompt); ok {
+			cmd.Args = append(cmd.Args, extra...)
+		} else {
+			pendingInput = config.InitialPrompt
+		}
+	}
+
+	// 工作目錄與環境變量已由 createCommand 設置
+	return cmd, pendingInput, nil
 }
 
 // createCommand 創建對應類型的命令
@@ -208,6 +315,12 @@ func (tm *TerminalManager) createCommand(config TerminalConfig) *exec.Cmd {
 			cmd = exec.Command("cursor", "--cli")
 		case TypeAider:
 			cmd = exec.Command("aider")
+		case TypeCodex:
+			if config.YoloMode {
+				cmd = exec.Command("codex", "--dangerously-bypass-approvals-and-sandbox")
+			} else {
+				cmd = exec.Command("codex")
+			}
 		case TypeCustom:
 			// 對於測試或自定義命令
 			if config.YoloMode {
@@ -230,18 +343,14 @@ func (tm *TerminalManager) createCommand(config TerminalConfig) *exec.Cmd {
 		cmd.Dir = config.WorkingDir
 	}
 
-	// 設置環境變量
+	// 設置環境變量（在當前環境的基礎上追加，避免丟失 PATH 等變量）
 	if config.Environment != nil {
+		cmd.Env = os.Environ()
 		for key, value := range config.Environment {
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 		}
 	}
 
-	// 添加額外參數
-	if config.Args != nil {
-		cmd.Args = append(cmd.Args, config.Args...)
-	}
-
 	return cmd
 }
 
@@ -253,6 +362,7 @@ func (tm *TerminalManager) setupPipes(terminal *Terminal) error {
 		return fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
 	terminal.Stdin = bufio.NewWriter(stdin)
+	terminal.stdin = stdin
 
 	// 設置標準輸出管道
 	stdout, err := terminal.Process.StdoutPipe()
@@ -260,6 +370,8 @@ func (tm *TerminalManager) setupPipes(terminal *Terminal) error {
 		return fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 	terminal.Stdout = bufio.NewScanner(stdout)
+	terminal.Stdout.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	terminal.stdout = stdout
 
 	return nil
 }
@@ -273,7 +385,17 @@ func (tm *TerminalManager) startProcess(ctx context.Context, terminal *Terminal)
 	// 設置超時
 	done := make(chan error, 1)
 
+	// 記錄未返回的啟動調用，超時後仍卡住的會被健康檢查報告
+	tm.startMu.Lock()
+	tm.pendingStarts[terminal.Name] = time.Now()
+	tm.startMu.Unlock()
+
 	go func() {
+		defer func() {
+			tm.startMu.Lock()
+			delete(tm.pendingStarts, terminal.Name)
+			tm.startMu.Unlock()
+		}()
 		done <- terminal.Process.Start()
 	}()
 
@@ -291,4 +413,43 @@ func (tm *TerminalManager) startProcess(ctx context.Context, terminal *Terminal)
 		terminal.SetStatus(StatusError)
 		return fmt.Errorf("timeout starting process")
 	}
+}
+
+// monitorTerminal 讀取終端輸出直到 EOF，然後回收進程並記錄退出碼
+func (tm *TerminalManager) monitorTerminal(terminal *Terminal) {
+	defer atomic.AddInt64(&tm.workers, -1)
+
+	if terminal.Stdout != nil {
+		for terminal.Stdout.Scan() {
+			line := terminal.Stdout.Text()
+			terminal.recordOutput(line)
+			if terminal.onOutput != nil {
+				terminal.onOutput(line)
+			}
+		}
+		// 超長行等讀取錯誤會結束掃描；繼續排空管道，避免子進程寫滿管道後阻塞，Wait 無法返回
+		if err := terminal.Stdout.Err(); err != nil && terminal.stdout != nil {
+			terminal.mu.Lock()
+			terminal.outputErr = err
+			terminal.mu.Unlock()
+			terminal.recordOutput(fmt.Sprintf("[output truncated: %v]", err))
+			_, _ = io.Copy(io.Discard, terminal.stdout)
+		}
+	}
+
+	// 必須在讀取完所有輸出後才能調用 Wait
+	_ = terminal.Process.Wait()
+
+	terminal.mu.Lock()
+	terminal.exitCode = -1
+	if terminal.Process.ProcessState != nil {
+		terminal.exitCode = terminal.Process.ProcessState.ExitCode()
+	}
+	terminal.mu.Unlock()
+
+	close(terminal.done)
+
+	if terminal.onExit != nil {
+		terminal.onExit(terminal)
+	}
 }
\ No newline at end of file
@@ -1,8 +1,10 @@
 package terminal
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 
@@ -67,6 +69,137 @@ func TestTerminalManager_GetTerminal(t *testing.T) {
 	assert.Equal(t, TypeCustom, terminal.Type)
 }
 
+func TestTerminalManager_RemoveTerminal(t *testing.T) {
+	manager := NewTerminalManager()
+
+	err := manager.RemoveTerminal("non-existent")
+	assert.Error(t, err)
+
+	err = manager.StartTerminal(TerminalConfig{Type: TypeCustom, Name: "to-remove"})
+	require.NoError(t, err)
+
+	err = manager.RemoveTerminal("to-remove")
+	assert.NoError(t, err)
+
+	_, exists := manager.GetTerminal("to-remove")
+	assert.False(t, exists)
+
+	// 名稱可以重新使用
+	err = manager.StartTerminal(TerminalConfig{Type: TypeCustom, Name: "to-remove"})
+	assert.NoError(t, err)
+	manager.RemoveTerminal("to-remove")
+}
+
+func TestTerminalManager_CloseInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires cat")
+	}
+
+	manager := NewTerminalManager()
+	err := manager.StartTerminal(TerminalConfig{Type: TypeCustom, Name: "cat", Command: []string{"cat"}})
+	require.NoError(t, err)
+	defer manager.RemoveTerminal("cat")
+
+	terminal, _ := manager.GetTerminal("cat")
+	require.NoError(t, manager.CloseInput("cat"))
+
+	// cat 讀到 EOF 後自行退出
+	select {
+	case <-terminal.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("process did not exit after stdin was closed")
+	}
+	assert.Equal(t, 0, terminal.ExitCode())
+}
+
+func TestTerminalManager_LongOutputLines(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	manager := NewTerminalManager()
+	script := "head -c 100000 /dev/zero | tr '\\0' a; echo; head -c 17000000 /dev/zero | tr '\\0' b; echo; head -c 1000000 /dev/zero"
+	err := manager.StartTerminal(TerminalConfig{Type: TypeCustom, Name: "long", Command: []string{"sh", "-c", script}})
+	require.NoError(t, err)
+	defer manager.RemoveTerminal("long")
+
+	// 超過上限的行結束掃描後仍需排空管道，否則進程寫滿管道後無法退出
+	terminal, _ := manager.GetTerminal("long")
+	select {
+	case <-terminal.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("process did not exit after an overlong output line")
+	}
+	assert.Equal(t, 0, terminal.ExitCode())
+	output := terminal.Output()
+	require.NotEmpty(t, output)
+	assert.Len(t, output[0], 100000)
+	assert.Contains(t, output[len(output)-1], "output truncated")
+	assert.ErrorIs(t, terminal.OutputErr(), bufio.ErrTooLong)
+}
+
+func TestTerminalManager_InitialPromptAndOnExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires cat")
+	}
+
+	exited := make(chan []string, 1)
+	manager := NewTerminalManager()
+	err := manager.StartTerminal(TerminalConfig{
+		Type:          TypeCustom,
+		Name:          "cat",
+		Command:       []string{"cat"},
+		InitialPrompt: "continue from handoff",
+		OnExit:        func(t *Terminal) { exited <- t.Output() },
+	})
+	require.NoError(t, err)
+	defer manager.RemoveTerminal("cat")
+
+	// cat 不支持命令行提示詞，初始提示詞寫入標準輸入
+	require.NoError(t, manager.CloseInput("cat"))
+	select {
+	case output := <-exited:
+		assert.Equal(t, []string{"continue from handoff"}, output)
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnExit was not called")
+	}
+}
+
+func TestTerminalManager_BuildCommand(t *testing.T) {
+	dir := t.TempDir()
+	cmd, _, err := NewTerminalManager().BuildCommand(TerminalConfig{
+		Type:        TypeCustom,
+		Name:        "env",
+		Command:     []string{"env"},
+		WorkingDir:  dir,
+		Environment: map[string]string{"AI_LAUNCHER_TEST": "1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dir, cmd.Dir)
+
+	count := 0
+	for _, kv := range cmd.Env {
+		if kv == "AI_LAUNCHER_TEST=1" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count, "environment variables should be set once")
+	assert.Greater(t, len(cmd.Env), 1, "environment should extend the current one")
+}
+
+func TestInitialPromptArgs(t *testing.T) {
+	args, ok := InitialPromptArgs(TypeClaudeCode, "hi")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"hi"}, args)
+
+	args, ok = InitialPromptArgs(TypeGeminiCLI, "hi")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"--prompt-interactive", "hi"}, args)
+
+	_, ok = InitialPromptArgs(TypeAider, "hi")
+	assert.False(t, ok)
+}
+
 func TestTerminalManager_StopTerminal(t *testing.T) {
 	manager := NewTerminalManager()
 
@@ -92,6 +92,10 @@ func (pa *PlatformAdapter) CreateCommand(config TerminalConfig) *exec.Cmd {
 		cmdPath = pa.GetExecutablePath("aider")
 		args = []string{cmdPath}
 
+	case TypeCodex:
+		cmdPath = pa.GetExecutablePath("codex")
+		args = []string{cmdPath}
+
 	case TypeCustom:
 		// 對於測試，使用平台適當的測試命令
 		if pa.os == "windows" {
@@ -0,0 +1,105 @@
+package terminal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+)
+
+// ResumeLatest 表示繼續該項目最近一次的會話
+const ResumeLatest = "latest"
+
+// sessionIDPattern 從工具輸出中識別會話 ID（如 Codex 的 "session id: ..."）
+var sessionIDPattern = regexp.MustCompile(`(?i)session[ _-]?id:\s*([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})`)
+
+// NewSessionID 生成一個隨機的 UUID v4 會話 ID
+func NewSessionID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// SupportsSessionID 檢查工具是否允許啟動時預先指定會話 ID
+func SupportsSessionID(t TerminalType) bool {
+	return t == TypeClaudeCode
+}
+
+// SupportsResume 檢查工具是否支持恢復會話；byID 表示按 ID 恢復而不是繼續最近一次
+func SupportsResume(t TerminalType, byID bool) bool {
+	switch t {
+	case TypeClaudeCode, TypeGeminiCLI, TypeCodex:
+		return true
+	case TypeAider:
+		return !byID
+	default:
+		return false
+	}
+}
+
+// ResumeArgs 將恢復目標轉換為工具對應的命令行參數
+func ResumeArgs(t TerminalType, resume string) ([]string, error) {
+	if !SupportsResume(t, resume != ResumeLatest) {
+		return nil, fmt.Errorf("resuming session %q not supported for terminal type %s", resume, t.String())
+	}
+
+	switch t {
+	case TypeClaudeCode:
+		if resume == ResumeLatest {
+			return []string{"--continue"}, nil
+		}
+		return []string{"--resume", resume}, nil
+	case TypeGeminiCLI:
+		return []string{"--resume", resume}, nil
+	case TypeCodex:
+		// Codex 以子命令恢復會話
+		if resume == ResumeLatest {
+			return []string{"resume", "--last"}, nil
+		}
+		return []string{"resume", resume}, nil
+	default:
+		return []string{"--restore-chat-history"}, nil
+	}
+}
+
+// withSessionArgs 根據配置插入恢復會話或預設會話 ID 的參數
+func withSessionArgs(args []string, config TerminalConfig) ([]string, error) {
+	var extra []string
+	switch {
+	case config.Resume != "":
+		resumeArgs, err := ResumeArgs(config.Type, config.Resume)
+		if err != nil {
+			return nil, err
+		}
+		extra = resumeArgs
+	case config.SessionID != "":
+		if !SupportsSessionID(config.Type) {
+			return nil, fmt.Errorf("session id assignment not supported for terminal type %s", config.Type.String())
+		}
+		extra = []string{"--session-id", config.SessionID}
+	default:
+		return args, nil
+	}
+
+	if len(args) == 0 || config.Type != TypeCodex {
+		return append(append([]string(nil), args...), extra...), nil
+	}
+
+	// Codex 的 resume 子命令需緊跟在程序名（及 exec）之後
+	pos := 1
+	if len(args) > 1 && args[1] == "exec" {
+		pos = 2
+	}
+	result := append([]string(nil), args[:pos]...)
+	result = append(result, extra...)
+	return append(result, args[pos:]...), nil
+}
+
+// parseSessionID 從一行輸出中提取會話 ID
+func parseSessionID(line string) string {
+	if m := sessionIDPattern.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package terminal
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		termType TerminalType
+		resume   string
+		expected []string
+	}{
+		{"Claude latest", TypeClaudeCode, ResumeLatest, []string{"--continue"}},
+		{"Claude by ID", TypeClaudeCode, "abc", []string{"--resume", "abc"}},
+		{"Gemini latest", TypeGeminiCLI, ResumeLatest, []string{"--resume", "latest"}},
+		{"Codex latest", TypeCodex, ResumeLatest, []string{"resume", "--last"}},
+		{"Codex by ID", TypeCodex, "abc", []string{"resume", "abc"}},
+		{"Aider latest", TypeAider, ResumeLatest, []string{"--restore-chat-history"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := ResumeArgs(tt.termType, tt.resume)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+
+	_, err := ResumeArgs(TypeAider, "abc")
+	assert.Error(t, err)
+	_, err = ResumeArgs(TypeCursor, ResumeLatest)
+	assert.Error(t, err)
+}
+
+func TestWithSessionArgs(t *testing.T) {
+	args, err := withSessionArgs([]string{"claude", "--dangerously-skip-permissions"}, TerminalConfig{Type: TypeClaudeCode, SessionID: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"claude", "--dangerously-skip-permissions", "--session-id", "s1"}, args)
+
+	// 恢復優先於預設 ID
+	args, err = withSessionArgs([]string{"claude"}, TerminalConfig{Type: TypeClaudeCode, Resume: "r1", SessionID: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"claude", "--resume", "r1"}, args)
+
+	// Codex 子命令插入到程序名之後
+	args, err = withSessionArgs([]string{"codex", "--dangerously-bypass-approvals-and-sandbox"}, TerminalConfig{Type: TypeCodex, Resume: ResumeLatest})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"codex", "resume", "--last", "--dangerously-bypass-approvals-and-sandbox"}, args)
+
+	args, err = withSessionArgs([]string{"codex", "exec", "--json", "hi"}, TerminalConfig{Type: TypeCodex, Resume: "r1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"codex", "exec", "resume", "r1", "--json", "hi"}, args)
+
+	_, err = withSessionArgs([]string{"gemini"}, TerminalConfig{Type: TypeGeminiCLI, SessionID: "s1"})
+	assert.Error(t, err)
+
+	args, err = withSessionArgs([]string{"aider"}, TerminalConfig{Type: TypeAider})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aider"}, args)
+}
+
+func TestNewSessionID(t *testing.T) {
+	id := NewSessionID()
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+	assert.NotEqual(t, id, NewSessionID())
+}
+
+func TestTerminal_SessionIDFromOutput(t *testing.T) {
+	term := &Terminal{}
+	term.recordOutput("OpenAI Codex v0.46.0 (research preview)")
+	assert.Empty(t, term.SessionID())
+
+	term.recordOutput("session id: 0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b")
+	assert.Equal(t, "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b", term.SessionID())
+
+	// 已識別後不再被覆蓋
+	term.recordOutput("Session ID: 11111111-2222-3333-4444-555555555555")
+	assert.Equal(t, "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b", term.SessionID())
+}
+
+func TestTerminalManager_StartTerminalResumeUnsupported(t *testing.T) {
+	tm := NewTerminalManager()
+
+	err := tm.StartTerminal(TerminalConfig{
+		Type:    TypeCursor,
+		Name:    "cursor-resume",
+		Command: []string{"echo", "hi"},
+		Resume:  ResumeLatest,
+	})
+	assert.Error(t, err)
+	assert.Empty(t, tm.ListTerminals())
+}
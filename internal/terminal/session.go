@@ -0,0 +1,251 @@
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// maxStderrBytes 結構化會話保留的 stderr 上限
+const maxStderrBytes = 64 * 1024
+
+// SessionConfig 結構化會話配置
+type SessionConfig struct {
+	TerminalConfig
+
+	Prompt  string      // 非交互模式下發送的提示詞
+	OnEvent func(Event) // 每解析出一個事件時回調（在讀取 goroutine 中調用）
+}
+
+// StructuredSession 以流式 JSON 模式運行的非交互會話
+type StructuredSession struct {
+	Name string
+	Type TerminalType
+
+	cmd    *exec.Cmd
+	stderr limitedBuffer
+
+	mu        sync.RWMutex
+	events    []Event
+	text      strings.Builder
+	sessionID string
+	usage     UsageEvent
+	result    *ResultEvent
+	err       error
+	onEvent   func(Event)
+	done      chan struct{}
+}
+
+// StartStructuredSession 以流式 JSON 模式啟動工具並在後台解析事件
+func StartStructuredSession(ctx context.Context, config SessionConfig) (*StructuredSession, error) {
+	parser, err := NewStreamParser(config.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// 提供完整命令時直接使用，否則根據類型生成流式命令
+	args := config.Command
+	if len(args) == 0 {
+		args, err = StructuredCommand(config.Type, config.Prompt, config.YoloMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args = append(append([]string(nil), args...), config.Args...)
+	if args, err = withSessionArgs(args, config.TerminalConfig); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = config.WorkingDir
+	if config.Environment != nil {
+		cmd.Env = os.Environ()
+		for key, value := range config.Environment {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+
+	session := &StructuredSession{
+		Name:    config.Name,
+		Type:    config.Type,
+		cmd:     cmd,
+		onEvent: config.OnEvent,
+		done:    make(chan struct{}),
+	}
+	cmd.Stderr = &session.stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start process: %w", err)
+	}
+
+	go session.run(stdout, parser)
+	return session, nil
+}
+
+// run 解析輸出直到結束，然後回收進程
+func (s *StructuredSession) run(stdout io.Reader, parser StreamParser) {
+	parseErr := ParseStream(stdout, parser, s.handle)
+	waitErr := s.cmd.Wait()
+
+	s.mu.Lock()
+	switch {
+	case waitErr != nil:
+		s.err = fmt.Errorf("process failed: %w: %s", waitErr, strings.TrimSpace(s.stderr.String()))
+	case parseErr != nil:
+		s.err = parseErr
+	case s.result == nil:
+		s.err = fmt.Errorf("stream ended without result")
+	}
+	s.mu.Unlock()
+
+	close(s.done)
+}
+
+// handle 記錄事件並更新會話狀態
+func (s *StructuredSession) handle(event Event) {
+	s.mu.Lock()
+	switch e := event.(type) {
+	case *InitEvent:
+		if e.SessionID != "" {
+			s.sessionID = e.SessionID
+		}
+	case *TextEvent:
+		s.text.WriteString(e.Text)
+		if !e.Delta {
+			s.text.WriteString("\n")
+		}
+	case *UsageEvent:
+		s.usage.Add(e)
+	case *ResultEvent:
+		// 部分工具的結果不包含會話 ID 或最終文本，使用已收集的內容補全
+		if e.SessionID == "" {
+			e.SessionID = s.sessionID
+		} else {
+			s.sessionID = e.SessionID
+		}
+		if e.Text == "" && !e.IsError {
+			e.Text = strings.TrimSpace(s.text.String())
+		}
+		if e.CostUSD == 0 {
+			e.CostUSD = s.usage.CostUSD
+		}
+		s.result = e
+	}
+	s.events = append(s.events, event)
+	onEvent := s.onEvent
+	s.mu.Unlock()
+
+	if onEvent != nil {
+		onEvent(event)
+	}
+}
+
+// Done 返回會話結束後關閉的通道
+func (s *StructuredSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait 等待會話結束並返回最終結果
+func (s *StructuredSession) Wait() (*ResultEvent, error) {
+	<-s.done
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.result != nil && s.err == nil && s.result.IsError {
+		return s.result, fmt.Errorf("session reported error: %s", s.result.Text)
+	}
+	return s.result, s.err
+}
+
+// Stop 終止會話進程
+func (s *StructuredSession) Stop() error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+	if s.cmd.Process == nil {
+		return nil
+	}
+	if err := s.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("failed to kill process: %w", err)
+	}
+	<-s.done
+	return nil
+}
+
+// Events 返回目前為止解析出的所有事件
+func (s *StructuredSession) Events() []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Event(nil), s.events...)
+}
+
+// SessionID 返回工具報告的會話 ID
+func (s *StructuredSession) SessionID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessionID
+}
+
+// Usage 返回累計的 token 用量與費用
+func (s *StructuredSession) Usage() UsageEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.usage
+}
+
+// Text 返回助手輸出的全部文本
+func (s *StructuredSession) Text() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return strings.TrimSpace(s.text.String())
+}
+
+// FileEdits 返回會話中修改過的文件
+func (s *StructuredSession) FileEdits() []*FileEditEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var edits []*FileEditEvent
+	for _, event := range s.events {
+		if edit, ok := event.(*FileEditEvent); ok {
+			edits = append(edits, edit)
+		}
+	}
+	return edits
+}
+
+// limitedBuffer 只保留前 maxStderrBytes 字節的並發安全緩衝區
+type limitedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if remaining := maxStderrBytes - b.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
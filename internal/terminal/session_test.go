@@ -0,0 +1,92 @@
+package terminal
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredSession_ReplaysFixture(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires cat")
+	}
+
+	var (
+		mu    sync.Mutex
+		kinds []EventKind
+	)
+	session, err := StartStructuredSession(context.Background(), SessionConfig{
+		TerminalConfig: TerminalConfig{
+			Type:    TypeCodex,
+			Name:    "codex-fixture",
+			Command: []string{"cat", filepath.Join("testdata", "codex_stream.jsonl")},
+		},
+		OnEvent: func(e Event) {
+			mu.Lock()
+			kinds = append(kinds, e.Kind())
+			mu.Unlock()
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := session.Wait()
+	require.NoError(t, err)
+
+	// Codex 的結果不含會話 ID 與文本，應由會話補全
+	assert.Equal(t, "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b", result.SessionID)
+	assert.Equal(t, "Implemented the cache and removed the TODO.", result.Text)
+	assert.Equal(t, "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b", session.SessionID())
+	assert.Equal(t, 15230, session.Usage().InputTokens)
+	assert.Len(t, session.FileEdits(), 2)
+	assert.Len(t, session.Events(), len(kinds))
+	assert.Equal(t, EventInit, kinds[0])
+}
+
+func TestStructuredSession_ProcessFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires false")
+	}
+
+	session, err := StartStructuredSession(context.Background(), SessionConfig{
+		TerminalConfig: TerminalConfig{Type: TypeClaudeCode, Command: []string{"false"}},
+	})
+	require.NoError(t, err)
+
+	result, err := session.Wait()
+	assert.Nil(t, result)
+	assert.Error(t, err)
+}
+
+func TestStructuredSession_Stop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+
+	session, err := StartStructuredSession(context.Background(), SessionConfig{
+		TerminalConfig: TerminalConfig{Type: TypeGeminiCLI, Command: []string{"sleep", "10"}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, session.Stop())
+	select {
+	case <-session.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("session did not stop")
+	}
+	_, err = session.Wait()
+	assert.Error(t, err)
+}
+
+func TestStartStructuredSession_Unsupported(t *testing.T) {
+	_, err := StartStructuredSession(context.Background(), SessionConfig{
+		TerminalConfig: TerminalConfig{Type: TypeAider},
+		Prompt:         "hi",
+	})
+	assert.Error(t, err)
+}
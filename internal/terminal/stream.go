@@ -0,0 +1,408 @@
+package terminal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EventKind 結構化事件類型
+type EventKind string
+
+const (
+	EventInit     EventKind = "init"      // 會話開始
+	EventText     EventKind = "text"      // 助手文本
+	EventToolCall EventKind = "tool_call" // 工具調用
+	EventFileEdit EventKind = "file_edit" // 文件修改
+	EventUsage    EventKind = "usage"     // token 用量與費用
+	EventResult   EventKind = "result"    // 最終結果
+)
+
+// Event 由 CLI 流式輸出解析出的結構化事件
+type Event interface {
+	Kind() EventKind
+}
+
+// InitEvent 會話初始化
+type InitEvent struct {
+	SessionID string `json:"session_id"`
+	Model     string `json:"model"`
+}
+
+// TextEvent 助手輸出的文本
+type TextEvent struct {
+	Text  string `json:"text"`
+	Delta bool   `json:"delta"` // 是否為增量片段
+}
+
+// ToolCallEvent 工具調用
+type ToolCallEvent struct {
+	ID    string                 `json:"id"`
+	Name  string                 `json:"name"`
+	Input map[string]interface{} `json:"input"`
+}
+
+// FileEditEvent 文件修改
+type FileEditEvent struct {
+	Path      string `json:"path"`
+	Operation string `json:"operation"` // write、edit、update、add、delete 等
+	Tool      string `json:"tool"`
+}
+
+// UsageEvent token 用量與費用
+type UsageEvent struct {
+	InputTokens       int     `json:"input_tokens"`
+	OutputTokens      int     `json:"output_tokens"`
+	CachedInputTokens int     `json:"cached_input_tokens"`
+	CostUSD           float64 `json:"cost_usd"`
+}
+
+// ResultEvent 會話結束時的最終結果
+type ResultEvent struct {
+	SessionID  string  `json:"session_id"`
+	IsError    bool    `json:"is_error"`
+	Text       string  `json:"text"`
+	DurationMs int64   `json:"duration_ms"`
+	NumTurns   int     `json:"num_turns"`
+	CostUSD    float64 `json:"cost_usd"`
+}
+
+func (*InitEvent) Kind() EventKind     { return EventInit }
+func (*TextEvent) Kind() EventKind     { return EventText }
+func (*ToolCallEvent) Kind() EventKind { return EventToolCall }
+func (*FileEditEvent) Kind() EventKind { return EventFileEdit }
+func (*UsageEvent) Kind() EventKind    { return EventUsage }
+func (*ResultEvent) Kind() EventKind   { return EventResult }
+
+// Add 累加另一份用量
+func (u *UsageEvent) Add(other *UsageEvent) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CachedInputTokens += other.CachedInputTokens
+	u.CostUSD += other.CostUSD
+}
+
+// StreamParser 將 CLI 的一行 JSON 輸出解析為事件
+type StreamParser interface {
+	ParseLine(line []byte) ([]Event, error)
+}
+
+// NewStreamParser 返回終端類型對應的解析器
+func NewStreamParser(t TerminalType) (StreamParser, error) {
+	switch t {
+	case TypeClaudeCode:
+		return claudeStreamParser{}, nil
+	case TypeGeminiCLI:
+		return geminiStreamParser{}, nil
+	case TypeCodex:
+		return codexStreamParser{}, nil
+	default:
+		return nil, fmt.Errorf("structured output not supported for terminal type %s", t.String())
+	}
+}
+
+// StructuredCommand 返回以流式 JSON 模式運行工具的命令
+func StructuredCommand(t TerminalType, prompt string, yoloMode bool) ([]string, error) {
+	switch t {
+	case TypeClaudeCode:
+		cmd := []string{"claude", "-p", prompt, "--output-format", "stream-json", "--verbose"}
+		if yoloMode {
+			cmd = append(cmd, "--dangerously-skip-permissions")
+		}
+		return cmd, nil
+	case TypeGeminiCLI:
+		cmd := []string{"gemini", "-p", prompt, "--output-format", "stream-json"}
+		if yoloMode {
+			cmd = append(cmd, "--yolo")
+		}
+		return cmd, nil
+	case TypeCodex:
+		cmd := []string{"codex", "exec", "--json"}
+		if yoloMode {
+			cmd = append(cmd, "--dangerously-bypass-approvals-and-sandbox")
+		}
+		return append(cmd, prompt), nil
+	default:
+		return nil, fmt.Errorf("structured output not supported for terminal type %s", t.String())
+	}
+}
+
+// ParseStream 逐行解析流式輸出並回調每個事件；非 JSON 行會被跳過
+func ParseStream(r io.Reader, parser StreamParser, handle func(Event)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		events, err := parser.ParseLine(line)
+		if err != nil {
+			continue
+		}
+		for _, event := range events {
+			handle(event)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// 被視為文件修改的工具名稱及其操作
+var fileEditTools = map[string]string{
+	// Claude Code
+	"Edit":         "edit",
+	"MultiEdit":    "edit",
+	"Write":        "write",
+	"NotebookEdit": "edit",
+	// Gemini CLI
+	"write_file": "write",
+	"replace":    "edit",
+}
+
+// fileEditFromTool 若工具調用修改了文件，返回對應的文件修改事件
+func fileEditFromTool(name string, input map[string]interface{}) *FileEditEvent {
+	operation, ok := fileEditTools[name]
+	if !ok {
+		return nil
+	}
+	for _, key := range []string{"file_path", "notebook_path", "path", "absolute_path"} {
+		if path, ok := input[key].(string); ok && path != "" {
+			return &FileEditEvent{Path: path, Operation: operation, Tool: name}
+		}
+	}
+	return nil
+}
+
+// claudeStreamParser 解析 claude --output-format stream-json
+type claudeStreamParser struct{}
+
+type claudeUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+}
+
+type claudeLine struct {
+	Type      string `json:"type"`
+	Subtype   string `json:"subtype"`
+	SessionID string `json:"session_id"`
+	Model     string `json:"model"`
+	Message   struct {
+		Content []struct {
+			Type  string                 `json:"type"`
+			Text  string                 `json:"text"`
+			ID    string                 `json:"id"`
+			Name  string                 `json:"name"`
+			Input map[string]interface{} `json:"input"`
+		} `json:"content"`
+	} `json:"message"`
+	IsError      bool         `json:"is_error"`
+	Result       string       `json:"result"`
+	DurationMs   int64        `json:"duration_ms"`
+	NumTurns     int          `json:"num_turns"`
+	TotalCostUSD float64      `json:"total_cost_usd"`
+	Usage        *claudeUsage `json:"usage"`
+}
+
+func (claudeStreamParser) ParseLine(line []byte) ([]Event, error) {
+	var msg claudeLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	switch msg.Type {
+	case "system":
+		if msg.Subtype == "init" {
+			events = append(events, &InitEvent{SessionID: msg.SessionID, Model: msg.Model})
+		}
+	case "assistant":
+		for _, block := range msg.Message.Content {
+			switch block.Type {
+			case "text":
+				events = append(events, &TextEvent{Text: block.Text})
+			case "tool_use":
+				events = append(events, &ToolCallEvent{ID: block.ID, Name: block.Name, Input: block.Input})
+				if edit := fileEditFromTool(block.Name, block.Input); edit != nil {
+					events = append(events, edit)
+				}
+			}
+		}
+	case "result":
+		// 結果中的用量是整個會話的總計
+		if msg.Usage != nil {
+			events = append(events, &UsageEvent{
+				InputTokens:       msg.Usage.InputTokens + msg.Usage.CacheCreationInputTokens,
+				OutputTokens:      msg.Usage.OutputTokens,
+				CachedInputTokens: msg.Usage.CacheReadInputTokens,
+				CostUSD:           msg.TotalCostUSD,
+			})
+		}
+		events = append(events, &ResultEvent{
+			SessionID:  msg.SessionID,
+			IsError:    msg.IsError || (msg.Subtype != "" && msg.Subtype != "success"),
+			Text:       msg.Result,
+			DurationMs: msg.DurationMs,
+			NumTurns:   msg.NumTurns,
+			CostUSD:    msg.TotalCostUSD,
+		})
+	}
+	return events, nil
+}
+
+// geminiStreamParser 解析 gemini --output-format stream-json
+type geminiStreamParser struct{}
+
+type geminiLine struct {
+	Type       string                 `json:"type"`
+	SessionID  string                 `json:"session_id"`
+	Model      string                 `json:"model"`
+	Role       string                 `json:"role"`
+	Content    string                 `json:"content"`
+	Delta      bool                   `json:"delta"`
+	ToolName   string                 `json:"tool_name"`
+	ToolID     string                 `json:"tool_id"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Status     string                 `json:"status"`
+	Error      *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Stats *struct {
+		InputTokens  int   `json:"input_tokens"`
+		OutputTokens int   `json:"output_tokens"`
+		CachedTokens int   `json:"cached"`
+		DurationMs   int64 `json:"duration_ms"`
+		ToolCalls    int   `json:"tool_calls"`
+	} `json:"stats"`
+}
+
+func (geminiStreamParser) ParseLine(line []byte) ([]Event, error) {
+	var msg geminiLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	switch msg.Type {
+	case "init":
+		events = append(events, &InitEvent{SessionID: msg.SessionID, Model: msg.Model})
+	case "message":
+		if msg.Role == "assistant" {
+			events = append(events, &TextEvent{Text: msg.Content, Delta: msg.Delta})
+		}
+	case "tool_use":
+		events = append(events, &ToolCallEvent{ID: msg.ToolID, Name: msg.ToolName, Input: msg.Parameters})
+		if edit := fileEditFromTool(msg.ToolName, msg.Parameters); edit != nil {
+			events = append(events, edit)
+		}
+	case "result":
+		result := &ResultEvent{SessionID: msg.SessionID, IsError: msg.Status != "" && msg.Status != "success"}
+		if msg.Error != nil {
+			result.IsError = true
+			result.Text = msg.Error.Message
+		}
+		if msg.Stats != nil {
+			events = append(events, &UsageEvent{
+				InputTokens:       msg.Stats.InputTokens,
+				OutputTokens:      msg.Stats.OutputTokens,
+				CachedInputTokens: msg.Stats.CachedTokens,
+			})
+			result.DurationMs = msg.Stats.DurationMs
+		}
+		events = append(events, result)
+	}
+	return events, nil
+}
+
+// codexStreamParser 解析 codex exec --json
+type codexStreamParser struct{}
+
+type codexLine struct {
+	Type     string `json:"type"`
+	ThreadID string `json:"thread_id"`
+	Item     *struct {
+		ID               string `json:"id"`
+		Type             string `json:"type"`
+		Text             string `json:"text"`
+		Command          string `json:"command"`
+		AggregatedOutput string `json:"aggregated_output"`
+		ExitCode         *int   `json:"exit_code"`
+		Server           string `json:"server"`
+		Tool             string `json:"tool"`
+		Changes          []struct {
+			Path string `json:"path"`
+			Kind string `json:"kind"`
+		} `json:"changes"`
+	} `json:"item"`
+	Usage *struct {
+		InputTokens       int `json:"input_tokens"`
+		CachedInputTokens int `json:"cached_input_tokens"`
+		OutputTokens      int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Message string `json:"message"`
+}
+
+func (codexStreamParser) ParseLine(line []byte) ([]Event, error) {
+	var msg codexLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	switch msg.Type {
+	case "thread.started":
+		events = append(events, &InitEvent{SessionID: msg.ThreadID})
+	case "item.completed":
+		if msg.Item == nil {
+			break
+		}
+		switch msg.Item.Type {
+		case "agent_message":
+			events = append(events, &TextEvent{Text: msg.Item.Text})
+		case "command_execution":
+			events = append(events, &ToolCallEvent{
+				ID:    msg.Item.ID,
+				Name:  "shell",
+				Input: map[string]interface{}{"command": msg.Item.Command},
+			})
+		case "mcp_tool_call":
+			events = append(events, &ToolCallEvent{
+				ID:    msg.Item.ID,
+				Name:  msg.Item.Server + "." + msg.Item.Tool,
+				Input: map[string]interface{}{},
+			})
+		case "file_change":
+			for _, change := range msg.Item.Changes {
+				events = append(events, &FileEditEvent{Path: change.Path, Operation: change.Kind, Tool: "apply_patch"})
+			}
+		}
+	case "turn.completed":
+		if msg.Usage != nil {
+			events = append(events, &UsageEvent{
+				InputTokens:       msg.Usage.InputTokens,
+				OutputTokens:      msg.Usage.OutputTokens,
+				CachedInputTokens: msg.Usage.CachedInputTokens,
+			})
+		}
+		events = append(events, &ResultEvent{})
+	case "turn.failed", "error":
+		text := msg.Message
+		if msg.Error != nil {
+			text = msg.Error.Message
+		}
+		events = append(events, &ResultEvent{IsError: true, Text: strings.TrimSpace(text)})
+	}
+	return events, nil
+}
@@ -0,0 +1,200 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseFixture 解析 testdata 中錄製的流式輸出
+func parseFixture(t *testing.T, termType TerminalType, name string) []Event {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	parser, err := NewStreamParser(termType)
+	require.NoError(t, err)
+
+	var events []Event
+	require.NoError(t, ParseStream(f, parser, func(e Event) { events = append(events, e) }))
+	return events
+}
+
+// eventsOfKind 過濾指定類型的事件
+func eventsOfKind(events []Event, kind EventKind) []Event {
+	var filtered []Event
+	for _, e := range events {
+		if e.Kind() == kind {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func TestParseStream_Claude(t *testing.T) {
+	events := parseFixture(t, TypeClaudeCode, "claude_stream.jsonl")
+
+	init := eventsOfKind(events, EventInit)
+	require.Len(t, init, 1)
+	assert.Equal(t, "9f2c1e4a-7b3d-4c55-8e21-0d6a1b2c3d4e", init[0].(*InitEvent).SessionID)
+	assert.Equal(t, "claude-sonnet-4-5", init[0].(*InitEvent).Model)
+
+	texts := eventsOfKind(events, EventText)
+	require.Len(t, texts, 2)
+	assert.Equal(t, "I'll update the copyright header.", texts[0].(*TextEvent).Text)
+
+	calls := eventsOfKind(events, EventToolCall)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "Read", calls[0].(*ToolCallEvent).Name)
+	assert.Equal(t, "toolu_02", calls[1].(*ToolCallEvent).ID)
+
+	edits := eventsOfKind(events, EventFileEdit)
+	require.Len(t, edits, 1)
+	assert.Equal(t, &FileEditEvent{Path: "/work/app/main.go", Operation: "edit", Tool: "Edit"}, edits[0])
+
+	usage := eventsOfKind(events, EventUsage)
+	require.Len(t, usage, 1)
+	assert.Equal(t, 1222, usage[0].(*UsageEvent).InputTokens)
+	assert.Equal(t, 126, usage[0].(*UsageEvent).OutputTokens)
+	assert.Equal(t, 2500, usage[0].(*UsageEvent).CachedInputTokens)
+	assert.InDelta(t, 0.0213, usage[0].(*UsageEvent).CostUSD, 1e-9)
+
+	result := events[len(events)-1].(*ResultEvent)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Updated the header in main.go to 2025.", result.Text)
+	assert.Equal(t, 5, result.NumTurns)
+	assert.Equal(t, int64(8421), result.DurationMs)
+}
+
+func TestParseStream_Gemini(t *testing.T) {
+	events := parseFixture(t, TypeGeminiCLI, "gemini_stream.jsonl")
+
+	init := eventsOfKind(events, EventInit)
+	require.Len(t, init, 1)
+	assert.Equal(t, "gemini-2.5-pro", init[0].(*InitEvent).Model)
+
+	// 用戶消息不應被當作助手文本
+	texts := eventsOfKind(events, EventText)
+	require.Len(t, texts, 2)
+	assert.True(t, texts[0].(*TextEvent).Delta)
+
+	assert.Len(t, eventsOfKind(events, EventToolCall), 2)
+
+	edits := eventsOfKind(events, EventFileEdit)
+	require.Len(t, edits, 1)
+	assert.Equal(t, "/work/app/TODO.md", edits[0].(*FileEditEvent).Path)
+	assert.Equal(t, "write", edits[0].(*FileEditEvent).Operation)
+
+	usage := eventsOfKind(events, EventUsage)
+	require.Len(t, usage, 1)
+	assert.Equal(t, 4980, usage[0].(*UsageEvent).InputTokens)
+
+	result := events[len(events)-1].(*ResultEvent)
+	assert.False(t, result.IsError)
+	assert.Equal(t, int64(5280), result.DurationMs)
+}
+
+func TestParseStream_Codex(t *testing.T) {
+	events := parseFixture(t, TypeCodex, "codex_stream.jsonl")
+
+	init := eventsOfKind(events, EventInit)
+	require.Len(t, init, 1)
+	assert.Equal(t, "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b", init[0].(*InitEvent).SessionID)
+
+	// 推理內容與未完成的項目不產生事件
+	texts := eventsOfKind(events, EventText)
+	require.Len(t, texts, 1)
+	assert.Equal(t, "Implemented the cache and removed the TODO.", texts[0].(*TextEvent).Text)
+
+	calls := eventsOfKind(events, EventToolCall)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "shell", calls[0].(*ToolCallEvent).Name)
+
+	edits := eventsOfKind(events, EventFileEdit)
+	require.Len(t, edits, 2)
+	assert.Equal(t, "add", edits[1].(*FileEditEvent).Operation)
+
+	usage := eventsOfKind(events, EventUsage)
+	require.Len(t, usage, 1)
+	assert.Equal(t, 910, usage[0].(*UsageEvent).OutputTokens)
+
+	assert.Equal(t, EventResult, events[len(events)-1].Kind())
+}
+
+func TestParseStream_SkipsNoise(t *testing.T) {
+	parser, err := NewStreamParser(TypeClaudeCode)
+	require.NoError(t, err)
+
+	input := strings.Join([]string{
+		"Loaded cached credentials.",
+		"",
+		"{broken json",
+		`{"type":"result","subtype":"error_max_turns","is_error":false,"session_id":"s1"}`,
+	}, "\n")
+
+	var events []Event
+	require.NoError(t, ParseStream(strings.NewReader(input), parser, func(e Event) { events = append(events, e) }))
+	require.Len(t, events, 1)
+	assert.True(t, events[0].(*ResultEvent).IsError)
+}
+
+func TestNewStreamParser_Unsupported(t *testing.T) {
+	_, err := NewStreamParser(TypeAider)
+	assert.Error(t, err)
+
+	_, err = StructuredCommand(TypeCursor, "hi", false)
+	assert.Error(t, err)
+}
+
+func TestStructuredCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		termType TerminalType
+		yolo     bool
+		expected []string
+	}{
+		{"Claude", TypeClaudeCode, false, []string{"claude", "-p", "hi", "--output-format", "stream-json", "--verbose"}},
+		{"Claude YOLO", TypeClaudeCode, true, []string{"claude", "-p", "hi", "--output-format", "stream-json", "--verbose", "--dangerously-skip-permissions"}},
+		{"Gemini YOLO", TypeGeminiCLI, true, []string{"gemini", "-p", "hi", "--output-format", "stream-json", "--yolo"}},
+		{"Codex", TypeCodex, false, []string{"codex", "exec", "--json", "hi"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := StructuredCommand(tt.termType, "hi", tt.yolo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cmd)
+		})
+	}
+}
+
+func TestHeadlessCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		termType TerminalType
+		yolo     bool
+		expected []string
+	}{
+		{"Claude", TypeClaudeCode, false, []string{"claude", "-p", "hi"}},
+		{"Gemini YOLO", TypeGeminiCLI, true, []string{"gemini", "-p", "hi", "--yolo"}},
+		{"Codex YOLO", TypeCodex, true, []string{"codex", "exec", "--dangerously-bypass-approvals-and-sandbox", "hi"}},
+		{"Aider", TypeAider, false, []string{"aider", "--message", "hi", "--no-pretty"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := HeadlessCommand(tt.termType, "hi", tt.yolo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cmd)
+		})
+	}
+
+	_, err := HeadlessCommand(TypeCustom, "hi", false)
+	assert.Error(t, err)
+}
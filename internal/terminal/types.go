@@ -2,8 +2,11 @@ package terminal
 
 import (
 	"bufio"
+	"io"
 	"os/exec"
+	"strings"
 	"sync"
+	"time"
 )
 
 // TerminalType 表示支援的 AI 終端類型
@@ -15,6 +18,7 @@ const (
 	TypeCursor                         // Cursor CLI
 	TypeAider                          // Aider CLI
 	TypeCustom                         // 自定義終端
+	TypeCodex                          // Codex CLI
 )
 
 // String 返回終端類型的字符串表示
@@ -30,6 +34,8 @@ func (t TerminalType) String() string {
 		return "aider"
 	case TypeCustom:
 		return "custom"
+	case TypeCodex:
+		return "codex"
 	default:
 		return "unknown"
 	}
@@ -46,6 +52,8 @@ func (t TerminalType) CommandName() string {
 		return "cursor"
 	case TypeAider:
 		return "aider"
+	case TypeCodex:
+		return "codex"
 	default:
 		return "bash"
 	}
@@ -87,11 +95,33 @@ type Terminal struct {
 	Status   TerminalStatus // 終端狀態
 	Process  *exec.Cmd      // 底層進程
 	Stdin    *bufio.Writer  // 標準輸入寫入器
+	stdin    io.Closer      // 標準輸入管道
 	Stdout   *bufio.Scanner // 標準輸出掃描器
+	stdout   io.Reader      // 標準輸出管道，掃描出錯後用於排空剩餘輸出
 	LastUsed int64          // 最後使用時間戳
 	mu       sync.RWMutex   // 保護並發訪問的鎖
+
+	startedAt     time.Time     // 進程啟動時間
+	lastOutput    time.Time     // 最後一次收到輸出的時間
+	readyPatterns []string      // 就緒標誌
+	ready         bool          // 是否已達到就緒狀態
+	output        []string      // 最近的輸出行（有上限）
+	outputTotal   int           // 累計輸出行數，用於增量讀取
+	stdinErr      error         // 最後一次寫入 stdin 的錯誤
+	outputErr     error         // 讀取輸出出錯（例如單行超過 maxLineSize），其後的輸出被丟棄
+	done          chan struct{} // 進程退出後關閉
+	exitCode      int           // 進程退出碼
+	sessionID     string        // 工具會話 ID（預先指定、恢復或從輸出中識別）
+	onOutput      func(string)
+	onExit        func(*Terminal)
 }
 
+// maxOutputLines 每個終端保留的輸出行數上限
+const maxOutputLines = 1000
+
+// maxLineSize 單行輸出的長度上限，與解析流式輸出時相同
+const maxLineSize = 16 * 1024 * 1024
+
 // GetStatus 安全地獲取終端狀態
 func (t *Terminal) GetStatus() TerminalStatus {
 	t.mu.RLock()
@@ -111,15 +141,141 @@ func (t *Terminal) IsRunning() bool {
 	return t.GetStatus() == StatusRunning
 }
 
+// IsReady 檢查終端是否已輸出就緒標誌
+func (t *Terminal) IsReady() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.ready
+}
+
+// LastOutputAt 返回最後一次收到輸出的時間
+func (t *Terminal) LastOutputAt() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastOutput
+}
+
+// Output 返回最近捕獲的輸出行
+func (t *Terminal) Output() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	lines := make([]string, len(t.output))
+	copy(lines, t.output)
+	return lines
+}
+
+// OutputSince 返回累計序號從 offset 開始的輸出行及下一次讀取的序號；
+// 早於保留範圍的行已被丟棄，不再返回
+func (t *Terminal) OutputSince(offset int) ([]string, int) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	first := t.outputTotal - len(t.output) // 緩衝區中第一行的序號
+	if offset < first {
+		offset = first
+	}
+	if offset >= t.outputTotal {
+		return nil, t.outputTotal
+	}
+	lines := make([]string, t.outputTotal-offset)
+	copy(lines, t.output[offset-first:])
+	return lines, t.outputTotal
+}
+
+// OutputErr 返回讀取輸出時的錯誤；非 nil 時錯誤之後的輸出已被丟棄
+func (t *Terminal) OutputErr() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.outputErr
+}
+
+// Done 返回一個在進程退出後關閉的通道
+func (t *Terminal) Done() <-chan struct{} {
+	return t.done
+}
+
+// Exited 檢查底層進程是否已退出
+func (t *Terminal) Exited() bool {
+	if t.done == nil {
+		return false
+	}
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExitCode 返回進程退出碼，進程未退出時返回 -1
+func (t *Terminal) ExitCode() int {
+	if !t.Exited() {
+		return -1
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.exitCode
+}
+
+// setStdinErr 記錄最後一次寫入 stdin 的結果
+func (t *Terminal) setStdinErr(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stdinErr = err
+}
+
+// SessionID 返回工具會話 ID，尚未知曉時返回空字串
+func (t *Terminal) SessionID() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.sessionID
+}
+
+// recordOutput 記錄一行輸出並檢查就緒標誌
+func (t *Terminal) recordOutput(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastOutput = time.Now()
+	t.output = append(t.output, line)
+	t.outputTotal++
+	if len(t.output) > maxOutputLines {
+		t.output = t.output[len(t.output)-maxOutputLines:]
+	}
+
+	if t.sessionID == "" {
+		t.sessionID = parseSessionID(line)
+	}
+
+	if !t.ready {
+		for _, pattern := range t.readyPatterns {
+			if strings.Contains(line, pattern) {
+				t.ready = true
+				break
+			}
+		}
+	}
+}
+
 // TerminalConfig 終端配置
 type TerminalConfig struct {
 	Type        TerminalType          // 終端類型
 	Name        string                // 終端名稱
 	WorkingDir  string                // 工作目錄
+	ProjectDir  string                // 項目根目錄，為空時即工作目錄；工作目錄為項目子目錄時用於門禁與啟動鉤子
 	Environment map[string]string     // 環境變量
 	Args        []string              // 額外參數
 	Command     []string              // 完整的啟動命令
 	YoloMode    bool                  // YOLO模式標誌
+
+	ReadyPatterns []string // 就緒標誌（輸出包含任一字串即視為就緒）
+	PreLaunch     []string // 啟動前在工作目錄中依次運行的 shell 命令，由 launch.Start 運行
+
+	Resume    string // 要恢復的工具會話 ID；ResumeLatest 表示繼續最近一次會話
+	SessionID string // 新會話預先指定的 ID（需工具支持，見 SupportsSessionID）
+
+	InitialPrompt string          // 啟動後的第一條提示詞（見 InitialPromptArgs），不支持時寫入標準輸入
+	OnOutput      func(string)    // 每讀取一行輸出調用，用於保存超出 Output 保留範圍的完整輸出
+	OnExit        func(*Terminal) // 進程退出且輸出讀取完畢後調用
 }
 
 // Manager 介面定義終端管理器的行為
@@ -133,6 +289,12 @@ type Manager interface {
 	// SendCommand 向指定終端發送命令
 	SendCommand(name string, command string) error
 
+	// CloseInput 關閉指定終端的標準輸入
+	CloseInput(name string) error
+
+	// RemoveTerminal 停止並移除指定名稱的終端
+	RemoveTerminal(name string) error
+
 	// GetTerminal 獲取指定名稱的終端
 	GetTerminal(name string) (*Terminal, bool)
 
@@ -141,4 +303,7 @@ type Manager interface {
 
 	// IsHealthy 檢查終端管理器是否健康
 	IsHealthy() bool
+
+	// CheckHealth 生成管理器及所有終端的健康報告
+	CheckHealth() *HealthReport
 }
\ No newline at end of file
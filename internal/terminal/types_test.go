@@ -17,6 +17,7 @@ func TestTerminalType_String(t *testing.T) {
 		{"Cursor", TypeCursor, "cursor"},
 		{"Aider", TypeAider, "aider"},
 		{"Custom", TypeCustom, "custom"},
+		{"Codex", TypeCodex, "codex"},
 		{"Unknown", TerminalType(999), "unknown"},
 	}
 
@@ -38,6 +39,7 @@ func TestTerminalType_CommandName(t *testing.T) {
 		{"Gemini CLI command", TypeGeminiCLI, "gemini"},
 		{"Cursor command", TypeCursor, "cursor"},
 		{"Aider command", TypeAider, "aider"},
+		{"Codex command", TypeCodex, "codex"},
 		{"Unknown command", TerminalType(999), "bash"},
 	}
 
@@ -143,4 +145,34 @@ func TestTerminalConfig_Creation(t *testing.T) {
 	assert.Equal(t, "/tmp", config.WorkingDir)
 	assert.Equal(t, "claude", config.Environment["AI_TOOL"])
 	assert.Contains(t, config.Args, "--verbose")
+}
+
+func TestTerminal_OutputSince(t *testing.T) {
+	terminal := &Terminal{}
+	lines, next := terminal.OutputSince(0)
+	assert.Empty(t, lines)
+	assert.Equal(t, 0, next)
+
+	terminal.recordOutput("one")
+	terminal.recordOutput("two")
+	lines, next = terminal.OutputSince(0)
+	assert.Equal(t, []string{"one", "two"}, lines)
+	assert.Equal(t, 2, next)
+
+	terminal.recordOutput("three")
+	lines, next = terminal.OutputSince(next)
+	assert.Equal(t, []string{"three"}, lines)
+	assert.Equal(t, 3, next)
+
+	lines, next = terminal.OutputSince(next)
+	assert.Empty(t, lines)
+	assert.Equal(t, 3, next)
+
+	// 超出保留範圍的行被丟棄後，從最早保留的一行開始返回
+	for i := 0; i < maxOutputLines; i++ {
+		terminal.recordOutput("more")
+	}
+	lines, next = terminal.OutputSince(1)
+	assert.Len(t, lines, maxOutputLines)
+	assert.Equal(t, maxOutputLines+3, next)
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+// Package tui 提供鍵盤驅動的終端界面，在無法運行 GUI 的環境（如 SSH）中
+// 管理項目、啟動 AI 工具並附加到運行中的會話
+package tui
+
+import "ai-launcher/internal/config"
+
+// keyMap 按鍵到動作的反向映射
+type keyMap struct {
+	main    map[string]string
+	general map[string]string
+}
+
+func newKeyMap(k config.Keybindings) keyMap {
+	km := keyMap{main: make(map[string]string), general: make(map[string]string)}
+	for action, key := range k.MainMenu {
+		km.main[key] = action
+	}
+	for action, key := range k.General {
+		km.general[key] = action
+	}
+	return km
+}
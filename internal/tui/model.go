@@ -0,0 +1,627 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/launch"
+	"ai-launcher/internal/memory"
+	"ai-launcher/internal/ollama"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/project/detect"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+)
+
+// View TUI 的頁面
+type View int
+
+const (
+	ViewMain      View = iota // 項目列表
+	ViewTools                 // 選擇 AI 工具
+	ViewTerminals             // 運行中的會話
+	ViewAttach                // 附加到會話：查看輸出並發送輸入
+	ViewTemplates             // 查詢優化：選擇模板並用 Ollama 改寫提示詞
+)
+
+const (
+	refreshInterval = 300 * time.Millisecond // 刷新會話狀態與輸出的間隔
+	optimizeTimeout = 2 * time.Minute        // 單次查詢優化的超時
+	maxAttachLines  = 1000                   // 附加頁面保留的輸出行數
+)
+
+// errNoProjects 沒有已登記的項目
+var errNoProjects = errors.New("no projects, add one with: ai-launcher project add <path>")
+
+type tickMsg time.Time
+
+// optimizedMsg 後台查詢優化完成
+type optimizedMsg struct {
+	result *ollama.OptimizationResult
+	err    error
+}
+
+// Model TUI 狀態；與 GUI 共用 ConfigManager、TerminalManager 與 launch 流程
+type Model struct {
+	config    *config.Config
+	keys      keyMap
+	styles    styles
+	projects  *project.ConfigManager
+	terminals terminal.Manager
+	ollama    *ollama.OllamaClient
+	templates *template.TemplateManager
+
+	view   View
+	cursor map[View]int
+	width  int
+	height int
+
+	status    string
+	statusErr bool
+	input     string // 附加頁面與查詢優化頁面的輸入行
+
+	tool          project.AIModelType // 工具選擇頁面的默認工具
+	pendingPrompt string              // 下一次啟動時的第一條提示詞（來自查詢優化）
+
+	attached     string   // 附加的會話名稱
+	attachLines  []string // 附加後收到的輸出
+	attachOffset int      // 下一次讀取輸出的位置
+
+	optimizing bool
+	optimized  *ollama.OptimizationResult
+
+	confirmQuit bool
+}
+
+// NewModel 使用 ~/.ai-launcher/config.yaml 與默認的項目、終端管理器創建 TUI；
+// 配置無效時使用默認配置並在狀態欄提示
+func NewModel() *Model {
+	return loadModel(config.Options{})
+}
+
+// loadModel 按 opts 分層加載配置並創建 TUI
+func loadModel(opts config.Options) *Model {
+	cfg := config.Default()
+	res, err := config.Load(opts)
+	if err == nil {
+		cfg = res.Config
+	}
+	cm := project.NewConfigManager()
+	if err := cm.LoadProjects(); err != nil {
+		log.Printf("[tui] load projects failed: %v", err)
+	}
+	m := newModel(cfg, cm, terminal.NewTerminalManager())
+	if err := m.templates.LoadDir(cfg.Templates.Dir()); err != nil {
+		m.setError(fmt.Errorf("invalid template: %v", err))
+	}
+	if err != nil {
+		m.setError(fmt.Errorf("invalid configuration, using defaults: %v", oneLine(err)))
+	}
+	return m
+}
+
+func newModel(cfg *config.Config, cm *project.ConfigManager, tm terminal.Manager) *Model {
+	return &Model{
+		config:    cfg,
+		keys:      newKeyMap(cfg.Keybindings),
+		styles:    newStyles(cfg.Global.Theme),
+		projects:  cm,
+		terminals: tm,
+		ollama:    cfg.Ollama.NewClient(),
+		templates: template.NewTemplateManager(),
+		view:      ViewMain,
+		cursor:    make(map[View]int),
+	}
+}
+
+// GetCurrentView 返回當前頁面
+func (m *Model) GetCurrentView() View {
+	return m.view
+}
+
+// SwitchView 切換頁面並清空輸入行
+func (m *Model) SwitchView(view View) {
+	m.view = view
+	m.input = ""
+	m.confirmQuit = false
+}
+
+// GetTerminalManager 返回終端管理器
+func (m *Model) GetTerminalManager() terminal.Manager {
+	return m.terminals
+}
+
+// GetOllamaClient 返回 Ollama 客戶端
+func (m *Model) GetOllamaClient() *ollama.OllamaClient {
+	return m.ollama
+}
+
+// GetTemplateManager 返回模板管理器
+func (m *Model) GetTemplateManager() *template.TemplateManager {
+	return m.templates
+}
+
+// ListTerminals 返回所有會話，按名稱排序
+func (m *Model) ListTerminals() []*terminal.Terminal {
+	terminals := m.terminals.ListTerminals()
+	if terminals == nil {
+		terminals = []*terminal.Terminal{}
+	}
+	sortTerminals(terminals)
+	return terminals
+}
+
+// OptimizeQuery 套用模板（templateID 為空時跳過）並結合選中項目的環境與記憶，用 Ollama 改寫查詢
+func (m *Model) OptimizeQuery(query, templateID string) (*ollama.OptimizationResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	// 選中項目時以檢測到的語言與框架作為模板變量
+	var env *detect.Result
+	var vars map[string]string
+	proj, ok := m.selectedProject()
+	if ok {
+		env = detect.Detect(proj.Path)
+		vars = env.TemplateVars()
+	}
+	if templateID != "" {
+		applied, err := m.templates.ApplyTemplate(templateID, query, vars)
+		if err != nil {
+			return nil, err
+		}
+		query = applied.OptimizedPrompt
+	}
+
+	var queryContext string
+	if ok {
+		queryContext = strings.TrimSpace(env.ContextPrompt() + "\n\n" + memory.QueryContext(proj.Path, query, memory.DefaultRelevant))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), optimizeTimeout)
+	defer cancel()
+	return m.ollama.OptimizeQuery(ctx, query, queryContext)
+}
+
+// Close 停止並移除所有會話；退出 TUI 時調用
+func (m *Model) Close() {
+	for _, t := range m.terminals.ListTerminals() {
+		if err := m.terminals.RemoveTerminal(t.Name); err != nil {
+			log.Printf("[tui] remove terminal %s failed: %v", t.Name, err)
+		}
+	}
+}
+
+// Init 實現 tea.Model
+func (m *Model) Init() tea.Cmd {
+	return tick()
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// Update 實現 tea.Model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case tickMsg:
+		m.readAttached()
+		return m, tick()
+	case optimizedMsg:
+		m.optimizing = false
+		if msg.err != nil {
+			m.setError(fmt.Errorf("optimize failed: %v", msg.err))
+			return m, nil
+		}
+		m.optimized = msg.result
+		m.setStatus(fmt.Sprintf("optimized in %s, press %s to launch with it", msg.result.ProcessingTime.Round(time.Millisecond), m.keyName(config.ActionConfirm)))
+		return m, nil
+	case config.Change:
+		m.applyChange(msg)
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// applyChange 應用配置文件、模板或項目列表的變化；無效的文件被拒絕，保留上一次的內容
+func (m *Model) applyChange(c config.Change) {
+	switch c.Kind {
+	case config.KindConfig:
+		if c.Err != nil {
+			m.setError(fmt.Errorf("invalid configuration, keeping the previous one: %v", oneLine(c.Err)))
+			return
+		}
+		m.config = c.Config
+		m.keys = newKeyMap(c.Config.Keybindings)
+		m.styles = newStyles(c.Config.Global.Theme)
+		m.ollama = c.Config.Ollama.NewClient()
+		m.setStatus("configuration reloaded")
+	case config.KindTemplates:
+		if err := m.templates.LoadDir(c.Config.Templates.Dir()); err != nil {
+			m.setError(fmt.Errorf("invalid template, keeping the previous ones: %v", err))
+			return
+		}
+		m.setStatus("templates reloaded")
+	case config.KindProjects:
+		if err := m.projects.LoadProjects(); err != nil {
+			m.setError(fmt.Errorf("invalid projects.json, keeping the previous projects: %v", err))
+			return
+		}
+	}
+	m.moveCursor(0)
+}
+
+// handleKey 按當前頁面分派按鍵；ctrl+c 總是退出
+func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+	action := m.keys.general[key]
+
+	switch m.view {
+	case ViewAttach:
+		return m.updateAttach(msg, action)
+	case ViewTemplates:
+		return m.updateTemplates(msg, action)
+	}
+
+	// 各列表頁面都響應主選單快捷鍵
+	if main, ok := m.keys.main[key]; ok {
+		return m.mainAction(main)
+	}
+	m.confirmQuit = false
+
+	switch action {
+	case config.ActionUp, config.ActionLeft:
+		m.moveCursor(-1)
+	case config.ActionDown, config.ActionRight:
+		m.moveCursor(1)
+	case config.ActionBack:
+		if m.view != ViewMain {
+			m.pendingPrompt = ""
+			m.SwitchView(ViewMain)
+		}
+	case config.ActionConfirm:
+		m.confirm()
+	case config.ActionStop:
+		if m.view == ViewTerminals {
+			m.stopSelected()
+		}
+	}
+	return m, nil
+}
+
+// mainAction 執行主選單動作
+func (m *Model) mainAction(action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case config.ActionQuit:
+		running := 0
+		for _, t := range m.terminals.ListTerminals() {
+			if !t.Exited() {
+				running++
+			}
+		}
+		if running > 0 && !m.confirmQuit {
+			m.confirmQuit = true
+			m.setStatus(fmt.Sprintf("%d session(s) still running, press %s again to stop them and quit", running, m.keyName(config.ActionQuit)))
+			return m, nil
+		}
+		return m, tea.Quit
+	case config.ActionQueryOptimize:
+		m.SwitchView(ViewTemplates)
+	case config.ActionTerminalManager:
+		m.SwitchView(ViewTerminals)
+	default:
+		m.launch(project.AIModelType(action))
+	}
+	return m, nil
+}
+
+// confirm 列表頁面中的確認鍵
+func (m *Model) confirm() {
+	switch m.view {
+	case ViewMain:
+		m.openTools()
+	case ViewTools:
+		tools := m.tools()
+		if i := m.cursor[ViewTools]; i < len(tools) {
+			m.launch(tools[i])
+		}
+	case ViewTerminals:
+		terminals := m.ListTerminals()
+		if i := m.cursor[ViewTerminals]; i < len(terminals) {
+			m.attach(terminals[i].Name)
+		}
+	}
+}
+
+// openTools 為選中項目打開工具選擇頁面，光標停在項目的默認工具上
+func (m *Model) openTools() bool {
+	proj, ok := m.selectedProject()
+	if !ok {
+		m.setError(errNoProjects)
+		return false
+	}
+	m.tool = proj.AIModel
+	m.cursor[ViewTools] = indexOf(m.tools(), m.tool)
+	m.SwitchView(ViewTools)
+	return true
+}
+
+// launch 用指定工具在選中項目中啟動會話並附加到它
+func (m *Model) launch(tool project.AIModelType) {
+	if !m.projects.IsValidModel(tool) {
+		m.setError(fmt.Errorf("unknown tool: %s", tool))
+		return
+	}
+	proj, ok := m.selectedProject()
+	if !ok {
+		m.setError(errNoProjects)
+		return
+	}
+
+	opts := launch.Options{Tool: tool, Prompt: m.pendingPrompt, Templates: m.templates}
+	if err := launch.CheckAvailable(m.terminals, opts.TerminalName(proj)); err != nil {
+		m.setError(err)
+		return
+	}
+
+	config, warnings, err := launch.Prepare(m.projects, proj, opts)
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	if err := launch.Start(m.terminals, config, nil); err != nil {
+		m.setError(err)
+		return
+	}
+	m.pendingPrompt = ""
+	m.optimized = nil
+	m.attach(config.Name)
+	status := fmt.Sprintf("started %s in %s", tool, proj.Path)
+	if len(warnings) > 0 {
+		status += " (" + strings.Join(warnings, "; ") + ")"
+	}
+	m.setStatus(status)
+}
+
+// attach 附加到會話，顯示其已保留的全部輸出
+func (m *Model) attach(name string) {
+	m.SwitchView(ViewAttach)
+	m.attached = name
+	m.attachLines = nil
+	m.attachOffset = 0
+	m.readAttached()
+}
+
+// readAttached 讀取附加會話的新輸出
+func (m *Model) readAttached() {
+	if m.view != ViewAttach {
+		return
+	}
+	t, ok := m.terminals.GetTerminal(m.attached)
+	if !ok {
+		return
+	}
+	lines, next := t.OutputSince(m.attachOffset)
+	m.attachOffset = next
+	m.attachLines = append(m.attachLines, lines...)
+	if n := len(m.attachLines) - maxAttachLines; n > 0 {
+		m.attachLines = m.attachLines[n:]
+	}
+}
+
+// updateAttach 附加頁面：字符鍵編輯輸入行，確認鍵發送，返回鍵回到會話列表
+func (m *Model) updateAttach(msg tea.KeyMsg, action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case config.ActionBack:
+		m.SwitchView(ViewTerminals)
+		return m, nil
+	case config.ActionConfirm:
+		if err := m.terminals.SendCommand(m.attached, m.input); err != nil {
+			m.setError(err)
+			return m, nil
+		}
+		m.input = ""
+		return m, nil
+	}
+	m.editInput(msg)
+	return m, nil
+}
+
+// updateTemplates 查詢優化頁面：上下鍵選擇模板，輸入查詢後按確認鍵優化；
+// 輸入為空且已有結果時，確認鍵進入工具選擇並以結果作為第一條提示詞
+func (m *Model) updateTemplates(msg tea.KeyMsg, action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case config.ActionBack:
+		m.SwitchView(ViewMain)
+		return m, nil
+	case config.ActionUp:
+		m.moveCursor(-1)
+		return m, nil
+	case config.ActionDown:
+		m.moveCursor(1)
+		return m, nil
+	case config.ActionConfirm:
+		if m.optimizing {
+			return m, nil
+		}
+		if strings.TrimSpace(m.input) == "" {
+			if m.optimized == nil {
+				return m, nil
+			}
+			if m.openTools() {
+				m.pendingPrompt = m.optimized.OptimizedQuery
+			}
+			return m, nil
+		}
+		query, templateID := m.input, m.selectedTemplate()
+		m.optimizing = true
+		m.optimized = nil
+		m.setStatus("optimizing with " + m.ollama.GetModel() + "...")
+		return m, func() tea.Msg {
+			result, err := m.OptimizeQuery(query, templateID)
+			return optimizedMsg{result: result, err: err}
+		}
+	}
+	m.editInput(msg)
+	return m, nil
+}
+
+// editInput 將字符鍵寫入輸入行
+func (m *Model) editInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+		if msg.Type == tea.KeySpace && len(msg.Runes) == 0 {
+			m.input += " "
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		m.input = ""
+	}
+}
+
+// stopSelected 停止並移除選中的會話
+func (m *Model) stopSelected() {
+	terminals := m.ListTerminals()
+	i := m.cursor[ViewTerminals]
+	if i >= len(terminals) {
+		return
+	}
+	name := terminals[i].Name
+	if err := m.terminals.RemoveTerminal(name); err != nil {
+		m.setError(err)
+		return
+	}
+	m.setStatus("stopped " + name)
+	m.moveCursor(0)
+}
+
+// moveCursor 移動當前列表的光標並限制在範圍內
+func (m *Model) moveCursor(delta int) {
+	n := m.listLen()
+	i := m.cursor[m.view] + delta
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	m.cursor[m.view] = i
+}
+
+func (m *Model) listLen() int {
+	switch m.view {
+	case ViewMain:
+		return len(m.projectList())
+	case ViewTools:
+		return len(m.tools())
+	case ViewTerminals:
+		return len(m.terminals.ListTerminals())
+	case ViewTemplates:
+		return len(m.templateList())
+	}
+	return 0
+}
+
+// projectList 返回主頁面列出的項目：置頂的在前，其餘按最近使用排序，不含歸檔項目
+func (m *Model) projectList() []project.ProjectConfig {
+	return m.projects.Query(project.Query{})
+}
+
+// selectedProject 返回主頁面中選中的項目
+func (m *Model) selectedProject() (project.ProjectConfig, bool) {
+	projects := m.projectList()
+	i := m.cursor[ViewMain]
+	if i >= len(projects) {
+		return project.ProjectConfig{}, false
+	}
+	return projects[i], true
+}
+
+// selectedTemplate 返回查詢優化頁面中選中的模板 ID，第一項「不使用模板」返回空字串
+func (m *Model) selectedTemplate() string {
+	list := m.templateList()
+	i := m.cursor[ViewTemplates]
+	if i == 0 || i >= len(list) {
+		return ""
+	}
+	return list[i].ID
+}
+
+// templateList 返回模板列表，第一項為 nil，表示不使用模板
+func (m *Model) templateList() []*template.QueryTemplate {
+	templates := m.templates.GetAvailableTemplates()
+	sortTemplates(templates)
+	return append([]*template.QueryTemplate{nil}, templates...)
+}
+
+func (m *Model) tools() []project.AIModelType {
+	return m.projects.GetAvailableModels()
+}
+
+// keyName 返回動作綁定的按鍵，用於提示
+func (m *Model) keyName(action string) string {
+	if key, ok := m.config.Keybindings.General[action]; ok {
+		return key
+	}
+	return m.config.Keybindings.MainMenu[action]
+}
+
+func (m *Model) setStatus(s string) {
+	m.status, m.statusErr = s, false
+}
+
+func (m *Model) setError(err error) {
+	m.status, m.statusErr = err.Error(), true
+}
+
+// oneLine 將多行錯誤合併為一行
+func oneLine(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", "; ")
+}
+
+func indexOf(tools []project.AIModelType, tool project.AIModelType) int {
+	for i, t := range tools {
+		if t == tool {
+			return i
+		}
+	}
+	return 0
+}
+
+// Run 以全屏模式運行 TUI，退出時停止所有會話；opts 為配置加載選項，用於傳入命令行覆蓋。
+// 配置文件、自定義模板與 projects.json 變化時自動重新加載
+func Run(opts config.Options) error {
+	m := loadModel(opts)
+	defer m.Close()
+	p := tea.NewProgram(m, tea.WithAltScreen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if watcher, err := config.NewWatcher(m.config, config.WatchOptions{Options: opts}); err != nil {
+		log.Printf("[tui] config watcher disabled: %v", err)
+	} else {
+		watcher.Subscribe(func(c config.Change) { p.Send(c) })
+		go watcher.Run(ctx)
+	}
+
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/terminal"
+)
+
+// newTestModel 在臨時 HOME 中創建模型並登記一個項目
+func newTestModel(t *testing.T, cfg *config.Config) (*Model, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+
+	dir := t.TempDir()
+	cm := project.NewConfigManager()
+	require.NoError(t, cm.LoadProjects())
+	require.NoError(t, cm.AddProject(project.ProjectConfig{Name: "demo", Path: dir, AIModel: project.ModelCodex}))
+
+	tm := terminal.NewTerminalManager()
+	m := newModel(cfg, cm, tm)
+	t.Cleanup(m.Close)
+	return m, dir
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func press(m *Model, keys ...string) tea.Cmd {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		_, cmd = m.Update(key(k))
+	}
+	return cmd
+}
+
+func TestModel_Navigation(t *testing.T) {
+	m, dir := newTestModel(t, config.Default())
+	assert.Equal(t, ViewMain, m.GetCurrentView())
+	assert.NotNil(t, m.ListTerminals())
+
+	out := m.View()
+	assert.Contains(t, out, "demo")
+	assert.Contains(t, out, dir)
+	assert.Contains(t, out, "1 Claude Code · 2 Gemini CLI · 3 Codex · 4 Aider · 5 optimize · 6 terminals · q quit")
+
+	// 確認鍵打開工具選擇，光標停在項目的默認工具上
+	press(m, "enter")
+	assert.Equal(t, ViewTools, m.GetCurrentView())
+	assert.Equal(t, project.ModelCodex, m.tools()[m.cursor[ViewTools]])
+	assert.Contains(t, m.View(), "Codex  (project default)")
+
+	press(m, "down", "down", "down", "down")
+	assert.Equal(t, len(m.tools())-1, m.cursor[ViewTools], "cursor stops at the last tool")
+
+	press(m, "esc")
+	assert.Equal(t, ViewMain, m.GetCurrentView())
+	press(m, "6")
+	assert.Equal(t, ViewTerminals, m.GetCurrentView())
+	assert.Contains(t, m.View(), "No sessions")
+	press(m, "5")
+	assert.Equal(t, ViewTemplates, m.GetCurrentView())
+
+	// 查詢優化頁面中字符鍵寫入輸入行，不觸發快捷鍵
+	press(m, "q", "5", "x", "backspace")
+	assert.Equal(t, ViewTemplates, m.GetCurrentView())
+	assert.Equal(t, "q5", m.input)
+	press(m, "esc")
+	assert.Equal(t, ViewMain, m.GetCurrentView())
+
+	cmd := press(m, "q")
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+func TestModel_CustomKeybindings(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keybindings.General[config.ActionConfirm] = "l"
+	cfg.Keybindings.General[config.ActionBack] = "h"
+	cfg.Keybindings.MainMenu[config.ActionTerminalManager] = "t"
+	m, _ := newTestModel(t, cfg)
+
+	press(m, "enter")
+	assert.Equal(t, ViewMain, m.GetCurrentView(), "enter is no longer bound")
+	press(m, "l")
+	assert.Equal(t, ViewTools, m.GetCurrentView())
+	press(m, "h", "t")
+	assert.Equal(t, ViewTerminals, m.GetCurrentView())
+	assert.Contains(t, m.View(), "l select · x stop · h back")
+}
+
+func TestModel_ConfigReload(t *testing.T) {
+	m, _ := newTestModel(t, config.Default())
+
+	cfg := config.Default()
+	cfg.Keybindings.MainMenu[config.ActionTerminalManager] = "t"
+	cfg.Ollama.DefaultModel = "llama3"
+	m.Update(config.Change{Kind: config.KindConfig, Config: cfg})
+	assert.Equal(t, "configuration reloaded", m.status)
+	assert.Equal(t, "llama3", m.GetOllamaClient().GetModel())
+	press(m, "t")
+	assert.Equal(t, ViewTerminals, m.GetCurrentView(), "new keybindings apply without a restart")
+
+	// 無效配置被拒絕，繼續使用上一次的配置
+	m.Update(config.Change{Kind: config.KindConfig, Config: cfg, Err: config.Errors{{Path: "ollama.host", Message: "must be an http or https URL"}}})
+	assert.True(t, m.statusErr)
+	assert.Contains(t, m.status, "keeping the previous one: ollama.host")
+	assert.Equal(t, "llama3", m.GetOllamaClient().GetModel())
+
+	// projects.json 被其他實例修改
+	other := project.NewConfigManager()
+	require.NoError(t, other.LoadProjects())
+	require.NoError(t, other.AddProject(project.ProjectConfig{Name: "second", Path: t.TempDir(), AIModel: project.ModelAider}))
+	m.Update(config.Change{Kind: config.KindProjects, Config: cfg})
+	assert.Len(t, m.projects.GetProjects(), 2)
+}
+
+func TestModel_LaunchAndAttach(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the AI tool")
+	}
+	m, dir := newTestModel(t, config.Default())
+
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\necho ready\nwhile read line; do echo \"got $line\"; done\n"), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	press(m, "1")
+	require.Equal(t, ViewAttach, m.GetCurrentView(), m.status)
+	assert.Equal(t, "demo(Claude Code)", m.attached)
+	assert.Contains(t, m.status, dir)
+
+	press(m, "h", "i", " ", "t", "h", "e", "r", "e", "enter")
+	assert.Empty(t, m.input)
+	require.Eventually(t, func() bool {
+		m.Update(tickMsg(time.Now()))
+		return strings.Contains(strings.Join(m.attachLines, "\n"), "got hi there")
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "ready", m.attachLines[0])
+	assert.Contains(t, m.View(), "got hi there")
+
+	// 同名會話仍在運行時不能重複啟動
+	press(m, "esc")
+	assert.Equal(t, ViewTerminals, m.GetCurrentView())
+	press(m, "1")
+	assert.True(t, m.statusErr)
+	assert.Contains(t, m.status, "already running")
+
+	// 退出前需要再次確認
+	cmd := press(m, "q")
+	assert.Nil(t, cmd)
+	assert.Contains(t, m.status, "1 session(s) still running")
+
+	press(m, "6", "x")
+	assert.Empty(t, m.ListTerminals())
+	assert.Equal(t, "stopped demo(Claude Code)", m.status)
+
+	sessions, err := m.projects.GetSessions(dir, project.ModelClaudeCode)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
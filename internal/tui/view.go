@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"ai-launcher/internal/config"
+	"ai-launcher/internal/project"
+	"ai-launcher/internal/template"
+	"ai-launcher/internal/terminal"
+)
+
+// styles 由主題生成的樣式
+type styles struct {
+	title    lipgloss.Style
+	selected lipgloss.Style
+	muted    lipgloss.Style
+	success  lipgloss.Style
+	error    lipgloss.Style
+	warning  lipgloss.Style
+}
+
+func newStyles(t config.Theme) styles {
+	color := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+	return styles{
+		title:    color(t.PrimaryColor).Bold(true),
+		selected: color(t.PrimaryColor).Bold(true),
+		muted:    color(t.SecondaryColor),
+		success:  color(t.SuccessColor),
+		error:    color(t.ErrorColor),
+		warning:  color(t.WarningColor),
+	}
+}
+
+// actionLabels 幫助欄中的動作名稱
+var actionLabels = map[string]string{
+	config.ActionClaudeCode:      project.ModelClaudeCode.String(),
+	config.ActionGeminiCLI:       project.ModelGeminiCLI.String(),
+	config.ActionCodex:           project.ModelCodex.String(),
+	config.ActionAider:           project.ModelAider.String(),
+	config.ActionQueryOptimize:   "optimize",
+	config.ActionTerminalManager: "terminals",
+	config.ActionQuit:            "quit",
+	config.ActionBack:            "back",
+	config.ActionConfirm:         "select",
+	config.ActionStop:            "stop",
+}
+
+// View 實現 tea.Model
+func (m *Model) View() string {
+	var body string
+	switch m.view {
+	case ViewMain:
+		body = m.viewProjects()
+	case ViewTools:
+		body = m.viewTools()
+	case ViewTerminals:
+		body = m.viewTerminals()
+	case ViewAttach:
+		body = m.viewAttach()
+	case ViewTemplates:
+		body = m.viewTemplates()
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.title.Render("AI Launcher"))
+	b.WriteString("\n\n")
+	b.WriteString(body)
+	b.WriteString("\n")
+	if m.status != "" {
+		style := m.styles.success
+		if m.statusErr {
+			style = m.styles.error
+		}
+		b.WriteString(style.Render(m.status))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.styles.muted.Render(m.help()))
+
+	out := b.String()
+	if m.width > 0 {
+		out = lipgloss.NewStyle().MaxWidth(m.width).Render(out)
+	}
+	return out
+}
+
+func (m *Model) viewProjects() string {
+	projects := m.projectList()
+	if len(projects) == 0 {
+		return m.styles.muted.Render("No projects yet. Add one with: ai-launcher project add <path>") + "\n"
+	}
+
+	rows := make([]string, len(projects))
+	for i, p := range projects {
+		rows[i] = fmt.Sprintf("%-20s %-12s %s", truncate(p.Name, 20), p.AIModel.String(), p.Path)
+	}
+	return m.styles.title.Render("Projects") + "\n" + m.list(ViewMain, rows)
+}
+
+func (m *Model) viewTools() string {
+	proj, _ := m.selectedProject()
+	tools := m.tools()
+	rows := make([]string, len(tools))
+	for i, t := range tools {
+		rows[i] = t.String()
+		if t == m.tool {
+			rows[i] += m.styles.muted.Render("  (project default)")
+		}
+		if key := m.config.Keybindings.MainMenu[string(t)]; key != "" {
+			rows[i] = fmt.Sprintf("[%s] %s", key, rows[i])
+		}
+	}
+
+	title := fmt.Sprintf("Launch %s with", proj.Name)
+	var b strings.Builder
+	b.WriteString(m.styles.title.Render(title) + "\n")
+	b.WriteString(m.list(ViewTools, rows))
+	if m.pendingPrompt != "" {
+		b.WriteString("\n" + m.styles.warning.Render("First prompt: ") + truncate(firstLine(m.pendingPrompt), 70) + "\n")
+	}
+	return b.String()
+}
+
+func (m *Model) viewTerminals() string {
+	terminals := m.ListTerminals()
+	if len(terminals) == 0 {
+		return m.styles.muted.Render("No sessions. Select a project and press a tool key to start one.") + "\n"
+	}
+
+	rows := make([]string, len(terminals))
+	for i, t := range terminals {
+		rows[i] = fmt.Sprintf("%-30s %s", truncate(t.Name, 30), m.terminalStatus(t))
+	}
+	return m.styles.title.Render("Sessions") + "\n" + m.list(ViewTerminals, rows)
+}
+
+// terminalStatus 返回會話狀態；已退出的會話顯示退出碼
+func (m *Model) terminalStatus(t *terminal.Terminal) string {
+	if t.Exited() {
+		if code := t.ExitCode(); code != 0 {
+			return m.styles.error.Render(fmt.Sprintf("exited (%d)", code))
+		}
+		return m.styles.muted.Render("exited")
+	}
+	if t.IsReady() {
+		return m.styles.success.Render(t.GetStatus().String())
+	}
+	return m.styles.warning.Render(t.GetStatus().String())
+}
+
+func (m *Model) viewAttach() string {
+	var b strings.Builder
+	header := m.attached
+	if t, ok := m.terminals.GetTerminal(m.attached); ok {
+		header += "  " + m.terminalStatus(t)
+	}
+	b.WriteString(m.styles.title.Render(header) + "\n")
+
+	// 標題、狀態欄、幫助欄與輸入行之外的行數用於顯示輸出
+	lines := m.attachLines
+	if visible := m.height - 8; m.height > 0 && len(lines) > visible {
+		lines = lines[len(lines)-max(visible, 1):]
+	}
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n" + m.styles.selected.Render("> ") + m.input + "█\n")
+	return b.String()
+}
+
+func (m *Model) viewTemplates() string {
+	list := m.templateList()
+	rows := make([]string, len(list))
+	for i, t := range list {
+		if t == nil {
+			rows[i] = "(no template)"
+			continue
+		}
+		rows[i] = fmt.Sprintf("%-14s %s", t.ID, m.styles.muted.Render(t.Name))
+	}
+
+	var b strings.Builder
+	title := "Optimize a prompt"
+	if proj, ok := m.selectedProject(); ok {
+		title += " for " + proj.Name
+	}
+	b.WriteString(m.styles.title.Render(title) + "\n")
+	b.WriteString(m.list(ViewTemplates, rows))
+	b.WriteString("\n" + m.styles.selected.Render("Query: ") + m.input + "█\n")
+	if m.optimizing {
+		b.WriteString(m.styles.warning.Render("Optimizing...") + "\n")
+	}
+	if m.optimized != nil {
+		b.WriteString("\n" + m.styles.success.Render("Optimized prompt:") + "\n" + m.optimized.OptimizedQuery + "\n")
+	}
+	return b.String()
+}
+
+// list 渲染帶光標的列表
+func (m *Model) list(view View, rows []string) string {
+	var b strings.Builder
+	for i, row := range rows {
+		if i == m.cursor[view] {
+			b.WriteString(m.styles.selected.Render("> ") + m.styles.selected.Render(row))
+		} else {
+			b.WriteString("  " + row)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// help 按當前頁面生成幫助欄
+func (m *Model) help() string {
+	general := m.config.Keybindings.General
+	var parts []string
+	add := func(key, label string) {
+		if key != "" {
+			parts = append(parts, key+" "+label)
+		}
+	}
+
+	switch m.view {
+	case ViewAttach:
+		add(general[config.ActionConfirm], "send")
+		add(general[config.ActionBack], "detach")
+	case ViewTemplates:
+		add(general[config.ActionUp]+"/"+general[config.ActionDown], "template")
+		add(general[config.ActionConfirm], "optimize")
+		add(general[config.ActionBack], "back")
+	default:
+		add(general[config.ActionConfirm], actionLabels[config.ActionConfirm])
+		if m.view == ViewTerminals {
+			add(general[config.ActionStop], actionLabels[config.ActionStop])
+		}
+		if m.view != ViewMain {
+			add(general[config.ActionBack], actionLabels[config.ActionBack])
+		}
+		for _, action := range config.MainMenuActions {
+			add(m.config.Keybindings.MainMenu[action], actionLabels[action])
+		}
+	}
+	add("ctrl+c", "exit")
+	return strings.Join(parts, " · ")
+}
+
+func sortTerminals(terminals []*terminal.Terminal) {
+	sort.Slice(terminals, func(i, j int) bool { return terminals[i].Name < terminals[j].Name })
+}
+
+func sortTemplates(templates []*template.QueryTemplate) {
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
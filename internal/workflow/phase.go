@@ -0,0 +1,123 @@
+// Package workflow 實現 ADDP 四階段工作流（分析、設計、開發、持久化）：
+// 在 .addp/workflows 中記錄每個項目的當前階段，推進前檢查階段門禁，並為啟動的工具提供階段提示詞
+package workflow
+
+import "fmt"
+
+// Phase ADDP 工作流階段
+type Phase string
+
+const (
+	PhaseAnalysis    Phase = "analysis"    // 分析
+	PhaseDesign      Phase = "design"      // 設計
+	PhaseDevelopment Phase = "development" // 開發
+	PhasePersistence Phase = "persistence" // 持久化
+)
+
+// Phases 按執行順序排列的所有階段
+var Phases = []Phase{PhaseAnalysis, PhaseDesign, PhaseDevelopment, PhasePersistence}
+
+// ParsePhase 解析階段名稱
+func ParsePhase(s string) (Phase, error) {
+	for _, p := range Phases {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown phase: %s", s)
+}
+
+// Index 返回階段在流程中的位置，未知階段返回 -1
+func (p Phase) Index() int {
+	for i, phase := range Phases {
+		if phase == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// Next 返回下一個階段；持久化之後回到分析，開始新一輪循環
+func (p Phase) Next() Phase {
+	i := p.Index()
+	if i < 0 || i == len(Phases)-1 {
+		return PhaseAnalysis
+	}
+	return Phases[i+1]
+}
+
+// Label 返回階段的顯示名稱
+func (p Phase) Label() string {
+	if spec, ok := specs[p]; ok {
+		return spec.Title
+	}
+	return string(p)
+}
+
+// Spec 階段定義，內容與 Python WorkflowManager 的階段模板一致
+type Spec struct {
+	Phase         Phase    `json:"phase"`
+	Title         string   `json:"title"`
+	Tasks         []string `json:"tasks"`
+	Outputs       []string `json:"outputs"`
+	QualityChecks []string `json:"quality_checks"`
+	Rules         []string `json:"rules,omitempty"`
+	NextInput     string   `json:"next_phase_input"`
+}
+
+// SpecFor 返回階段定義
+func SpecFor(p Phase) (Spec, bool) {
+	spec, ok := specs[p]
+	return spec, ok
+}
+
+var specs = map[Phase]Spec{
+	PhaseAnalysis: {
+		Phase: PhaseAnalysis,
+		Title: "分析",
+		Tasks: []string{"需求澄清和验证", "技术约束识别", "风险评估分析", "影响范围评估", "资源需求评估"},
+		Outputs: []string{
+			"需求澄清结果", "技术约束清单", "风险评估报告", "影响分析报告", "资源需求评估",
+		},
+		QualityChecks: []string{"需求是否清晰明确", "技术约束是否完整", "风险是否充分识别", "影响范围是否准确"},
+		NextInput:     "为设计阶段准备的详细需求和约束文档",
+	},
+	PhaseDesign: {
+		Phase: PhaseDesign,
+		Title: "设计",
+		Tasks: []string{"架构方案设计", "技术方案选择", "接口设计定义", "数据模型设计", "实施计划制定"},
+		Outputs: []string{
+			"系统架构设计", "技术方案决策", "接口规格说明", "数据模型设计", "详细实施计划",
+		},
+		QualityChecks: []string{"架构是否合理可行", "技术选择是否恰当", "接口设计是否完整", "实施计划是否可执行"},
+		Rules:         []string{"遵循 TDD 先行原则", "避免过度抽象设计", "优先选择简单方案", "确保集成测试优先"},
+		NextInput:     "可直接执行的开发计划和技术规格",
+	},
+	PhaseDevelopment: {
+		Phase: PhaseDevelopment,
+		Title: "开发",
+		Tasks: []string{"TDD 测试用例编写", "核心功能实现", "单元测试执行", "集成测试验证", "代码质量检查"},
+		Outputs: []string{
+			"TDD 测试用例", "功能实现代码", "测试执行结果", "代码质量报告",
+		},
+		QualityChecks: []string{"所有测试是否通过", "代码覆盖率是否达标", "代码质量是否符合标准", "是否遵循 TDD 流程"},
+		Rules: []string{
+			"编写失败测试，实现最小代码使测试通过，重构后重复循环",
+			"测试先行，代码后行",
+			"最小化文件修改（≤3个文件）",
+			"每次提交都要通过所有测试",
+		},
+		NextInput: "完整的功能实现和测试验证结果",
+	},
+	PhasePersistence: {
+		Phase: PhasePersistence,
+		Title: "持久化",
+		Tasks: []string{"功能验证确认", "性能指标检查", "项目记忆更新", "经验教训记录", "状态同步执行"},
+		Outputs: []string{
+			"功能验证结果", "性能指标报告", "更新的项目记忆", "经验教训记录", "跨工具同步状态",
+		},
+		QualityChecks: []string{"验证结果是否符合预期", "性能指标是否达标", "记忆更新是否完整", "同步状态是否正常"},
+		Rules:         []string{"记录技术决策和理由", "保存成功的实践模式", "记录遇到的问题和解决方案", "更新项目上下文信息"},
+		NextInput:     "ADDP 工作流循环完成，可开始下一轮迭代",
+	},
+}
@@ -0,0 +1,130 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"ai-launcher/internal/terminal"
+)
+
+// DefaultPromptTemplate 階段提示詞的默認模板；
+// 項目可在 .addp/workflows/templates/<phase>.md 中覆蓋
+const DefaultPromptTemplate = `[ADDP 工作流 · {{.Spec.Title}}阶段 ({{.Phase}})，第 {{.Iteration}} 轮]
+项目当前处于 ADDP {{.Spec.Title}}阶段，本次会话只处理该阶段的工作，不要提前进入后续阶段。
+{{if .PreviousOutputs}}
+上一阶段的产出（请先阅读）：
+{{range .PreviousOutputs}}- {{.}}
+{{end}}{{end}}
+阶段任务：
+{{range .Spec.Tasks}}- {{.}}
+{{end}}
+需要产出：
+{{range .Spec.Outputs}}- {{.}}
+{{end}}{{if .Spec.Rules}}
+必须遵循：
+{{range .Spec.Rules}}- {{.}}
+{{end}}{{end}}
+请将阶段产出以 Markdown 文件保存到 {{.OutputDir}}/ 目录，并对照以下质量检查自检：
+{{range .Spec.QualityChecks}}- {{.}}
+{{end}}
+阶段结束时应交付：{{.Spec.NextInput}}
+[ADDP 工作流提示结束]`
+
+// PromptData 渲染階段提示詞時可用的數據
+type PromptData struct {
+	Project         string   // 項目目錄名
+	Phase           Phase    // 當前階段
+	Spec            Spec     // 階段定義
+	Iteration       int      // 第幾輪循環
+	OutputDir       string   // 階段產出目錄（相對項目根目錄）
+	PreviousOutputs []string // 上一階段的產出文件（相對項目根目錄）
+}
+
+// TemplatePath 返回項目自定義的階段提示詞模板路徑
+func (m *Manager) TemplatePath(p Phase) string {
+	return filepath.Join(m.dir, "templates", string(p)+".md")
+}
+
+// Prompt 渲染當前階段的提示詞；工作流尚未開始時返回空字串
+func (m *Manager) Prompt() (string, error) {
+	status, err := m.Load()
+	if err != nil || status == nil {
+		return "", err
+	}
+	return m.RenderPrompt(status.Current(), status.Iteration)
+}
+
+// RenderPrompt 渲染指定階段的提示詞，優先使用項目自定義模板
+func (m *Manager) RenderPrompt(p Phase, iteration int) (string, error) {
+	spec, ok := specs[p]
+	if !ok {
+		return "", fmt.Errorf("unknown phase: %s", p)
+	}
+
+	text := DefaultPromptTemplate
+	if data, err := os.ReadFile(m.TemplatePath(p)); err == nil {
+		text = string(data)
+	}
+	tmpl, err := template.New(string(p)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s prompt template: %w", p, err)
+	}
+
+	data := PromptData{
+		Project:   filepath.Base(m.projectPath),
+		Phase:     p,
+		Spec:      spec,
+		Iteration: iteration,
+		OutputDir: m.relPath(m.PhaseDir(p)),
+	}
+	if i := p.Index(); i > 0 {
+		previous := Phases[i-1]
+		for _, name := range m.Outputs(previous) {
+			data.PreviousOutputs = append(data.PreviousOutputs, m.relPath(filepath.Join(m.PhaseDir(previous), name)))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s prompt: %w", p, err)
+	}
+	return buf.String(), nil
+}
+
+// PhasePrompt 返回項目當前階段的提示詞；項目未開始工作流或渲染失敗時返回空字串
+func PhasePrompt(projectPath string) string {
+	if !Active(projectPath) {
+		return ""
+	}
+	prompt, err := NewManager(projectPath).Prompt()
+	if err != nil {
+		return ""
+	}
+	return prompt
+}
+
+// InjectPrompt 在提示詞前加入當前階段的提示詞，用於非交互任務
+func InjectPrompt(projectPath, prompt string) string {
+	phase := PhasePrompt(projectPath)
+	if phase == "" {
+		return prompt
+	}
+	return phase + "\n\n" + prompt
+}
+
+// Attach 為新的交互式會話加入當前階段的提示詞；恢復已有會話時不再注入
+func Attach(config *terminal.TerminalConfig, projectPath string) {
+	if config.Resume != "" {
+		return
+	}
+	if phase := PhasePrompt(projectPath); phase != "" {
+		if config.InitialPrompt != "" {
+			config.InitialPrompt = phase + "\n\n" + config.InitialPrompt
+		} else {
+			config.InitialPrompt = phase
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-launcher/internal/fsutil"
+)
+
+const (
+	statusFile      = "workflow_status.json"
+	timestampLayout = "2006-01-02T15:04:05.000000" // 與 Python datetime.isoformat() 一致
+
+	StatusInProgress = "in_progress" // 當前階段進行中
+	StatusCompleted  = "completed"   // 階段已完成（Python 實現寫入的狀態）
+
+	ActionStarted   = "started"   // 歷史：開始工作流
+	ActionCompleted = "completed" // 歷史：通過門禁完成階段
+	ActionForced    = "forced"    // 歷史：跳過門禁強制推進
+	ActionReturned  = "returned"  // 歷史：退回到較早的階段
+)
+
+// Status 工作流狀態文件 .addp/workflows/workflow_status.json，
+// 前五個字段與 Python WorkflowManager 相同
+type Status struct {
+	CurrentPhase    Phase              `json:"current_phase"`
+	LastUpdate      string             `json:"last_update"`
+	PhaseStatus     string             `json:"phase_status"`
+	NextPhase       Phase              `json:"next_phase"`
+	WorkflowHistory []HistoryEntry     `json:"workflow_history"`
+	Iteration       int                `json:"iteration,omitempty"`
+	PhaseStartedAt  string             `json:"phase_started_at,omitempty"`
+	ConfirmedChecks map[Phase][]string `json:"confirmed_checks,omitempty"`
+}
+
+// HistoryEntry 階段變更記錄
+type HistoryEntry struct {
+	Phase     Phase  `json:"phase"`
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	Duration  string `json:"duration,omitempty"`
+	Note      string `json:"note,omitempty"`
+}
+
+// Current 返回正在進行的階段；Python 實現在階段完成後把 next_phase 作為下一步
+func (s *Status) Current() Phase {
+	if s.PhaseStatus == StatusCompleted && s.NextPhase.Index() >= 0 {
+		return s.NextPhase
+	}
+	if s.CurrentPhase.Index() < 0 {
+		return PhaseAnalysis
+	}
+	return s.CurrentPhase
+}
+
+// Confirmed 返回階段已確認的質量檢查項
+func (s *Status) Confirmed(p Phase) []string {
+	return s.ConfirmedChecks[p]
+}
+
+// GateCheck 門禁中的單項檢查
+type GateCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// GateResult 階段門禁的檢查結果
+type GateResult struct {
+	Phase  Phase       `json:"phase"`
+	Checks []GateCheck `json:"checks"`
+	Passed bool        `json:"passed"`
+}
+
+// Failed 返回未通過的檢查
+func (g *GateResult) Failed() []GateCheck {
+	var failed []GateCheck
+	for _, c := range g.Checks {
+		if !c.Passed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// GateError 門禁未通過時 Advance 返回的錯誤
+type GateError struct {
+	Gate *GateResult
+}
+
+func (e *GateError) Error() string {
+	names := make([]string, 0, len(e.Gate.Checks))
+	for _, c := range e.Gate.Failed() {
+		names = append(names, c.Name)
+	}
+	return fmt.Sprintf("phase gate for %s not passed: %s", e.Gate.Phase, strings.Join(names, ", "))
+}
+
+// Manager 管理單個項目的 .addp/workflows 目錄
+type Manager struct {
+	projectPath string
+	dir         string
+}
+
+// NewManager 創建項目的工作流管理器
+func NewManager(projectPath string) *Manager {
+	return &Manager{
+		projectPath: projectPath,
+		dir:         filepath.Join(projectPath, ".addp", "workflows"),
+	}
+}
+
+// Dir 返回工作流目錄
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// PhaseDir 返回階段產出目錄
+func (m *Manager) PhaseDir(p Phase) string {
+	return filepath.Join(m.dir, string(p))
+}
+
+// Active 檢查項目是否已開始工作流
+func Active(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, ".addp", "workflows", statusFile))
+	return err == nil
+}
+
+// Load 讀取工作流狀態；尚未開始時返回 nil
+func (m *Manager) Load() (*Status, error) {
+	data, err := os.ReadFile(m.statusPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow status: %w", err)
+	}
+
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow status: %w", err)
+	}
+	return &status, nil
+}
+
+// Start 從分析階段開始工作流並創建各階段目錄；已開始時返回現有狀態
+func (m *Manager) Start() (*Status, error) {
+	for _, p := range Phases {
+		if err := os.MkdirAll(m.PhaseDir(p), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create workflow directory: %w", err)
+		}
+	}
+
+	var started *Status
+	err := fsutil.WithLock(m.statusPath(), func() error {
+		status, err := m.Load()
+		if err != nil || status != nil {
+			started = status
+			return err
+		}
+
+		now := timestamp(time.Now())
+		started = &Status{Iteration: 1, WorkflowHistory: []HistoryEntry{}}
+		started.enter(PhaseAnalysis, now)
+		started.WorkflowHistory = append(started.WorkflowHistory, HistoryEntry{
+			Phase: PhaseAnalysis, Timestamp: now, Status: ActionStarted,
+		})
+		return m.save(started)
+	})
+	return started, err
+}
+
+// CheckGate 檢查當前階段能否推進：階段目錄中需要有產出，且所有質量檢查項都已確認
+func (m *Manager) CheckGate() (*GateResult, error) {
+	status, err := m.requireStatus()
+	if err != nil {
+		return nil, err
+	}
+	return m.checkGate(status), nil
+}
+
+func (m *Manager) checkGate(status *Status) *GateResult {
+	phase := status.Current()
+	spec := specs[phase]
+	gate := &GateResult{Phase: phase, Passed: true}
+
+	outputs := m.Outputs(phase)
+	output := GateCheck{Name: "phase output", Passed: len(outputs) > 0}
+	if output.Passed {
+		output.Detail = strings.Join(outputs, ", ")
+	} else {
+		output.Detail = "no output in " + m.relPath(m.PhaseDir(phase))
+	}
+	gate.Checks = append(gate.Checks, output)
+
+	confirmed := status.Confirmed(phase)
+	for _, check := range spec.QualityChecks {
+		gate.Checks = append(gate.Checks, GateCheck{Name: check, Passed: contains(confirmed, check)})
+	}
+
+	for _, c := range gate.Checks {
+		gate.Passed = gate.Passed && c.Passed
+	}
+	return gate
+}
+
+// Confirm 確認當前階段的質量檢查項
+func (m *Manager) Confirm(checks ...string) (*Status, error) {
+	return m.update(func(status *Status) error {
+		phase := status.Current()
+		spec := specs[phase]
+		for _, check := range checks {
+			if !contains(spec.QualityChecks, check) {
+				return fmt.Errorf("unknown quality check for %s: %s", phase, check)
+			}
+			if !contains(status.ConfirmedChecks[phase], check) {
+				if status.ConfirmedChecks == nil {
+					status.ConfirmedChecks = make(map[Phase][]string)
+				}
+				status.ConfirmedChecks[phase] = append(status.ConfirmedChecks[phase], check)
+			}
+		}
+		return nil
+	})
+}
+
+// Unconfirm 取消確認當前階段的質量檢查項
+func (m *Manager) Unconfirm(checks ...string) (*Status, error) {
+	return m.update(func(status *Status) error {
+		phase := status.Current()
+		kept := status.ConfirmedChecks[phase][:0]
+		for _, check := range status.ConfirmedChecks[phase] {
+			if !contains(checks, check) {
+				kept = append(kept, check)
+			}
+		}
+		if len(kept) == 0 {
+			delete(status.ConfirmedChecks, phase)
+		} else {
+			status.ConfirmedChecks[phase] = kept
+		}
+		return nil
+	})
+}
+
+// Advance 門禁通過後進入下一階段；force 為 true 時跳過門禁並在歷史中注明。
+// 持久化階段完成後回到分析階段開始新一輪
+func (m *Manager) Advance(force bool) (*Status, error) {
+	return m.update(func(status *Status) error {
+		gate := m.checkGate(status)
+		if !gate.Passed && !force {
+			return &GateError{Gate: gate}
+		}
+
+		now := time.Now()
+		phase := status.Current()
+		action := ActionCompleted
+		if !gate.Passed {
+			action = ActionForced
+		}
+		status.WorkflowHistory = append(status.WorkflowHistory, HistoryEntry{
+			Phase:     phase,
+			Timestamp: timestamp(now),
+			Status:    action,
+			Duration:  status.elapsed(now),
+		})
+
+		next := phase.Next()
+		if next == PhaseAnalysis {
+			status.Iteration++
+			status.ConfirmedChecks = nil
+		}
+		status.enter(next, timestamp(now))
+		return nil
+	})
+}
+
+// Return 退回到較早的階段，該階段及之後階段的確認項需要重新確認
+func (m *Manager) Return(target Phase, note string) (*Status, error) {
+	return m.update(func(status *Status) error {
+		phase := status.Current()
+		if target.Index() < 0 || target.Index() >= phase.Index() {
+			return fmt.Errorf("can only return to a phase before %s", phase)
+		}
+
+		now := time.Now()
+		status.WorkflowHistory = append(status.WorkflowHistory, HistoryEntry{
+			Phase:     phase,
+			Timestamp: timestamp(now),
+			Status:    ActionReturned,
+			Duration:  status.elapsed(now),
+			Note:      strings.TrimSpace(fmt.Sprintf("returned to %s %s", target, note)),
+		})
+		for _, p := range Phases[target.Index():] {
+			delete(status.ConfirmedChecks, p)
+		}
+		status.enter(target, timestamp(now))
+		return nil
+	})
+}
+
+// TemplateFileName 返回 ADDP 初始化時寫入階段目錄的模板文件名，該文件不算作階段產出
+func TemplateFileName(p Phase) string {
+	return string(p) + "_template.md"
+}
+
+// Outputs 返回階段目錄中的產出文件名（忽略隱藏文件與階段模板），按名稱排序
+func (m *Manager) Outputs(p Phase) []string {
+	entries, err := os.ReadDir(m.PhaseDir(p))
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") && e.Name() != TemplateFileName(p) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// update 在鎖內讀取、修改並保存狀態
+func (m *Manager) update(fn func(*Status) error) (*Status, error) {
+	var updated *Status
+	err := fsutil.WithLock(m.statusPath(), func() error {
+		status, err := m.requireStatus()
+		if err != nil {
+			return err
+		}
+		if err := fn(status); err != nil {
+			return err
+		}
+		updated = status
+		return m.save(status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (m *Manager) requireStatus() (*Status, error) {
+	status, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, fmt.Errorf("workflow not started in %s", m.projectPath)
+	}
+	return status, nil
+}
+
+func (m *Manager) save(status *Status) error {
+	status.LastUpdate = timestamp(time.Now())
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow status: %w", err)
+	}
+	return fsutil.WriteFileAtomic(m.statusPath(), data, 0644)
+}
+
+func (m *Manager) statusPath() string {
+	return filepath.Join(m.dir, statusFile)
+}
+
+// relPath 返回相對項目根目錄的路徑，用於提示詞與錯誤信息
+func (m *Manager) relPath(path string) string {
+	if rel, err := filepath.Rel(m.projectPath, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// enter 切換到指定階段
+func (s *Status) enter(p Phase, now string) {
+	s.CurrentPhase = p
+	s.PhaseStatus = StatusInProgress
+	s.NextPhase = p.Next()
+	s.PhaseStartedAt = now
+	if s.Iteration == 0 {
+		s.Iteration = 1
+	}
+}
+
+// elapsed 返回當前階段已持續的時間
+func (s *Status) elapsed(now time.Time) string {
+	started, err := time.ParseInLocation(timestampLayout, s.PhaseStartedAt, time.Local)
+	if err != nil {
+		return ""
+	}
+	return now.Sub(started).Round(time.Second).String()
+}
+
+func timestamp(t time.Time) string {
+	return t.Format(timestampLayout)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
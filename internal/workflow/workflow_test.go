@@ -0,0 +1,233 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ai-launcher/internal/terminal"
+)
+
+// writeOutput 在階段目錄中寫入一個產出文件
+func writeOutput(t *testing.T, m *Manager, p Phase) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(m.PhaseDir(p), string(p)+".md"), []byte("# "+string(p)), 0644))
+}
+
+// passGate 讓當前階段滿足門禁
+func passGate(t *testing.T, m *Manager) {
+	t.Helper()
+	status, err := m.Load()
+	require.NoError(t, err)
+	phase := status.Current()
+	writeOutput(t, m, phase)
+	_, err = m.Confirm(specs[phase].QualityChecks...)
+	require.NoError(t, err)
+}
+
+func TestPhase_Order(t *testing.T) {
+	assert.Equal(t, PhaseDesign, PhaseAnalysis.Next())
+	assert.Equal(t, PhaseAnalysis, PhasePersistence.Next())
+	assert.Equal(t, "分析", PhaseAnalysis.Label())
+
+	p, err := ParsePhase("development")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Index())
+
+	_, err = ParsePhase("review")
+	assert.Error(t, err)
+}
+
+func TestManager_StartIsIdempotent(t *testing.T) {
+	project := t.TempDir()
+	m := NewManager(project)
+
+	assert.False(t, Active(project))
+	status, err := m.Load()
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	status, err = m.Start()
+	require.NoError(t, err)
+	assert.True(t, Active(project))
+	assert.Equal(t, PhaseAnalysis, status.Current())
+	assert.Equal(t, PhaseDesign, status.NextPhase)
+	assert.Equal(t, 1, status.Iteration)
+	for _, p := range Phases {
+		assert.DirExists(t, m.PhaseDir(p))
+	}
+
+	_, err = m.Confirm(specs[PhaseAnalysis].QualityChecks[0])
+	require.NoError(t, err)
+	again, err := m.Start()
+	require.NoError(t, err)
+	assert.Len(t, again.Confirmed(PhaseAnalysis), 1)
+	assert.Len(t, again.WorkflowHistory, 1)
+}
+
+func TestManager_GateBlocksAdvance(t *testing.T) {
+	m := NewManager(t.TempDir())
+	_, err := m.Start()
+	require.NoError(t, err)
+
+	// 初始化時生成的階段模板不算作產出
+	require.NoError(t, os.WriteFile(filepath.Join(m.PhaseDir(PhaseAnalysis), TemplateFileName(PhaseAnalysis)), []byte("# template"), 0644))
+
+	_, err = m.Advance(false)
+	var gateErr *GateError
+	require.True(t, errors.As(err, &gateErr))
+	assert.Equal(t, PhaseAnalysis, gateErr.Gate.Phase)
+	assert.Len(t, gateErr.Gate.Failed(), 1+len(specs[PhaseAnalysis].QualityChecks))
+
+	writeOutput(t, m, PhaseAnalysis)
+	gate, err := m.CheckGate()
+	require.NoError(t, err)
+	assert.False(t, gate.Passed)
+	assert.True(t, gate.Checks[0].Passed)
+	assert.Equal(t, "analysis.md", gate.Checks[0].Detail)
+
+	_, err = m.Confirm("不存在的检查")
+	assert.Error(t, err)
+
+	_, err = m.Confirm(specs[PhaseAnalysis].QualityChecks...)
+	require.NoError(t, err)
+	status, err := m.Unconfirm(specs[PhaseAnalysis].QualityChecks[0])
+	require.NoError(t, err)
+	assert.Len(t, status.Confirmed(PhaseAnalysis), len(specs[PhaseAnalysis].QualityChecks)-1)
+	_, err = m.Confirm(specs[PhaseAnalysis].QualityChecks[0])
+	require.NoError(t, err)
+	gate, err = m.CheckGate()
+	require.NoError(t, err)
+	assert.True(t, gate.Passed)
+
+	status, err = m.Advance(false)
+	require.NoError(t, err)
+	assert.Equal(t, PhaseDesign, status.Current())
+	last := status.WorkflowHistory[len(status.WorkflowHistory)-1]
+	assert.Equal(t, PhaseAnalysis, last.Phase)
+	assert.Equal(t, ActionCompleted, last.Status)
+	assert.NotEmpty(t, last.Duration)
+}
+
+func TestManager_ForceAndFullCycle(t *testing.T) {
+	m := NewManager(t.TempDir())
+	_, err := m.Start()
+	require.NoError(t, err)
+
+	status, err := m.Advance(true)
+	require.NoError(t, err)
+	assert.Equal(t, PhaseDesign, status.Current())
+	assert.Equal(t, ActionForced, status.WorkflowHistory[len(status.WorkflowHistory)-1].Status)
+
+	for i := 0; i < 3; i++ {
+		passGate(t, m)
+		status, err = m.Advance(false)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, PhaseAnalysis, status.Current())
+	assert.Equal(t, 2, status.Iteration)
+	assert.Empty(t, status.ConfirmedChecks)
+}
+
+func TestManager_Return(t *testing.T) {
+	m := NewManager(t.TempDir())
+	_, err := m.Start()
+	require.NoError(t, err)
+	passGate(t, m)
+	_, err = m.Advance(false)
+	require.NoError(t, err)
+	passGate(t, m)
+	_, err = m.Advance(false)
+	require.NoError(t, err)
+
+	_, err = m.Return(PhaseDevelopment, "")
+	assert.Error(t, err, "cannot return to the current phase")
+	_, err = m.Return(PhasePersistence, "")
+	assert.Error(t, err)
+
+	status, err := m.Return(PhaseDesign, "接口需要调整")
+	require.NoError(t, err)
+	assert.Equal(t, PhaseDesign, status.Current())
+	assert.Empty(t, status.Confirmed(PhaseDesign))
+	assert.NotEmpty(t, status.Confirmed(PhaseAnalysis))
+	last := status.WorkflowHistory[len(status.WorkflowHistory)-1]
+	assert.Equal(t, ActionReturned, last.Status)
+	assert.Equal(t, PhaseDevelopment, last.Phase)
+	assert.Contains(t, last.Note, "接口需要调整")
+}
+
+func TestStatus_PythonCompatible(t *testing.T) {
+	project := t.TempDir()
+	m := NewManager(project)
+	require.NoError(t, os.MkdirAll(m.Dir(), 0755))
+
+	// Python WorkflowManager 在完成設計階段後寫入的狀態
+	python := `{
+  "current_phase": "design",
+  "last_update": "2025-01-02T10:00:00.123456",
+  "phase_status": "completed",
+  "next_phase": "development",
+  "workflow_history": [
+    {"phase": "design", "timestamp": "2025-01-02T10:00:00.123456", "status": "completed", "duration": "自动执行"}
+  ]
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(m.Dir(), statusFile), []byte(python), 0644))
+
+	status, err := m.Load()
+	require.NoError(t, err)
+	assert.Equal(t, PhaseDevelopment, status.Current())
+
+	gate, err := m.CheckGate()
+	require.NoError(t, err)
+	assert.Equal(t, PhaseDevelopment, gate.Phase)
+
+	status, err = m.Advance(true)
+	require.NoError(t, err)
+	assert.Equal(t, PhasePersistence, status.Current())
+	assert.Equal(t, StatusInProgress, status.PhaseStatus)
+	assert.Len(t, status.WorkflowHistory, 2)
+}
+
+func TestPrompt_DefaultAndOverride(t *testing.T) {
+	project := t.TempDir()
+	m := NewManager(project)
+
+	assert.Empty(t, PhasePrompt(project))
+	assert.Equal(t, "task", InjectPrompt(project, "task"))
+
+	_, err := m.Start()
+	require.NoError(t, err)
+	passGate(t, m)
+	_, err = m.Advance(false)
+	require.NoError(t, err)
+
+	prompt := PhasePrompt(project)
+	assert.Contains(t, prompt, "设计阶段 (design)")
+	assert.Contains(t, prompt, ".addp/workflows/design/")
+	assert.Contains(t, prompt, "- .addp/workflows/analysis/analysis.md")
+	assert.Contains(t, prompt, "遵循 TDD 先行原则")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(m.TemplatePath(PhaseDesign)), 0755))
+	require.NoError(t, os.WriteFile(m.TemplatePath(PhaseDesign), []byte("{{.Project}}: {{.Spec.Title}} -> {{.OutputDir}}"), 0644))
+	assert.Equal(t, filepath.Base(project)+": 设计 -> .addp/workflows/design", PhasePrompt(project))
+	assert.Equal(t, filepath.Base(project)+": 设计 -> .addp/workflows/design\n\ntask", InjectPrompt(project, "task"))
+}
+
+func TestAttach(t *testing.T) {
+	project := t.TempDir()
+	_, err := NewManager(project).Start()
+	require.NoError(t, err)
+
+	config := terminal.TerminalConfig{InitialPrompt: "handoff"}
+	Attach(&config, project)
+	assert.Contains(t, config.InitialPrompt, "分析阶段")
+	assert.Contains(t, config.InitialPrompt, "\n\nhandoff")
+
+	resumed := terminal.TerminalConfig{Resume: terminal.ResumeLatest}
+	Attach(&resumed, project)
+	assert.Empty(t, resumed.InitialPrompt)
+}